@@ -22,6 +22,8 @@ var allIndexes = []string{
 	"supplier",
 	"order",
 	"plan",
+	"inventory",
+	"inventory_aging",
 }
 
 func main() {
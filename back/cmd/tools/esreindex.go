@@ -9,6 +9,7 @@ import (
 
 	"back/config"
 	clientDomain "back/internal/client/domain"
+	inventoryDomain "back/internal/inventory/domain"
 	materialDomain "back/internal/material/domain"
 	orderDomain "back/internal/order/domain"
 	planDomain "back/internal/plan/domain"
@@ -24,7 +25,7 @@ import (
 )
 
 // 支持的域列表
-var supportedDomains = []string{"client", "material", "process", "product", "supplier", "order", "plan"}
+var supportedDomains = []string{"client", "material", "process", "product", "supplier", "order", "plan", "inventory"}
 
 func main() {
 	log.Println("=== ES Reindex Tool ===")
@@ -163,6 +164,8 @@ func reindexDomain(db *gorm.DB, esSync *es.ESSync, domain string) (success, fail
 		return reindexOrders(db, esSync)
 	case "plan":
 		return reindexPlans(db, esSync)
+	case "inventory":
+		return reindexInventories(db, esSync)
 	default:
 		return 0, 0, fmt.Errorf("unsupported domain: %s", domain)
 	}
@@ -385,6 +388,37 @@ func reindexPlans(db *gorm.DB, esSync *es.ESSync) (success, failed int, err erro
 	return batchIndexDocuments(esSync, activePlans, 200)
 }
 
+// reindexInventories 重新索引库存批次数据
+func reindexInventories(db *gorm.DB, esSync *es.ESSync) (success, failed int, err error) {
+	log.Println("→ Fetching inventories from database...")
+
+	var inventories []inventoryDomain.Inventory
+	if err := db.Unscoped().Find(&inventories).Error; err != nil {
+		return 0, 0, fmt.Errorf("failed to fetch inventories: %w", err)
+	}
+
+	total := len(inventories)
+	log.Printf("  Found %d inventories", total)
+
+	if total == 0 {
+		return 0, 0, nil
+	}
+
+	// 过滤掉已删除的记录
+	var activeInventories []interface{}
+	for _, inventory := range inventories {
+		if !inventory.DeletedAt.Valid {
+			inv := inventory
+			activeInventories = append(activeInventories, &inv)
+		}
+	}
+
+	log.Printf("  Indexing %d active inventories...", len(activeInventories))
+
+	// 批量索引（每批 200 条）
+	return batchIndexDocuments(esSync, activeInventories, 200)
+}
+
 // batchIndexDocuments 批量索引文档的辅助函数
 func batchIndexDocuments(esSync *es.ESSync, docs []interface{}, batchSize int) (totalSuccess, totalFailed int, err error) {
 	total := len(docs)
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"back/config"
+	currencyApp "back/internal/currency/application"
+	currencyInfra "back/internal/currency/infra"
+	pricingApp "back/internal/pricing/application"
+	pricingInfra "back/internal/pricing/infra"
+	supplierApp "back/internal/supplier/application"
+	supplierInfra "back/internal/supplier/infra"
+	applog "back/pkg/log"
+)
+
+func main() {
+	log.Println("=== Price Cache Rebuild Tool ===")
+
+	// 1. 加载配置
+	cfg := config.LoadConfig()
+	log.Println("✓ Config loaded")
+
+	// 2. 初始化日志
+	if err := applog.Init(cfg.LogLevel, cfg.LogFormat); err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+	log.Println("✓ Logger initialized")
+
+	// 3. 初始化数据库与 Redis
+	db := config.InitDatabase(cfg)
+	log.Println("✓ Database connected")
+	rdb := config.InitRedis(cfg)
+	log.Println("✓ Redis connected")
+
+	// 4. 组装缓存服务（只读取供应商名称，不需要 ES 同步）
+	ttl, _ := time.ParseDuration(cfg.PriceCacheTTL)
+	supplierService := supplierApp.NewSupplierService(supplierInfra.NewSupplierRepo(db), nil)
+	currencyService := currencyApp.NewCurrencyService(currencyInfra.NewExchangeRateRepo(db), cfg.BaseCurrency)
+	service := pricingApp.NewPriceCacheService(
+		pricingInfra.NewSupplierPriceRepo(db),
+		pricingInfra.NewPriceCacheImpl(rdb),
+		supplierService,
+		currencyService,
+		ttl,
+	)
+
+	// 5. 重建
+	resp, err := service.Rebuild(context.Background())
+	if err != nil {
+		log.Fatalf("✗ Rebuild failed: %v", err)
+	}
+
+	for _, e := range resp.Errors {
+		log.Printf("✗ %s", e)
+	}
+
+	log.Println("=== Summary ===")
+	log.Printf("Targets: %d", resp.Targets)
+	log.Printf("Cached:  %d", resp.Cached)
+	log.Printf("Empty:   %d", resp.Empty)
+	log.Printf("Failed:  %d", resp.Failed)
+	log.Printf("Took:    %s", resp.Duration)
+}
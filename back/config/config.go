@@ -2,6 +2,8 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
 )
 
 type Config struct {
@@ -31,6 +33,29 @@ type Config struct {
 	// Log
 	LogLevel  string
 	LogFormat string
+
+	// Jobs
+	ReplenishmentInterval string // 补货评估间隔（time.ParseDuration 格式）
+
+	// Currency
+	BaseCurrency string // 本位币（成本、订单毛利与报表默认以此币种计）
+
+	// Pricing
+	PriceCacheTTL      string // 最低价 / 最高价缓存过期时间（time.ParseDuration 格式）
+	PriceWebhookSecret string // 报价提醒 Webhook 签名密钥（为空时不签名）
+
+	// Plan
+	PlanBatchCapacity float64 // 自动生成计划时单个计划的产能上限（0 表示不拆分）
+
+	// Product
+	ProductApprovalChain []string // 产品 / BOM 多级审批角色（逗号分隔，为空时一级审批）
+	ProductReworkRate    float64  // 无历史次品数据时的默认回修率（%）
+	ProductOverheadRates []string // 默认间接费用分摊（"名称:百分比"，逗号分隔）
+
+	ProductCostAlertThreshold float64 // 已审批产品成本变动预警阈值（%，0 表示不预警）
+
+	// Sales
+	SalesMarginFloor float64 // 销售单价相对产品单位成本的加成下限（%），低于时下单预警
 }
 
 func LoadConfig() *Config {
@@ -61,6 +86,29 @@ func LoadConfig() *Config {
 		// Log
 		LogLevel:  getEnv("LOG_LEVEL", "info"),
 		LogFormat: getEnv("LOG_FORMAT", "console"),
+
+		// Jobs
+		ReplenishmentInterval: getEnv("REPLENISHMENT_INTERVAL", "6h"),
+
+		// Currency
+		BaseCurrency: getEnv("BASE_CURRENCY", "CNY"),
+
+		// Pricing
+		PriceCacheTTL:      getEnv("PRICE_CACHE_TTL", "1h"),
+		PriceWebhookSecret: getEnv("PRICE_WEBHOOK_SECRET", ""),
+
+		// Plan
+		PlanBatchCapacity: getEnvFloat("PLAN_BATCH_CAPACITY", 0),
+
+		// Product
+		ProductApprovalChain: getEnvList("PRODUCT_APPROVAL_CHAIN"),
+		ProductReworkRate:    getEnvFloat("PRODUCT_REWORK_RATE", 0),
+		ProductOverheadRates: getEnvList("PRODUCT_OVERHEAD_RATES"),
+
+		ProductCostAlertThreshold: getEnvFloat("PRODUCT_COST_ALERT_THRESHOLD", 5),
+
+		// Sales
+		SalesMarginFloor: getEnvFloat("SALES_MARGIN_FLOOR", 10),
 	}
 }
 
@@ -70,3 +118,20 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value, err := strconv.ParseFloat(os.Getenv(key), 64); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -15,6 +15,7 @@ import (
 	userDomain "back/internal/user/domain"
 	"back/pkg/es"
 	applog "back/pkg/log"
+	"back/pkg/schedule"
 )
 
 func Init() error {
@@ -64,7 +65,7 @@ func Init() error {
 	log.Printf("✓ Search registry initialized with indices: %v", searchRegistry.ListIndices())
 
 	log.Println("=== Initializing Services ===")
-	services := InitServices(db, rdb, esClient, jwtWang, whitelistManager, casbinManager, esSync, searchRegistry)
+	services := InitServices(cfg, db, rdb, esClient, jwtWang, whitelistManager, casbinManager, esSync, searchRegistry)
 	log.Println("✓ Services initialized")
 
 	log.Println("=== Initializing Router ===")
@@ -76,7 +77,12 @@ func Init() error {
 		Handler: router,
 	}
 
-	go gracefulShutdown(server, logger)
+	log.Println("=== Initializing Jobs ===")
+	scheduler := InitJobs(cfg, services, logger)
+	scheduler.Start()
+	log.Println("✓ Jobs started")
+
+	go gracefulShutdown(server, scheduler, logger)
 
 	log.Printf("=== Server started on %s ===\n", cfg.ServerAddr)
 
@@ -111,7 +117,7 @@ func InitAdminUser(db *gorm.DB) {
 	log.Println("✓ Default admin user created (login_id: 8000, password: admin)")
 }
 
-func gracefulShutdown(server *http.Server, logger *applog.Logger) {
+func gracefulShutdown(server *http.Server, scheduler *schedule.Scheduler, logger *applog.Logger) {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
@@ -125,6 +131,9 @@ func gracefulShutdown(server *http.Server, logger *applog.Logger) {
 		log.Fatal("Server forced to shutdown:", err)
 	}
 
+	// 停止定时任务
+	scheduler.Stop()
+
 	// 同步日志
 	if logger != nil {
 		logger.Sync()
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"context"
+	"log"
+	"time"
+
+	inventoryDomain "back/internal/inventory/domain"
+	applog "back/pkg/log"
+	"back/pkg/schedule"
+)
+
+// InitJobs 注册定时任务
+func InitJobs(cfg *Config, services *Services, logger *applog.Logger) *schedule.Scheduler {
+	scheduler := schedule.NewScheduler(logger)
+
+	// 补货评估：生成补货建议并发送低库存预警
+	interval, err := time.ParseDuration(cfg.ReplenishmentInterval)
+	if err != nil || interval <= 0 {
+		log.Printf("Warning: invalid REPLENISHMENT_INTERVAL %q, using 6h", cfg.ReplenishmentInterval)
+		interval = 6 * time.Hour
+	}
+	scheduler.Every("replenishment", interval, func(ctx context.Context) error {
+		_, err := services.Replenishment.RunReplenishment(ctx)
+		return err
+	})
+
+	// 库龄索引：每日凌晨重建，供搜索模块按库龄与呆滞标记过滤
+	scheduler.Daily("inventory_aging_index", 2, 0, func(ctx context.Context) error {
+		_, err := services.InventoryAging.RebuildAgingIndex(ctx, inventoryDomain.DefaultSlowMovingDays)
+		return err
+	})
+
+	// 产品成本快照：每日生成一次，供成本趋势与变动预警使用
+	scheduler.Daily("product_cost_snapshot", 3, 0, func(ctx context.Context) error {
+		_, err := services.ProductCostSnapshot.RunDailySnapshots(ctx)
+		return err
+	})
+
+	// 报价到期提醒：每日早上扫描关注目标中即将到期的报价
+	scheduler.Daily("price_watch_expiry", 8, 0, func(ctx context.Context) error {
+		_, err := services.PriceWatch.ScanExpiring(ctx)
+		return err
+	})
+
+	return scheduler
+}
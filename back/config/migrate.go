@@ -3,15 +3,19 @@ package config
 import (
 	"gorm.io/gorm"
 	"log"
+	"time"
 
 	clientDomain "back/internal/client/domain"
+	currencyDomain "back/internal/currency/domain"
 	inventoryDomain "back/internal/inventory/domain"
 	materialDomain "back/internal/material/domain"
+	notificationDomain "back/internal/notification/domain"
 	orderDomain "back/internal/order/domain"
 	planDomain "back/internal/plan/domain"
 	pricingDomain "back/internal/pricing/domain"
 	processDomain "back/internal/process/domain"
 	productDomain "back/internal/product/domain"
+	salesDomain "back/internal/sales/domain"
 	supplierDomain "back/internal/supplier/domain"
 	userDomain "back/internal/user/domain"
 	"back/pkg/audit"
@@ -31,14 +35,41 @@ func AutoMigrate(db *gorm.DB) error {
 		&clientDomain.Client{},
 		&materialDomain.Material{},
 		&processDomain.Process{},
+		&currencyDomain.ExchangeRate{},
 		&pricingDomain.SupplierPrice{},
+		&pricingDomain.PriceTier{},
+		&pricingDomain.RFQ{},
+		&pricingDomain.RFQItem{},
+		&pricingDomain.RFQInvitation{},
+		&pricingDomain.PreferredSupplier{},
+		&pricingDomain.PriceWatch{},
+		&pricingDomain.PriceAlert{},
 		&productDomain.Product{},
+		&productDomain.BOMVersion{},
+		&productDomain.ProductApproval{},
+		&productDomain.CostSnapshot{},
 		&planDomain.Plan{},
+		&planDomain.WorkCenter{},
+		&planDomain.ScheduleSlot{},
 		&orderDomain.Order{},
 		&orderDomain.OrderParticipant{},
 		&orderDomain.OrderProgress{},
+		&orderDomain.OrderStepProgress{},
 		&orderDomain.OrderEvent{},
+		&salesDomain.SalesPrice{},
+		&salesDomain.Quotation{},
+		&salesDomain.QuotationItem{},
 		&inventoryDomain.Inventory{},
+		&inventoryDomain.CostingConfig{},
+		&inventoryDomain.InventoryMovement{},
+		&inventoryDomain.StocktakeSession{},
+		&inventoryDomain.StocktakeLine{},
+		&inventoryDomain.BatchLink{},
+		&inventoryDomain.ReorderRule{},
+		&inventoryDomain.InventoryReservation{},
+		&inventoryDomain.ReplenishmentSuggestion{},
+		&notificationDomain.Notification{},
+		&notificationDomain.NotificationRead{},
 	)
 
 	if err != nil {
@@ -47,5 +78,79 @@ func AutoMigrate(db *gorm.DB) error {
 	}
 
 	log.Println("✓ All tables migrated successfully")
+
+	if err := seedExchangeRates(db); err != nil {
+		log.Printf("✗ Seeding exchange rates failed: %v", err)
+		return err
+	}
+
+	if err := seedOpeningMovements(db); err != nil {
+		log.Printf("✗ Seeding opening inventory movements failed: %v", err)
+		return err
+	}
+	return nil
+}
+
+// seedExchangeRates 没有任何 USD → CNY 汇率时写入启用汇率管理前固定使用的汇率（自 2000-01-01 生效），
+// 保证既有的美元报价与退款数据在维护实际汇率前仍可换算；其他币种须先通过汇率接口维护
+func seedExchangeRates(db *gorm.DB) error {
+	var count int64
+	if err := db.Model(&currencyDomain.ExchangeRate{}).
+		Where("from_currency = ? AND to_currency = ?", currencyDomain.USD, currencyDomain.CNY).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	rate := &currencyDomain.ExchangeRate{
+		FromCurrency:  currencyDomain.USD,
+		ToCurrency:    currencyDomain.CNY,
+		Rate:          currencyDomain.LegacyUSDRate,
+		EffectiveDate: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+		Source:        currencyDomain.RateSourceSeed,
+		CreatedBy:     "system",
+	}
+	if err := db.Create(rate).Error; err != nil {
+		return err
+	}
+	log.Printf("✓ Seeded USD → CNY exchange rate %.2f (update it via /currency/rates)", rate.Rate)
+	return nil
+}
+
+// seedOpeningMovements 为启用计价前已有的批次补记期初流水（当前结存扣除已有流水，已删除批次结存为 0），使估值报表包含这部分库存
+// 启用计价后新建的批次均以入库流水开始，因此只处理没有入库 / 期初流水的批次，重复执行不会重复补记
+func seedOpeningMovements(db *gorm.DB) error {
+	result := db.Exec(`
+		INSERT INTO inventory_movements
+			(inventory_id, product_id, category, batch_id, type, quantity, unit_cost, total_cost, costing_method, remark, created_at)
+		SELECT id, product_id, category, batch_id, ?, quantity, unit_cost, total_cost, method, ?, created_at
+		FROM (
+			SELECT i.id, i.product_id, i.category, i.batch_id, i.unit_cost, i.created_at,
+				COALESCE(c.method, ?) AS method,
+				CASE WHEN i.deleted_at IS NULL THEN i.quantity ELSE 0 END - COALESCE(m.quantity, 0) AS quantity,
+				CASE WHEN i.deleted_at IS NULL THEN i.total_cost ELSE 0 END - COALESCE(m.total_cost, 0) AS total_cost
+			FROM inventories i
+			LEFT JOIN (
+				SELECT inventory_id, SUM(quantity) AS quantity, SUM(total_cost) AS total_cost
+				FROM inventory_movements GROUP BY inventory_id
+			) m ON m.inventory_id = i.id
+			LEFT JOIN inventory_costing_configs c ON c.category = i.category
+			WHERE NOT EXISTS (
+				SELECT 1 FROM inventory_movements o
+				WHERE o.inventory_id = i.id AND o.type IN (?, ?)
+			)
+		) opening
+		WHERE quantity <> 0 OR total_cost <> 0`,
+		inventoryDomain.MovementTypeOpening, "期初结存", inventoryDomain.DefaultCostingMethod,
+		inventoryDomain.MovementTypeReceipt, inventoryDomain.MovementTypeOpening,
+	)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("✓ Seeded %d opening inventory movements", result.RowsAffected)
+	}
 	return nil
 }
@@ -12,13 +12,16 @@ import (
 	analyticsInterfaces "back/internal/analytics/interfaces"
 	authInterfaces "back/internal/auth/interfaces"
 	clientInterfaces "back/internal/client/interfaces"
+	currencyInterfaces "back/internal/currency/interfaces"
 	inventoryInterfaces "back/internal/inventory/interfaces"
 	materialInterfaces "back/internal/material/interfaces"
+	notificationInterfaces "back/internal/notification/interfaces"
 	orderInterfaces "back/internal/order/interfaces"
 	planInterfaces "back/internal/plan/interfaces"
 	pricingInterfaces "back/internal/pricing/interfaces"
 	processInterfaces "back/internal/process/interfaces"
 	productInterfaces "back/internal/product/interfaces"
+	salesInterfaces "back/internal/sales/interfaces"
 	searchInterfaces "back/internal/search/interfaces"
 	supplierInterfaces "back/internal/supplier/interfaces"
 	userInterfaces "back/internal/user/interfaces"
@@ -94,26 +97,74 @@ func InitRoutes(authWang *auth.AuthWang, services *Services, db *gorm.DB) *gin.E
 		processHandler := processInterfaces.NewProcessHandler(services.Process)
 		endpoint.RegisterRoutes(protected, processHandler.GetRoutes())
 
+		// Currency
+		currencyHandler := currencyInterfaces.NewCurrencyHandler(services.Currency)
+		endpoint.RegisterRoutes(protected, currencyHandler.GetRoutes())
+
+		// Price（通用报价路由，材料 / 工艺路由为别名）
+		priceHandler := pricingInterfaces.NewPriceHandler(services.Price)
+		endpoint.RegisterRoutes(protected, priceHandler.GetRoutes())
+
 		// Material Price
-		materialPriceHandler := pricingInterfaces.NewMaterialPriceHandler(services.MaterialPrice)
+		materialPriceHandler := pricingInterfaces.NewMaterialPriceHandler(services.Price)
 		endpoint.RegisterRoutes(protected, materialPriceHandler.GetRoutes())
 
 		// Process Price
-		processPriceHandler := pricingInterfaces.NewProcessPriceHandler(services.ProcessPrice)
+		processPriceHandler := pricingInterfaces.NewProcessPriceHandler(services.Price)
 		endpoint.RegisterRoutes(protected, processPriceHandler.GetRoutes())
 
+		// Price Cache
+		priceCacheHandler := pricingInterfaces.NewPriceCacheHandler(services.PriceCache)
+		endpoint.RegisterRoutes(protected, priceCacheHandler.GetRoutes())
+
+		// Price Analytics
+		priceAnalyticsHandler := pricingInterfaces.NewPriceAnalyticsHandler(services.PriceAnalytics)
+		endpoint.RegisterRoutes(protected, priceAnalyticsHandler.GetRoutes())
+
+		// RFQ
+		rfqHandler := pricingInterfaces.NewRFQHandler(services.RFQ)
+		endpoint.RegisterRoutes(protected, rfqHandler.GetRoutes())
+
+		// Price Watch
+		priceWatchHandler := pricingInterfaces.NewPriceWatchHandler(services.PriceWatch)
+		endpoint.RegisterRoutes(protected, priceWatchHandler.GetRoutes())
+
 		// Product
 		productHandler := productInterfaces.NewProductHandler(services.Product, services.ProductCostCalculator, services.ProductPrice)
 		endpoint.RegisterRoutes(protected, productHandler.GetRoutes())
 
+		// Product BOM
+		bomHandler := productInterfaces.NewBOMHandler(services.ProductBOM)
+		endpoint.RegisterRoutes(protected, bomHandler.GetRoutes())
+
+		// Product Cost Snapshot
+		costSnapshotHandler := productInterfaces.NewCostSnapshotHandler(services.ProductCostSnapshot)
+		endpoint.RegisterRoutes(protected, costSnapshotHandler.GetRoutes())
+
 		// Plan
 		planHandler := planInterfaces.NewPlanHandler(services.Plan)
 		endpoint.RegisterRoutes(protected, planHandler.GetRoutes())
 
+		// Schedule
+		scheduleHandler := planInterfaces.NewScheduleHandler(services.Schedule)
+		endpoint.RegisterRoutes(protected, scheduleHandler.GetRoutes())
+
+		// MRP
+		mrpHandler := planInterfaces.NewMRPHandler(services.MRP)
+		endpoint.RegisterRoutes(protected, mrpHandler.GetRoutes())
+
 		// Order
 		orderHandler := orderInterfaces.NewOrderHandler(services.Order)
 		endpoint.RegisterRoutes(protected, orderHandler.GetRoutes())
 
+		// Sales Price
+		salesPriceHandler := salesInterfaces.NewSalesPriceHandler(services.SalesPrice)
+		endpoint.RegisterRoutes(protected, salesPriceHandler.GetRoutes())
+
+		// Quotation
+		quotationHandler := salesInterfaces.NewQuotationHandler(services.Quotation)
+		endpoint.RegisterRoutes(protected, quotationHandler.GetRoutes())
+
 		// Search
 		searchHandler := searchInterfaces.NewSearchHandler(services.Search)
 		endpoint.RegisterRoutes(protected, searchHandler.GetRoutes())
@@ -127,8 +178,28 @@ func InitRoutes(authWang *auth.AuthWang, services *Services, db *gorm.DB) *gin.E
 		endpoint.RegisterRoutes(protected, permissionHandler.GetRoutes())
 
 		// Inventory
-		inventoryHandler := inventoryInterfaces.NewInventoryHandler(services.Inventory)
+		inventoryHandler := inventoryInterfaces.NewInventoryHandler(services.Inventory, services.InventoryCosting)
 		endpoint.RegisterRoutes(protected, inventoryHandler.GetRoutes())
+
+		// Stocktake
+		stocktakeHandler := inventoryInterfaces.NewStocktakeHandler(services.Stocktake)
+		endpoint.RegisterRoutes(protected, stocktakeHandler.GetRoutes())
+
+		// Genealogy
+		genealogyHandler := inventoryInterfaces.NewGenealogyHandler(services.Genealogy)
+		endpoint.RegisterRoutes(protected, genealogyHandler.GetRoutes())
+
+		// Replenishment
+		replenishmentHandler := inventoryInterfaces.NewReplenishmentHandler(services.Replenishment)
+		endpoint.RegisterRoutes(protected, replenishmentHandler.GetRoutes())
+
+		// Inventory Aging
+		agingHandler := inventoryInterfaces.NewAgingHandler(services.InventoryAging)
+		endpoint.RegisterRoutes(protected, agingHandler.GetRoutes())
+
+		// Notification
+		notificationHandler := notificationInterfaces.NewNotificationHandler(services.Notification)
+		endpoint.RegisterRoutes(protected, notificationHandler.GetRoutes())
 	}
 
 	return router
@@ -10,6 +10,7 @@ import (
 	"gopkg.in/yaml.v3"
 
 	clientDomain "back/internal/client/domain"
+	inventoryDomain "back/internal/inventory/domain"
 	materialDomain "back/internal/material/domain"
 	orderDomain "back/internal/order/domain"
 	planDomain "back/internal/plan/domain"
@@ -55,6 +56,8 @@ func registerAllDomains(registry *infra.DomainAwareRegistry) error {
 		{"process", &processDomain.Process{}},
 		{"order", &orderDomain.Order{}},
 		{"plan", &planDomain.Plan{}},
+		{"inventory", &inventoryDomain.Inventory{}},
+		{inventoryDomain.AgingIndexName, &inventoryDomain.AgingDocument{}},
 	}
 
 	for _, d := range domains {
@@ -2,12 +2,16 @@
 package config
 
 import (
+	"log"
+	"time"
+
 	"github.com/elastic/go-elasticsearch/v8"
 	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
 
 	"back/pkg/auth"
 	"back/pkg/es"
+	"back/pkg/webhook"
 
 	// Auth
 	authApp "back/internal/auth/application"
@@ -32,12 +36,18 @@ import (
 	processApp "back/internal/process/application"
 	processInfra "back/internal/process/infra"
 
+	// Currency
+	currencyApp "back/internal/currency/application"
+	currencyInfra "back/internal/currency/infra"
+
 	// Pricing
 	pricingApp "back/internal/pricing/application"
+	pricingDomain "back/internal/pricing/domain"
 	pricingInfra "back/internal/pricing/infra"
 
 	// Product
 	productApp "back/internal/product/application"
+	productDomain "back/internal/product/domain"
 	productInfra "back/internal/product/infra"
 
 	// Plan
@@ -48,6 +58,10 @@ import (
 	orderApp "back/internal/order/application"
 	orderInfra "back/internal/order/infra"
 
+	// Sales
+	salesApp "back/internal/sales/application"
+	salesInfra "back/internal/sales/infra"
+
 	// Search
 	searchApp "back/internal/search/application"
 	searchInfra "back/internal/search/infra"
@@ -63,6 +77,10 @@ import (
 	inventoryApp "back/internal/inventory/application"
 	inventoryInfra "back/internal/inventory/infra"
 
+	// Notification
+	notificationApp "back/internal/notification/application"
+	notificationInfra "back/internal/notification/infra"
+
 	// Casbin
 	casbinPkg "back/pkg/casbin"
 )
@@ -80,18 +98,35 @@ type Services struct {
 	Material   *materialApp.MaterialService
 	Process    *processApp.ProcessService
 
+	// Currency
+	Currency *currencyApp.CurrencyService
+
 	// Pricing
-	MaterialPrice *pricingApp.MaterialPriceService
-	ProcessPrice  *pricingApp.ProcessPriceService
+	Price          *pricingApp.PriceService
+	MaterialPrice  *pricingApp.TargetPriceService
+	ProcessPrice   *pricingApp.TargetPriceService
+	PriceCache     *pricingApp.PriceCacheService
+	RFQ            *pricingApp.RFQService
+	PriceAnalytics *pricingApp.PriceAnalyticsService
+	PriceWatch     *pricingApp.PriceWatchService
+	PriceTargets   *pricingDomain.TargetRegistry
 
 	// Product
 	Product               *productApp.ProductService
 	ProductCostCalculator *productApp.CostCalculator
 	ProductPrice          *productApp.ProductPriceService
+	ProductBOM            *productApp.BOMService
+	ProductCostSnapshot   *productApp.CostSnapshotService
 
 	// Plan & Order
-	Plan  *planApp.PlanService
-	Order *orderApp.OrderService
+	Plan     *planApp.PlanService
+	MRP      *planApp.MRPService
+	Schedule *planApp.ScheduleService
+	Order    *orderApp.OrderService
+
+	// Sales
+	SalesPrice *salesApp.SalesPriceService
+	Quotation  *salesApp.QuotationService
 
 	// Search
 	Search *searchApp.SearchService
@@ -103,10 +138,18 @@ type Services struct {
 	Permission *permissionApp.PermissionService
 
 	// Inventory
-	Inventory *inventoryApp.InventoryService
+	Inventory        *inventoryApp.InventoryService
+	InventoryCosting *inventoryApp.CostingService
+	Stocktake        *inventoryApp.StocktakeService
+	Genealogy        *inventoryApp.GenealogyService
+	Replenishment    *inventoryApp.ReplenishmentService
+	InventoryAging   *inventoryApp.AgingService
+
+	// Notification
+	Notification *notificationApp.NotificationService
 }
 
-func InitServices(db *gorm.DB, rdb *redis.Client, esClient *elasticsearch.Client, jwtWang *auth.JWTWang, whitelistManager *auth.WhitelistManager, casbinManager *casbinPkg.Manager, esSync *es.ESSync, searchRegistry *searchInfra.DomainAwareRegistry) *Services {
+func InitServices(cfg *Config, db *gorm.DB, rdb *redis.Client, esClient *elasticsearch.Client, jwtWang *auth.JWTWang, whitelistManager *auth.WhitelistManager, casbinManager *casbinPkg.Manager, esSync *es.ESSync, searchRegistry *searchInfra.DomainAwareRegistry) *Services {
 	// ========== Supplier ==========
 	supplierRepo := supplierInfra.NewSupplierRepo(db)
 	supplierService := supplierApp.NewSupplierService(supplierRepo, esSync)
@@ -138,27 +181,57 @@ func InitServices(db *gorm.DB, rdb *redis.Client, esClient *elasticsearch.Client
 	processRepo := processInfra.NewProcessRepo(db)
 	processService := processApp.NewProcessService(processRepo, esSync)
 
+	// ========== Currency ==========
+	exchangeRateRepo := currencyInfra.NewExchangeRateRepo(db)
+	currencyService := currencyApp.NewCurrencyService(exchangeRateRepo, cfg.BaseCurrency)
+
 	// ========== Pricing ==========
 	supplierPriceRepo := pricingInfra.NewSupplierPriceRepo(db)
 	priceCache := pricingInfra.NewPriceCacheImpl(rdb)
+	priceCacheTTL, err := time.ParseDuration(cfg.PriceCacheTTL)
+	if err != nil {
+		log.Printf("Warning: invalid PRICE_CACHE_TTL %q, using %s", cfg.PriceCacheTTL, pricingApp.DefaultPriceCacheTTL)
+	}
+	priceCacheService := pricingApp.NewPriceCacheService(supplierPriceRepo, priceCache, supplierService, currencyService, priceCacheTTL)
+
+	// 报价目标类型：由目标所属领域提供存在性检查与名称解析
+	priceTargets := pricingDomain.NewTargetRegistry()
+	for _, target := range []pricingDomain.TargetType{
+		{Name: pricingDomain.TargetTypeMaterial, Label: "材料", Exists: materialService.Exists, DisplayName: materialService.DisplayName,
+			Resolve: materialService.ResolveCodes, KeyLabel: "材料编号"},
+		{Name: pricingDomain.TargetTypeProcess, Label: "工序", Exists: processService.Exists, DisplayName: processService.DisplayName,
+			Resolve: processService.ResolveNames, KeyLabel: "工序名称"},
+	} {
+		if err := priceTargets.Register(target); err != nil {
+			log.Fatalf("Failed to register price target %q: %v", target.Name, err)
+		}
+	}
 
-	materialPriceService := pricingApp.NewMaterialPriceService(
+	priceService := pricingApp.NewPriceService(
 		supplierPriceRepo,
-		priceCache,
-		materialService,
+		priceCacheService,
+		priceTargets,
 		supplierService,
+		currencyService,
 	)
+	materialPriceService := priceService.For(pricingDomain.TargetTypeMaterial)
+	processPriceService := priceService.For(pricingDomain.TargetTypeProcess)
 
-	processPriceService := pricingApp.NewProcessPriceService(
+	priceAnalyticsService := pricingApp.NewPriceAnalyticsService(pricingInfra.NewPriceAnalyticsRepo(db), priceService, currencyService)
+
+	rfqRepo := pricingInfra.NewRFQRepo(db)
+	rfqService := pricingApp.NewRFQService(
+		rfqRepo,
 		supplierPriceRepo,
-		priceCache,
-		processService,
+		priceService,
 		supplierService,
+		currencyService,
 	)
 
 	// ========== Product ==========
 	productRepo := productInfra.NewProductRepo(db)
-	productService := productApp.NewProductService(productRepo, esSync)
+	productService := productApp.NewProductService(productRepo, esSync, cfg.ProductApprovalChain)
+	productBOMService := productApp.NewBOMService(productRepo, cfg.ProductApprovalChain)
 
 	productCostCalculator := productApp.NewCostCalculator(
 		productRepo,
@@ -166,21 +239,39 @@ func InitServices(db *gorm.DB, rdb *redis.Client, esClient *elasticsearch.Client
 		processService,
 		materialPriceService,
 		processPriceService,
+		currencyService,
+		productDomain.CostPolicy{
+			ReworkRate: cfg.ProductReworkRate,
+			Overheads:  productDomain.ParseOverheadRates(cfg.ProductOverheadRates),
+		},
 	)
 
-	productPriceService := productApp.NewProductPriceService(
-		productRepo,
-		materialPriceService,
-		processPriceService,
-	)
-
-	// ========== Plan ==========
-	planRepo := planInfra.NewPlanRepo(db)
-	planService := planApp.NewPlanService(planRepo, esSync)
+	productPriceService := productApp.NewProductPriceService(productRepo, productCostCalculator)
 
 	// ========== Order ==========
 	orderRepo := orderInfra.NewOrderRepo(db)
 	orderService := orderApp.NewOrderService(orderRepo, esSync)
+	orderService.SetCurrencyConverter(currencyService)
+	orderService.SetBOMResolver(productBOMService)
+	productCostCalculator.SetHistoryProvider(orderService)
+
+	// ========== Sales ==========
+	salesPriceService := salesApp.NewSalesPriceService(
+		salesInfra.NewSalesPriceRepo(db),
+		clientService,
+		productService,
+		productPriceService,
+		currencyService,
+		cfg.SalesMarginFloor,
+	)
+	orderService.SetSalesPricing(salesPriceService)
+	quotationService := salesApp.NewQuotationService(salesInfra.NewQuotationRepo(db), salesPriceService, clientService, productService, currencyService)
+
+	// ========== Plan ==========
+	planRepo := planInfra.NewPlanRepo(db)
+	planService := planApp.NewPlanService(planRepo, esSync, orderService, productService, cfg.PlanBatchCapacity)
+	orderService.SetPlanGenerator(planService)
+	scheduleService := planApp.NewScheduleService(planRepo, esSync)
 
 	// ========== Search ==========
 	searchRepo := searchInfra.NewESSearchRepository(esClient)
@@ -188,14 +279,41 @@ func InitServices(db *gorm.DB, rdb *redis.Client, esClient *elasticsearch.Client
 
 	// ========== Analytics ==========
 	returnAnalysisRepo := analyticsInfra.NewReturnAnalysisRepository(db)
-	returnAnalysisService := analyticsApp.NewReturnAnalysisService(returnAnalysisRepo)
+	returnAnalysisService := analyticsApp.NewReturnAnalysisService(returnAnalysisRepo, currencyService)
 
 	// ========== Permission ==========
 	permissionService := permissionApp.NewPermissionService(casbinManager)
 
+	// ========== Notification ==========
+	notificationRepo := notificationInfra.NewNotificationRepo(db)
+	notificationService := notificationApp.NewNotificationService(notificationRepo)
+
+	// ========== Product Cost Snapshot ==========
+	productCostSnapshotService := productApp.NewCostSnapshotService(productRepo, productPriceService, notificationService, cfg.ProductCostAlertThreshold)
+	priceService.SetQuoteListener(productCostSnapshotService)
+
+	// ========== Price Watch ==========
+	priceWatchService := pricingApp.NewPriceWatchService(
+		pricingInfra.NewPriceWatchRepo(db),
+		supplierPriceRepo,
+		rfqRepo,
+		priceService,
+		notificationService,
+		webhook.NewSender(10*time.Second, cfg.PriceWebhookSecret),
+	)
+	priceService.SetQuoteWatcher(priceWatchService)
+
 	// ========== Inventory ==========
 	inventoryRepo := inventoryInfra.NewInventoryRepo(db)
-	inventoryService := inventoryApp.NewInventoryService(inventoryRepo)
+	inventoryCostingService := inventoryApp.NewCostingService(inventoryRepo, orderService, esSync)
+	inventoryService := inventoryApp.NewInventoryService(inventoryRepo, inventoryCostingService)
+	stocktakeService := inventoryApp.NewStocktakeService(inventoryRepo, inventoryCostingService)
+	genealogyService := inventoryApp.NewGenealogyService(inventoryRepo, inventoryCostingService, orderService, supplierService)
+	replenishmentService := inventoryApp.NewReplenishmentService(inventoryRepo, orderService, productService, priceCacheService, notificationService)
+	inventoryAgingService := inventoryApp.NewAgingService(inventoryRepo, esSync)
+
+	// ========== MRP ==========
+	mrpService := planApp.NewMRPService(planRepo, orderService, productService, replenishmentService)
 
 	return &Services{
 		Auth:                  authService,
@@ -206,16 +324,35 @@ func InitServices(db *gorm.DB, rdb *redis.Client, esClient *elasticsearch.Client
 		Role:                  roleService,
 		Material:              materialService,
 		Process:               processService,
+		Currency:              currencyService,
+		Price:                 priceService,
 		MaterialPrice:         materialPriceService,
 		ProcessPrice:          processPriceService,
+		PriceCache:            priceCacheService,
+		RFQ:                   rfqService,
+		PriceAnalytics:        priceAnalyticsService,
+		PriceWatch:            priceWatchService,
+		PriceTargets:          priceTargets,
 		Product:               productService,
 		ProductCostCalculator: productCostCalculator,
 		ProductPrice:          productPriceService,
+		ProductBOM:            productBOMService,
+		ProductCostSnapshot:   productCostSnapshotService,
 		Plan:                  planService,
+		MRP:                   mrpService,
+		Schedule:              scheduleService,
 		Order:                 orderService,
+		SalesPrice:            salesPriceService,
+		Quotation:             quotationService,
 		Search:                searchService,
 		ReturnAnalysis:        returnAnalysisService,
 		Permission:            permissionService,
 		Inventory:             inventoryService,
+		InventoryCosting:      inventoryCostingService,
+		Stocktake:             stocktakeService,
+		Genealogy:             genealogyService,
+		Replenishment:         replenishmentService,
+		InventoryAging:        inventoryAgingService,
+		Notification:          notificationService,
 	}
 }
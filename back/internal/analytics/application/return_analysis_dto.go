@@ -73,6 +73,8 @@ func (d *DateRange) ParseDateRange() (time.Time, time.Time, error) {
 type ReturnAnalysisRequest struct {
 	CustomerNo string    `json:"customerNo" form:"customerNo" example:"CS0678"` // 客户编号，空表示所有客户
 	DateRange  DateRange `json:"dateRange"`                                      // 日期范围，start和end都不传表示查询全部时间
+	Currency   string    `json:"currency" example:"CNY"`                         // 金额报告币种，默认本位币
+	RateDate   string    `json:"rateDate" example:"2024-12-31"`                  // 汇率日期 YYYY-MM-DD，默认结束日期（未指定时为今天）
 }
 
 // MeterDimensionResponse 米数维度响应
@@ -93,8 +95,13 @@ type WeightDimensionResponse struct {
 
 // AmountDimensionResponse 金额维度响应
 type AmountDimensionResponse struct {
-	TotalAmountRMB     float64 `json:"totalAmountRMB" example:"120000.0"`     // 退款总金额（RMB，USD已按汇率8换算）
-	ReturnedOrderCount int64   `json:"returnedOrderCount" example:"15"`       // 有退货的订单数
+	TotalAmountRMB        float64            `json:"totalAmountRMB" example:"120000.0"`                // 退款总金额（按汇率表换算为人民币）
+	TotalAmount           float64            `json:"totalAmount" example:"120000.0"`                   // 退款总金额（报告币种）
+	Currency              string             `json:"currency" example:"CNY"`                           // 报告币种
+	RateDate              string             `json:"rateDate" example:"2024-12-31"`                    // 换算使用的汇率日期
+	Amounts               map[string]float64 `json:"amounts"`                                          // 按原币种汇总的退款金额
+	UnconvertedCurrencies []string           `json:"unconvertedCurrencies,omitempty" example:"EUR"` // 无法识别或缺少汇率、未计入总额的币种
+	ReturnedOrderCount    int64              `json:"returnedOrderCount" example:"15"`                  // 有退货的订单数
 }
 
 // ReturnAnalysisResponse 退货分析响应
@@ -3,20 +3,32 @@ package application
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"time"
 
 	"back/internal/analytics/domain"
+	currencyDomain "back/internal/currency/domain"
 )
 
+// CurrencyConverter 币种换算（由 currency 模块实现）
+type CurrencyConverter interface {
+	Normalize(code string) (string, error)
+	Convert(ctx context.Context, amount float64, from, to string, asOf time.Time) (float64, error)
+}
+
 // ReturnAnalysisService 退货分析服务
 type ReturnAnalysisService struct {
-	repo domain.ReturnAnalysisRepository
+	repo     domain.ReturnAnalysisRepository
+	currency CurrencyConverter
 }
 
 // NewReturnAnalysisService 创建退货分析服务
-func NewReturnAnalysisService(repo domain.ReturnAnalysisRepository) *ReturnAnalysisService {
+func NewReturnAnalysisService(repo domain.ReturnAnalysisRepository, currency CurrencyConverter) *ReturnAnalysisService {
 	return &ReturnAnalysisService{
-		repo: repo,
+		repo:     repo,
+		currency: currency,
 	}
 }
 
@@ -51,6 +63,21 @@ func (s *ReturnAnalysisService) GetReturnAnalysis(ctx context.Context, req *Retu
 		return nil, err
 	}
 
+	// 报告币种与汇率日期
+	reportCurrency, err := s.currency.Normalize(req.Currency)
+	if err != nil {
+		return nil, err
+	}
+	rateDate := time.Now()
+	if !endDate.IsZero() {
+		rateDate = endDate
+	}
+	if req.RateDate != "" {
+		if rateDate, err = time.ParseInLocation(currencyDomain.DateLayout, req.RateDate, time.Local); err != nil {
+			return nil, fmt.Errorf("汇率日期格式错误: %w", err)
+		}
+	}
+
 	// 构建查询
 	query := domain.ReturnAnalysisQuery{
 		CustomerNo: req.CustomerNo,
@@ -86,9 +113,9 @@ func (s *ReturnAnalysisService) GetReturnAnalysis(ctx context.Context, req *Retu
 	weightStats := s.calculateWeightDimension(orderSummary, returnSummary)
 
 	// 金额维度
-	amountStats := AmountDimensionResponse{
-		TotalAmountRMB:     returnSummary.ReturnAmountRMB,
-		ReturnedOrderCount: returnSummary.ReturnedOrderCount,
+	amountStats, err := s.calculateAmountDimension(ctx, returnSummary, reportCurrency, rateDate)
+	if err != nil {
+		return nil, fmt.Errorf("换算退款金额失败: %w", err)
 	}
 
 	// 构建响应
@@ -141,4 +168,50 @@ func (s *ReturnAnalysisService) calculateWeightDimension(order *domain.OrderSumm
 	}
 
 	return resp
-}
\ No newline at end of file
+}
+
+// calculateAmountDimension 计算金额维度：各币种按汇率日期换算为报告币种与人民币
+//
+// 无法识别或缺少汇率的币种不计入总额，在 UnconvertedCurrencies 中列出。
+func (s *ReturnAnalysisService) calculateAmountDimension(ctx context.Context, ret *domain.ReturnSummary, reportCurrency string, rateDate time.Time) (AmountDimensionResponse, error) {
+	resp := AmountDimensionResponse{
+		Currency:           reportCurrency,
+		RateDate:           rateDate.Format("2006-01-02"),
+		Amounts:            make(map[string]float64, len(ret.ReturnAmounts)),
+		ReturnedOrderCount: ret.ReturnedOrderCount,
+	}
+
+	// 历史数据中同一币种有多种写法，先规范化再汇总
+	unknown := make(map[string]bool)
+	for raw, amount := range ret.ReturnAmounts {
+		code, err := s.currency.Normalize(raw)
+		if err != nil {
+			code = raw
+			unknown[raw] = true
+		}
+		resp.Amounts[code] += amount
+	}
+
+	for code, amount := range resp.Amounts {
+		if unknown[code] {
+			resp.UnconvertedCurrencies = append(resp.UnconvertedCurrencies, code)
+			continue
+		}
+		total, err := s.currency.Convert(ctx, amount, code, reportCurrency, rateDate)
+		if err == nil {
+			var rmb float64
+			if rmb, err = s.currency.Convert(ctx, amount, code, currencyDomain.CNY, rateDate); err == nil {
+				resp.TotalAmount += total
+				resp.TotalAmountRMB += rmb
+				continue
+			}
+		}
+		if !errors.Is(err, currencyDomain.ErrRateNotFound) {
+			return resp, err
+		}
+		resp.UnconvertedCurrencies = append(resp.UnconvertedCurrencies, code)
+	}
+
+	sort.Strings(resp.UnconvertedCurrencies)
+	return resp, nil
+}
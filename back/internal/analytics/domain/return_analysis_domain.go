@@ -64,7 +64,7 @@ type OrderSummary struct {
 type ReturnSummary struct {
 	ReturnedMeters      float64
 	ReturnedWeight      float64
-	ReturnAmountRMB     float64 // 统一换算为RMB
+	ReturnAmounts       map[string]float64 // 按原币种汇总的退款金额
 	ReturnedOrderCount  int64
 }
 
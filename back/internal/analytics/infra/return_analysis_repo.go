@@ -12,15 +12,13 @@ import (
 
 // ReturnAnalysisRepositoryImpl 退货分析仓储实现
 type ReturnAnalysisRepositoryImpl struct {
-	db           *gorm.DB
-	exchangeRate float64 // USD 到 RMB 的汇率
+	db *gorm.DB
 }
 
 // NewReturnAnalysisRepository 创建退货分析仓储
 func NewReturnAnalysisRepository(db *gorm.DB) domain.ReturnAnalysisRepository {
 	return &ReturnAnalysisRepositoryImpl{
-		db:           db,
-		exchangeRate: 8.0, // 默认汇率
+		db: db,
 	}
 }
 
@@ -199,21 +197,10 @@ func (r *ReturnAnalysisRepositoryImpl) GetReturnSummary(ctx context.Context, que
 		return nil, fmt.Errorf("查询退款金额失败: %w", err)
 	}
 
-	// 计算统一换算为 RMB 的总金额
-	totalAmountRMB := 0.0
+	// 按币种汇总（换算由应用层按汇率表处理）
+	amounts := make(map[string]float64, len(amountResults))
 	for _, result := range amountResults {
-		amount := result.TotalAmount
-		currency := strings.ToUpper(strings.TrimSpace(result.Currency))
-
-		// 根据币种换算为 RMB
-		switch currency {
-		case "RMB", "CNY", "人民币":
-			totalAmountRMB += amount
-		case "USD", "美元", "美金":
-			totalAmountRMB += amount * r.exchangeRate
-		default:
-			// 其他币种暂不处理，可根据需要扩展
-		}
+		amounts[strings.TrimSpace(result.Currency)] += result.TotalAmount
 	}
 
 	// 第三步：查询有退货的订单数
@@ -250,7 +237,7 @@ func (r *ReturnAnalysisRepositoryImpl) GetReturnSummary(ctx context.Context, que
 	return &domain.ReturnSummary{
 		ReturnedMeters:     returnedMeters,
 		ReturnedWeight:     returnedWeight,
-		ReturnAmountRMB:    totalAmountRMB,
+		ReturnAmounts:      amounts,
 		ReturnedOrderCount: orderCountResult.Count,
 	}, nil
 }
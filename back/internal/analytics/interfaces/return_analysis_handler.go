@@ -2,10 +2,12 @@
 package interfaces
 
 import (
+	"errors"
 	"net/http"
 
 	"back/pkg/endpoint"
 	"back/internal/analytics/application"
+	currencyDomain "back/internal/currency/domain"
 	"github.com/gin-gonic/gin"
 )
 
@@ -69,6 +71,10 @@ func (h *ReturnAnalysisHandler) GetReturnAnalysis(c *gin.Context) {
 	}
 
 	resp, err := h.service.GetReturnAnalysis(c.Request.Context(), &req)
+	if errors.Is(err, currencyDomain.ErrInvalidCurrency) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的币种代码"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
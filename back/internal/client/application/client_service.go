@@ -49,6 +49,7 @@ func (s *ClientService) Create(ctx context.Context, req *CreateClientRequest) (*
 		FaxNum:       req.FaxNum,
 		Email:        req.Email,
 		PyCustomName: req.PyCustomName,
+		PriceGroup:   req.PriceGroup,
 		CheckRequest: req.CheckRequest,
 		CustomStatus: req.CustomStatus,
 		DocMan:       req.DocMan,
@@ -140,6 +141,9 @@ func (s *ClientService) Update(ctx context.Context, id uint, req *UpdateClientRe
 	if req.PyCustomName != "" {
 		client.PyCustomName = req.PyCustomName
 	}
+	if req.PriceGroup != "" {
+		client.PriceGroup = req.PriceGroup
+	}
 	if req.CheckRequest != "" {
 		client.CheckRequest = req.CheckRequest
 	}
@@ -217,6 +221,7 @@ func toClientResponse(client *domain.Client) *ClientResponse {
 		FaxNum:       client.FaxNum,
 		Email:        client.Email,
 		PyCustomName: client.PyCustomName,
+		PriceGroup:   client.PriceGroup,
 		CheckRequest: client.CheckRequest,
 		CustomStatus: client.CustomStatus,
 		DocMan:       client.DocMan,
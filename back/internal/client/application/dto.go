@@ -20,6 +20,7 @@ type CreateClientRequest struct {
 	FaxNum       string     `json:"faxNum" binding:"omitempty,max=50"`
 	Email        string     `json:"email" binding:"omitempty,email"`
 	PyCustomName string     `json:"pyCustomName" binding:"omitempty,max=200"`
+	PriceGroup   string     `json:"priceGroup" binding:"omitempty,max=50"` // 销售价目组
 	CheckRequest string     `json:"checkRequest" binding:"omitempty"`
 	CustomStatus string     `json:"customStatus" binding:"omitempty,max=50"`
 	DocMan       string     `json:"docMan" binding:"omitempty,max=50"`
@@ -43,6 +44,7 @@ type UpdateClientRequest struct {
 	FaxNum       string     `json:"faxNum" binding:"omitempty,max=50"`
 	Email        string     `json:"email" binding:"omitempty,email"`
 	PyCustomName string     `json:"pyCustomName" binding:"omitempty,max=200"`
+	PriceGroup   string     `json:"priceGroup" binding:"omitempty,max=50"` // 销售价目组
 	CheckRequest string     `json:"checkRequest" binding:"omitempty"`
 	CustomStatus string     `json:"customStatus" binding:"omitempty,max=50"`
 	DocMan       string     `json:"docMan" binding:"omitempty,max=50"`
@@ -67,6 +69,7 @@ type ClientResponse struct {
 	FaxNum       string     `json:"faxNum"`
 	Email        string     `json:"email"`
 	PyCustomName string     `json:"pyCustomName"`
+	PriceGroup   string     `json:"priceGroup"`
 	CheckRequest string     `json:"checkRequest"`
 	CustomStatus string     `json:"customStatus"`
 	DocMan       string     `json:"docMan"`
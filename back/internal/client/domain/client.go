@@ -27,6 +27,7 @@ type Client struct {
 	FaxNum         string         `gorm:"size:50" json:"faxNum"`                      // 传真
 	Email          string         `gorm:"size:100" json:"email"`                      // 邮箱
 	PyCustomName   string         `gorm:"size:200" json:"pyCustomName"`               // 所属客户
+	PriceGroup     string         `gorm:"size:50;index" json:"priceGroup"`            // 销售价目组
 	CheckRequest   string         `gorm:"type:text" json:"checkRequest"`              // 检验要求
 	CustomStatus   string         `gorm:"size:50" json:"customStatus"`                // 状态
 	DocMan         string         `gorm:"size:50" json:"docMan"`                      // 输入人
@@ -83,6 +84,10 @@ func (c *Client) Validate() error {
 		return ErrAddressTooLong
 	}
 
+	if len(c.PriceGroup) > 50 {
+		return ErrPriceGroupTooLong
+	}
+
 	return nil
 }
 
@@ -106,6 +111,7 @@ func (c *Client) ToDocument() map[string]interface{} {
 		"faxNum":       c.FaxNum,
 		"email":        c.Email,
 		"pyCustomName": c.PyCustomName,
+		"priceGroup":   c.PriceGroup,
 		"checkRequest": c.CheckRequest,
 		"customStatus": c.CustomStatus,
 		"docMan":       c.DocMan,
@@ -13,4 +13,5 @@ var (
 	ErrEmailTooLong      = errors.New("email cannot exceed 100 characters")
 	ErrEmailInvalid      = errors.New("invalid email format")
 	ErrAddressTooLong    = errors.New("address cannot exceed 200 characters")
+	ErrPriceGroupTooLong = errors.New("price group cannot exceed 50 characters")
 )
\ No newline at end of file
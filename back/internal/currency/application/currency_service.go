@@ -0,0 +1,260 @@
+package application
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"back/internal/currency/domain"
+	"back/internal/currency/infra"
+)
+
+// CurrencyService 汇率维护与币种换算
+//
+// 换算优先使用直接汇率，其次反向汇率（1 / rate），都没有时经本位币交叉换算。
+type CurrencyService struct {
+	repo *infra.ExchangeRateRepo
+	base string
+}
+
+// NewCurrencyService 创建币种服务（base 为本位币，为空或无效时使用 CNY）
+func NewCurrencyService(repo *infra.ExchangeRateRepo, base string) *CurrencyService {
+	code, err := domain.NormalizeCurrency(base)
+	if err != nil {
+		code = domain.CNY
+	}
+	return &CurrencyService{repo: repo, base: code}
+}
+
+// Base 本位币
+func (s *CurrencyService) Base() string {
+	return s.base
+}
+
+// Normalize 规范化币种代码（为空时返回本位币）
+func (s *CurrencyService) Normalize(code string) (string, error) {
+	if strings.TrimSpace(code) == "" {
+		return s.base, nil
+	}
+	return domain.NormalizeCurrency(code)
+}
+
+// Save 录入汇率（同一币种对同一日期已有汇率时覆盖）
+func (s *CurrencyService) Save(ctx context.Context, req *ExchangeRateRequest, actor string) (*domain.ExchangeRate, error) {
+	rate := &domain.ExchangeRate{
+		FromCurrency:  req.FromCurrency,
+		ToCurrency:    req.ToCurrency,
+		Rate:          req.Rate,
+		EffectiveDate: req.EffectiveDate,
+		Source:        domain.RateSourceManual,
+		CreatedBy:     actor,
+	}
+	if err := rate.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Upsert(ctx, rate); err != nil {
+		return nil, err
+	}
+	return rate, nil
+}
+
+// Import 从 CSV 导入汇率
+//
+// 列：from_currency, to_currency, rate, effective_date(YYYY-MM-DD)，首行为表头时跳过。
+// 任一行有误时整批不导入并返回各行错误。
+func (s *CurrencyService) Import(ctx context.Context, r io.Reader, actor string) (*ImportRatesResponse, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ImportRatesResponse{}
+	rates := make([]*domain.ExchangeRate, 0, len(records))
+	for i, record := range records {
+		if i == 0 && isHeader(record) {
+			continue
+		}
+		rate, err := parseRateRecord(record)
+		if err == nil {
+			err = rate.Validate()
+		}
+		if err != nil {
+			resp.Errors = append(resp.Errors, ImportRowError{Line: i + 1, Error: err.Error()})
+			continue
+		}
+		rate.Source = domain.RateSourceImport
+		rate.CreatedBy = actor
+		rates = append(rates, rate)
+	}
+	if len(resp.Errors) > 0 {
+		return resp, nil
+	}
+
+	err = s.repo.Transaction(ctx, func(txRepo *infra.ExchangeRateRepo) error {
+		for _, rate := range rates {
+			if err := txRepo.Upsert(ctx, rate); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Imported = len(rates)
+	return resp, nil
+}
+
+// isHeader 首行是否为表头（汇率列不是数字）
+func isHeader(record []string) bool {
+	if len(record) < 3 {
+		return false
+	}
+	_, err := strconv.ParseFloat(strings.TrimSpace(record[2]), 64)
+	return err != nil
+}
+
+// parseRateRecord 解析一行 CSV
+func parseRateRecord(record []string) (*domain.ExchangeRate, error) {
+	if len(record) < 4 {
+		return nil, fmt.Errorf("expected 4 columns, got %d", len(record))
+	}
+	value, err := strconv.ParseFloat(strings.TrimSpace(record[2]), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rate %q", record[2])
+	}
+	date, err := time.ParseInLocation(domain.DateLayout, strings.TrimSpace(record[3]), time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q", record[3])
+	}
+	return &domain.ExchangeRate{
+		FromCurrency:  record[0],
+		ToCurrency:    record[1],
+		Rate:          value,
+		EffectiveDate: date,
+	}, nil
+}
+
+// List 分页查询汇率
+func (s *CurrencyService) List(ctx context.Context, from, to string, limit, offset int) (*ExchangeRateListResponse, error) {
+	var err error
+	if from != "" {
+		if from, err = domain.NormalizeCurrency(from); err != nil {
+			return nil, err
+		}
+	}
+	if to != "" {
+		if to, err = domain.NormalizeCurrency(to); err != nil {
+			return nil, err
+		}
+	}
+
+	items, total, err := s.repo.FindList(ctx, from, to, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return &ExchangeRateListResponse{Total: total, Items: items}, nil
+}
+
+// Delete 删除汇率
+func (s *CurrencyService) Delete(ctx context.Context, id uint) error {
+	if _, err := s.repo.FindByID(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// Rate 查询 asOf 当日 from → to 的汇率
+func (s *CurrencyService) Rate(ctx context.Context, from, to string, asOf time.Time) (float64, error) {
+	from, err := s.Normalize(from)
+	if err != nil {
+		return 0, err
+	}
+	to, err = s.Normalize(to)
+	if err != nil {
+		return 0, err
+	}
+	if from == to {
+		return 1, nil
+	}
+
+	rate, err := s.pairRate(ctx, from, to, asOf)
+	if !errors.Is(err, domain.ErrRateNotFound) || from == s.base || to == s.base {
+		return rate, err
+	}
+
+	// 经本位币交叉换算
+	toBase, err := s.pairRate(ctx, from, s.base, asOf)
+	if err != nil {
+		return 0, err
+	}
+	fromBase, err := s.pairRate(ctx, s.base, to, asOf)
+	if err != nil {
+		return 0, err
+	}
+	return toBase * fromBase, nil
+}
+
+// pairRate 直接汇率或反向汇率
+func (s *CurrencyService) pairRate(ctx context.Context, from, to string, asOf time.Time) (float64, error) {
+	rate, err := s.repo.FindRate(ctx, from, to, asOf)
+	if err == nil {
+		return rate.Rate, nil
+	}
+	if !errors.Is(err, domain.ErrRateNotFound) {
+		return 0, err
+	}
+
+	inverse, err := s.repo.FindRate(ctx, to, from, asOf)
+	if err != nil {
+		if errors.Is(err, domain.ErrRateNotFound) {
+			return 0, fmt.Errorf("%w: %s→%s on %s", domain.ErrRateNotFound, from, to, asOf.Format(domain.DateLayout))
+		}
+		return 0, err
+	}
+	return 1 / inverse.Rate, nil
+}
+
+// Convert 按 asOf 当日汇率换算金额（供 Product / Order / Analytics 模块调用，币种为空时视为本位币）
+func (s *CurrencyService) Convert(ctx context.Context, amount float64, from, to string, asOf time.Time) (float64, error) {
+	if amount == 0 {
+		return 0, nil
+	}
+	rate, err := s.Rate(ctx, from, to, asOf)
+	if err != nil {
+		return 0, err
+	}
+	return amount * rate, nil
+}
+
+// Conversion 换算金额并返回所用汇率
+func (s *CurrencyService) Conversion(ctx context.Context, amount float64, from, to string, asOf time.Time) (*ConversionResponse, error) {
+	from, err := s.Normalize(from)
+	if err != nil {
+		return nil, err
+	}
+	to, err = s.Normalize(to)
+	if err != nil {
+		return nil, err
+	}
+	rate, err := s.Rate(ctx, from, to, asOf)
+	if err != nil {
+		return nil, err
+	}
+	return &ConversionResponse{
+		Amount:    amount,
+		From:      from,
+		To:        to,
+		Rate:      rate,
+		Date:      asOf.Format(domain.DateLayout),
+		Converted: amount * rate,
+	}, nil
+}
@@ -0,0 +1,43 @@
+package application
+
+import (
+	"time"
+
+	"back/internal/currency/domain"
+)
+
+// ExchangeRateRequest 录入汇率请求
+type ExchangeRateRequest struct {
+	FromCurrency  string    `json:"from_currency" binding:"required"`
+	ToCurrency    string    `json:"to_currency" binding:"required"`
+	Rate          float64   `json:"rate" binding:"required,gt=0"`
+	EffectiveDate time.Time `json:"effective_date" binding:"required"`
+}
+
+// ExchangeRateListResponse 汇率列表响应
+type ExchangeRateListResponse struct {
+	Total int64                  `json:"total"`
+	Items []*domain.ExchangeRate `json:"items"`
+}
+
+// ImportRowError 导入失败的行
+type ImportRowError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// ImportRatesResponse 汇率导入结果（任一行有误时整批不导入）
+type ImportRatesResponse struct {
+	Imported int              `json:"imported"`
+	Errors   []ImportRowError `json:"errors,omitempty"`
+}
+
+// ConversionResponse 换算结果
+type ConversionResponse struct {
+	Amount    float64 `json:"amount"`
+	From      string  `json:"from"`
+	To        string  `json:"to"`
+	Rate      float64 `json:"rate"`
+	Date      string  `json:"date"`
+	Converted float64 `json:"converted"`
+}
@@ -0,0 +1,11 @@
+package domain
+
+import "errors"
+
+var (
+	ErrInvalidCurrency  = errors.New("invalid currency code")
+	ErrInvalidRate      = errors.New("exchange rate must be greater than 0")
+	ErrSameCurrency     = errors.New("from and to currency must differ")
+	ErrRateNotFound     = errors.New("exchange rate not found")
+	ErrRateDateRequired = errors.New("effective date is required")
+)
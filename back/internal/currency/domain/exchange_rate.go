@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// 常用币种
+const (
+	CNY = "CNY"
+	USD = "USD"
+)
+
+// 汇率来源
+const (
+	RateSourceManual = "manual" // 接口录入
+	RateSourceImport = "import" // CSV 导入
+	RateSourceSeed   = "seed"   // 数据库迁移初始化
+)
+
+// LegacyUSDRate 启用汇率管理前系统固定使用的 USD → CNY 汇率（迁移时作为初始汇率写入）
+const LegacyUSDRate = 8.0
+
+// DateLayout 汇率日期格式
+const DateLayout = "2006-01-02"
+
+// currencyAliases 历史数据中的币种写法
+var currencyAliases = map[string]string{
+	"RMB": CNY,
+	"人民币": CNY,
+	"美元":  USD,
+	"美金":  USD,
+	"US$": USD,
+	"$":   USD,
+	"¥":   CNY,
+	"CNH": CNY,
+}
+
+// NormalizeCurrency 规范化币种代码（ISO 4217 三位大写字母，兼容 RMB / 人民币 / 美元等写法）
+func NormalizeCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if alias, ok := currencyAliases[code]; ok {
+		return alias, nil
+	}
+	if len(code) != 3 {
+		return "", ErrInvalidCurrency
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return "", ErrInvalidCurrency
+		}
+	}
+	return code, nil
+}
+
+// ExchangeRate 汇率（1 单位 From 币种 = Rate 单位 To 币种，自生效日期起直到下一条汇率）
+type ExchangeRate struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	FromCurrency  string    `gorm:"size:3;not null;uniqueIndex:idx_rate_pair_date" json:"from_currency"`
+	ToCurrency    string    `gorm:"size:3;not null;uniqueIndex:idx_rate_pair_date" json:"to_currency"`
+	Rate          float64   `gorm:"type:decimal(18,8);not null" json:"rate"`
+	EffectiveDate time.Time `gorm:"type:date;not null;uniqueIndex:idx_rate_pair_date" json:"effective_date"`
+	Source        string    `gorm:"size:20;default:manual" json:"source"`
+	CreatedBy     string    `gorm:"size:50" json:"created_by"`
+	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 表名
+func (ExchangeRate) TableName() string {
+	return "exchange_rates"
+}
+
+// Validate 校验并规范化币种
+func (r *ExchangeRate) Validate() error {
+	from, err := NormalizeCurrency(r.FromCurrency)
+	if err != nil {
+		return err
+	}
+	to, err := NormalizeCurrency(r.ToCurrency)
+	if err != nil {
+		return err
+	}
+	if from == to {
+		return ErrSameCurrency
+	}
+	if r.Rate <= 0 {
+		return ErrInvalidRate
+	}
+	if r.EffectiveDate.IsZero() {
+		return ErrRateDateRequired
+	}
+
+	r.FromCurrency = from
+	r.ToCurrency = to
+	r.EffectiveDate = Day(r.EffectiveDate)
+	return nil
+}
+
+// Day 取日期部分
+func Day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
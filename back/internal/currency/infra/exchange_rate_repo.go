@@ -0,0 +1,96 @@
+package infra
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"back/internal/currency/domain"
+	"back/pkg/repo"
+)
+
+// ExchangeRateRepo 汇率仓储实现
+type ExchangeRateRepo struct {
+	*repo.Repo[domain.ExchangeRate]
+	db *gorm.DB
+}
+
+// NewExchangeRateRepo 创建仓储
+func NewExchangeRateRepo(db *gorm.DB) *ExchangeRateRepo {
+	return &ExchangeRateRepo{
+		Repo: repo.NewRepo[domain.ExchangeRate](db),
+		db:   db,
+	}
+}
+
+// Transaction 在事务中执行
+func (r *ExchangeRateRepo) Transaction(ctx context.Context, fn func(txRepo *ExchangeRateRepo) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(NewExchangeRateRepo(tx))
+	})
+}
+
+// Upsert 保存汇率（同一币种对同一日期已有汇率时覆盖）
+func (r *ExchangeRateRepo) Upsert(ctx context.Context, rate *domain.ExchangeRate) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "from_currency"}, {Name: "to_currency"}, {Name: "effective_date"}},
+			DoUpdates: clause.AssignmentColumns([]string{"rate", "source", "created_by", "updated_at"}),
+		}).
+		Create(rate).Error
+}
+
+// FindByID 根据 ID 查询
+func (r *ExchangeRateRepo) FindByID(ctx context.Context, id uint) (*domain.ExchangeRate, error) {
+	rate, err := r.GetByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrRateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rate, nil
+}
+
+// FindRate 查找某日生效的汇率（生效日期不晚于 asOf 的最新一条）
+func (r *ExchangeRateRepo) FindRate(ctx context.Context, from, to string, asOf time.Time) (*domain.ExchangeRate, error) {
+	var result domain.ExchangeRate
+	err := r.db.WithContext(ctx).
+		Where("from_currency = ? AND to_currency = ? AND effective_date <= ?", from, to, domain.Day(asOf)).
+		Order("effective_date DESC").
+		First(&result).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrRateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// FindList 分页查询汇率（from / to 为空时不过滤）
+func (r *ExchangeRateRepo) FindList(ctx context.Context, from, to string, limit, offset int) ([]*domain.ExchangeRate, int64, error) {
+	query := r.db.WithContext(ctx).Model(&domain.ExchangeRate{})
+	if from != "" {
+		query = query.Where("from_currency = ?", from)
+	}
+	if to != "" {
+		query = query.Where("to_currency = ?", to)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var results []*domain.ExchangeRate
+	err := query.
+		Order("effective_date DESC, from_currency, to_currency").
+		Limit(limit).
+		Offset(offset).
+		Find(&results).Error
+	return results, total, err
+}
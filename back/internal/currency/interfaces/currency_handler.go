@@ -0,0 +1,220 @@
+package interfaces
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"back/internal/currency/application"
+	"back/internal/currency/domain"
+	"back/pkg/audit"
+	"back/pkg/endpoint"
+)
+
+// CurrencyHandler 汇率 Handler
+type CurrencyHandler struct {
+	service *application.CurrencyService
+}
+
+// NewCurrencyHandler 创建 Handler
+func NewCurrencyHandler(service *application.CurrencyService) *CurrencyHandler {
+	return &CurrencyHandler{service: service}
+}
+
+// handleError 统一错误响应
+func (h *CurrencyHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrRateNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidCurrency):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的币种代码"})
+	case errors.Is(err, domain.ErrSameCurrency):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "源币种与目标币种不能相同"})
+	case errors.Is(err, domain.ErrInvalidRate):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "汇率必须大于 0"})
+	case errors.Is(err, domain.ErrRateDateRequired):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "生效日期不能为空"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// Save godoc
+// @Summary      录入汇率
+// @Description  录入某日起生效的汇率（1 单位源币种 = rate 单位目标币种），同一币种对同一日期已有汇率时覆盖
+// @Tags         汇率管理
+// @Accept       json
+// @Produce      json
+// @Param        request body application.ExchangeRateRequest true "汇率"
+// @Success      200 {object} domain.ExchangeRate "保存成功"
+// @Failure      400 {object} map[string]string "请求参数错误"
+// @Security     Bearer
+// @Router       /currency/rates [post]
+func (h *CurrencyHandler) Save(c *gin.Context) {
+	var req application.ExchangeRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rate, err := h.service.Save(c.Request.Context(), &req, c.GetString("username"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	if recorder := audit.Get(c); recorder != nil {
+		recorder.SetResourceID(rate.ID)
+		recorder.SetNew(rate)
+	}
+
+	c.JSON(http.StatusOK, rate)
+}
+
+// Import godoc
+// @Summary      导入汇率
+// @Description  上传 CSV 批量导入汇率，列为 from_currency,to_currency,rate,effective_date(YYYY-MM-DD)，首行可为表头；任一行有误时整批不导入
+// @Tags         汇率管理
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file formData file true "CSV 文件"
+// @Success      200 {object} application.ImportRatesResponse "导入结果"
+// @Failure      400 {object} application.ImportRatesResponse "存在错误行"
+// @Security     Bearer
+// @Router       /currency/rates/import [post]
+func (h *CurrencyHandler) Import(c *gin.Context) {
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请上传 CSV 文件"})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	resp, err := h.service.Import(c.Request.Context(), file, c.GetString("username"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(resp.Errors) > 0 {
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// List godoc
+// @Summary      汇率列表
+// @Description  按币种对分页查询汇率，按生效日期倒序
+// @Tags         汇率管理
+// @Accept       json
+// @Produce      json
+// @Param        from query string false "源币种"
+// @Param        to query string false "目标币种"
+// @Param        limit query int false "每页数量" default(20)
+// @Param        offset query int false "偏移量" default(0)
+// @Success      200 {object} application.ExchangeRateListResponse "汇率列表"
+// @Failure      400 {object} map[string]string "请求参数错误"
+// @Security     Bearer
+// @Router       /currency/rates [get]
+func (h *CurrencyHandler) List(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	resp, err := h.service.List(c.Request.Context(), c.Query("from"), c.Query("to"), limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Delete godoc
+// @Summary      删除汇率
+// @Description  删除一条汇率
+// @Tags         汇率管理
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "汇率ID"
+// @Success      200 {object} map[string]string "删除成功"
+// @Failure      404 {object} map[string]string "汇率不存在"
+// @Security     Bearer
+// @Router       /currency/rates/{id} [delete]
+func (h *CurrencyHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的ID"})
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), uint(id)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
+
+// Convert godoc
+// @Summary      币种换算
+// @Description  按指定日期生效的汇率换算金额（无直接汇率时使用反向汇率或经本位币交叉换算）
+// @Tags         汇率管理
+// @Accept       json
+// @Produce      json
+// @Param        amount query number true "金额"
+// @Param        from query string true "源币种"
+// @Param        to query string false "目标币种（默认本位币）"
+// @Param        date query string false "汇率日期 YYYY-MM-DD（默认今天）"
+// @Success      200 {object} application.ConversionResponse "换算结果"
+// @Failure      400 {object} map[string]string "请求参数错误"
+// @Failure      404 {object} map[string]string "汇率不存在"
+// @Security     Bearer
+// @Router       /currency/convert [get]
+func (h *CurrencyHandler) Convert(c *gin.Context) {
+	amount, err := strconv.ParseFloat(c.Query("amount"), 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的金额"})
+		return
+	}
+	asOf, err := parseDate(c.Query("date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "日期格式应为 YYYY-MM-DD"})
+		return
+	}
+
+	resp, err := h.service.Conversion(c.Request.Context(), amount, c.Query("from"), c.Query("to"), asOf)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// parseDate 解析汇率日期参数（为空时为今天）
+func parseDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Now(), nil
+	}
+	return time.ParseInLocation(domain.DateLayout, value, time.Local)
+}
+
+// GetRoutes 获取路由定义
+func (h *CurrencyHandler) GetRoutes() []endpoint.RouteDefinition {
+	return []endpoint.RouteDefinition{
+		{Method: "GET", Path: "/currency/rates", Handler: h.List, Domain: "", Action: ""},
+		{Method: "POST", Path: "/currency/rates", Handler: h.Save, Domain: "currency", Action: "rate"},
+		{Method: "POST", Path: "/currency/rates/import", Handler: h.Import, Domain: "currency", Action: "rate"},
+		{Method: "DELETE", Path: "/currency/rates/:id", Handler: h.Delete, Domain: "currency", Action: "rate"},
+		{Method: "GET", Path: "/currency/convert", Handler: h.Convert, Domain: "", Action: ""},
+	}
+}
@@ -0,0 +1,238 @@
+package application
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"back/internal/inventory/domain"
+	"back/internal/inventory/infra"
+	"back/pkg/es"
+)
+
+// agingBulkSize 库龄索引批量写入条数
+const agingBulkSize = 200
+
+// AgingService 库龄服务（库龄报表、导出与搜索索引）
+type AgingService struct {
+	repo   *infra.InventoryRepo
+	esSync *es.ESSync
+}
+
+// NewAgingService 创建库龄服务
+func NewAgingService(repo *infra.InventoryRepo, esSync *es.ESSync) *AgingService {
+	return &AgingService{
+		repo:   repo,
+		esSync: esSync,
+	}
+}
+
+// GetAgingReport 获取库龄报表：按库龄分段汇总，并按类别、产品、仓库细分，标记呆滞批次
+func (s *AgingService) GetAgingReport(ctx context.Context, filter *domain.AgingFilter, slowMovingDays int) (*AgingReportResponse, error) {
+	batches, now, err := s.loadBatches(ctx, filter, slowMovingDays)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &AgingReportResponse{
+		AsOf:           now,
+		SlowMovingDays: slowMovingDays,
+		Buckets:        newBucketAmounts(),
+		Groups:         []*AgingGroupResponse{},
+		SlowMoving:     []*AgingBatchResponse{},
+	}
+
+	type groupKey struct {
+		category  string
+		productID uint
+		warehouse string
+	}
+	groups := make(map[groupKey]*AgingGroupResponse)
+
+	for _, b := range batches {
+		key := groupKey{b.Category, b.ProductID, b.Warehouse}
+		group, ok := groups[key]
+		if !ok {
+			group = &AgingGroupResponse{
+				Category:  b.Category,
+				ProductID: b.ProductID,
+				Warehouse: b.Warehouse,
+				Buckets:   newBucketAmounts(),
+			}
+			groups[key] = group
+			resp.Groups = append(resp.Groups, group)
+		}
+
+		addToBucket(resp.Buckets, b)
+		addToBucket(group.Buckets, b)
+		group.Quantity += b.Quantity
+		group.Value += b.TotalCost
+		resp.TotalValue += b.TotalCost
+
+		if b.SlowMoving {
+			group.SlowMovingValue += b.TotalCost
+			resp.SlowMovingValue += b.TotalCost
+			resp.SlowMovingBatches++
+			resp.SlowMoving = append(resp.SlowMoving, b)
+		}
+	}
+
+	return resp, nil
+}
+
+// ExportAgingCSV 导出批次库龄明细（CSV，带 UTF-8 BOM 便于 Excel 打开）
+func (s *AgingService) ExportAgingCSV(ctx context.Context, filter *domain.AgingFilter, slowMovingDays int, w io.Writer) error {
+	batches, _, err := s.loadBatches(ctx, filter, slowMovingDays)
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write([]byte("\xEF\xBB\xBF")); err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	header := []string{"批次ID", "产品ID", "类别", "仓库", "数量", "单位", "单价", "总成本", "入库时间", "最近出库时间", "库龄(天)", "库龄分段", "未动(天)", "呆滞"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, b := range batches {
+		lastIssue := ""
+		if b.LastIssueAt != nil {
+			lastIssue = b.LastIssueAt.Format("2006-01-02")
+		}
+		slow := "否"
+		if b.SlowMoving {
+			slow = "是"
+		}
+
+		record := []string{
+			b.BatchID,
+			strconv.FormatUint(uint64(b.ProductID), 10),
+			b.Category,
+			b.Warehouse,
+			fmt.Sprintf("%.2f", b.Quantity),
+			b.Unit,
+			fmt.Sprintf("%.2f", b.UnitCost),
+			fmt.Sprintf("%.2f", b.TotalCost),
+			b.CreatedAt.Format("2006-01-02"),
+			lastIssue,
+			strconv.Itoa(b.AgeDays),
+			b.AgeBucket,
+			strconv.Itoa(b.IdleDays),
+			slow,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// RebuildAgingIndex 重建库龄搜索索引（含已领完批次，供搜索模块过滤），并清理本次快照之前的过期文档
+func (s *AgingService) RebuildAgingIndex(ctx context.Context, slowMovingDays int) (*AgingIndexResponse, error) {
+	rows, err := s.repo.FindAgingRows(ctx, nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// ES 日期精度为毫秒，快照时间截断后才能与清理条件精确比较
+	now := time.Now().Truncate(time.Millisecond)
+	resp := &AgingIndexResponse{SnapshotAt: now}
+
+	docs := make([]interface{}, 0, agingBulkSize)
+	flush := func() error {
+		if len(docs) == 0 {
+			return nil
+		}
+		success, failed, err := s.esSync.BulkIndex(docs, true)
+		if err != nil {
+			return err
+		}
+		resp.Indexed += success
+		resp.Failed += failed
+		docs = docs[:0]
+		return nil
+	}
+
+	for i := range rows {
+		docs = append(docs, domain.NewAgingDocument(&rows[i], now, slowMovingDays))
+		if len(docs) == agingBulkSize {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	// 已删除的批次不会出现在本次快照中，按快照时间清理
+	if resp.Failed == 0 {
+		if err := s.esSync.DeleteOlderThan(domain.AgingIndexName, "snapshotAt", now); err != nil {
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
+// loadBatches 查询有结存的批次并计算库龄
+func (s *AgingService) loadBatches(ctx context.Context, filter *domain.AgingFilter, slowMovingDays int) ([]*AgingBatchResponse, time.Time, error) {
+	rows, err := s.repo.FindAgingRows(ctx, filter, false)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	now := time.Now()
+	batches := make([]*AgingBatchResponse, len(rows))
+	for i := range rows {
+		doc := domain.NewAgingDocument(&rows[i], now, slowMovingDays)
+		batches[i] = &AgingBatchResponse{
+			InventoryID: doc.ID,
+			BatchID:     doc.BatchID,
+			ProductID:   doc.ProductID,
+			Category:    doc.Category,
+			Warehouse:   doc.Warehouse,
+			Quantity:    doc.Quantity,
+			Unit:        doc.Unit,
+			UnitCost:    doc.UnitCost,
+			TotalCost:   doc.TotalCost,
+			AgeDays:     doc.AgeDays,
+			AgeBucket:   doc.AgeBucket,
+			IdleDays:    doc.IdleDays,
+			SlowMoving:  doc.SlowMoving,
+			LastIssueAt: doc.LastIssueAt,
+			CreatedAt:   doc.CreatedAt,
+		}
+	}
+	return batches, now, nil
+}
+
+// newBucketAmounts 创建空的库龄分段汇总
+func newBucketAmounts() []*AgingBucketAmount {
+	buckets := domain.AgingBuckets()
+	amounts := make([]*AgingBucketAmount, len(buckets))
+	for i, bucket := range buckets {
+		amounts[i] = &AgingBucketAmount{Bucket: bucket}
+	}
+	return amounts
+}
+
+// addToBucket 将批次计入对应库龄分段
+func addToBucket(amounts []*AgingBucketAmount, b *AgingBatchResponse) {
+	for _, amount := range amounts {
+		if amount.Bucket == b.AgeBucket {
+			amount.Batches++
+			amount.Quantity += b.Quantity
+			amount.Value += b.TotalCost
+			return
+		}
+	}
+}
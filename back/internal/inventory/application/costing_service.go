@@ -0,0 +1,434 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"back/internal/inventory/domain"
+	"back/internal/inventory/infra"
+)
+
+// OrderCostRecorder 订单实际成本记录接口（由订单服务实现，加入 ctx 携带的库存事务）
+type OrderCostRecorder interface {
+	AddActualCost(ctx context.Context, orderID uint, amount float64, description string) error
+}
+
+// ESSync ES 同步接口
+type ESSync interface {
+	Index(doc interface{}) error
+	Update(doc interface{}) error
+	Delete(indexName, docID string) error
+	BulkIndex(docs []interface{}, refresh bool) (success, failed int, err error)
+	DeleteOlderThan(indexName, field string, before time.Time) error
+}
+
+// itemKey 库存条目（产品 + 类别）
+type itemKey struct {
+	productID uint
+	category  string
+}
+
+// CostingService 库存计价服务
+type CostingService struct {
+	repo      *infra.InventoryRepo
+	orderCost OrderCostRecorder
+	esSync    ESSync
+}
+
+// NewCostingService 创建库存计价服务
+func NewCostingService(repo *infra.InventoryRepo, orderCost OrderCostRecorder, esSync ESSync) *CostingService {
+	return &CostingService{
+		repo:      repo,
+		orderCost: orderCost,
+		esSync:    esSync,
+	}
+}
+
+// ListCostingConfigs 获取各类别计价方法（未配置的类别返回默认方法）
+func (s *CostingService) ListCostingConfigs(ctx context.Context) ([]*CostingConfigResponse, error) {
+	configs, err := s.repo.FindAllCostingConfigs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	methods := make(map[string]string, len(configs))
+	for _, c := range configs {
+		methods[c.Category] = c.Method
+	}
+
+	categories := []string{domain.CategoryRawMaterial, domain.CategorySemiFinished, domain.CategoryFinished}
+	responses := make([]*CostingConfigResponse, len(categories))
+	for i, category := range categories {
+		method, ok := methods[category]
+		if !ok {
+			method = domain.DefaultCostingMethod
+		}
+		responses[i] = &CostingConfigResponse{Category: category, Method: method}
+	}
+
+	return responses, nil
+}
+
+// SetCostingMethod 设置类别计价方法
+func (s *CostingService) SetCostingMethod(ctx context.Context, req *SetCostingMethodRequest) (*CostingConfigResponse, error) {
+	config, err := s.repo.FindCostingConfig(ctx, req.Category)
+	if errors.Is(err, domain.ErrCostingConfigNotFound) {
+		config = &domain.CostingConfig{Category: req.Category}
+	} else if err != nil {
+		return nil, err
+	}
+
+	config.Method = req.Method
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SaveCostingConfig(ctx, config); err != nil {
+		return nil, err
+	}
+
+	return &CostingConfigResponse{Category: config.Category, Method: config.Method}, nil
+}
+
+// IssueInventory 按类别计价方法出库，并将出库成本计入订单实际成本
+func (s *CostingService) IssueInventory(ctx context.Context, req *IssueInventoryRequest) (*IssueInventoryResponse, error) {
+	resp := &IssueInventoryResponse{
+		ProductID: req.ProductID,
+		Category:  req.Category,
+		Quantity:  req.Quantity,
+		OrderID:   req.OrderID,
+	}
+
+	var changed []*domain.Inventory
+	err := s.repo.Transaction(ctx, func(txRepo *infra.InventoryRepo) error {
+		method, err := s.methodFor(ctx, txRepo, req.Category)
+		if err != nil {
+			return err
+		}
+		resp.CostingMethod = method
+
+		batches, err := txRepo.FindOnHandForUpdate(ctx, req.ProductID, req.Category)
+		if err != nil {
+			return err
+		}
+
+		var available float64
+		for _, b := range batches {
+			available += b.Quantity
+		}
+		if available < req.Quantity {
+			return domain.ErrInsufficientInventory
+		}
+
+		// 其他订单的预留不可被占用
+		reservedByOthers, err := txRepo.SumReserved(ctx, req.ProductID, req.Category, req.OrderID)
+		if err != nil {
+			return err
+		}
+		if available-reservedByOthers < req.Quantity {
+			return domain.ErrInsufficientAvailable
+		}
+
+		// 移动加权平均：出库前先将各批次单价统一为当前平均单价
+		if method == domain.CostingMethodMovingAverage {
+			restated, err := s.restateToAverage(ctx, txRepo, batches)
+			if err != nil {
+				return err
+			}
+			changed = append(changed, restated...)
+		}
+
+		// 按入库先后依次消耗批次
+		remaining := req.Quantity
+		for _, batch := range batches {
+			if remaining <= 0 {
+				break
+			}
+
+			take := batch.Quantity
+			if take > remaining {
+				take = remaining
+			}
+
+			unitCost := batch.UnitCost
+			if err := batch.Deduct(take); err != nil {
+				return err
+			}
+			if err := txRepo.Update(ctx, batch); err != nil {
+				return err
+			}
+			changed = append(changed, batch)
+
+			movement := domain.NewMovement(batch, domain.MovementTypeIssue, -take, unitCost, req.Remark)
+			movement.CostingMethod = method
+			movement.OrderID = req.OrderID
+			if err := s.createMovement(ctx, txRepo, movement); err != nil {
+				return err
+			}
+
+			resp.TotalCost += take * unitCost
+			resp.Movements = append(resp.Movements, s.toMovementResponse(movement))
+			remaining -= take
+		}
+
+		if req.OrderID == 0 {
+			return nil
+		}
+
+		// 领用抵扣本订单的预留，出库成本在同一事务中计入订单实际成本
+		if err := s.consumeReservations(ctx, txRepo, req.ProductID, req.Category, req.OrderID, req.Quantity); err != nil {
+			return err
+		}
+		description := fmt.Sprintf("领料出库：产品 %d（%s）数量 %.2f，成本 %.2f（%s）",
+			req.ProductID, req.Category, req.Quantity, resp.TotalCost, resp.CostingMethod)
+		return s.recordOrderCost(ctx, txRepo, req.OrderID, resp.TotalCost, description)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp.UnitCost = resp.TotalCost / resp.Quantity
+	s.syncIndex(changed...)
+
+	return resp, nil
+}
+
+// recordOrderCost 在库存事务中累加订单实际成本（未关联订单时跳过；订单不存在或已完结时整笔回滚）
+func (s *CostingService) recordOrderCost(ctx context.Context, txRepo *infra.InventoryRepo, orderID uint, amount float64, description string) error {
+	if orderID == 0 || s.orderCost == nil {
+		return nil
+	}
+	if err := s.orderCost.AddActualCost(txRepo.TxContext(ctx), orderID, amount, description); err != nil {
+		return fmt.Errorf("订单 %d 计入实际成本失败: %w", orderID, err)
+	}
+	return nil
+}
+
+// ListMovements 查询库存流水
+func (s *CostingService) ListMovements(ctx context.Context, filter *domain.MovementFilter, limit, offset int) (*MovementListResponse, error) {
+	movements, total, err := s.repo.FindMovements(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*MovementResponse, len(movements))
+	for i := range movements {
+		responses[i] = s.toMovementResponse(&movements[i])
+	}
+
+	return &MovementListResponse{
+		Total:     total,
+		Movements: responses,
+	}, nil
+}
+
+// GetValuation 获取截至某日（含当日）的库存估值报表
+func (s *CostingService) GetValuation(ctx context.Context, date time.Time, productID uint, category string) (*ValuationResponse, error) {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+
+	rows, err := s.repo.GetValuation(ctx, day.AddDate(0, 0, 1), productID, category)
+	if err != nil {
+		return nil, err
+	}
+
+	configs, err := s.ListCostingConfigs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	methods := make(map[string]string, len(configs))
+	for _, c := range configs {
+		methods[c.Category] = c.Method
+	}
+
+	resp := &ValuationResponse{
+		Date:  day.Format("2006-01-02"),
+		Items: make([]*ValuationItemResponse, 0, len(rows)),
+	}
+	for _, row := range rows {
+		item := &ValuationItemResponse{
+			ProductID:     row.ProductID,
+			Category:      row.Category,
+			CostingMethod: methods[row.Category],
+			Quantity:      row.Quantity,
+			Value:         row.Value,
+		}
+		if row.Quantity > 0 {
+			item.UnitCost = row.Value / row.Quantity
+		}
+		resp.TotalValue += row.Value
+		resp.Items = append(resp.Items, item)
+	}
+
+	return resp, nil
+}
+
+// recordReceipt 记录入库流水；移动加权平均类别同时重算该产品各批次单价，返回单价被重算的批次
+func (s *CostingService) recordReceipt(ctx context.Context, txRepo *infra.InventoryRepo, inventory *domain.Inventory, quantity float64, remark string) ([]*domain.Inventory, error) {
+	method, err := s.methodFor(ctx, txRepo, inventory.Category)
+	if err != nil {
+		return nil, err
+	}
+
+	movement := domain.NewMovement(inventory, domain.MovementTypeReceipt, quantity, inventory.UnitCost, remark)
+	movement.CostingMethod = method
+	if err := s.createMovement(ctx, txRepo, movement); err != nil {
+		return nil, err
+	}
+
+	if method != domain.CostingMethodMovingAverage {
+		return nil, nil
+	}
+
+	batches, err := txRepo.FindOnHandForUpdate(ctx, inventory.ProductID, inventory.Category)
+	if err != nil {
+		return nil, err
+	}
+	restated, err := s.restateToAverage(ctx, txRepo, batches)
+	if err != nil {
+		return nil, err
+	}
+
+	// 同步调用方持有的批次对象
+	for _, b := range batches {
+		if b.ID == inventory.ID {
+			inventory.UnitCost = b.UnitCost
+			inventory.TotalCost = b.TotalCost
+		}
+	}
+	return restated, nil
+}
+
+// recordAdjustment 记录调整流水（数量与金额均为变化量）
+func (s *CostingService) recordAdjustment(ctx context.Context, txRepo *infra.InventoryRepo, inventory *domain.Inventory, quantityDelta, valueDelta float64, remark string) error {
+	if quantityDelta == 0 && valueDelta == 0 {
+		return nil
+	}
+
+	method, err := s.methodFor(ctx, txRepo, inventory.Category)
+	if err != nil {
+		return err
+	}
+
+	movement := domain.NewMovement(inventory, domain.MovementTypeAdjustment, quantityDelta, inventory.UnitCost, remark)
+	movement.TotalCost = valueDelta
+	movement.CostingMethod = method
+	return s.createMovement(ctx, txRepo, movement)
+}
+
+// createMovement 写入库存流水；批次处于盘点锁定期间时标记流水与盘点明细
+func (s *CostingService) createMovement(ctx context.Context, txRepo *infra.InventoryRepo, movement *domain.InventoryMovement) error {
+	sessionID, err := txRepo.MarkMovedDuringCount(ctx, movement.InventoryID)
+	if err != nil {
+		return err
+	}
+	if sessionID > 0 {
+		movement.StocktakeID = sessionID
+		movement.DuringCount = true
+	}
+
+	return txRepo.CreateMovement(ctx, movement)
+}
+
+// syncIndex 事务提交后将发生变化的批次同步到搜索索引
+// （按变化先后传入，同一批次以最后一次为准；同步失败只记录日志，不影响已提交的业务）
+func (s *CostingService) syncIndex(batches ...*domain.Inventory) {
+	if s.esSync == nil || len(batches) == 0 {
+		return
+	}
+
+	position := make(map[uint]int, len(batches))
+	docs := make([]interface{}, 0, len(batches))
+	for _, b := range batches {
+		if i, ok := position[b.ID]; ok {
+			docs[i] = b
+			continue
+		}
+		position[b.ID] = len(docs)
+		docs = append(docs, b)
+	}
+
+	if _, failed, err := s.esSync.BulkIndex(docs, false); err != nil || failed > 0 {
+		log.Printf("库存批次索引同步失败: %d/%d 个批次未同步, err=%v", failed, len(docs), err)
+	}
+}
+
+// removeIndex 从搜索索引删除批次
+func (s *CostingService) removeIndex(id uint) {
+	if s.esSync != nil {
+		s.esSync.Delete((&domain.Inventory{}).GetIndexName(), strconv.Itoa(int(id)))
+	}
+}
+
+// consumeReservations 按创建先后抵扣订单的有效预留
+func (s *CostingService) consumeReservations(ctx context.Context, txRepo *infra.InventoryRepo, productID uint, category string, orderID uint, quantity float64) error {
+	reservations, err := txRepo.FindActiveReservationsForUpdate(ctx, productID, category, orderID)
+	if err != nil {
+		return err
+	}
+
+	remaining := quantity
+	for i := range reservations {
+		if remaining <= 0 {
+			break
+		}
+		remaining -= reservations[i].Consume(remaining)
+		if err := txRepo.UpdateReservation(ctx, &reservations[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restateToAverage 将批次单价统一重算为移动加权平均单价（总金额不变），返回单价被重算的批次
+func (s *CostingService) restateToAverage(ctx context.Context, txRepo *infra.InventoryRepo, batches []*domain.Inventory) ([]*domain.Inventory, error) {
+	average := domain.MovingAverageCost(batches)
+	var restated []*domain.Inventory
+	for _, b := range batches {
+		if b.UnitCost == average {
+			continue
+		}
+		if err := b.UpdateUnitCost(average); err != nil {
+			return nil, err
+		}
+		if err := txRepo.Update(ctx, b); err != nil {
+			return nil, err
+		}
+		restated = append(restated, b)
+	}
+	return restated, nil
+}
+
+// methodFor 获取类别计价方法
+func (s *CostingService) methodFor(ctx context.Context, repo *infra.InventoryRepo, category string) (string, error) {
+	config, err := repo.FindCostingConfig(ctx, category)
+	if errors.Is(err, domain.ErrCostingConfigNotFound) {
+		return domain.DefaultCostingMethod, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return config.Method, nil
+}
+
+// toMovementResponse 转换为流水响应
+func (s *CostingService) toMovementResponse(m *domain.InventoryMovement) *MovementResponse {
+	return &MovementResponse{
+		ID:            m.ID,
+		InventoryID:   m.InventoryID,
+		ProductID:     m.ProductID,
+		Category:      m.Category,
+		BatchID:       m.BatchID,
+		Type:          m.Type,
+		Quantity:      m.Quantity,
+		UnitCost:      m.UnitCost,
+		TotalCost:     m.TotalCost,
+		CostingMethod: m.CostingMethod,
+		OrderID:       m.OrderID,
+		Remark:        m.Remark,
+		CreatedAt:     m.CreatedAt,
+	}
+}
@@ -4,25 +4,29 @@ import "time"
 
 // CreateInventoryRequest 创建库存请求
 type CreateInventoryRequest struct {
-	ProductID uint    `json:"productId" binding:"required"`
-	Category  string  `json:"category" binding:"required"`
-	BatchID   string  `json:"batchId" binding:"required"`
-	Quantity  float64 `json:"quantity" binding:"required,gt=0"`
-	Unit      string  `json:"unit" binding:"required"`
-	UnitCost  float64 `json:"unitCost" binding:"required,gte=0"`
-	Remark    string  `json:"remark"`
+	ProductID  uint    `json:"productId" binding:"required"`
+	Category   string  `json:"category" binding:"required"`
+	BatchID    string  `json:"batchId" binding:"required"`
+	Warehouse  string  `json:"warehouse"`
+	SupplierID uint    `json:"supplierId"`
+	Quantity   float64 `json:"quantity" binding:"required,gt=0"`
+	Unit       string  `json:"unit" binding:"required"`
+	UnitCost   float64 `json:"unitCost" binding:"required,gte=0"`
+	Remark     string  `json:"remark"`
 }
 
 // UpdateInventoryRequest 更新库存请求
 type UpdateInventoryRequest struct {
-	ID        uint    `json:"id" binding:"required"`
-	ProductID uint    `json:"productId" binding:"required"`
-	Category  string  `json:"category" binding:"required"`
-	BatchID   string  `json:"batchId" binding:"required"`
-	Quantity  float64 `json:"quantity" binding:"required,gt=0"`
-	Unit      string  `json:"unit" binding:"required"`
-	UnitCost  float64 `json:"unitCost" binding:"required,gte=0"`
-	Remark    string  `json:"remark"`
+	ID         uint    `json:"id" binding:"required"`
+	ProductID  uint    `json:"productId" binding:"required"`
+	Category   string  `json:"category" binding:"required"`
+	BatchID    string  `json:"batchId" binding:"required"`
+	Warehouse  string  `json:"warehouse"`
+	SupplierID uint    `json:"supplierId"`
+	Quantity   float64 `json:"quantity" binding:"required,gt=0"`
+	Unit       string  `json:"unit" binding:"required"`
+	UnitCost   float64 `json:"unitCost" binding:"required,gte=0"`
+	Remark     string  `json:"remark"`
 }
 
 // UpdateQuantityRequest 更新数量请求
@@ -45,17 +49,20 @@ type AddInventoryRequest struct {
 
 // InventoryResponse 库存响应
 type InventoryResponse struct {
-	ID        uint      `json:"id"`
-	ProductID uint      `json:"productId"`
-	Category  string    `json:"category"`
-	BatchID   string    `json:"batchId"`
-	Quantity  float64   `json:"quantity"`
-	Unit      string    `json:"unit"`
-	UnitCost  float64   `json:"unitCost"`
-	TotalCost float64   `json:"totalCost"`
-	Remark    string    `json:"remark"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	ID         uint      `json:"id"`
+	ProductID  uint      `json:"productId"`
+	Category   string    `json:"category"`
+	BatchID    string    `json:"batchId"`
+	Warehouse  string    `json:"warehouse"`
+	SupplierID uint      `json:"supplierId"`
+	OrderID    uint      `json:"orderId"`
+	Quantity   float64   `json:"quantity"`
+	Unit       string    `json:"unit"`
+	UnitCost   float64   `json:"unitCost"`
+	TotalCost  float64   `json:"totalCost"`
+	Remark     string    `json:"remark"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
 }
 
 // InventoryListResponse 库存列表响应
@@ -63,3 +70,384 @@ type InventoryListResponse struct {
 	Total       int                  `json:"total"`
 	Inventories []*InventoryResponse `json:"inventories"`
 }
+
+// ==================== 计价与估值 ====================
+
+// SetCostingMethodRequest 设置类别计价方法请求
+type SetCostingMethodRequest struct {
+	Category string `json:"category" binding:"required,oneof=raw_material semi_finished finished"`
+	Method   string `json:"method" binding:"required,oneof=fifo moving_average"`
+}
+
+// CostingConfigResponse 计价配置响应
+type CostingConfigResponse struct {
+	Category string `json:"category"`
+	Method   string `json:"method"`
+}
+
+// IssueInventoryRequest 出库请求（按类别计价方法计算出库成本）
+type IssueInventoryRequest struct {
+	ProductID uint    `json:"productId" binding:"required"`
+	Category  string  `json:"category" binding:"required"`
+	Quantity  float64 `json:"quantity" binding:"required,gt=0"`
+	OrderID   uint    `json:"orderId"` // 关联订单（可选），出库成本计入订单实际成本
+	Remark    string  `json:"remark"`
+}
+
+// IssueInventoryResponse 出库响应
+type IssueInventoryResponse struct {
+	ProductID     uint                `json:"productId"`
+	Category      string              `json:"category"`
+	CostingMethod string              `json:"costingMethod"`
+	Quantity      float64             `json:"quantity"`
+	UnitCost      float64             `json:"unitCost"`  // 本次出库平均单位成本
+	TotalCost     float64             `json:"totalCost"` // 本次出库总成本
+	OrderID       uint                `json:"orderId,omitempty"`
+	Movements     []*MovementResponse `json:"movements"`
+}
+
+// MovementResponse 库存流水响应
+type MovementResponse struct {
+	ID            uint      `json:"id"`
+	InventoryID   uint      `json:"inventoryId"`
+	ProductID     uint      `json:"productId"`
+	Category      string    `json:"category"`
+	BatchID       string    `json:"batchId"`
+	Type          string    `json:"type"`
+	Quantity      float64   `json:"quantity"`
+	UnitCost      float64   `json:"unitCost"`
+	TotalCost     float64   `json:"totalCost"`
+	CostingMethod string    `json:"costingMethod"`
+	OrderID       uint      `json:"orderId"`
+	Remark        string    `json:"remark"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// MovementListResponse 库存流水列表响应
+type MovementListResponse struct {
+	Total     int64               `json:"total"`
+	Movements []*MovementResponse `json:"movements"`
+}
+
+// ValuationItemResponse 库存估值明细
+type ValuationItemResponse struct {
+	ProductID     uint    `json:"productId"`
+	Category      string  `json:"category"`
+	CostingMethod string  `json:"costingMethod"`
+	Quantity      float64 `json:"quantity"`
+	Value         float64 `json:"value"`
+	UnitCost      float64 `json:"unitCost"` // 结存平均单位成本
+}
+
+// ValuationResponse 库存估值报表响应
+type ValuationResponse struct {
+	Date       string                   `json:"date"` // 估值日期（当日结束时点）
+	TotalValue float64                  `json:"totalValue"`
+	Items      []*ValuationItemResponse `json:"items"`
+}
+
+// ==================== 盘点 ====================
+
+// CreateStocktakeRequest 创建盘点单请求（仓库与类别至少指定一个）
+type CreateStocktakeRequest struct {
+	Warehouse string `json:"warehouse"`
+	Category  string `json:"category" binding:"omitempty,oneof=raw_material semi_finished finished"`
+}
+
+// StocktakeCountItem 单个批次的实盘数量
+type StocktakeCountItem struct {
+	InventoryID     uint     `json:"inventoryId" binding:"required"`
+	CountedQuantity *float64 `json:"countedQuantity" binding:"required,gte=0"`
+	Remark          string   `json:"remark"`
+}
+
+// SubmitCountRequest 提交实盘数量请求
+type SubmitCountRequest struct {
+	Items []StocktakeCountItem `json:"items" binding:"required,min=1,dive"`
+}
+
+// ApproveStocktakeRequest 审批盘点单请求
+type ApproveStocktakeRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// StocktakeLineResponse 盘点明细响应
+type StocktakeLineResponse struct {
+	ID               uint       `json:"id"`
+	InventoryID      uint       `json:"inventoryId"`
+	ProductID        uint       `json:"productId"`
+	Category         string     `json:"category"`
+	Warehouse        string     `json:"warehouse"`
+	BatchID          string     `json:"batchId"`
+	Unit             string     `json:"unit"`
+	UnitCost         float64    `json:"unitCost"`
+	ExpectedQuantity float64    `json:"expectedQuantity"`
+	BookQuantity     float64    `json:"bookQuantity"`
+	CountedQuantity  *float64   `json:"countedQuantity"`
+	Variance         float64    `json:"variance"`
+	VarianceValue    float64    `json:"varianceValue"`
+	CounterName      string     `json:"counterName"`
+	CountSource      string     `json:"countSource"`
+	CountedAt        *time.Time `json:"countedAt"`
+	MovedDuringCount bool       `json:"movedDuringCount"`
+	Remark           string     `json:"remark"`
+}
+
+// StocktakeResponse 盘点单响应
+type StocktakeResponse struct {
+	ID                 uint                     `json:"id"`
+	SessionNo          string                   `json:"sessionNo"`
+	Warehouse          string                   `json:"warehouse"`
+	Category           string                   `json:"category"`
+	Status             string                   `json:"status"`
+	Reason             string                   `json:"reason"`
+	CreatorName        string                   `json:"creatorName"`
+	ApproverName       string                   `json:"approverName"`
+	ApprovedAt         *time.Time               `json:"approvedAt"`
+	TotalLines         int                      `json:"totalLines"`
+	CountedLines       int                      `json:"countedLines"`
+	VarianceLines      int                      `json:"varianceLines"`
+	MovedLines         int                      `json:"movedLines"` // 盘点期间发生变动的批次数
+	TotalVarianceValue float64                  `json:"totalVarianceValue"`
+	CreatedAt          time.Time                `json:"createdAt"`
+	Lines              []*StocktakeLineResponse `json:"lines,omitempty"`
+}
+
+// StocktakeListResponse 盘点单列表响应
+type StocktakeListResponse struct {
+	Total      int64                `json:"total"`
+	Stocktakes []*StocktakeResponse `json:"stocktakes"`
+}
+
+// ==================== 生产与追溯 ====================
+
+// ProductionInput 生产投入批次
+type ProductionInput struct {
+	InventoryID uint    `json:"inventoryId" binding:"required"`
+	Quantity    float64 `json:"quantity" binding:"required,gt=0"`
+}
+
+// ProductionOutput 生产产出批次
+type ProductionOutput struct {
+	ProductID uint    `json:"productId" binding:"required"`
+	Category  string  `json:"category" binding:"required,oneof=semi_finished finished"`
+	BatchID   string  `json:"batchId" binding:"required"`
+	Warehouse string  `json:"warehouse"`
+	Quantity  float64 `json:"quantity" binding:"required,gt=0"`
+	Unit      string  `json:"unit" binding:"required"`
+	Remark    string  `json:"remark"`
+}
+
+// RecordProductionRequest 生产记录请求（消耗投入批次、产出新批次并记录谱系）
+type RecordProductionRequest struct {
+	OrderID uint               `json:"orderId"`
+	Inputs  []ProductionInput  `json:"inputs" binding:"required,min=1,dive"`
+	Outputs []ProductionOutput `json:"outputs" binding:"required,min=1,dive"`
+	Remark  string             `json:"remark"`
+}
+
+// ProductionResponse 生产记录响应
+type ProductionResponse struct {
+	OrderID   uint                 `json:"orderId"`
+	InputCost float64              `json:"inputCost"` // 投入总成本
+	UnitCost  float64              `json:"unitCost"`  // 产出单位成本
+	Outputs   []*InventoryResponse `json:"outputs"`
+	Links     []*TraceLink         `json:"links"`
+}
+
+// TraceNode 追溯节点（批次）
+type TraceNode struct {
+	InventoryID  uint    `json:"inventoryId"`
+	BatchID      string  `json:"batchId"`
+	ProductID    uint    `json:"productId"`
+	Category     string  `json:"category"`
+	Warehouse    string  `json:"warehouse"`
+	Quantity     float64 `json:"quantity"` // 当前结存
+	Unit         string  `json:"unit"`
+	UnitCost     float64 `json:"unitCost"`
+	SupplierID   uint    `json:"supplierId,omitempty"`
+	SupplierName string  `json:"supplierName,omitempty"`
+	OrderID      uint    `json:"orderId,omitempty"`
+	Depth        int     `json:"depth"` // 距起点的层级
+	Deleted      bool    `json:"deleted,omitempty"`
+}
+
+// TraceLink 追溯边（投入 → 产出）
+type TraceLink struct {
+	ParentInventoryID uint    `json:"parentInventoryId"`
+	ParentBatchID     string  `json:"parentBatchId"`
+	ChildInventoryID  uint    `json:"childInventoryId"`
+	ChildBatchID      string  `json:"childBatchId"`
+	Quantity          float64 `json:"quantity"`
+	Cost              float64 `json:"cost"`
+	OrderID           uint    `json:"orderId,omitempty"`
+}
+
+// TraceOrder 追溯涉及的订单
+type TraceOrder struct {
+	OrderID        uint    `json:"orderId"`
+	OrderNo        string  `json:"orderNo"`
+	ClientID       uint    `json:"clientId"`
+	Status         string  `json:"status"`
+	DefectQuantity float64 `json:"defectQuantity"` // 订单累计次品数量
+}
+
+// TraceSupplier 追溯涉及的供应商
+type TraceSupplier struct {
+	SupplierID   uint     `json:"supplierId"`
+	SupplierName string   `json:"supplierName"`
+	BatchIDs     []string `json:"batchIds"`
+}
+
+// TraceResponse 批次追溯响应
+type TraceResponse struct {
+	Direction string           `json:"direction"` // backward / forward
+	Root      *TraceNode       `json:"root"`
+	Nodes     []*TraceNode     `json:"nodes"`
+	Links     []*TraceLink     `json:"links"`
+	Orders    []*TraceOrder    `json:"orders"`
+	Suppliers []*TraceSupplier `json:"suppliers"`
+}
+
+// ==================== 补货与预留 ====================
+
+// SaveReorderRuleRequest 保存补货规则请求（同一产品与类别仅一条，已存在则更新）
+type SaveReorderRuleRequest struct {
+	ProductID    uint    `json:"productId" binding:"required"`
+	Category     string  `json:"category" binding:"required,oneof=raw_material semi_finished finished"`
+	ReorderPoint float64 `json:"reorderPoint" binding:"gte=0"`
+	TargetLevel  float64 `json:"targetLevel" binding:"required,gt=0"`
+	Enabled      *bool   `json:"enabled"` // 为空时默认启用
+}
+
+// ReorderRuleResponse 补货规则响应
+type ReorderRuleResponse struct {
+	ID           uint      `json:"id"`
+	ProductID    uint      `json:"productId"`
+	Category     string    `json:"category"`
+	ReorderPoint float64   `json:"reorderPoint"`
+	TargetLevel  float64   `json:"targetLevel"`
+	Enabled      bool      `json:"enabled"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// ReserveInventoryRequest 预留库存请求
+type ReserveInventoryRequest struct {
+	ProductID uint    `json:"productId" binding:"required"`
+	Category  string  `json:"category" binding:"required"`
+	OrderID   uint    `json:"orderId" binding:"required"`
+	Quantity  float64 `json:"quantity" binding:"required,gt=0"`
+	Remark    string  `json:"remark"`
+}
+
+// ReservationResponse 库存预留响应
+type ReservationResponse struct {
+	ID         uint       `json:"id"`
+	ProductID  uint       `json:"productId"`
+	Category   string     `json:"category"`
+	OrderID    uint       `json:"orderId"`
+	Quantity   float64    `json:"quantity"`
+	Status     string     `json:"status"`
+	Remark     string     `json:"remark"`
+	ReleasedAt *time.Time `json:"releasedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+// ReservationListResponse 库存预留列表响应
+type ReservationListResponse struct {
+	Total        int64                  `json:"total"`
+	Reservations []*ReservationResponse `json:"reservations"`
+}
+
+// AvailabilityResponse 可用库存响应
+type AvailabilityResponse struct {
+	ProductID uint    `json:"productId"`
+	Category  string  `json:"category"`
+	OnHand    float64 `json:"onHand"`
+	Reserved  float64 `json:"reserved"`
+	Available float64 `json:"available"`
+}
+
+// ReplenishmentItemResponse 补货建议条目
+type ReplenishmentItemResponse struct {
+	ProductID         uint    `json:"productId"`
+	Category          string  `json:"category"`
+	OnHand            float64 `json:"onHand"`
+	Reserved          float64 `json:"reserved"`
+	Available         float64 `json:"available"`
+	OpenDemand        float64 `json:"openDemand"`
+	Projected         float64 `json:"projected"`
+	ReorderPoint      float64 `json:"reorderPoint"`
+	TargetLevel       float64 `json:"targetLevel"`
+	SuggestedQuantity float64 `json:"suggestedQuantity"`
+	SupplierID        uint    `json:"supplierId"`
+	SupplierName      string  `json:"supplierName"`
+	UnitPrice         float64 `json:"unitPrice"`
+	EstimatedCost     float64 `json:"estimatedCost"`
+}
+
+// ReplenishmentResponse 补货建议列表响应
+type ReplenishmentResponse struct {
+	RunAt              *time.Time                   `json:"runAt"` // 为空表示尚未运行
+	Items              []*ReplenishmentItemResponse `json:"items"`
+	TotalEstimatedCost float64                      `json:"totalEstimatedCost"`
+}
+
+// ==================== 库龄 ====================
+
+// AgingBucketAmount 库龄分段汇总
+type AgingBucketAmount struct {
+	Bucket   string  `json:"bucket"`
+	Batches  int     `json:"batches"`
+	Quantity float64 `json:"quantity"`
+	Value    float64 `json:"value"`
+}
+
+// AgingGroupResponse 按类别、产品、仓库汇总的库龄
+type AgingGroupResponse struct {
+	Category        string               `json:"category"`
+	ProductID       uint                 `json:"productId"`
+	Warehouse       string               `json:"warehouse"`
+	Quantity        float64              `json:"quantity"`
+	Value           float64              `json:"value"`
+	SlowMovingValue float64              `json:"slowMovingValue"`
+	Buckets         []*AgingBucketAmount `json:"buckets"`
+}
+
+// AgingBatchResponse 批次库龄
+type AgingBatchResponse struct {
+	InventoryID uint       `json:"inventoryId"`
+	BatchID     string     `json:"batchId"`
+	ProductID   uint       `json:"productId"`
+	Category    string     `json:"category"`
+	Warehouse   string     `json:"warehouse"`
+	Quantity    float64    `json:"quantity"`
+	Unit        string     `json:"unit"`
+	UnitCost    float64    `json:"unitCost"`
+	TotalCost   float64    `json:"totalCost"`
+	AgeDays     int        `json:"ageDays"`
+	AgeBucket   string     `json:"ageBucket"`
+	IdleDays    int        `json:"idleDays"`
+	SlowMoving  bool       `json:"slowMoving"`
+	LastIssueAt *time.Time `json:"lastIssueAt"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+// AgingReportResponse 库龄报表响应
+type AgingReportResponse struct {
+	AsOf              time.Time             `json:"asOf"`
+	SlowMovingDays    int                   `json:"slowMovingDays"`
+	TotalValue        float64               `json:"totalValue"`
+	SlowMovingValue   float64               `json:"slowMovingValue"`
+	SlowMovingBatches int                   `json:"slowMovingBatches"`
+	Buckets           []*AgingBucketAmount  `json:"buckets"`
+	Groups            []*AgingGroupResponse `json:"groups"`
+	SlowMoving        []*AgingBatchResponse `json:"slowMoving"` // 呆滞批次明细
+}
+
+// AgingIndexResponse 库龄索引重建响应
+type AgingIndexResponse struct {
+	SnapshotAt time.Time `json:"snapshotAt"`
+	Indexed    int       `json:"indexed"`
+	Failed     int       `json:"failed"`
+}
@@ -0,0 +1,334 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"back/internal/inventory/domain"
+	"back/internal/inventory/infra"
+	orderApp "back/internal/order/application"
+	supplierApp "back/internal/supplier/application"
+)
+
+// OrderTraceProvider 订单追溯信息查询接口（由订单服务实现）
+type OrderTraceProvider interface {
+	GetOrderTraceInfo(ctx context.Context, id uint) (*orderApp.OrderTraceInfo, error)
+}
+
+// SupplierInfoProvider 供应商信息查询接口（由供应商服务实现）
+type SupplierInfoProvider interface {
+	GetSupplierInfo(ctx context.Context, id uint) (*supplierApp.SupplierInfo, error)
+}
+
+// GenealogyService 批次谱系服务（生产投入产出记录与追溯）
+type GenealogyService struct {
+	repo      *infra.InventoryRepo
+	costing   *CostingService
+	orders    OrderTraceProvider
+	suppliers SupplierInfoProvider
+}
+
+// NewGenealogyService 创建批次谱系服务
+func NewGenealogyService(
+	repo *infra.InventoryRepo,
+	costing *CostingService,
+	orders OrderTraceProvider,
+	suppliers SupplierInfoProvider,
+) *GenealogyService {
+	return &GenealogyService{
+		repo:      repo,
+		costing:   costing,
+		orders:    orders,
+		suppliers: suppliers,
+	}
+}
+
+// RecordProduction 记录生产：消耗投入批次、按投入成本产出新批次，并记录父子批次关系
+func (s *GenealogyService) RecordProduction(ctx context.Context, req *RecordProductionRequest) (*ProductionResponse, error) {
+	var totalOutput float64
+	for _, o := range req.Outputs {
+		if !domain.IsProducedCategory(o.Category) {
+			return nil, domain.ErrInvalidOutputCategory
+		}
+		totalOutput += o.Quantity
+	}
+	if totalOutput <= 0 {
+		return nil, domain.ErrInvalidProductionQty
+	}
+
+	resp := &ProductionResponse{OrderID: req.OrderID}
+	inputs := make([]*domain.Inventory, len(req.Inputs))
+	var changed []*domain.Inventory // 按变化先后记录，提交后同步搜索索引
+
+	err := s.repo.Transaction(ctx, func(txRepo *infra.InventoryRepo) error {
+		// 1. 消耗投入批次（按类别计价方法计成本：先进先出按批次单价，移动加权平均按当前平均单价）
+		inputCosts := make([]float64, len(req.Inputs))
+		for i, in := range req.Inputs {
+			batch, err := txRepo.FindByIDForUpdate(ctx, in.InventoryID)
+			if err != nil {
+				return fmt.Errorf("投入批次 %d: %w", in.InventoryID, err)
+			}
+
+			method, err := s.costing.methodFor(ctx, txRepo, batch.Category)
+			if err != nil {
+				return err
+			}
+			if method == domain.CostingMethodMovingAverage {
+				var restated []*domain.Inventory
+				if batch, restated, err = s.restateBatch(ctx, txRepo, batch); err != nil {
+					return err
+				}
+				changed = append(changed, restated...)
+			}
+
+			unitCost := batch.UnitCost
+			if err := batch.Deduct(in.Quantity); err != nil {
+				return fmt.Errorf("投入批次 %s: %w", batch.BatchID, err)
+			}
+			if err := txRepo.Update(ctx, batch); err != nil {
+				return err
+			}
+			changed = append(changed, batch)
+
+			movement := domain.NewMovement(batch, domain.MovementTypeIssue, -in.Quantity, unitCost, "生产投入："+req.Remark)
+			movement.CostingMethod = method
+			movement.OrderID = req.OrderID
+			if err := s.costing.createMovement(ctx, txRepo, movement); err != nil {
+				return err
+			}
+
+			inputs[i] = batch
+			inputCosts[i] = in.Quantity * unitCost
+			resp.InputCost += inputCosts[i]
+		}
+		resp.UnitCost = resp.InputCost / totalOutput
+
+		// 2. 产出新批次（投入成本按产出数量分摊）
+		for _, out := range req.Outputs {
+			exists, err := txRepo.ExistsByBatchID(ctx, out.BatchID)
+			if err != nil {
+				return err
+			}
+			if exists {
+				return fmt.Errorf("%s: %w", out.BatchID, domain.ErrBatchIDDuplicate)
+			}
+
+			output := &domain.Inventory{
+				ProductID: out.ProductID,
+				Category:  out.Category,
+				BatchID:   out.BatchID,
+				Warehouse: out.Warehouse,
+				OrderID:   req.OrderID,
+				Quantity:  out.Quantity,
+				Unit:      out.Unit,
+				UnitCost:  resp.UnitCost,
+				Remark:    out.Remark,
+			}
+			output.CalculateTotalCost()
+			if err := output.Validate(); err != nil {
+				return err
+			}
+			if err := txRepo.Save(ctx, output); err != nil {
+				return err
+			}
+			restated, err := s.costing.recordReceipt(ctx, txRepo, output, output.Quantity, "生产产出："+req.Remark)
+			if err != nil {
+				return err
+			}
+			changed = append(changed, restated...)
+			changed = append(changed, output)
+
+			// 3. 记录谱系：每个投入批次按产出占比分摊到该产出批次
+			share := out.Quantity / totalOutput
+			for i, parent := range inputs {
+				link := &domain.BatchLink{
+					ParentInventoryID: parent.ID,
+					ParentBatchID:     parent.BatchID,
+					ChildInventoryID:  output.ID,
+					ChildBatchID:      output.BatchID,
+					Quantity:          req.Inputs[i].Quantity * share,
+					Cost:              inputCosts[i] * share,
+					OrderID:           req.OrderID,
+				}
+				if err := txRepo.CreateBatchLink(ctx, link); err != nil {
+					return err
+				}
+				resp.Links = append(resp.Links, toTraceLink(link))
+			}
+
+			resp.Outputs = append(resp.Outputs, toInventoryResponse(output))
+		}
+
+		// 4. 投入成本在同一事务中计入订单实际成本
+		description := fmt.Sprintf("生产投入：%d 个批次，成本 %.2f", len(req.Inputs), resp.InputCost)
+		return s.costing.recordOrderCost(ctx, txRepo, req.OrderID, resp.InputCost, description)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.costing.syncIndex(changed...)
+
+	return resp, nil
+}
+
+// restateBatch 将投入批次所属条目的结存统一为移动加权平均单价，返回重算后的投入批次与单价被重算的批次
+func (s *GenealogyService) restateBatch(ctx context.Context, txRepo *infra.InventoryRepo, batch *domain.Inventory) (*domain.Inventory, []*domain.Inventory, error) {
+	batches, err := txRepo.FindOnHandForUpdate(ctx, batch.ProductID, batch.Category)
+	if err != nil {
+		return nil, nil, err
+	}
+	restated, err := s.costing.restateToAverage(ctx, txRepo, batches)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, b := range batches {
+		if b.ID == batch.ID {
+			return b, restated, nil
+		}
+	}
+	return batch, restated, nil
+}
+
+// Trace 从指定批次出发追溯谱系（backward：追溯到原料批次与供应商；forward：追溯到下游产出与订单）
+func (s *GenealogyService) Trace(ctx context.Context, inventoryID uint, batchID, direction string) (*TraceResponse, error) {
+	var root *domain.Inventory
+	var err error
+	// 已删除或已全部出库的批次同样可以追溯
+	if inventoryID > 0 {
+		root, err = s.repo.FindByIDUnscoped(ctx, inventoryID)
+	} else {
+		root, err = s.repo.FindByBatchIDUnscoped(ctx, batchID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &TraceResponse{Direction: direction}
+	nodes := map[uint]*TraceNode{root.ID: toTraceNode(root, 0)}
+	order := []uint{root.ID}
+
+	// 按层级广度优先遍历，已访问的批次不再展开（防止环路）
+	frontier := []uint{root.ID}
+	for depth := 1; len(frontier) > 0 && depth <= domain.MaxTraceDepth; depth++ {
+		var links []domain.BatchLink
+		if direction == domain.TraceDirectionForward {
+			links, err = s.repo.FindLinksByParentIDs(ctx, frontier)
+		} else {
+			links, err = s.repo.FindLinksByChildIDs(ctx, frontier)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var next []uint
+		for i := range links {
+			link := &links[i]
+			resp.Links = append(resp.Links, toTraceLink(link))
+
+			neighbor := link.ParentInventoryID
+			if direction == domain.TraceDirectionForward {
+				neighbor = link.ChildInventoryID
+			}
+			if _, visited := nodes[neighbor]; !visited {
+				nodes[neighbor] = &TraceNode{InventoryID: neighbor, Depth: depth}
+				order = append(order, neighbor)
+				next = append(next, neighbor)
+			}
+		}
+
+		// 补全新节点的批次信息
+		if len(next) > 0 {
+			batches, err := s.repo.FindByIDsUnscoped(ctx, next)
+			if err != nil {
+				return nil, err
+			}
+			for i := range batches {
+				nodes[batches[i].ID] = toTraceNode(&batches[i], nodes[batches[i].ID].Depth)
+			}
+		}
+		frontier = next
+	}
+
+	for _, id := range order {
+		resp.Nodes = append(resp.Nodes, nodes[id])
+	}
+	resp.Root = resp.Nodes[0]
+
+	s.enrich(ctx, resp)
+	return resp, nil
+}
+
+// enrich 补充追溯涉及的供应商与订单（含次品数量）
+func (s *GenealogyService) enrich(ctx context.Context, resp *TraceResponse) {
+	suppliers := make(map[uint]*TraceSupplier)
+	orderIDs := make(map[uint]bool)
+
+	for _, node := range resp.Nodes {
+		if node.OrderID > 0 {
+			orderIDs[node.OrderID] = true
+		}
+		if node.SupplierID == 0 {
+			continue
+		}
+
+		supplier, ok := suppliers[node.SupplierID]
+		if !ok {
+			supplier = &TraceSupplier{SupplierID: node.SupplierID}
+			if info, err := s.suppliers.GetSupplierInfo(ctx, node.SupplierID); err == nil {
+				supplier.SupplierName = info.Name
+			}
+			suppliers[node.SupplierID] = supplier
+			resp.Suppliers = append(resp.Suppliers, supplier)
+		}
+		node.SupplierName = supplier.SupplierName
+		supplier.BatchIDs = append(supplier.BatchIDs, node.BatchID)
+	}
+
+	for _, link := range resp.Links {
+		if link.OrderID > 0 {
+			orderIDs[link.OrderID] = true
+		}
+	}
+	for orderID := range orderIDs {
+		traceOrder := &TraceOrder{OrderID: orderID}
+		if info, err := s.orders.GetOrderTraceInfo(ctx, orderID); err == nil {
+			traceOrder.OrderNo = info.OrderNo
+			traceOrder.ClientID = info.ClientID
+			traceOrder.Status = info.Status
+			traceOrder.DefectQuantity = info.DefectQuantity
+		}
+		resp.Orders = append(resp.Orders, traceOrder)
+	}
+}
+
+// toTraceNode 转换为追溯节点
+func toTraceNode(inventory *domain.Inventory, depth int) *TraceNode {
+	return &TraceNode{
+		InventoryID: inventory.ID,
+		BatchID:     inventory.BatchID,
+		ProductID:   inventory.ProductID,
+		Category:    inventory.Category,
+		Warehouse:   inventory.Warehouse,
+		Quantity:    inventory.Quantity,
+		Unit:        inventory.Unit,
+		UnitCost:    inventory.UnitCost,
+		SupplierID:  inventory.SupplierID,
+		OrderID:     inventory.OrderID,
+		Depth:       depth,
+		Deleted:     inventory.DeletedAt.Valid,
+	}
+}
+
+// toTraceLink 转换为追溯边
+func toTraceLink(link *domain.BatchLink) *TraceLink {
+	return &TraceLink{
+		ParentInventoryID: link.ParentInventoryID,
+		ParentBatchID:     link.ParentBatchID,
+		ChildInventoryID:  link.ChildInventoryID,
+		ChildBatchID:      link.ChildBatchID,
+		Quantity:          link.Quantity,
+		Cost:              link.Cost,
+		OrderID:           link.OrderID,
+	}
+}
@@ -9,26 +9,30 @@ import (
 
 // InventoryService 库存服务
 type InventoryService struct {
-	repo *infra.InventoryRepo
+	repo    *infra.InventoryRepo
+	costing *CostingService
 }
 
 // NewInventoryService 创建库存服务
-func NewInventoryService(repo *infra.InventoryRepo) *InventoryService {
+func NewInventoryService(repo *infra.InventoryRepo, costing *CostingService) *InventoryService {
 	return &InventoryService{
-		repo: repo,
+		repo:    repo,
+		costing: costing,
 	}
 }
 
 // CreateInventory 创建库存
 func (s *InventoryService) CreateInventory(ctx context.Context, req *CreateInventoryRequest) (*InventoryResponse, error) {
 	inventory := &domain.Inventory{
-		ProductID: req.ProductID,
-		Category:  req.Category,
-		BatchID:   req.BatchID,
-		Quantity:  req.Quantity,
-		Unit:      req.Unit,
-		UnitCost:  req.UnitCost,
-		Remark:    req.Remark,
+		ProductID:  req.ProductID,
+		Category:   req.Category,
+		BatchID:    req.BatchID,
+		Warehouse:  req.Warehouse,
+		SupplierID: req.SupplierID,
+		Quantity:   req.Quantity,
+		Unit:       req.Unit,
+		UnitCost:   req.UnitCost,
+		Remark:     req.Remark,
 	}
 
 	// 计算总成本
@@ -39,11 +43,21 @@ func (s *InventoryService) CreateInventory(ctx context.Context, req *CreateInven
 		return nil, err
 	}
 
-	// 保存
-	if err := s.repo.Save(ctx, inventory); err != nil {
+	// 保存并记录入库流水
+	var restated []*domain.Inventory
+	err := s.repo.Transaction(ctx, func(txRepo *infra.InventoryRepo) error {
+		if err := txRepo.Save(ctx, inventory); err != nil {
+			return err
+		}
+		var err error
+		restated, err = s.costing.recordReceipt(ctx, txRepo, inventory, inventory.Quantity, req.Remark)
+		return err
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	s.costing.syncIndex(append(restated, inventory)...)
 	return s.toResponse(inventory), nil
 }
 
@@ -129,10 +143,14 @@ func (s *InventoryService) UpdateInventory(ctx context.Context, req *UpdateInven
 		return nil, err
 	}
 
+	before := *inventory
+
 	// 更新字段
 	inventory.ProductID = req.ProductID
 	inventory.Category = req.Category
 	inventory.BatchID = req.BatchID
+	inventory.Warehouse = req.Warehouse
+	inventory.SupplierID = req.SupplierID
 	inventory.Quantity = req.Quantity
 	inventory.Unit = req.Unit
 	inventory.UnitCost = req.UnitCost
@@ -146,11 +164,28 @@ func (s *InventoryService) UpdateInventory(ctx context.Context, req *UpdateInven
 		return nil, err
 	}
 
-	// 保存
-	if err := s.repo.Update(ctx, inventory); err != nil {
+	// 保存并记录调整流水（产品或类别变化时冲销原结存）
+	err = s.repo.Transaction(ctx, func(txRepo *infra.InventoryRepo) error {
+		if err := txRepo.Update(ctx, inventory); err != nil {
+			return err
+		}
+
+		if before.ProductID != inventory.ProductID || before.Category != inventory.Category {
+			if err := s.costing.recordAdjustment(ctx, txRepo, &before, -before.Quantity, -before.TotalCost, "库存信息变更冲销"); err != nil {
+				return err
+			}
+			return s.costing.recordAdjustment(ctx, txRepo, inventory, inventory.Quantity, inventory.TotalCost, "库存信息变更转入")
+		}
+
+		return s.costing.recordAdjustment(ctx, txRepo, inventory,
+			inventory.Quantity-before.Quantity, inventory.TotalCost-before.TotalCost, "库存信息变更")
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	s.costing.syncIndex(inventory)
+
 	return s.toResponse(inventory), nil
 }
 
@@ -161,14 +196,24 @@ func (s *InventoryService) UpdateQuantity(ctx context.Context, req *UpdateQuanti
 		return nil, err
 	}
 
+	before := *inventory
 	if err := inventory.UpdateQuantity(req.Quantity); err != nil {
 		return nil, err
 	}
 
-	if err := s.repo.Update(ctx, inventory); err != nil {
+	err = s.repo.Transaction(ctx, func(txRepo *infra.InventoryRepo) error {
+		if err := txRepo.Update(ctx, inventory); err != nil {
+			return err
+		}
+		return s.costing.recordAdjustment(ctx, txRepo, inventory,
+			inventory.Quantity-before.Quantity, inventory.TotalCost-before.TotalCost, "数量调整")
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	s.costing.syncIndex(inventory)
+
 	return s.toResponse(inventory), nil
 }
 
@@ -183,10 +228,25 @@ func (s *InventoryService) DeductInventory(ctx context.Context, req *DeductInven
 		return nil, err
 	}
 
-	if err := s.repo.Update(ctx, inventory); err != nil {
+	err = s.repo.Transaction(ctx, func(txRepo *infra.InventoryRepo) error {
+		if err := txRepo.Update(ctx, inventory); err != nil {
+			return err
+		}
+
+		method, err := s.costing.methodFor(ctx, txRepo, inventory.Category)
+		if err != nil {
+			return err
+		}
+		movement := domain.NewMovement(inventory, domain.MovementTypeIssue, -req.Quantity, inventory.UnitCost, "指定批次扣减")
+		movement.CostingMethod = method
+		return s.costing.createMovement(ctx, txRepo, movement)
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	s.costing.syncIndex(inventory)
+
 	return s.toResponse(inventory), nil
 }
 
@@ -201,31 +261,66 @@ func (s *InventoryService) AddInventory(ctx context.Context, req *AddInventoryRe
 		return nil, err
 	}
 
-	if err := s.repo.Update(ctx, inventory); err != nil {
+	var restated []*domain.Inventory
+	err = s.repo.Transaction(ctx, func(txRepo *infra.InventoryRepo) error {
+		if err := txRepo.Update(ctx, inventory); err != nil {
+			return err
+		}
+		var err error
+		restated, err = s.costing.recordReceipt(ctx, txRepo, inventory, req.Quantity, "指定批次入库")
+		return err
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	s.costing.syncIndex(append(restated, inventory)...)
+
 	return s.toResponse(inventory), nil
 }
 
-// DeleteInventory 删除库存
+// DeleteInventory 删除库存（结存冲销记入调整流水）
 func (s *InventoryService) DeleteInventory(ctx context.Context, id uint) error {
-	return s.repo.Delete(ctx, id)
+	inventory, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.Transaction(ctx, func(txRepo *infra.InventoryRepo) error {
+		if err := txRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.costing.recordAdjustment(ctx, txRepo, inventory, -inventory.Quantity, -inventory.TotalCost, "删除库存")
+	})
+	if err != nil {
+		return err
+	}
+
+	s.costing.removeIndex(id)
+	return nil
 }
 
 // toResponse 转换为响应
 func (s *InventoryService) toResponse(inventory *domain.Inventory) *InventoryResponse {
+	return toInventoryResponse(inventory)
+}
+
+// toInventoryResponse 转换为库存响应
+func toInventoryResponse(inventory *domain.Inventory) *InventoryResponse {
 	return &InventoryResponse{
-		ID:        inventory.ID,
-		ProductID: inventory.ProductID,
-		Category:  inventory.Category,
-		BatchID:   inventory.BatchID,
-		Quantity:  inventory.Quantity,
-		Unit:      inventory.Unit,
-		UnitCost:  inventory.UnitCost,
-		TotalCost: inventory.TotalCost,
-		Remark:    inventory.Remark,
-		CreatedAt: inventory.CreatedAt,
-		UpdatedAt: inventory.UpdatedAt,
+		ID:         inventory.ID,
+		ProductID:  inventory.ProductID,
+		Category:   inventory.Category,
+		BatchID:    inventory.BatchID,
+		Warehouse:  inventory.Warehouse,
+		SupplierID: inventory.SupplierID,
+		OrderID:    inventory.OrderID,
+		Quantity:   inventory.Quantity,
+		Unit:       inventory.Unit,
+		UnitCost:   inventory.UnitCost,
+		TotalCost:  inventory.TotalCost,
+		Remark:     inventory.Remark,
+		CreatedAt:  inventory.CreatedAt,
+		UpdatedAt:  inventory.UpdatedAt,
 	}
 }
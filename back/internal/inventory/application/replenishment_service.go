@@ -0,0 +1,519 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"back/internal/inventory/domain"
+	"back/internal/inventory/infra"
+	notificationApp "back/internal/notification/application"
+	notificationDomain "back/internal/notification/domain"
+	orderApp "back/internal/order/application"
+	pricingApp "back/internal/pricing/application"
+	pricingDomain "back/internal/pricing/domain"
+	productApp "back/internal/product/application"
+	userDomain "back/internal/user/domain"
+)
+
+// ReplenishmentAlertRoles 低库存预警接收角色
+var ReplenishmentAlertRoles = []string{userDomain.RoleWarehouse, userDomain.RolePurchasing}
+
+// ReplenishmentService 补货服务（补货规则、库存预留与补货建议）
+type ReplenishmentService struct {
+	repo                *infra.InventoryRepo
+	orderService        *orderApp.OrderService
+	productService      *productApp.ProductService
+	priceCache          *pricingApp.PriceCacheService
+	notificationService *notificationApp.NotificationService
+}
+
+// NewReplenishmentService 创建补货服务
+func NewReplenishmentService(
+	repo *infra.InventoryRepo,
+	orderService *orderApp.OrderService,
+	productService *productApp.ProductService,
+	priceCache *pricingApp.PriceCacheService,
+	notificationService *notificationApp.NotificationService,
+) *ReplenishmentService {
+	return &ReplenishmentService{
+		repo:                repo,
+		orderService:        orderService,
+		productService:      productService,
+		priceCache:          priceCache,
+		notificationService: notificationService,
+	}
+}
+
+// ==================== 补货规则 ====================
+
+// ListReorderRules 获取补货规则列表
+func (s *ReplenishmentService) ListReorderRules(ctx context.Context) ([]*ReorderRuleResponse, error) {
+	rules, err := s.repo.FindReorderRules(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*ReorderRuleResponse, len(rules))
+	for i := range rules {
+		responses[i] = toReorderRuleResponse(&rules[i])
+	}
+	return responses, nil
+}
+
+// SaveReorderRule 保存补货规则（同一产品与类别已存在时更新）
+func (s *ReplenishmentService) SaveReorderRule(ctx context.Context, req *SaveReorderRuleRequest) (*ReorderRuleResponse, error) {
+	rule, err := s.repo.FindReorderRule(ctx, req.ProductID, req.Category)
+	if errors.Is(err, domain.ErrReorderRuleNotFound) {
+		rule = &domain.ReorderRule{ProductID: req.ProductID, Category: req.Category, Enabled: true}
+	} else if err != nil {
+		return nil, err
+	}
+
+	rule.ReorderPoint = req.ReorderPoint
+	rule.TargetLevel = req.TargetLevel
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SaveReorderRule(ctx, rule); err != nil {
+		return nil, err
+	}
+	return toReorderRuleResponse(rule), nil
+}
+
+// DeleteReorderRule 删除补货规则
+func (s *ReplenishmentService) DeleteReorderRule(ctx context.Context, id uint) error {
+	return s.repo.DeleteReorderRule(ctx, id)
+}
+
+// ==================== 库存预留 ====================
+
+// GetAvailability 获取产品可用库存（在库 - 有效预留）
+func (s *ReplenishmentService) GetAvailability(ctx context.Context, productID uint, category string) (*AvailabilityResponse, error) {
+	onHand, err := s.repo.SumOnHand(ctx, productID, category)
+	if err != nil {
+		return nil, err
+	}
+	reserved, err := s.repo.SumReserved(ctx, productID, category, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AvailabilityResponse{
+		ProductID: productID,
+		Category:  category,
+		OnHand:    onHand,
+		Reserved:  reserved,
+		Available: onHand - reserved,
+	}, nil
+}
+
+// Reserve 为订单预留库存（不能超过当前可用量）
+func (s *ReplenishmentService) Reserve(ctx context.Context, req *ReserveInventoryRequest) (*ReservationResponse, error) {
+	reservation, err := domain.NewReservation(req.ProductID, req.Category, req.OrderID, req.Quantity, req.Remark)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Transaction(ctx, func(txRepo *infra.InventoryRepo) error {
+		// 锁定在库批次，避免并发预留超出可用量
+		batches, err := txRepo.FindOnHandForUpdate(ctx, req.ProductID, req.Category)
+		if err != nil {
+			return err
+		}
+
+		var onHand float64
+		for _, b := range batches {
+			onHand += b.Quantity
+		}
+		reserved, err := txRepo.SumReserved(ctx, req.ProductID, req.Category, 0)
+		if err != nil {
+			return err
+		}
+		if onHand-reserved < req.Quantity {
+			return domain.ErrInsufficientAvailable
+		}
+
+		return txRepo.CreateReservation(ctx, reservation)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toReservationResponse(reservation), nil
+}
+
+// ReleaseReservation 释放库存预留
+func (s *ReplenishmentService) ReleaseReservation(ctx context.Context, id uint) (*ReservationResponse, error) {
+	reservation, err := s.repo.FindReservationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := reservation.Release(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateReservation(ctx, reservation); err != nil {
+		return nil, err
+	}
+
+	return toReservationResponse(reservation), nil
+}
+
+// ListReservations 查询库存预留
+func (s *ReplenishmentService) ListReservations(ctx context.Context, productID, orderID uint, status string, limit, offset int) (*ReservationListResponse, error) {
+	reservations, total, err := s.repo.FindReservations(ctx, productID, orderID, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*ReservationResponse, len(reservations))
+	for i := range reservations {
+		responses[i] = toReservationResponse(&reservations[i])
+	}
+
+	return &ReservationListResponse{
+		Total:        total,
+		Reservations: responses,
+	}, nil
+}
+
+// ==================== 补货建议 ====================
+
+// GetLatestReplenishment 获取最近一次运行生成的补货建议
+func (s *ReplenishmentService) GetLatestReplenishment(ctx context.Context, category string, includeAll bool) (*ReplenishmentResponse, error) {
+	suggestions, runAt, err := s.repo.FindLatestSuggestions(ctx, category, includeAll)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ReplenishmentResponse{Items: make([]*ReplenishmentItemResponse, 0, len(suggestions))}
+	if !runAt.IsZero() {
+		resp.RunAt = &runAt
+	}
+	for i := range suggestions {
+		resp.Items = append(resp.Items, toReplenishmentItem(&suggestions[i]))
+		resp.TotalEstimatedCost += suggestions[i].EstimatedCost
+	}
+	return resp, nil
+}
+
+// RunReplenishment 按补货规则评估所有条目，保存结果并向仓管、采购发送低库存预警
+func (s *ReplenishmentService) RunReplenishment(ctx context.Context) (*ReplenishmentResponse, error) {
+	rules, err := s.repo.FindReorderRules(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	runAt := time.Now()
+	resp := &ReplenishmentResponse{RunAt: &runAt, Items: []*ReplenishmentItemResponse{}}
+	if len(rules) == 0 {
+		return resp, nil
+	}
+
+	onHand, reserved, reservedByOrder, err := s.loadStock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	demand, err := s.openDemand(ctx, reservedByOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]domain.ReplenishmentSuggestion, len(rules))
+	for i := range rules {
+		rule := &rules[i]
+		key := itemKey{rule.ProductID, rule.Category}
+
+		suggestion := &suggestions[i]
+		suggestion.RunAt = runAt
+		suggestion.ProductID = rule.ProductID
+		suggestion.Category = rule.Category
+		suggestion.OnHand = onHand[key]
+		suggestion.Reserved = reserved[key]
+		suggestion.Available = suggestion.OnHand - suggestion.Reserved
+		suggestion.OpenDemand = demand[key]
+		suggestion.Projected = suggestion.Available - suggestion.OpenDemand
+		suggestion.ReorderPoint = rule.ReorderPoint
+		suggestion.TargetLevel = rule.TargetLevel
+		suggestion.SuggestedQuantity = rule.SuggestQuantity(suggestion.Projected)
+
+		if suggestion.SuggestedQuantity > 0 {
+			s.recommendSupplier(ctx, suggestion)
+			resp.Items = append(resp.Items, toReplenishmentItem(suggestion))
+			resp.TotalEstimatedCost += suggestion.EstimatedCost
+		}
+	}
+
+	if err := s.repo.CreateSuggestions(ctx, suggestions); err != nil {
+		return nil, err
+	}
+
+	if len(resp.Items) > 0 && s.notificationService != nil {
+		if err := s.notificationService.NotifyRoles(ctx, ReplenishmentAlertRoles, lowStockMessage(resp)); err != nil {
+			return nil, fmt.Errorf("补货建议已生成，但发送预警失败: %w", err)
+		}
+	}
+
+	return resp, nil
+}
+
+// loadStock 汇总在库数量与有效预留（总量及按订单）
+func (s *ReplenishmentService) loadStock(ctx context.Context) (map[itemKey]float64, map[itemKey]float64, map[itemKey]map[uint]float64, error) {
+	onHandRows, err := s.repo.SumOnHandByItem(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	onHand := make(map[itemKey]float64, len(onHandRows))
+	for _, row := range onHandRows {
+		onHand[itemKey{row.ProductID, row.Category}] = row.Quantity
+	}
+
+	reservedRows, err := s.repo.SumReservedByItem(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	reserved := make(map[itemKey]float64)
+	reservedByOrder := make(map[itemKey]map[uint]float64)
+	for _, row := range reservedRows {
+		key := itemKey{row.ProductID, row.Category}
+		reserved[key] += row.Quantity
+		if reservedByOrder[key] == nil {
+			reservedByOrder[key] = make(map[uint]float64)
+		}
+		reservedByOrder[key][row.OrderID] += row.Quantity
+	}
+
+	return onHand, reserved, reservedByOrder, nil
+}
+
+// ListMaterialStock 获取原料的在库与有效预留数量（供 Plan 模块调用）
+func (s *ReplenishmentService) ListMaterialStock(ctx context.Context) ([]*MaterialStock, error) {
+	onHand, reserved, reservedByOrder, err := s.loadStock(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stocks := make(map[uint]*MaterialStock)
+	get := func(key itemKey) *MaterialStock {
+		stock, ok := stocks[key.productID]
+		if !ok {
+			stock = &MaterialStock{MaterialID: key.productID, ReservedByOrder: make(map[uint]float64)}
+			stocks[key.productID] = stock
+		}
+		return stock
+	}
+	for key, qty := range onHand {
+		if key.category == domain.CategoryRawMaterial {
+			get(key).OnHand = qty
+		}
+	}
+	for key, qty := range reserved {
+		if key.category != domain.CategoryRawMaterial {
+			continue
+		}
+		stock := get(key)
+		stock.Reserved = qty
+		for orderID, orderQty := range reservedByOrder[key] {
+			stock.ReservedByOrder[orderID] = orderQty
+		}
+	}
+
+	result := make([]*MaterialStock, 0, len(stocks))
+	for _, stock := range stocks {
+		result = append(result, stock)
+	}
+	return result, nil
+}
+
+// ListIssuedMaterials 查询订单已领用的原料数量：原料ID → 订单ID → 数量（供其他模块使用）
+func (s *ReplenishmentService) ListIssuedMaterials(ctx context.Context, orderIDs []uint) (map[uint]map[uint]float64, error) {
+	rows, err := s.repo.SumIssuedByOrder(ctx, orderIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	issued := make(map[uint]map[uint]float64)
+	for _, row := range rows {
+		if row.Category != domain.CategoryRawMaterial {
+			continue
+		}
+		if issued[row.ProductID] == nil {
+			issued[row.ProductID] = make(map[uint]float64)
+		}
+		issued[row.ProductID][row.OrderID] += row.Quantity
+	}
+	return issued, nil
+}
+
+// MaterialStock 原料库存信息（供其他模块使用）
+type MaterialStock struct {
+	MaterialID      uint
+	OnHand          float64
+	Reserved        float64
+	ReservedByOrder map[uint]float64
+}
+
+// openDemand 计算未完结订单的净需求：成品按订单数量，原料按 BOM 占比展开，并扣除订单已领用与已预留的部分
+func (s *ReplenishmentService) openDemand(ctx context.Context, reservedByOrder map[itemKey]map[uint]float64) (map[itemKey]float64, error) {
+	orders, err := s.orderService.ListOpenDemands(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	orderIDs := make([]uint, len(orders))
+	for i, order := range orders {
+		orderIDs[i] = order.OrderID
+	}
+	rows, err := s.repo.SumIssuedByOrder(ctx, orderIDs)
+	if err != nil {
+		return nil, err
+	}
+	issuedByOrder := make(map[itemKey]map[uint]float64)
+	for _, row := range rows {
+		key := itemKey{row.ProductID, row.Category}
+		if issuedByOrder[key] == nil {
+			issuedByOrder[key] = make(map[uint]float64)
+		}
+		issuedByOrder[key][row.OrderID] += row.Quantity
+	}
+
+	products := make(map[uint]*productApp.ProductResponse)
+	demand := make(map[itemKey]float64)
+	addNet := func(key itemKey, orderID uint, gross float64) {
+		net := gross - issuedByOrder[key][orderID] - reservedByOrder[key][orderID]
+		if net > 0 {
+			demand[key] += net
+		}
+	}
+
+	for _, order := range orders {
+		addNet(itemKey{order.ProductID, domain.CategoryFinished}, order.OrderID, order.RequiredQuantity)
+
+		product, ok := products[order.ProductID]
+		if !ok {
+			product, err = s.productService.Get(ctx, order.ProductID)
+			if err != nil {
+				// 产品已删除时跳过原料展开
+				product = nil
+			}
+			products[order.ProductID] = product
+		}
+		if product == nil {
+			continue
+		}
+
+		for _, m := range product.Materials {
+			addNet(itemKey{m.MaterialID, domain.CategoryRawMaterial}, order.OrderID, order.RequiredQuantity*m.Ratio)
+		}
+	}
+
+	return demand, nil
+}
+
+// recommendSupplier 原料按建议补货量的当前有效最低价推荐供应商（计入阶梯价与起订量，暂无有效报价时不推荐）
+//
+// 建议补货量低于推荐报价的起订量时提高到起订量。
+func (s *ReplenishmentService) recommendSupplier(ctx context.Context, suggestion *domain.ReplenishmentSuggestion) {
+	if suggestion.Category != domain.CategoryRawMaterial || s.priceCache == nil {
+		return
+	}
+
+	price, err := s.priceCache.Min(ctx, pricingDomain.TargetTypeMaterial, suggestion.ProductID, suggestion.SuggestedQuantity)
+	if err != nil {
+		return
+	}
+
+	if price.PurchaseQuantity > suggestion.SuggestedQuantity {
+		suggestion.SuggestedQuantity = price.PurchaseQuantity
+	}
+	suggestion.SupplierID = price.SupplierID
+	suggestion.SupplierName = price.SupplierName
+	suggestion.UnitPrice = price.BasePrice
+	suggestion.EstimatedCost = price.BasePrice * suggestion.SuggestedQuantity
+}
+
+// lowStockMessage 构建低库存预警通知
+func lowStockMessage(resp *ReplenishmentResponse) *notificationApp.Message {
+	const maxLines = 10
+
+	lines := make([]string, 0, maxLines+1)
+	for i, item := range resp.Items {
+		if i == maxLines {
+			lines = append(lines, fmt.Sprintf("……共 %d 项", len(resp.Items)))
+			break
+		}
+		line := fmt.Sprintf("%s #%d：预计可用 %.2f，低于补货点 %.2f，建议补货 %.2f",
+			item.Category, item.ProductID, item.Projected, item.ReorderPoint, item.SuggestedQuantity)
+		if item.SupplierName != "" {
+			line += fmt.Sprintf("（推荐供应商：%s，单价 %.2f）", item.SupplierName, item.UnitPrice)
+		}
+		lines = append(lines, line)
+	}
+
+	return &notificationApp.Message{
+		Type:    notificationDomain.TypeLowStock,
+		Level:   notificationDomain.LevelWarning,
+		Title:   fmt.Sprintf("低库存预警：%d 项需要补货", len(resp.Items)),
+		Content: strings.Join(lines, "\n"),
+		Payload: map[string]interface{}{
+			"runAt":              resp.RunAt,
+			"items":              len(resp.Items),
+			"totalEstimatedCost": resp.TotalEstimatedCost,
+		},
+	}
+}
+
+// toReorderRuleResponse 转换为补货规则响应
+func toReorderRuleResponse(rule *domain.ReorderRule) *ReorderRuleResponse {
+	return &ReorderRuleResponse{
+		ID:           rule.ID,
+		ProductID:    rule.ProductID,
+		Category:     rule.Category,
+		ReorderPoint: rule.ReorderPoint,
+		TargetLevel:  rule.TargetLevel,
+		Enabled:      rule.Enabled,
+		UpdatedAt:    rule.UpdatedAt,
+	}
+}
+
+// toReservationResponse 转换为库存预留响应
+func toReservationResponse(r *domain.InventoryReservation) *ReservationResponse {
+	return &ReservationResponse{
+		ID:         r.ID,
+		ProductID:  r.ProductID,
+		Category:   r.Category,
+		OrderID:    r.OrderID,
+		Quantity:   r.Quantity,
+		Status:     r.Status,
+		Remark:     r.Remark,
+		ReleasedAt: r.ReleasedAt,
+		CreatedAt:  r.CreatedAt,
+	}
+}
+
+// toReplenishmentItem 转换为补货建议条目
+func toReplenishmentItem(s *domain.ReplenishmentSuggestion) *ReplenishmentItemResponse {
+	return &ReplenishmentItemResponse{
+		ProductID:         s.ProductID,
+		Category:          s.Category,
+		OnHand:            s.OnHand,
+		Reserved:          s.Reserved,
+		Available:         s.Available,
+		OpenDemand:        s.OpenDemand,
+		Projected:         s.Projected,
+		ReorderPoint:      s.ReorderPoint,
+		TargetLevel:       s.TargetLevel,
+		SuggestedQuantity: s.SuggestedQuantity,
+		SupplierID:        s.SupplierID,
+		SupplierName:      s.SupplierName,
+		UnitPrice:         s.UnitPrice,
+		EstimatedCost:     s.EstimatedCost,
+	}
+}
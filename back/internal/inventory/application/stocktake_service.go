@@ -0,0 +1,263 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"back/internal/inventory/domain"
+	"back/internal/inventory/infra"
+)
+
+// StocktakeService 盘点服务
+type StocktakeService struct {
+	repo    *infra.InventoryRepo
+	costing *CostingService
+}
+
+// NewStocktakeService 创建盘点服务
+func NewStocktakeService(repo *infra.InventoryRepo, costing *CostingService) *StocktakeService {
+	return &StocktakeService{
+		repo:    repo,
+		costing: costing,
+	}
+}
+
+// CreateStocktake 创建盘点单并快照账面数量，盘点期间批次处于锁定状态
+func (s *StocktakeService) CreateStocktake(ctx context.Context, req *CreateStocktakeRequest, operatorID uint, operatorName string) (*StocktakeResponse, error) {
+	var session *domain.StocktakeSession
+
+	err := s.repo.Transaction(ctx, func(txRepo *infra.InventoryRepo) error {
+		batches, err := txRepo.FindForStocktake(ctx, req.Warehouse, req.Category)
+		if err != nil {
+			return err
+		}
+
+		session, err = domain.NewStocktakeSession(req.Warehouse, req.Category, batches, operatorID, operatorName)
+		if err != nil {
+			return err
+		}
+
+		ids := make([]uint, len(batches))
+		for i, b := range batches {
+			ids[i] = b.ID
+		}
+		count, err := txRepo.CountOpenStocktakeLines(ctx, ids)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return domain.ErrBatchInStocktake
+		}
+
+		return txRepo.CreateStocktake(ctx, session)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(session, true), nil
+}
+
+// GetStocktake 获取盘点单详情（含差异）
+func (s *StocktakeService) GetStocktake(ctx context.Context, id uint) (*StocktakeResponse, error) {
+	session, err := s.repo.FindStocktakeByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(session, true), nil
+}
+
+// ListStocktakes 获取盘点单列表
+func (s *StocktakeService) ListStocktakes(ctx context.Context, status string, limit, offset int) (*StocktakeListResponse, error) {
+	sessions, total, err := s.repo.FindStocktakes(ctx, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*StocktakeResponse, len(sessions))
+	for i := range sessions {
+		responses[i] = s.toResponse(&sessions[i], false)
+	}
+
+	return &StocktakeListResponse{
+		Total:      total,
+		Stocktakes: responses,
+	}, nil
+}
+
+// SubmitCount 提交实盘数量（可重复提交，以最后一次为准）
+func (s *StocktakeService) SubmitCount(ctx context.Context, id uint, req *SubmitCountRequest, counterID uint, counterName, source string) (*StocktakeResponse, error) {
+	var session *domain.StocktakeSession
+
+	err := s.repo.Transaction(ctx, func(txRepo *infra.InventoryRepo) error {
+		var err error
+		session, err = txRepo.FindStocktakeByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if !session.IsCounting() {
+			return domain.ErrStocktakeNotCounting
+		}
+
+		for _, item := range req.Items {
+			line := session.FindLine(item.InventoryID)
+			if line == nil {
+				return domain.ErrStocktakeLineNotFound
+			}
+			// 差异以盘点时的账面为准，盘点后至审批前的出入库不影响差异
+			moved, err := txRepo.SumMovedDuringCount(ctx, session.ID, line.InventoryID)
+			if err != nil {
+				return err
+			}
+			if err := line.RecordCount(*item.CountedQuantity, moved, counterID, counterName, source, item.Remark); err != nil {
+				return err
+			}
+			if err := txRepo.UpdateStocktakeLine(ctx, line); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(session, true), nil
+}
+
+// ApproveStocktake 审批盘点单，按实盘与盘点时账面的差异过账调整流水
+func (s *StocktakeService) ApproveStocktake(ctx context.Context, id uint, req *ApproveStocktakeRequest, approverID uint, approverName string) (*StocktakeResponse, error) {
+	var session *domain.StocktakeSession
+	var adjusted []*domain.Inventory
+
+	err := s.repo.Transaction(ctx, func(txRepo *infra.InventoryRepo) error {
+		var err error
+		session, err = txRepo.FindStocktakeByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		// 先解除锁定，后续调整流水不再被标记为盘点期间变动
+		if err := session.Approve(approverID, approverName, req.Reason); err != nil {
+			return err
+		}
+		if err := txRepo.UpdateStocktake(ctx, session); err != nil {
+			return err
+		}
+
+		for i := range session.Lines {
+			line := &session.Lines[i]
+			inventory, err := txRepo.FindByIDForUpdate(ctx, line.InventoryID)
+			if err != nil {
+				return err
+			}
+
+			if line.Variance == 0 {
+				continue
+			}
+
+			beforeValue := inventory.TotalCost
+			if err := inventory.Adjust(line.Variance); err != nil {
+				return fmt.Errorf("批次 %s: %w", inventory.BatchID, err)
+			}
+			if err := txRepo.Update(ctx, inventory); err != nil {
+				return err
+			}
+
+			remark := fmt.Sprintf("盘点调整 %s：%s", session.SessionNo, req.Reason)
+			if line.Remark != "" {
+				remark += "（" + line.Remark + "）"
+			}
+			method, err := s.costing.methodFor(ctx, txRepo, inventory.Category)
+			if err != nil {
+				return err
+			}
+			movement := domain.NewMovement(inventory, domain.MovementTypeAdjustment, line.Variance, inventory.UnitCost, remark)
+			movement.TotalCost = inventory.TotalCost - beforeValue
+			movement.CostingMethod = method
+			movement.StocktakeID = session.ID
+			if err := txRepo.CreateMovement(ctx, movement); err != nil {
+				return err
+			}
+			adjusted = append(adjusted, inventory)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.costing.syncIndex(adjusted...)
+
+	return s.toResponse(session, true), nil
+}
+
+// CancelStocktake 取消盘点单（解除锁定，不过账）
+func (s *StocktakeService) CancelStocktake(ctx context.Context, id uint) error {
+	session, err := s.repo.FindStocktakeByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := session.Cancel(); err != nil {
+		return err
+	}
+
+	return s.repo.UpdateStocktake(ctx, session)
+}
+
+// toResponse 转换为响应
+func (s *StocktakeService) toResponse(session *domain.StocktakeSession, withLines bool) *StocktakeResponse {
+	resp := &StocktakeResponse{
+		ID:           session.ID,
+		SessionNo:    session.SessionNo,
+		Warehouse:    session.Warehouse,
+		Category:     session.Category,
+		Status:       session.Status,
+		Reason:       session.Reason,
+		CreatorName:  session.CreatorName,
+		ApproverName: session.ApproverName,
+		ApprovedAt:   session.ApprovedAt,
+		TotalLines:   len(session.Lines),
+		CreatedAt:    session.CreatedAt,
+	}
+
+	for _, l := range session.Lines {
+		if l.CountedQuantity != nil {
+			resp.CountedLines++
+		}
+		if l.Variance != 0 {
+			resp.VarianceLines++
+		}
+		if l.MovedDuringCount {
+			resp.MovedLines++
+		}
+		resp.TotalVarianceValue += l.VarianceValue
+
+		if withLines {
+			resp.Lines = append(resp.Lines, &StocktakeLineResponse{
+				ID:               l.ID,
+				InventoryID:      l.InventoryID,
+				ProductID:        l.ProductID,
+				Category:         l.Category,
+				Warehouse:        l.Warehouse,
+				BatchID:          l.BatchID,
+				Unit:             l.Unit,
+				UnitCost:         l.UnitCost,
+				ExpectedQuantity: l.ExpectedQuantity,
+				BookQuantity:     l.BookQuantity,
+				CountedQuantity:  l.CountedQuantity,
+				Variance:         l.Variance,
+				VarianceValue:    l.VarianceValue,
+				CounterName:      l.CounterName,
+				CountSource:      l.CountSource,
+				CountedAt:        l.CountedAt,
+				MovedDuringCount: l.MovedDuringCount,
+				Remark:           l.Remark,
+			})
+		}
+	}
+
+	return resp
+}
@@ -0,0 +1,151 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// 库龄分段常量
+const (
+	AgingBucket0To30      = "0-30"
+	AgingBucket31To90     = "31-90"
+	AgingBucket91To180    = "91-180"
+	AgingBucketOver180    = "180+"
+	DefaultSlowMovingDays = 90 // 默认呆滞天数（无出库超过该天数视为呆滞）
+	AgingIndexName        = "inventory_aging"
+)
+
+// AgingBuckets 库龄分段（按顺序）
+func AgingBuckets() []string {
+	return []string{AgingBucket0To30, AgingBucket31To90, AgingBucket91To180, AgingBucketOver180}
+}
+
+// AgingBucketOf 根据天数确定库龄分段
+func AgingBucketOf(days int) string {
+	switch {
+	case days <= 30:
+		return AgingBucket0To30
+	case days <= 90:
+		return AgingBucket31To90
+	case days <= 180:
+		return AgingBucket91To180
+	default:
+		return AgingBucketOver180
+	}
+}
+
+// DaysBetween 两个时间之间的整天数（不足一天按 0 计）
+func DaysBetween(from, to time.Time) int {
+	if to.Before(from) {
+		return 0
+	}
+	return int(to.Sub(from).Hours() / 24)
+}
+
+// AgingFilter 库龄查询条件（零值表示不过滤）
+type AgingFilter struct {
+	ProductID uint
+	Category  string
+	Warehouse string
+}
+
+// AgingRow 批次及其最近一次出库时间
+type AgingRow struct {
+	Inventory   `gorm:"embedded"`
+	LastIssueAt *time.Time
+}
+
+// IdleSince 最近一次出库时间，从未出库时为入库时间
+func (r *AgingRow) IdleSince() time.Time {
+	if r.LastIssueAt != nil {
+		return *r.LastIssueAt
+	}
+	return r.CreatedAt
+}
+
+// AgingDocument 库龄 ES 文档（定时重建，供搜索模块过滤与聚合）
+type AgingDocument struct {
+	ID          uint       `json:"id"`
+	BatchID     string     `json:"batchId"`
+	ProductID   uint       `json:"productId"`
+	Category    string     `json:"category"`
+	Warehouse   string     `json:"warehouse"`
+	SupplierID  uint       `json:"supplierId"`
+	Quantity    float64    `json:"quantity"`
+	Unit        string     `json:"unit"`
+	UnitCost    float64    `json:"unitCost"`
+	TotalCost   float64    `json:"totalCost"`
+	AgeDays     int        `json:"ageDays"`
+	AgeBucket   string     `json:"ageBucket"`
+	IdleDays    int        `json:"idleDays"`
+	SlowMoving  bool       `json:"slowMoving"`
+	OnHand      bool       `json:"onHand"`
+	LastIssueAt *time.Time `json:"lastIssueAt"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	SnapshotAt  time.Time  `json:"snapshotAt"`
+}
+
+// NewAgingDocument 根据批次生成库龄文档
+func NewAgingDocument(row *AgingRow, now time.Time, slowMovingDays int) *AgingDocument {
+	ageDays := DaysBetween(row.CreatedAt, now)
+	idleDays := DaysBetween(row.IdleSince(), now)
+	onHand := row.Quantity > 0
+
+	return &AgingDocument{
+		ID:          row.ID,
+		BatchID:     row.BatchID,
+		ProductID:   row.ProductID,
+		Category:    row.Category,
+		Warehouse:   row.Warehouse,
+		SupplierID:  row.SupplierID,
+		Quantity:    row.Quantity,
+		Unit:        row.Unit,
+		UnitCost:    row.UnitCost,
+		TotalCost:   row.TotalCost,
+		AgeDays:     ageDays,
+		AgeBucket:   AgingBucketOf(ageDays),
+		IdleDays:    idleDays,
+		SlowMoving:  onHand && idleDays >= slowMovingDays,
+		OnHand:      onHand,
+		LastIssueAt: row.LastIssueAt,
+		CreatedAt:   row.CreatedAt,
+		SnapshotAt:  now,
+	}
+}
+
+// ToDocument 转换为 ES 文档
+func (d *AgingDocument) ToDocument() map[string]interface{} {
+	doc := map[string]interface{}{
+		"id":         d.ID,
+		"batchId":    d.BatchID,
+		"productId":  d.ProductID,
+		"category":   d.Category,
+		"warehouse":  d.Warehouse,
+		"supplierId": d.SupplierID,
+		"quantity":   d.Quantity,
+		"unit":       d.Unit,
+		"unitCost":   d.UnitCost,
+		"totalCost":  d.TotalCost,
+		"ageDays":    d.AgeDays,
+		"ageBucket":  d.AgeBucket,
+		"idleDays":   d.IdleDays,
+		"slowMoving": d.SlowMoving,
+		"onHand":     d.OnHand,
+		"createdAt":  d.CreatedAt,
+		"snapshotAt": d.SnapshotAt,
+	}
+	if d.LastIssueAt != nil {
+		doc["lastIssueAt"] = *d.LastIssueAt
+	}
+	return doc
+}
+
+// GetIndexName ES 索引名称
+func (d *AgingDocument) GetIndexName() string {
+	return AgingIndexName
+}
+
+// GetDocumentID ES 文档 ID
+func (d *AgingDocument) GetDocumentID() string {
+	return fmt.Sprintf("%d", d.ID)
+}
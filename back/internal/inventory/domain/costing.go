@@ -0,0 +1,58 @@
+package domain
+
+import "time"
+
+// 计价方法常量
+const (
+	CostingMethodFIFO          = "fifo"           // 先进先出
+	CostingMethodMovingAverage = "moving_average" // 移动加权平均
+)
+
+// DefaultCostingMethod 未配置时的默认计价方法
+const DefaultCostingMethod = CostingMethodFIFO
+
+// CostingConfig 库存计价配置（按类别）
+type CostingConfig struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Category  string    `gorm:"size:50;not null;uniqueIndex" json:"category"` // 库存类别
+	Method    string    `gorm:"size:20;not null" json:"method"`               // 计价方法
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+// TableName 表名
+func (CostingConfig) TableName() string {
+	return "inventory_costing_configs"
+}
+
+// Validate 验证计价配置
+func (c *CostingConfig) Validate() error {
+	if c.Category == "" {
+		return ErrCategoryRequired
+	}
+
+	if !IsValidCostingMethod(c.Method) {
+		return ErrInvalidCostingMethod
+	}
+
+	return nil
+}
+
+// IsValidCostingMethod 检查计价方法是否合法
+func IsValidCostingMethod(method string) bool {
+	return method == CostingMethodFIFO || method == CostingMethodMovingAverage
+}
+
+// MovingAverageCost 计算移动加权平均单价
+func MovingAverageCost(batches []*Inventory) float64 {
+	var quantity, value float64
+	for _, b := range batches {
+		quantity += b.Quantity
+		value += b.TotalCost
+	}
+
+	if quantity <= 0 {
+		return 0
+	}
+	return value / quantity
+}
@@ -11,8 +11,36 @@ var (
 	ErrUnitRequired           = errors.New("单位不能为空")
 	ErrInvalidUnitCost        = errors.New("单价不能为负数")
 	ErrInvalidTotalCost       = errors.New("总成本不能为负数")
+	ErrInvalidCostingMethod   = errors.New("计价方法必须为 fifo 或 moving_average")
 
 	// 业务错误
 	ErrInventoryNotFound      = errors.New("库存不存在")
 	ErrInsufficientInventory  = errors.New("库存不足")
+	ErrCostingConfigNotFound  = errors.New("计价配置不存在")
+	ErrNegativeInventory      = errors.New("调整后库存不能为负数")
+
+	// 生产与追溯错误
+	ErrInvalidOutputCategory = errors.New("产出批次类别必须为半成品或成品")
+	ErrBatchIDDuplicate      = errors.New("批次ID已存在")
+	ErrInvalidProductionQty  = errors.New("产出总数量必须大于0")
+
+	// 盘点错误
+	ErrStocktakeNotFound       = errors.New("盘点单不存在")
+	ErrStocktakeScopeRequired  = errors.New("仓库和类别至少指定一个")
+	ErrStocktakeNoBatches      = errors.New("盘点范围内没有库存批次")
+	ErrStocktakeNotCounting    = errors.New("盘点单不在盘点中状态")
+	ErrStocktakeLineNotFound   = errors.New("批次不在该盘点单范围内")
+	ErrStocktakeUncounted      = errors.New("存在未盘点的批次")
+	ErrStocktakeReasonRequired = errors.New("差异调整原因不能为空")
+	ErrBatchInStocktake        = errors.New("批次已在其他进行中的盘点单中")
+	ErrInvalidCountedQuantity  = errors.New("实盘数量不能为负数")
+
+	// 补货与预留错误
+	ErrInvalidReorderPoint    = errors.New("补货点不能为负数")
+	ErrInvalidTargetLevel     = errors.New("目标库存必须大于补货点")
+	ErrReorderRuleNotFound    = errors.New("补货规则不存在")
+	ErrOrderIDRequired        = errors.New("订单ID不能为空")
+	ErrReservationNotFound    = errors.New("库存预留不存在")
+	ErrReservationNotActive   = errors.New("库存预留已失效")
+	ErrInsufficientAvailable  = errors.New("可用库存不足（已扣除其他订单预留）")
 )
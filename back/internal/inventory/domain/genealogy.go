@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// BatchLink 批次谱系关系（生产中父批次被消耗、产出子批次）
+type BatchLink struct {
+	ID                uint      `gorm:"primaryKey" json:"id"`
+	ParentInventoryID uint      `gorm:"not null;index" json:"parentInventoryId"` // 投入批次库存ID
+	ParentBatchID     string    `gorm:"size:100;not null" json:"parentBatchId"`  // 投入批次ID
+	ChildInventoryID  uint      `gorm:"not null;index" json:"childInventoryId"`  // 产出批次库存ID
+	ChildBatchID      string    `gorm:"size:100;not null" json:"childBatchId"`   // 产出批次ID
+	Quantity          float64   `gorm:"not null" json:"quantity"`                // 分摊到该产出批次的投入数量
+	Cost              float64   `gorm:"not null" json:"cost"`                    // 分摊到该产出批次的投入成本
+	OrderID           uint      `gorm:"index" json:"orderId"`                    // 生产订单ID
+	CreatedAt         time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+// TableName 表名
+func (BatchLink) TableName() string {
+	return "inventory_batch_links"
+}
+
+// 追溯方向常量
+const (
+	TraceDirectionBackward = "backward" // 向上游追溯（成品 → 原料）
+	TraceDirectionForward  = "forward"  // 向下游追溯（原料 → 成品）
+)
+
+// MaxTraceDepth 追溯最大层级
+const MaxTraceDepth = 20
+
+// IsProducedCategory 是否为可由生产产出的类别
+func IsProducedCategory(category string) bool {
+	return category == CategorySemiFinished || category == CategoryFinished
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"fmt"
 	"time"
 
 	"gorm.io/gorm"
@@ -25,6 +26,9 @@ type Inventory struct {
 	ProductID   uint           `gorm:"not null;index" json:"productId"`        // 产品ID
 	Category    string         `gorm:"size:50;not null;index" json:"category"` // 类别
 	BatchID     string         `gorm:"size:100;not null;index" json:"batchId"` // 批次ID
+	Warehouse   string         `gorm:"size:100;index" json:"warehouse"`        // 仓库
+	SupplierID  uint           `gorm:"index" json:"supplierId"`                // 供应商ID（采购入库批次）
+	OrderID     uint           `gorm:"index" json:"orderId"`                   // 生产订单ID（生产产出批次）
 	Quantity    float64        `gorm:"not null" json:"quantity"`               // 数量
 	Unit        string         `gorm:"size:20;not null" json:"unit"`           // 单位（米或kg）
 	UnitCost    float64        `gorm:"not null" json:"unitCost"`               // 单价
@@ -125,3 +129,45 @@ func (i *Inventory) Add(quantity float64) error {
 	i.CalculateTotalCost()
 	return nil
 }
+
+
+// Adjust 按差异调整数量（允许调整为0，不允许为负）
+func (i *Inventory) Adjust(delta float64) error {
+	if i.Quantity+delta < 0 {
+		return ErrNegativeInventory
+	}
+
+	i.Quantity += delta
+	i.CalculateTotalCost()
+	return nil
+}
+
+// ToDocument 转换为 ES 文档（小驼峰字段名）
+func (i *Inventory) ToDocument() map[string]interface{} {
+	return map[string]interface{}{
+		"id":         i.ID,
+		"productId":  i.ProductID,
+		"category":   i.Category,
+		"batchId":    i.BatchID,
+		"warehouse":  i.Warehouse,
+		"supplierId": i.SupplierID,
+		"orderId":    i.OrderID,
+		"quantity":   i.Quantity,
+		"unit":       i.Unit,
+		"unitCost":   i.UnitCost,
+		"totalCost":  i.TotalCost,
+		"remark":     i.Remark,
+		"createdAt":  i.CreatedAt,
+		"updatedAt":  i.UpdatedAt,
+	}
+}
+
+// GetIndexName ES 索引名称
+func (i *Inventory) GetIndexName() string {
+	return "inventory"
+}
+
+// GetDocumentID ES 文档 ID
+func (i *Inventory) GetDocumentID() string {
+	return fmt.Sprintf("%d", i.ID)
+}
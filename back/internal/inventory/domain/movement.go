@@ -0,0 +1,67 @@
+package domain
+
+import "time"
+
+// 库存流水类型常量
+const (
+	MovementTypeReceipt    = "receipt"    // 入库
+	MovementTypeIssue      = "issue"      // 出库
+	MovementTypeAdjustment = "adjustment" // 调整
+	MovementTypeOpening    = "opening"    // 期初（启用计价前已有的结存）
+)
+
+// InventoryMovement 库存流水（数量与金额带符号：入库为正，出库为负）
+type InventoryMovement struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	InventoryID   uint      `gorm:"not null;index" json:"inventoryId"`      // 库存批次记录ID
+	ProductID     uint      `gorm:"not null;index" json:"productId"`        // 产品ID
+	Category      string    `gorm:"size:50;not null;index" json:"category"` // 类别
+	BatchID       string    `gorm:"size:100;not null;index" json:"batchId"` // 批次ID
+	Type          string    `gorm:"size:20;not null;index" json:"type"`     // 流水类型
+	Quantity      float64   `gorm:"not null" json:"quantity"`               // 数量变化
+	UnitCost      float64   `gorm:"not null" json:"unitCost"`               // 单位成本
+	TotalCost     float64   `gorm:"not null" json:"totalCost"`              // 金额变化
+	CostingMethod string    `gorm:"size:20" json:"costingMethod"`           // 计价方法
+	OrderID       uint      `gorm:"index" json:"orderId"`                   // 关联订单ID（出库领料时）
+	StocktakeID   uint      `gorm:"index" json:"stocktakeId"`               // 关联盘点单ID（盘点调整或盘点期间发生的流水）
+	DuringCount   bool      `gorm:"default:false" json:"duringCount"`       // 是否发生在盘点锁定期间
+	Remark        string    `gorm:"size:500" json:"remark"`                 // 备注
+	CreatedAt     time.Time `gorm:"autoCreateTime;index" json:"createdAt"`
+}
+
+// TableName 表名
+func (InventoryMovement) TableName() string {
+	return "inventory_movements"
+}
+
+// NewMovement 根据库存批次创建流水
+func NewMovement(inventory *Inventory, movementType string, quantity, unitCost float64, remark string) *InventoryMovement {
+	return &InventoryMovement{
+		InventoryID: inventory.ID,
+		ProductID:   inventory.ProductID,
+		Category:    inventory.Category,
+		BatchID:     inventory.BatchID,
+		Type:        movementType,
+		Quantity:    quantity,
+		UnitCost:    unitCost,
+		TotalCost:   quantity * unitCost,
+		Remark:      remark,
+	}
+}
+
+// MovementFilter 库存流水查询条件
+type MovementFilter struct {
+	InventoryID uint
+	ProductID   uint
+	Category    string
+	Type        string
+	OrderID     uint
+}
+
+// ValuationRow 库存估值汇总行
+type ValuationRow struct {
+	ProductID uint    `json:"productId"`
+	Category  string  `json:"category"`
+	Quantity  float64 `json:"quantity"`
+	Value     float64 `json:"value"`
+}
@@ -0,0 +1,183 @@
+package domain
+
+import "time"
+
+// 预留状态常量
+const (
+	ReservationStatusActive    = "active"    // 有效
+	ReservationStatusReleased  = "released"  // 已释放
+	ReservationStatusFulfilled = "fulfilled" // 已领用完毕
+)
+
+// ReorderRule 补货规则（按产品/原料与类别）
+type ReorderRule struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	ProductID    uint      `gorm:"not null;uniqueIndex:idx_reorder_rule_item" json:"productId"`        // 产品ID（原料类别为原料ID）
+	Category     string    `gorm:"size:50;not null;uniqueIndex:idx_reorder_rule_item" json:"category"` // 库存类别
+	ReorderPoint float64   `gorm:"type:decimal(12,2);not null" json:"reorderPoint"`                    // 补货点
+	TargetLevel  float64   `gorm:"type:decimal(12,2);not null" json:"targetLevel"`                     // 目标库存
+	Enabled      bool      `gorm:"default:true" json:"enabled"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+// TableName 表名
+func (ReorderRule) TableName() string {
+	return "inventory_reorder_rules"
+}
+
+// Validate 验证补货规则
+func (r *ReorderRule) Validate() error {
+	if r.ProductID == 0 {
+		return ErrProductIDRequired
+	}
+
+	if r.Category == "" {
+		return ErrCategoryRequired
+	}
+
+	if r.ReorderPoint < 0 {
+		return ErrInvalidReorderPoint
+	}
+
+	if r.TargetLevel <= r.ReorderPoint {
+		return ErrInvalidTargetLevel
+	}
+
+	return nil
+}
+
+// SuggestQuantity 根据预计可用量计算建议补货量（未低于补货点时返回 0）
+func (r *ReorderRule) SuggestQuantity(projected float64) float64 {
+	if projected >= r.ReorderPoint {
+		return 0
+	}
+	return r.TargetLevel - projected
+}
+
+// InventoryReservation 库存预留（为订单锁定可用量，不绑定具体批次）
+type InventoryReservation struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	ProductID  uint       `gorm:"not null;index:idx_reservation_item" json:"productId"`
+	Category   string     `gorm:"size:50;not null;index:idx_reservation_item" json:"category"`
+	OrderID    uint       `gorm:"not null;index" json:"orderId"`
+	Quantity   float64    `gorm:"type:decimal(12,2);not null" json:"quantity"` // 剩余预留数量
+	Status     string     `gorm:"size:20;not null;default:active;index" json:"status"`
+	Remark     string     `gorm:"type:text" json:"remark"`
+	ReleasedAt *time.Time `json:"releasedAt"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt  time.Time  `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+// TableName 表名
+func (InventoryReservation) TableName() string {
+	return "inventory_reservations"
+}
+
+// NewReservation 创建库存预留
+func NewReservation(productID uint, category string, orderID uint, quantity float64, remark string) (*InventoryReservation, error) {
+	if productID == 0 {
+		return nil, ErrProductIDRequired
+	}
+	if category == "" {
+		return nil, ErrCategoryRequired
+	}
+	if orderID == 0 {
+		return nil, ErrOrderIDRequired
+	}
+	if quantity <= 0 {
+		return nil, ErrInvalidQuantity
+	}
+
+	return &InventoryReservation{
+		ProductID: productID,
+		Category:  category,
+		OrderID:   orderID,
+		Quantity:  quantity,
+		Status:    ReservationStatusActive,
+		Remark:    remark,
+	}, nil
+}
+
+// IsActive 是否有效
+func (r *InventoryReservation) IsActive() bool {
+	return r.Status == ReservationStatusActive
+}
+
+// Release 释放预留
+func (r *InventoryReservation) Release() error {
+	if !r.IsActive() {
+		return ErrReservationNotActive
+	}
+
+	now := time.Now()
+	r.Status = ReservationStatusReleased
+	r.ReleasedAt = &now
+	return nil
+}
+
+// Consume 领用预留数量，返回实际抵扣的数量
+func (r *InventoryReservation) Consume(quantity float64) float64 {
+	if !r.IsActive() || quantity <= 0 {
+		return 0
+	}
+
+	taken := quantity
+	if taken > r.Quantity {
+		taken = r.Quantity
+	}
+	r.Quantity -= taken
+	if r.Quantity <= 0 {
+		r.Status = ReservationStatusFulfilled
+	}
+	return taken
+}
+
+// ReservedRow 按产品汇总的预留数量
+type ReservedRow struct {
+	ProductID uint
+	Category  string
+	OrderID   uint
+	Quantity  float64
+}
+
+// IssuedRow 按产品与订单汇总的已领用数量
+type IssuedRow struct {
+	ProductID uint
+	Category  string
+	OrderID   uint
+	Quantity  float64
+}
+
+// OnHandRow 按产品汇总的在库数量
+type OnHandRow struct {
+	ProductID uint
+	Category  string
+	Quantity  float64
+}
+
+// ReplenishmentSuggestion 补货建议（每次运行生成一批）
+type ReplenishmentSuggestion struct {
+	ID                uint      `gorm:"primaryKey" json:"id"`
+	RunAt             time.Time `gorm:"not null;index" json:"runAt"` // 运行批次时间
+	ProductID         uint      `gorm:"not null;index" json:"productId"`
+	Category          string    `gorm:"size:50;not null" json:"category"`
+	OnHand            float64   `gorm:"type:decimal(12,2)" json:"onHand"`            // 在库数量
+	Reserved          float64   `gorm:"type:decimal(12,2)" json:"reserved"`          // 已预留数量
+	Available         float64   `gorm:"type:decimal(12,2)" json:"available"`         // 可用数量（在库 - 预留）
+	OpenDemand        float64   `gorm:"type:decimal(12,2)" json:"openDemand"`        // 未完结订单中尚未预留的需求
+	Projected         float64   `gorm:"type:decimal(12,2)" json:"projected"`         // 预计可用量（可用 - 需求）
+	ReorderPoint      float64   `gorm:"type:decimal(12,2)" json:"reorderPoint"`      // 补货点
+	TargetLevel       float64   `gorm:"type:decimal(12,2)" json:"targetLevel"`       // 目标库存
+	SuggestedQuantity float64   `gorm:"type:decimal(12,2)" json:"suggestedQuantity"` // 建议补货量
+	SupplierID        uint      `json:"supplierId"`                                  // 推荐供应商（缓存最低价）
+	SupplierName      string    `gorm:"size:100" json:"supplierName"`
+	UnitPrice         float64   `gorm:"type:decimal(12,2)" json:"unitPrice"` // 本位币单价
+	EstimatedCost     float64   `gorm:"type:decimal(12,2)" json:"estimatedCost"`
+	CreatedAt         time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+// TableName 表名
+func (ReplenishmentSuggestion) TableName() string {
+	return "inventory_replenishment_suggestions"
+}
@@ -0,0 +1,176 @@
+package domain
+
+import (
+	"time"
+
+	"back/pkg/serial"
+)
+
+// 盘点单状态常量
+const (
+	StocktakeStatusCounting  = "counting"  // 盘点中（锁定）
+	StocktakeStatusApproved  = "approved"  // 已审批（差异已过账）
+	StocktakeStatusCancelled = "cancelled" // 已取消
+)
+
+// 盘点数据来源常量
+const (
+	CountSourceWeb    = "web"    // PC 端
+	CountSourceMobile = "mobile" // 移动端
+)
+
+// StocktakeSession 盘点单聚合根
+type StocktakeSession struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	SessionNo    string     `gorm:"size:50;uniqueIndex;not null" json:"sessionNo"` // 盘点单号
+	Warehouse    string     `gorm:"size:100;index" json:"warehouse"`               // 盘点仓库（为空表示不限）
+	Category     string     `gorm:"size:50;index" json:"category"`                 // 盘点类别（为空表示不限）
+	Status       string     `gorm:"size:20;not null;index" json:"status"`          // 状态
+	Reason       string     `gorm:"size:500" json:"reason"`                        // 差异调整原因（审批时填写）
+	CreatedBy    uint       `gorm:"not null" json:"createdBy"`
+	CreatorName  string     `gorm:"size:100" json:"creatorName"`
+	ApprovedBy   uint       `json:"approvedBy"`
+	ApproverName string     `gorm:"size:100" json:"approverName"`
+	ApprovedAt   *time.Time `json:"approvedAt,omitempty"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt    time.Time  `gorm:"autoUpdateTime" json:"updatedAt"`
+
+	Lines []StocktakeLine `gorm:"foreignKey:SessionID;constraint:OnDelete:CASCADE" json:"lines,omitempty"`
+}
+
+// TableName 表名
+func (StocktakeSession) TableName() string {
+	return "inventory_stocktake_sessions"
+}
+
+// StocktakeLine 盘点明细（按批次）
+type StocktakeLine struct {
+	ID               uint       `gorm:"primaryKey" json:"id"`
+	SessionID        uint       `gorm:"not null;index" json:"sessionId"`
+	InventoryID      uint       `gorm:"not null;index" json:"inventoryId"`
+	ProductID        uint       `gorm:"not null" json:"productId"`
+	Category         string     `gorm:"size:50;not null" json:"category"`
+	Warehouse        string     `gorm:"size:100" json:"warehouse"`
+	BatchID          string     `gorm:"size:100;not null" json:"batchId"`
+	Unit             string     `gorm:"size:20" json:"unit"`
+	UnitCost         float64    `gorm:"not null" json:"unitCost"`         // 快照单价
+	ExpectedQuantity float64    `gorm:"not null" json:"expectedQuantity"` // 快照账面数量
+	BookQuantity     float64    `json:"bookQuantity"`                     // 盘点时账面数量（快照 + 截至盘点时的出入库）
+	CountedQuantity  *float64   `json:"countedQuantity,omitempty"`        // 实盘数量（未盘点为空）
+	Variance         float64    `json:"variance"`                         // 差异数量（实盘 - 盘点时账面）
+	VarianceValue    float64    `json:"varianceValue"`                    // 差异金额
+	CountedBy        uint       `json:"countedBy"`
+	CounterName      string     `gorm:"size:100" json:"counterName"`
+	CountSource      string     `gorm:"size:20" json:"countSource"` // 盘点数据来源（web/mobile）
+	CountedAt        *time.Time `json:"countedAt,omitempty"`
+	MovedDuringCount bool       `gorm:"default:false" json:"movedDuringCount"` // 盘点期间是否发生过库存变动
+	Remark           string     `gorm:"size:500" json:"remark"`
+}
+
+// TableName 表名
+func (StocktakeLine) TableName() string {
+	return "inventory_stocktake_lines"
+}
+
+// NewStocktakeSession 根据账面批次快照创建盘点单
+func NewStocktakeSession(warehouse, category string, batches []*Inventory, creatorID uint, creatorName string) (*StocktakeSession, error) {
+	if warehouse == "" && category == "" {
+		return nil, ErrStocktakeScopeRequired
+	}
+	if len(batches) == 0 {
+		return nil, ErrStocktakeNoBatches
+	}
+
+	session := &StocktakeSession{
+		SessionNo:   serial.New("ST", time.Now()),
+		Warehouse:   warehouse,
+		Category:    category,
+		Status:      StocktakeStatusCounting,
+		CreatedBy:   creatorID,
+		CreatorName: creatorName,
+		Lines:       make([]StocktakeLine, len(batches)),
+	}
+	for i, b := range batches {
+		session.Lines[i] = StocktakeLine{
+			InventoryID:      b.ID,
+			ProductID:        b.ProductID,
+			Category:         b.Category,
+			Warehouse:        b.Warehouse,
+			BatchID:          b.BatchID,
+			Unit:             b.Unit,
+			UnitCost:         b.UnitCost,
+			ExpectedQuantity: b.Quantity,
+		}
+	}
+	return session, nil
+}
+
+// IsCounting 是否盘点中
+func (s *StocktakeSession) IsCounting() bool {
+	return s.Status == StocktakeStatusCounting
+}
+
+// FindLine 根据库存ID查找盘点明细
+func (s *StocktakeSession) FindLine(inventoryID uint) *StocktakeLine {
+	for i := range s.Lines {
+		if s.Lines[i].InventoryID == inventoryID {
+			return &s.Lines[i]
+		}
+	}
+	return nil
+}
+
+// Approve 审批盘点单（所有批次须已盘点）
+func (s *StocktakeSession) Approve(approverID uint, approverName, reason string) error {
+	if !s.IsCounting() {
+		return ErrStocktakeNotCounting
+	}
+	if reason == "" {
+		return ErrStocktakeReasonRequired
+	}
+	for _, l := range s.Lines {
+		if l.CountedQuantity == nil {
+			return ErrStocktakeUncounted
+		}
+	}
+
+	now := time.Now()
+	s.Status = StocktakeStatusApproved
+	s.Reason = reason
+	s.ApprovedBy = approverID
+	s.ApproverName = approverName
+	s.ApprovedAt = &now
+	return nil
+}
+
+// Cancel 取消盘点单
+func (s *StocktakeSession) Cancel() error {
+	if !s.IsCounting() {
+		return ErrStocktakeNotCounting
+	}
+
+	s.Status = StocktakeStatusCancelled
+	return nil
+}
+
+// RecordCount 录入实盘数量，按盘点时账面计算差异（movedQuantity 为快照后至盘点时的出入库数量）
+func (l *StocktakeLine) RecordCount(counted, movedQuantity float64, counterID uint, counterName, source, remark string) error {
+	if counted < 0 {
+		return ErrInvalidCountedQuantity
+	}
+	if source == "" {
+		source = CountSourceWeb
+	}
+
+	now := time.Now()
+	l.CountedQuantity = &counted
+	l.BookQuantity = l.ExpectedQuantity + movedQuantity
+	l.Variance = counted - l.BookQuantity
+	l.VarianceValue = l.Variance * l.UnitCost
+	l.CountedBy = counterID
+	l.CounterName = counterName
+	l.CountSource = source
+	l.CountedAt = &now
+	l.Remark = remark
+	return nil
+}
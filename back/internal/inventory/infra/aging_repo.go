@@ -0,0 +1,42 @@
+package infra
+
+import (
+	"context"
+
+	"back/internal/inventory/domain"
+)
+
+// ==================== 库龄 ====================
+
+// FindAgingRows 查询批次及其最近一次出库时间（includeDepleted 为 false 时仅返回有结存的批次）
+func (r *InventoryRepo) FindAgingRows(ctx context.Context, filter *domain.AgingFilter, includeDepleted bool) ([]domain.AgingRow, error) {
+	lastIssue := r.db.Model(&domain.InventoryMovement{}).
+		Select("inventory_id, MAX(created_at) AS last_issue_at").
+		Where("type = ?", domain.MovementTypeIssue).
+		Group("inventory_id")
+
+	query := r.db.WithContext(ctx).
+		Table("inventories").
+		Select("inventories.*, li.last_issue_at").
+		Joins("LEFT JOIN (?) li ON li.inventory_id = inventories.id", lastIssue).
+		Where("inventories.deleted_at IS NULL")
+
+	if !includeDepleted {
+		query = query.Where("inventories.quantity > 0")
+	}
+	if filter != nil {
+		if filter.ProductID > 0 {
+			query = query.Where("inventories.product_id = ?", filter.ProductID)
+		}
+		if filter.Category != "" {
+			query = query.Where("inventories.category = ?", filter.Category)
+		}
+		if filter.Warehouse != "" {
+			query = query.Where("inventories.warehouse = ?", filter.Warehouse)
+		}
+	}
+
+	var rows []domain.AgingRow
+	err := query.Order("inventories.created_at ASC, inventories.id ASC").Scan(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,98 @@
+package infra
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"back/internal/inventory/domain"
+)
+
+// ==================== 批次谱系 ====================
+
+// FindByIDForUpdate 根据 ID 查询并加行锁
+func (r *InventoryRepo) FindByIDForUpdate(ctx context.Context, id uint) (*domain.Inventory, error) {
+	var inventory domain.Inventory
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&inventory, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrInventoryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &inventory, nil
+}
+
+// ExistsByBatchID 检查批次ID是否存在
+func (r *InventoryRepo) ExistsByBatchID(ctx context.Context, batchID string) (bool, error) {
+	return r.Exists(ctx, map[string]interface{}{"batch_id": batchID})
+}
+
+// FindByIDUnscoped 根据 ID 查询批次（含已删除，用于追溯）
+func (r *InventoryRepo) FindByIDUnscoped(ctx context.Context, id uint) (*domain.Inventory, error) {
+	var inventory domain.Inventory
+	err := r.db.WithContext(ctx).Unscoped().First(&inventory, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrInventoryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &inventory, nil
+}
+
+// FindByBatchIDUnscoped 根据批次 ID 查询批次（含已删除，用于追溯；批次号被复用时优先未删除的批次）
+func (r *InventoryRepo) FindByBatchIDUnscoped(ctx context.Context, batchID string) (*domain.Inventory, error) {
+	var inventory domain.Inventory
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Where("batch_id = ?", batchID).
+		Order("deleted_at IS NOT NULL, id DESC").
+		First(&inventory).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrInventoryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &inventory, nil
+}
+
+// FindByIDsUnscoped 批量查询批次（含已删除，用于追溯）
+func (r *InventoryRepo) FindByIDsUnscoped(ctx context.Context, ids []uint) ([]domain.Inventory, error) {
+	var inventories []domain.Inventory
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Where("id IN ?", ids).
+		Find(&inventories).Error
+	return inventories, err
+}
+
+// CreateBatchLink 创建批次谱系关系
+func (r *InventoryRepo) CreateBatchLink(ctx context.Context, link *domain.BatchLink) error {
+	return r.db.WithContext(ctx).Create(link).Error
+}
+
+// FindLinksByChildIDs 查询产出批次的投入关系（向上游）
+func (r *InventoryRepo) FindLinksByChildIDs(ctx context.Context, childIDs []uint) ([]domain.BatchLink, error) {
+	var links []domain.BatchLink
+	err := r.db.WithContext(ctx).
+		Where("child_inventory_id IN ?", childIDs).
+		Order("id ASC").
+		Find(&links).Error
+	return links, err
+}
+
+// FindLinksByParentIDs 查询投入批次的产出关系（向下游）
+func (r *InventoryRepo) FindLinksByParentIDs(ctx context.Context, parentIDs []uint) ([]domain.BatchLink, error) {
+	var links []domain.BatchLink
+	err := r.db.WithContext(ctx).
+		Where("parent_inventory_id IN ?", parentIDs).
+		Order("id ASC").
+		Find(&links).Error
+	return links, err
+}
@@ -3,8 +3,10 @@ package infra
 import (
 	"context"
 	"errors"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"back/internal/inventory/domain"
 	"back/pkg/repo"
@@ -119,3 +121,129 @@ func (r *InventoryRepo) FindByCategory(ctx context.Context, category string, lim
 	}
 	return result, nil
 }
+
+// ==================== 事务 ====================
+
+// Transaction 执行事务（回调中的仓储绑定同一事务）
+func (r *InventoryRepo) Transaction(ctx context.Context, fn func(txRepo *InventoryRepo) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(NewInventoryRepo(tx))
+	})
+}
+
+// TxContext 将仓储绑定的事务放入上下文，供其他模块的仓储加入同一事务
+func (r *InventoryRepo) TxContext(ctx context.Context) context.Context {
+	return repo.WithTx(ctx, r.db)
+}
+
+// FindOnHandForUpdate 查询产品在某类别下有结存的批次（按入库先后排序并加行锁）
+func (r *InventoryRepo) FindOnHandForUpdate(ctx context.Context, productID uint, category string) ([]*domain.Inventory, error) {
+	var inventories []domain.Inventory
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("product_id = ? AND category = ? AND quantity > 0", productID, category).
+		Order("created_at ASC, id ASC").
+		Find(&inventories).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.Inventory, len(inventories))
+	for i := range inventories {
+		result[i] = &inventories[i]
+	}
+	return result, nil
+}
+
+// ==================== 计价配置 ====================
+
+// FindCostingConfig 根据类别查询计价配置
+func (r *InventoryRepo) FindCostingConfig(ctx context.Context, category string) (*domain.CostingConfig, error) {
+	var config domain.CostingConfig
+	err := r.db.WithContext(ctx).
+		Where("category = ?", category).
+		First(&config).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrCostingConfigNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// SaveCostingConfig 保存计价配置
+func (r *InventoryRepo) SaveCostingConfig(ctx context.Context, config *domain.CostingConfig) error {
+	return r.db.WithContext(ctx).Save(config).Error
+}
+
+// FindAllCostingConfigs 查询所有计价配置
+func (r *InventoryRepo) FindAllCostingConfigs(ctx context.Context) ([]domain.CostingConfig, error) {
+	var configs []domain.CostingConfig
+	err := r.db.WithContext(ctx).Order("category ASC").Find(&configs).Error
+	return configs, err
+}
+
+// ==================== 库存流水 ====================
+
+// CreateMovement 创建库存流水
+func (r *InventoryRepo) CreateMovement(ctx context.Context, movement *domain.InventoryMovement) error {
+	return r.db.WithContext(ctx).Create(movement).Error
+}
+
+// FindMovements 查询库存流水
+func (r *InventoryRepo) FindMovements(ctx context.Context, filter *domain.MovementFilter, limit, offset int) ([]domain.InventoryMovement, int64, error) {
+	var movements []domain.InventoryMovement
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&domain.InventoryMovement{})
+	if filter.InventoryID > 0 {
+		query = query.Where("inventory_id = ?", filter.InventoryID)
+	}
+	if filter.ProductID > 0 {
+		query = query.Where("product_id = ?", filter.ProductID)
+	}
+	if filter.Category != "" {
+		query = query.Where("category = ?", filter.Category)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.OrderID > 0 {
+		query = query.Where("order_id = ?", filter.OrderID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&movements).Error
+	return movements, total, err
+}
+
+// GetValuation 按产品和类别汇总截至某时间点的库存结存（数量与金额）
+func (r *InventoryRepo) GetValuation(ctx context.Context, before time.Time, productID uint, category string) ([]domain.ValuationRow, error) {
+	var rows []domain.ValuationRow
+
+	query := r.db.WithContext(ctx).
+		Model(&domain.InventoryMovement{}).
+		Select("product_id, category, SUM(quantity) AS quantity, SUM(total_cost) AS value").
+		Where("created_at < ?", before)
+	if productID > 0 {
+		query = query.Where("product_id = ?", productID)
+	}
+	if category != "" {
+		query = query.Where("category = ?", category)
+	}
+
+	err := query.
+		Group("product_id, category").
+		Order("product_id ASC, category ASC").
+		Scan(&rows).Error
+	return rows, err
+}
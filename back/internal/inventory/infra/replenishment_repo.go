@@ -0,0 +1,222 @@
+package infra
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"back/internal/inventory/domain"
+)
+
+// ==================== 补货规则 ====================
+
+// FindReorderRules 查询补货规则
+func (r *InventoryRepo) FindReorderRules(ctx context.Context, enabledOnly bool) ([]domain.ReorderRule, error) {
+	var rules []domain.ReorderRule
+	query := r.db.WithContext(ctx)
+	if enabledOnly {
+		query = query.Where("enabled = ?", true)
+	}
+	err := query.Order("category ASC, product_id ASC").Find(&rules).Error
+	return rules, err
+}
+
+// FindReorderRule 根据产品与类别查询补货规则
+func (r *InventoryRepo) FindReorderRule(ctx context.Context, productID uint, category string) (*domain.ReorderRule, error) {
+	var rule domain.ReorderRule
+	err := r.db.WithContext(ctx).
+		Where("product_id = ? AND category = ?", productID, category).
+		First(&rule).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrReorderRuleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// SaveReorderRule 保存补货规则（新增或更新）
+func (r *InventoryRepo) SaveReorderRule(ctx context.Context, rule *domain.ReorderRule) error {
+	return r.db.WithContext(ctx).Save(rule).Error
+}
+
+// DeleteReorderRule 删除补货规则
+func (r *InventoryRepo) DeleteReorderRule(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&domain.ReorderRule{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrReorderRuleNotFound
+	}
+	return nil
+}
+
+// ==================== 库存预留 ====================
+
+// CreateReservation 创建库存预留
+func (r *InventoryRepo) CreateReservation(ctx context.Context, reservation *domain.InventoryReservation) error {
+	return r.db.WithContext(ctx).Create(reservation).Error
+}
+
+// UpdateReservation 更新库存预留
+func (r *InventoryRepo) UpdateReservation(ctx context.Context, reservation *domain.InventoryReservation) error {
+	return r.db.WithContext(ctx).Save(reservation).Error
+}
+
+// FindReservationByID 根据 ID 查询库存预留
+func (r *InventoryRepo) FindReservationByID(ctx context.Context, id uint) (*domain.InventoryReservation, error) {
+	var reservation domain.InventoryReservation
+	err := r.db.WithContext(ctx).First(&reservation, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrReservationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// FindReservations 查询库存预留（条件为零值时不过滤）
+func (r *InventoryRepo) FindReservations(ctx context.Context, productID, orderID uint, status string, limit, offset int) ([]domain.InventoryReservation, int64, error) {
+	query := r.db.WithContext(ctx).Model(&domain.InventoryReservation{})
+	if productID > 0 {
+		query = query.Where("product_id = ?", productID)
+	}
+	if orderID > 0 {
+		query = query.Where("order_id = ?", orderID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	var reservations []domain.InventoryReservation
+	err := query.Order("created_at DESC").Find(&reservations).Error
+	return reservations, total, err
+}
+
+// FindActiveReservationsForUpdate 查询订单在某产品上的有效预留（加行锁）
+func (r *InventoryRepo) FindActiveReservationsForUpdate(ctx context.Context, productID uint, category string, orderID uint) ([]domain.InventoryReservation, error) {
+	var reservations []domain.InventoryReservation
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("product_id = ? AND category = ? AND order_id = ? AND status = ?",
+			productID, category, orderID, domain.ReservationStatusActive).
+		Order("created_at ASC, id ASC").
+		Find(&reservations).Error
+	return reservations, err
+}
+
+// SumReserved 汇总产品的有效预留数量（excludeOrderID 大于 0 时排除该订单）
+func (r *InventoryRepo) SumReserved(ctx context.Context, productID uint, category string, excludeOrderID uint) (float64, error) {
+	query := r.db.WithContext(ctx).
+		Model(&domain.InventoryReservation{}).
+		Where("product_id = ? AND category = ? AND status = ?", productID, category, domain.ReservationStatusActive)
+	if excludeOrderID > 0 {
+		query = query.Where("order_id <> ?", excludeOrderID)
+	}
+
+	var total float64
+	err := query.Select("COALESCE(SUM(quantity), 0)").Scan(&total).Error
+	return total, err
+}
+
+// SumOnHand 汇总产品的在库数量
+func (r *InventoryRepo) SumOnHand(ctx context.Context, productID uint, category string) (float64, error) {
+	var total float64
+	err := r.db.WithContext(ctx).
+		Model(&domain.Inventory{}).
+		Where("product_id = ? AND category = ?", productID, category).
+		Select("COALESCE(SUM(quantity), 0)").
+		Scan(&total).Error
+	return total, err
+}
+
+// SumOnHandByItem 按产品与类别汇总在库数量
+func (r *InventoryRepo) SumOnHandByItem(ctx context.Context) ([]domain.OnHandRow, error) {
+	var rows []domain.OnHandRow
+	err := r.db.WithContext(ctx).
+		Model(&domain.Inventory{}).
+		Select("product_id, category, SUM(quantity) AS quantity").
+		Group("product_id, category").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// SumReservedByItem 按产品、类别与订单汇总有效预留数量
+func (r *InventoryRepo) SumReservedByItem(ctx context.Context) ([]domain.ReservedRow, error) {
+	var rows []domain.ReservedRow
+	err := r.db.WithContext(ctx).
+		Model(&domain.InventoryReservation{}).
+		Select("product_id, category, order_id, SUM(quantity) AS quantity").
+		Where("status = ?", domain.ReservationStatusActive).
+		Group("product_id, category, order_id").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// SumIssuedByOrder 按产品、类别与订单汇总已出库（领料、生产投入）数量
+func (r *InventoryRepo) SumIssuedByOrder(ctx context.Context, orderIDs []uint) ([]domain.IssuedRow, error) {
+	var rows []domain.IssuedRow
+	if len(orderIDs) == 0 {
+		return rows, nil
+	}
+	err := r.db.WithContext(ctx).
+		Model(&domain.InventoryMovement{}).
+		Select("product_id, category, order_id, -SUM(quantity) AS quantity").
+		Where("type = ? AND order_id IN ?", domain.MovementTypeIssue, orderIDs).
+		Group("product_id, category, order_id").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// ==================== 补货建议 ====================
+
+// CreateSuggestions 批量保存补货建议
+func (r *InventoryRepo) CreateSuggestions(ctx context.Context, suggestions []domain.ReplenishmentSuggestion) error {
+	if len(suggestions) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&suggestions).Error
+}
+
+// FindLatestSuggestions 查询最近一次运行的评估结果（includeAll 为 false 时仅返回需要补货的条目）
+func (r *InventoryRepo) FindLatestSuggestions(ctx context.Context, category string, includeAll bool) ([]domain.ReplenishmentSuggestion, time.Time, error) {
+	var latest domain.ReplenishmentSuggestion
+	err := r.db.WithContext(ctx).Order("run_at DESC").First(&latest).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, time.Time{}, nil
+	}
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	query := r.db.WithContext(ctx).Where("run_at = ?", latest.RunAt)
+	if category != "" {
+		query = query.Where("category = ?", category)
+	}
+	if !includeAll {
+		query = query.Where("suggested_quantity > 0")
+	}
+
+	var suggestions []domain.ReplenishmentSuggestion
+	err = query.Order("category ASC, product_id ASC").Find(&suggestions).Error
+	return suggestions, latest.RunAt, err
+}
@@ -0,0 +1,137 @@
+package infra
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"back/internal/inventory/domain"
+)
+
+// ==================== 盘点 ====================
+
+// FindForStocktake 查询盘点范围内有结存的批次
+func (r *InventoryRepo) FindForStocktake(ctx context.Context, warehouse, category string) ([]*domain.Inventory, error) {
+	var inventories []domain.Inventory
+	query := r.db.WithContext(ctx).Where("quantity > 0")
+	if warehouse != "" {
+		query = query.Where("warehouse = ?", warehouse)
+	}
+	if category != "" {
+		query = query.Where("category = ?", category)
+	}
+
+	if err := query.Order("product_id ASC, id ASC").Find(&inventories).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.Inventory, len(inventories))
+	for i := range inventories {
+		result[i] = &inventories[i]
+	}
+	return result, nil
+}
+
+// CountOpenStocktakeLines 统计批次在进行中盘点单里的明细数量
+func (r *InventoryRepo) CountOpenStocktakeLines(ctx context.Context, inventoryIDs []uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&domain.StocktakeLine{}).
+		Joins("JOIN inventory_stocktake_sessions s ON s.id = inventory_stocktake_lines.session_id").
+		Where("s.status = ? AND inventory_stocktake_lines.inventory_id IN ?", domain.StocktakeStatusCounting, inventoryIDs).
+		Count(&count).Error
+	return count, err
+}
+
+// CreateStocktake 创建盘点单（含明细）
+func (r *InventoryRepo) CreateStocktake(ctx context.Context, session *domain.StocktakeSession) error {
+	return r.db.WithContext(ctx).Create(session).Error
+}
+
+// FindStocktakeByID 根据 ID 查询盘点单（含明细）
+func (r *InventoryRepo) FindStocktakeByID(ctx context.Context, id uint) (*domain.StocktakeSession, error) {
+	var session domain.StocktakeSession
+	err := r.db.WithContext(ctx).
+		Preload("Lines", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id ASC")
+		}).
+		First(&session, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrStocktakeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// FindStocktakes 查询盘点单列表
+func (r *InventoryRepo) FindStocktakes(ctx context.Context, status string, limit, offset int) ([]domain.StocktakeSession, int64, error) {
+	var sessions []domain.StocktakeSession
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&domain.StocktakeSession{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("Lines").
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&sessions).Error
+	return sessions, total, err
+}
+
+// UpdateStocktake 更新盘点单主表
+func (r *InventoryRepo) UpdateStocktake(ctx context.Context, session *domain.StocktakeSession) error {
+	return r.db.WithContext(ctx).Omit("Lines").Save(session).Error
+}
+
+// UpdateStocktakeLine 更新盘点明细
+func (r *InventoryRepo) UpdateStocktakeLine(ctx context.Context, line *domain.StocktakeLine) error {
+	return r.db.WithContext(ctx).Save(line).Error
+}
+
+// SumMovedDuringCount 汇总批次在盘点单锁定期间（至今）的出入库数量
+func (r *InventoryRepo) SumMovedDuringCount(ctx context.Context, sessionID, inventoryID uint) (float64, error) {
+	var total float64
+	err := r.db.WithContext(ctx).
+		Model(&domain.InventoryMovement{}).
+		Where("stocktake_id = ? AND inventory_id = ? AND during_count = ?", sessionID, inventoryID, true).
+		Select("COALESCE(SUM(quantity), 0)").
+		Scan(&total).Error
+	return total, err
+}
+
+// MarkMovedDuringCount 标记批次在进行中盘点单里发生了变动，返回盘点单ID（无进行中盘点返回0）
+func (r *InventoryRepo) MarkMovedDuringCount(ctx context.Context, inventoryID uint) (uint, error) {
+	var line domain.StocktakeLine
+	err := r.db.WithContext(ctx).
+		Joins("JOIN inventory_stocktake_sessions s ON s.id = inventory_stocktake_lines.session_id").
+		Where("s.status = ? AND inventory_stocktake_lines.inventory_id = ?", domain.StocktakeStatusCounting, inventoryID).
+		First(&line).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if !line.MovedDuringCount {
+		err = r.db.WithContext(ctx).
+			Model(&domain.StocktakeLine{}).
+			Where("id = ?", line.ID).
+			Update("moved_during_count", true).Error
+		if err != nil {
+			return 0, err
+		}
+	}
+	return line.SessionID, nil
+}
@@ -0,0 +1,144 @@
+package interfaces
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"back/internal/inventory/application"
+	"back/internal/inventory/domain"
+	"back/pkg/endpoint"
+	"github.com/gin-gonic/gin"
+)
+
+// AgingHandler 库龄 Handler
+type AgingHandler struct {
+	service *application.AgingService
+}
+
+// NewAgingHandler 创建 Handler
+func NewAgingHandler(service *application.AgingService) *AgingHandler {
+	return &AgingHandler{service: service}
+}
+
+// parseAgingQuery 解析库龄查询参数
+func parseAgingQuery(c *gin.Context) (*domain.AgingFilter, int, error) {
+	productID, _ := strconv.ParseUint(c.Query("productId"), 10, 32)
+	filter := &domain.AgingFilter{
+		ProductID: uint(productID),
+		Category:  c.Query("category"),
+		Warehouse: c.Query("warehouse"),
+	}
+
+	slowMovingDays := domain.DefaultSlowMovingDays
+	if days := c.Query("slowMovingDays"); days != "" {
+		parsed, err := strconv.Atoi(days)
+		if err != nil || parsed <= 0 {
+			return nil, 0, fmt.Errorf("无效的呆滞天数")
+		}
+		slowMovingDays = parsed
+	}
+
+	return filter, slowMovingDays, nil
+}
+
+// GetAgingReport 获取库龄报表
+// @Summary      获取库龄报表
+// @Description  按入库时间分段（0-30/31-90/91-180/180+ 天）汇总库存金额，按类别、产品、仓库细分，并标记超过 N 天无出库的呆滞批次
+// @Tags         库存管理
+// @Accept       json
+// @Produce      json
+// @Param        category query string false "类别"
+// @Param        productId query int false "产品ID"
+// @Param        warehouse query string false "仓库"
+// @Param        slowMovingDays query int false "呆滞天数" default(90)
+// @Success      200 {object} application.AgingReportResponse "库龄报表"
+// @Failure      400 {object} map[string]interface{} "参数错误"
+// @Security     Bearer
+// @Router       /inventory/aging [get]
+func (h *AgingHandler) GetAgingReport(c *gin.Context) {
+	filter, slowMovingDays, err := parseAgingQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.service.GetAgingReport(c.Request.Context(), filter, slowMovingDays)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ExportAgingReport 导出库龄明细
+// @Summary      导出库龄明细
+// @Description  导出批次库龄明细 CSV（查询条件同库龄报表）
+// @Tags         库存管理
+// @Produce      text/csv
+// @Param        category query string false "类别"
+// @Param        productId query int false "产品ID"
+// @Param        warehouse query string false "仓库"
+// @Param        slowMovingDays query int false "呆滞天数" default(90)
+// @Success      200 {file} file "CSV 文件"
+// @Failure      400 {object} map[string]interface{} "参数错误"
+// @Security     Bearer
+// @Router       /inventory/aging/export [get]
+func (h *AgingHandler) ExportAgingReport(c *gin.Context) {
+	filter, slowMovingDays, err := parseAgingQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filename := fmt.Sprintf("inventory_aging_%s.csv", time.Now().Format("20060102"))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+
+	if err := h.service.ExportAgingCSV(c.Request.Context(), filter, slowMovingDays, c.Writer); err != nil {
+		// 尚未写出内容时返回 JSON 错误
+		if !c.Writer.Written() {
+			c.Header("Content-Disposition", "")
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+}
+
+// RebuildAgingIndex 重建库龄搜索索引
+// @Summary      重建库龄搜索索引
+// @Description  立即重建 inventory_aging 索引（每日定时任务也会重建），供 /search 按库龄分段、呆滞标记等过滤
+// @Tags         库存管理
+// @Accept       json
+// @Produce      json
+// @Param        slowMovingDays query int false "呆滞天数" default(90)
+// @Success      200 {object} application.AgingIndexResponse "重建结果"
+// @Failure      400 {object} map[string]interface{} "参数错误"
+// @Security     Bearer
+// @Router       /inventory/aging/reindex [post]
+func (h *AgingHandler) RebuildAgingIndex(c *gin.Context) {
+	_, slowMovingDays, err := parseAgingQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.service.RebuildAgingIndex(c.Request.Context(), slowMovingDays)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetRoutes 返回路由定义
+func (h *AgingHandler) GetRoutes() []endpoint.RouteDefinition {
+	return []endpoint.RouteDefinition{
+		{Method: "GET", Path: "/inventory/aging", Handler: h.GetAgingReport, Domain: "inventory", Action: "aging"},
+		{Method: "GET", Path: "/inventory/aging/export", Handler: h.ExportAgingReport, Domain: "inventory", Action: "aging"},
+		{Method: "POST", Path: "/inventory/aging/reindex", Handler: h.RebuildAgingIndex, Domain: "inventory", Action: "aging"},
+	}
+}
@@ -0,0 +1,109 @@
+package interfaces
+
+import (
+	"net/http"
+	"strconv"
+
+	"back/internal/inventory/application"
+	"back/internal/inventory/domain"
+	"back/pkg/endpoint"
+	"github.com/gin-gonic/gin"
+)
+
+// GenealogyHandler 批次谱系 Handler
+type GenealogyHandler struct {
+	service *application.GenealogyService
+}
+
+// NewGenealogyHandler 创建 Handler
+func NewGenealogyHandler(service *application.GenealogyService) *GenealogyHandler {
+	return &GenealogyHandler{service: service}
+}
+
+// RecordProduction 记录生产投入产出
+// @Summary      记录生产投入产出
+// @Description  消耗投入批次并产出半成品/成品批次，产出成本按投入成本分摊，同时记录父子批次关系
+// @Tags         批次追溯
+// @Accept       json
+// @Produce      json
+// @Param        request body application.RecordProductionRequest true "生产记录请求"
+// @Success      200 {object} application.ProductionResponse "记录成功"
+// @Failure      400 {object} map[string]interface{} "参数错误或库存不足"
+// @Security     Bearer
+// @Router       /inventory/production [post]
+func (h *GenealogyHandler) RecordProduction(c *gin.Context) {
+	var req application.RecordProductionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误: " + err.Error()})
+		return
+	}
+
+	resp, err := h.service.RecordProduction(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// TraceBackward 向上游追溯
+// @Summary      向上游追溯
+// @Description  从成品/半成品批次追溯到投入的原料批次、供应商及相关订单与次品
+// @Tags         批次追溯
+// @Accept       json
+// @Produce      json
+// @Param        inventoryId query int false "库存ID（与批次ID二选一）"
+// @Param        batchId query string false "批次ID"
+// @Success      200 {object} application.TraceResponse "追溯结果"
+// @Failure      400 {object} map[string]interface{} "参数错误"
+// @Failure      404 {object} map[string]interface{} "批次不存在"
+// @Security     Bearer
+// @Router       /inventory/trace/backward [get]
+func (h *GenealogyHandler) TraceBackward(c *gin.Context) {
+	h.trace(c, domain.TraceDirectionBackward)
+}
+
+// TraceForward 向下游追溯
+// @Summary      向下游追溯
+// @Description  从原料批次追溯到下游产出的半成品/成品批次及相关订单与次品
+// @Tags         批次追溯
+// @Accept       json
+// @Produce      json
+// @Param        inventoryId query int false "库存ID（与批次ID二选一）"
+// @Param        batchId query string false "批次ID"
+// @Success      200 {object} application.TraceResponse "追溯结果"
+// @Failure      400 {object} map[string]interface{} "参数错误"
+// @Failure      404 {object} map[string]interface{} "批次不存在"
+// @Security     Bearer
+// @Router       /inventory/trace/forward [get]
+func (h *GenealogyHandler) TraceForward(c *gin.Context) {
+	h.trace(c, domain.TraceDirectionForward)
+}
+
+// trace 追溯公共处理
+func (h *GenealogyHandler) trace(c *gin.Context, direction string) {
+	inventoryID, _ := strconv.ParseUint(c.Query("inventoryId"), 10, 32)
+	batchID := c.Query("batchId")
+	if inventoryID == 0 && batchID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "库存ID和批次ID至少指定一个"})
+		return
+	}
+
+	resp, err := h.service.Trace(c.Request.Context(), uint(inventoryID), batchID, direction)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetRoutes 返回路由定义
+func (h *GenealogyHandler) GetRoutes() []endpoint.RouteDefinition {
+	return []endpoint.RouteDefinition{
+		{Method: "POST", Path: "/inventory/production", Handler: h.RecordProduction, Domain: "inventory", Action: "production"},
+		{Method: "GET", Path: "/inventory/trace/backward", Handler: h.TraceBackward, Domain: "inventory", Action: "trace"},
+		{Method: "GET", Path: "/inventory/trace/forward", Handler: h.TraceForward, Domain: "inventory", Action: "trace"},
+	}
+}
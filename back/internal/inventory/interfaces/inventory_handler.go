@@ -3,8 +3,10 @@ package interfaces
 import (
 	"net/http"
 	"strconv"
+	"time"
 
 	"back/internal/inventory/application"
+	"back/internal/inventory/domain"
 	"back/pkg/endpoint"
 	"github.com/gin-gonic/gin"
 )
@@ -12,11 +14,12 @@ import (
 // InventoryHandler 库存 Handler
 type InventoryHandler struct {
 	service *application.InventoryService
+	costing *application.CostingService
 }
 
 // NewInventoryHandler 创建 Handler
-func NewInventoryHandler(service *application.InventoryService) *InventoryHandler {
-	return &InventoryHandler{service: service}
+func NewInventoryHandler(service *application.InventoryService, costing *application.CostingService) *InventoryHandler {
+	return &InventoryHandler{service: service, costing: costing}
 }
 
 // CreateInventory 创建库存
@@ -325,6 +328,156 @@ func (h *InventoryHandler) DeleteInventory(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
 }
 
+// ListCostingConfigs 获取计价方法配置
+// @Summary      获取计价方法配置
+// @Description  获取各库存类别的计价方法（先进先出 / 移动加权平均）
+// @Tags         库存管理
+// @Accept       json
+// @Produce      json
+// @Success      200 {array} application.CostingConfigResponse "计价配置"
+// @Failure      400 {object} map[string]interface{} "参数错误"
+// @Security     Bearer
+// @Router       /inventory/costing [get]
+func (h *InventoryHandler) ListCostingConfigs(c *gin.Context) {
+	resp, err := h.costing.ListCostingConfigs(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// SetCostingMethod 设置计价方法
+// @Summary      设置计价方法
+// @Description  设置某库存类别的计价方法（fifo / moving_average）
+// @Tags         库存管理
+// @Accept       json
+// @Produce      json
+// @Param        request body application.SetCostingMethodRequest true "设置计价方法请求"
+// @Success      200 {object} application.CostingConfigResponse "设置成功"
+// @Failure      400 {object} map[string]interface{} "参数错误"
+// @Security     Bearer
+// @Router       /inventory/costing [put]
+func (h *InventoryHandler) SetCostingMethod(c *gin.Context) {
+	var req application.SetCostingMethodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误: " + err.Error()})
+		return
+	}
+
+	resp, err := h.costing.SetCostingMethod(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// IssueInventory 出库
+// @Summary      出库
+// @Description  按类别计价方法出库并计算出库成本，关联订单时计入订单实际成本
+// @Tags         库存管理
+// @Accept       json
+// @Produce      json
+// @Param        request body application.IssueInventoryRequest true "出库请求"
+// @Success      200 {object} application.IssueInventoryResponse "出库成功"
+// @Failure      400 {object} map[string]interface{} "参数错误或库存不足"
+// @Security     Bearer
+// @Router       /inventory/issue [post]
+func (h *InventoryHandler) IssueInventory(c *gin.Context) {
+	var req application.IssueInventoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误: " + err.Error()})
+		return
+	}
+
+	resp, err := h.costing.IssueInventory(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ListMovements 获取库存流水
+// @Summary      获取库存流水
+// @Description  按批次、产品、类别、类型或订单查询库存流水
+// @Tags         库存管理
+// @Accept       json
+// @Produce      json
+// @Param        inventoryId query int false "库存ID"
+// @Param        productId query int false "产品ID"
+// @Param        category query string false "类别"
+// @Param        type query string false "流水类型（receipt/issue/adjustment）"
+// @Param        orderId query int false "订单ID"
+// @Param        limit query int false "每页数量" default(10)
+// @Param        offset query int false "偏移量" default(0)
+// @Success      200 {object} application.MovementListResponse "库存流水"
+// @Failure      400 {object} map[string]interface{} "参数错误"
+// @Security     Bearer
+// @Router       /inventory/movements [get]
+func (h *InventoryHandler) ListMovements(c *gin.Context) {
+	inventoryID, _ := strconv.ParseUint(c.Query("inventoryId"), 10, 32)
+	productID, _ := strconv.ParseUint(c.Query("productId"), 10, 32)
+	orderID, _ := strconv.ParseUint(c.Query("orderId"), 10, 32)
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	filter := &domain.MovementFilter{
+		InventoryID: uint(inventoryID),
+		ProductID:   uint(productID),
+		Category:    c.Query("category"),
+		Type:        c.Query("type"),
+		OrderID:     uint(orderID),
+	}
+
+	resp, err := h.costing.ListMovements(c.Request.Context(), filter, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetValuation 获取库存估值报表
+// @Summary      获取库存估值报表
+// @Description  按产品、类别汇总截至指定日期（含当日）的库存数量与金额
+// @Tags         库存管理
+// @Accept       json
+// @Produce      json
+// @Param        date query string false "估值日期（YYYY-MM-DD，默认今天）"
+// @Param        productId query int false "产品ID"
+// @Param        category query string false "类别"
+// @Success      200 {object} application.ValuationResponse "估值报表"
+// @Failure      400 {object} map[string]interface{} "参数错误"
+// @Security     Bearer
+// @Router       /inventory/valuation [get]
+func (h *InventoryHandler) GetValuation(c *gin.Context) {
+	date := time.Now()
+	if dateStr := c.Query("date"); dateStr != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", dateStr, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的日期格式，应为 YYYY-MM-DD"})
+			return
+		}
+		date = parsed
+	}
+
+	productID, _ := strconv.ParseUint(c.Query("productId"), 10, 32)
+
+	resp, err := h.costing.GetValuation(c.Request.Context(), date, uint(productID), c.Query("category"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
 // GetRoutes 返回路由定义
 func (h *InventoryHandler) GetRoutes() []endpoint.RouteDefinition {
 	return []endpoint.RouteDefinition{
@@ -339,5 +492,10 @@ func (h *InventoryHandler) GetRoutes() []endpoint.RouteDefinition {
 		{Method: "POST", Path: "/inventory/deduct", Handler: h.DeductInventory, Domain: "inventory", Action: "update"},
 		{Method: "POST", Path: "/inventory/add", Handler: h.AddInventory, Domain: "inventory", Action: "update"},
 		{Method: "DELETE", Path: "/inventory/:id", Handler: h.DeleteInventory, Domain: "inventory", Action: "delete"},
+		{Method: "GET", Path: "/inventory/costing", Handler: h.ListCostingConfigs, Domain: "inventory", Action: "read"},
+		{Method: "PUT", Path: "/inventory/costing", Handler: h.SetCostingMethod, Domain: "inventory", Action: "costing"},
+		{Method: "POST", Path: "/inventory/issue", Handler: h.IssueInventory, Domain: "inventory", Action: "issue"},
+		{Method: "GET", Path: "/inventory/movements", Handler: h.ListMovements, Domain: "inventory", Action: "read"},
+		{Method: "GET", Path: "/inventory/valuation", Handler: h.GetValuation, Domain: "inventory", Action: "valuation"},
 	}
 }
@@ -0,0 +1,300 @@
+package interfaces
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"back/internal/inventory/application"
+	"back/internal/inventory/domain"
+	"back/pkg/audit"
+	"back/pkg/endpoint"
+	"github.com/gin-gonic/gin"
+)
+
+// ReplenishmentHandler 补货 Handler
+type ReplenishmentHandler struct {
+	service *application.ReplenishmentService
+}
+
+// NewReplenishmentHandler 创建 Handler
+func NewReplenishmentHandler(service *application.ReplenishmentService) *ReplenishmentHandler {
+	return &ReplenishmentHandler{service: service}
+}
+
+// ListReorderRules 获取补货规则列表
+// @Summary      获取补货规则列表
+// @Description  获取所有产品/原料的补货点与目标库存
+// @Tags         库存补货
+// @Accept       json
+// @Produce      json
+// @Success      200 {array} application.ReorderRuleResponse "补货规则列表"
+// @Failure      400 {object} map[string]interface{} "参数错误"
+// @Security     Bearer
+// @Router       /inventory/reorder-rule/list [get]
+func (h *ReplenishmentHandler) ListReorderRules(c *gin.Context) {
+	resp, err := h.service.ListReorderRules(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// SaveReorderRule 保存补货规则
+// @Summary      保存补货规则
+// @Description  设置产品/原料的补货点与目标库存（已存在则更新）
+// @Tags         库存补货
+// @Accept       json
+// @Produce      json
+// @Param        request body application.SaveReorderRuleRequest true "补货规则"
+// @Success      200 {object} application.ReorderRuleResponse "保存成功"
+// @Failure      400 {object} map[string]interface{} "参数错误"
+// @Security     Bearer
+// @Router       /inventory/reorder-rule [put]
+func (h *ReplenishmentHandler) SaveReorderRule(c *gin.Context) {
+	var req application.SaveReorderRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误: " + err.Error()})
+		return
+	}
+
+	resp, err := h.service.SaveReorderRule(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if recorder := audit.Get(c); recorder != nil {
+		recorder.SetResourceID(resp.ID)
+		recorder.SetNew(req)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteReorderRule 删除补货规则
+// @Summary      删除补货规则
+// @Description  删除补货规则
+// @Tags         库存补货
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "补货规则ID"
+// @Success      200 {object} map[string]interface{} "删除成功"
+// @Failure      400 {object} map[string]interface{} "参数错误"
+// @Failure      404 {object} map[string]interface{} "补货规则不存在"
+// @Security     Bearer
+// @Router       /inventory/reorder-rule/{id} [delete]
+func (h *ReplenishmentHandler) DeleteReorderRule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的ID"})
+		return
+	}
+
+	if err := h.service.DeleteReorderRule(c.Request.Context(), uint(id)); err != nil {
+		if errors.Is(err, domain.ErrReorderRuleNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if recorder := audit.Get(c); recorder != nil {
+		recorder.SetResourceID(id)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
+
+// GetAvailability 获取可用库存
+// @Summary      获取可用库存
+// @Description  获取产品在某类别下的在库、已预留与可用数量
+// @Tags         库存补货
+// @Accept       json
+// @Produce      json
+// @Param        productId query int true "产品ID（原料为原料ID）"
+// @Param        category query string true "类别"
+// @Success      200 {object} application.AvailabilityResponse "可用库存"
+// @Failure      400 {object} map[string]interface{} "参数错误"
+// @Security     Bearer
+// @Router       /inventory/available [get]
+func (h *ReplenishmentHandler) GetAvailability(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Query("productId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的产品ID"})
+		return
+	}
+	category := c.Query("category")
+	if category == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrCategoryRequired.Error()})
+		return
+	}
+
+	resp, err := h.service.GetAvailability(c.Request.Context(), uint(productID), category)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Reserve 预留库存
+// @Summary      预留库存
+// @Description  为订单预留可用库存，预留部分不可被其他订单领用
+// @Tags         库存补货
+// @Accept       json
+// @Produce      json
+// @Param        request body application.ReserveInventoryRequest true "预留请求"
+// @Success      200 {object} application.ReservationResponse "预留成功"
+// @Failure      400 {object} map[string]interface{} "参数错误"
+// @Security     Bearer
+// @Router       /inventory/reservation [post]
+func (h *ReplenishmentHandler) Reserve(c *gin.Context) {
+	var req application.ReserveInventoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误: " + err.Error()})
+		return
+	}
+
+	resp, err := h.service.Reserve(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if recorder := audit.Get(c); recorder != nil {
+		recorder.SetResourceID(resp.ID)
+		recorder.SetNew(req)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ReleaseReservation 释放库存预留
+// @Summary      释放库存预留
+// @Description  释放有效的库存预留
+// @Tags         库存补货
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "预留ID"
+// @Success      200 {object} application.ReservationResponse "释放成功"
+// @Failure      400 {object} map[string]interface{} "参数错误"
+// @Failure      404 {object} map[string]interface{} "预留不存在"
+// @Security     Bearer
+// @Router       /inventory/reservation/{id}/release [post]
+func (h *ReplenishmentHandler) ReleaseReservation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的ID"})
+		return
+	}
+
+	resp, err := h.service.ReleaseReservation(c.Request.Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, domain.ErrReservationNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if recorder := audit.Get(c); recorder != nil {
+		recorder.SetResourceID(id)
+		recorder.SetOld(map[string]interface{}{"status": domain.ReservationStatusActive})
+		recorder.SetNew(map[string]interface{}{"status": resp.Status})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ListReservations 查询库存预留
+// @Summary      查询库存预留
+// @Description  按产品、订单、状态查询库存预留
+// @Tags         库存补货
+// @Accept       json
+// @Produce      json
+// @Param        productId query int false "产品ID"
+// @Param        orderId query int false "订单ID"
+// @Param        status query string false "状态（active/released/fulfilled）"
+// @Param        limit query int false "每页数量" default(10)
+// @Param        offset query int false "偏移量" default(0)
+// @Success      200 {object} application.ReservationListResponse "预留列表"
+// @Failure      400 {object} map[string]interface{} "参数错误"
+// @Security     Bearer
+// @Router       /inventory/reservation/list [get]
+func (h *ReplenishmentHandler) ListReservations(c *gin.Context) {
+	productID, _ := strconv.ParseUint(c.Query("productId"), 10, 32)
+	orderID, _ := strconv.ParseUint(c.Query("orderId"), 10, 32)
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	resp, err := h.service.ListReservations(c.Request.Context(), uint(productID), uint(orderID), c.Query("status"), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetReplenishment 获取补货建议
+// @Summary      获取补货建议
+// @Description  获取最近一次运行生成的补货建议（含推荐供应商）
+// @Tags         库存补货
+// @Accept       json
+// @Produce      json
+// @Param        category query string false "类别"
+// @Param        all query bool false "包含无需补货的条目"
+// @Success      200 {object} application.ReplenishmentResponse "补货建议"
+// @Failure      400 {object} map[string]interface{} "参数错误"
+// @Security     Bearer
+// @Router       /inventory/replenishment [get]
+func (h *ReplenishmentHandler) GetReplenishment(c *gin.Context) {
+	resp, err := h.service.GetLatestReplenishment(c.Request.Context(), c.Query("category"), c.Query("all") == "true")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// RunReplenishment 立即运行补货评估
+// @Summary      立即运行补货评估
+// @Description  手动触发补货评估（与定时任务相同），生成补货建议并发送低库存预警
+// @Tags         库存补货
+// @Accept       json
+// @Produce      json
+// @Success      200 {object} application.ReplenishmentResponse "补货建议"
+// @Failure      400 {object} map[string]interface{} "参数错误"
+// @Security     Bearer
+// @Router       /inventory/replenishment/run [post]
+func (h *ReplenishmentHandler) RunReplenishment(c *gin.Context) {
+	resp, err := h.service.RunReplenishment(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetRoutes 返回路由定义
+func (h *ReplenishmentHandler) GetRoutes() []endpoint.RouteDefinition {
+	return []endpoint.RouteDefinition{
+		{Method: "GET", Path: "/inventory/reorder-rule/list", Handler: h.ListReorderRules, Domain: "inventory", Action: "read"},
+		{Method: "PUT", Path: "/inventory/reorder-rule", Handler: h.SaveReorderRule, Domain: "inventory", Action: "replenishment"},
+		{Method: "DELETE", Path: "/inventory/reorder-rule/:id", Handler: h.DeleteReorderRule, Domain: "inventory", Action: "replenishment"},
+		{Method: "GET", Path: "/inventory/available", Handler: h.GetAvailability, Domain: "inventory", Action: "read"},
+		{Method: "POST", Path: "/inventory/reservation", Handler: h.Reserve, Domain: "inventory", Action: "reserve"},
+		{Method: "POST", Path: "/inventory/reservation/:id/release", Handler: h.ReleaseReservation, Domain: "inventory", Action: "reserve"},
+		{Method: "GET", Path: "/inventory/reservation/list", Handler: h.ListReservations, Domain: "inventory", Action: "read"},
+		{Method: "GET", Path: "/inventory/replenishment", Handler: h.GetReplenishment, Domain: "inventory", Action: "read"},
+		{Method: "POST", Path: "/inventory/replenishment/run", Handler: h.RunReplenishment, Domain: "inventory", Action: "replenishment"},
+	}
+}
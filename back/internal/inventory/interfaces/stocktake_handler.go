@@ -0,0 +1,284 @@
+package interfaces
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"back/internal/inventory/application"
+	"back/internal/inventory/domain"
+	"back/pkg/audit"
+	"back/pkg/endpoint"
+	"github.com/gin-gonic/gin"
+)
+
+// StocktakeHandler 盘点 Handler
+type StocktakeHandler struct {
+	service *application.StocktakeService
+}
+
+// NewStocktakeHandler 创建 Handler
+func NewStocktakeHandler(service *application.StocktakeService) *StocktakeHandler {
+	return &StocktakeHandler{service: service}
+}
+
+// getOperator 从上下文获取当前操作人
+func getOperator(c *gin.Context) (uint, string, error) {
+	loginIDVal, exists := c.Get("loginId")
+	if !exists {
+		return 0, "", errors.New("未找到用户登录信息")
+	}
+
+	loginIDStr, ok := loginIDVal.(string)
+	if !ok {
+		return 0, "", errors.New("用户登录信息格式错误")
+	}
+	id, err := strconv.Atoi(loginIDStr)
+	if err != nil {
+		return 0, "", errors.New("用户登录信息格式错误")
+	}
+
+	username, _ := c.Get("username")
+	name, _ := username.(string)
+	return uint(id), name, nil
+}
+
+// CreateStocktake 创建盘点单
+// @Summary      创建盘点单
+// @Description  按仓库或类别快照账面数量创建盘点单，盘点期间发生的库存变动会被标记
+// @Tags         库存盘点
+// @Accept       json
+// @Produce      json
+// @Param        request body application.CreateStocktakeRequest true "创建盘点单请求"
+// @Success      200 {object} application.StocktakeResponse "创建成功"
+// @Failure      400 {object} map[string]interface{} "参数错误"
+// @Security     Bearer
+// @Router       /inventory/stocktake [post]
+func (h *StocktakeHandler) CreateStocktake(c *gin.Context) {
+	var req application.CreateStocktakeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误: " + err.Error()})
+		return
+	}
+
+	operatorID, operatorName, err := getOperator(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.service.CreateStocktake(c.Request.Context(), &req, operatorID, operatorName)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if recorder := audit.Get(c); recorder != nil {
+		recorder.SetResourceID(resp.ID)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ListStocktakes 获取盘点单列表
+// @Summary      获取盘点单列表
+// @Description  获取盘点单列表，可按状态过滤
+// @Tags         库存盘点
+// @Accept       json
+// @Produce      json
+// @Param        status query string false "状态（counting/approved/cancelled）"
+// @Param        limit query int false "每页数量" default(10)
+// @Param        offset query int false "偏移量" default(0)
+// @Success      200 {object} application.StocktakeListResponse "盘点单列表"
+// @Failure      400 {object} map[string]interface{} "参数错误"
+// @Security     Bearer
+// @Router       /inventory/stocktake/list [get]
+func (h *StocktakeHandler) ListStocktakes(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	resp, err := h.service.ListStocktakes(c.Request.Context(), c.Query("status"), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetStocktake 获取盘点单详情
+// @Summary      获取盘点单详情
+// @Description  获取盘点单明细及差异
+// @Tags         库存盘点
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "盘点单ID"
+// @Success      200 {object} application.StocktakeResponse "盘点单详情"
+// @Failure      400 {object} map[string]interface{} "参数错误"
+// @Failure      404 {object} map[string]interface{} "盘点单不存在"
+// @Security     Bearer
+// @Router       /inventory/stocktake/{id} [get]
+func (h *StocktakeHandler) GetStocktake(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的ID"})
+		return
+	}
+
+	resp, err := h.service.GetStocktake(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// SubmitCount 提交实盘数量
+// @Summary      提交实盘数量
+// @Description  按批次提交实盘数量（支持移动端，来源取自 Source 请求头）
+// @Tags         库存盘点
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "盘点单ID"
+// @Param        request body application.SubmitCountRequest true "实盘数量"
+// @Success      200 {object} application.StocktakeResponse "提交成功"
+// @Failure      400 {object} map[string]interface{} "参数错误"
+// @Failure      404 {object} map[string]interface{} "盘点单不存在"
+// @Security     Bearer
+// @Router       /inventory/stocktake/{id}/count [post]
+func (h *StocktakeHandler) SubmitCount(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的ID"})
+		return
+	}
+
+	var req application.SubmitCountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误: " + err.Error()})
+		return
+	}
+
+	counterID, counterName, err := getOperator(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.service.SubmitCount(c.Request.Context(), uint(id), &req, counterID, counterName, c.GetString("source"))
+	if err != nil {
+		if errors.Is(err, domain.ErrStocktakeNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if recorder := audit.Get(c); recorder != nil {
+		recorder.SetResourceID(id)
+		recorder.SetNew(req)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ApproveStocktake 审批盘点单
+// @Summary      审批盘点单
+// @Description  审批盘点单并按差异过账调整流水
+// @Tags         库存盘点
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "盘点单ID"
+// @Param        request body application.ApproveStocktakeRequest true "调整原因"
+// @Success      200 {object} application.StocktakeResponse "审批成功"
+// @Failure      400 {object} map[string]interface{} "参数错误"
+// @Failure      404 {object} map[string]interface{} "盘点单不存在"
+// @Security     Bearer
+// @Router       /inventory/stocktake/{id}/approve [post]
+func (h *StocktakeHandler) ApproveStocktake(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的ID"})
+		return
+	}
+
+	var req application.ApproveStocktakeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误: " + err.Error()})
+		return
+	}
+
+	approverID, approverName, err := getOperator(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.service.ApproveStocktake(c.Request.Context(), uint(id), &req, approverID, approverName)
+	if err != nil {
+		if errors.Is(err, domain.ErrStocktakeNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if recorder := audit.Get(c); recorder != nil {
+		recorder.SetResourceID(id)
+		recorder.SetOld(map[string]interface{}{"status": domain.StocktakeStatusCounting})
+		recorder.SetNew(map[string]interface{}{
+			"status":             resp.Status,
+			"reason":             resp.Reason,
+			"varianceLines":      resp.VarianceLines,
+			"totalVarianceValue": resp.TotalVarianceValue,
+		})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// CancelStocktake 取消盘点单
+// @Summary      取消盘点单
+// @Description  取消盘点单并解除锁定，不过账
+// @Tags         库存盘点
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "盘点单ID"
+// @Success      200 {object} map[string]interface{} "取消成功"
+// @Failure      400 {object} map[string]interface{} "参数错误"
+// @Failure      404 {object} map[string]interface{} "盘点单不存在"
+// @Security     Bearer
+// @Router       /inventory/stocktake/{id}/cancel [post]
+func (h *StocktakeHandler) CancelStocktake(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的ID"})
+		return
+	}
+
+	if err := h.service.CancelStocktake(c.Request.Context(), uint(id)); err != nil {
+		if errors.Is(err, domain.ErrStocktakeNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "取消成功"})
+}
+
+// GetRoutes 返回路由定义
+func (h *StocktakeHandler) GetRoutes() []endpoint.RouteDefinition {
+	return []endpoint.RouteDefinition{
+		{Method: "POST", Path: "/inventory/stocktake", Handler: h.CreateStocktake, Domain: "inventory", Action: "stocktake"},
+		{Method: "GET", Path: "/inventory/stocktake/list", Handler: h.ListStocktakes, Domain: "inventory", Action: "read"},
+		{Method: "GET", Path: "/inventory/stocktake/:id", Handler: h.GetStocktake, Domain: "inventory", Action: "read"},
+		{Method: "POST", Path: "/inventory/stocktake/:id/count", Handler: h.SubmitCount, Domain: "inventory", Action: "count"},
+		{Method: "POST", Path: "/inventory/stocktake/:id/approve", Handler: h.ApproveStocktake, Domain: "inventory", Action: "stocktakeApprove"},
+		{Method: "POST", Path: "/inventory/stocktake/:id/cancel", Handler: h.CancelStocktake, Domain: "inventory", Action: "stocktake"},
+	}
+}
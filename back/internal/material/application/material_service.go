@@ -175,4 +175,17 @@ func (s *MaterialService) Delete(ctx context.Context, id uint) error {
 // Exists 检查材料是否存在（供其他模块调用）
 func (s *MaterialService) Exists(ctx context.Context, id uint) (bool, error) {
 	return s.repo.ExistsByID(ctx, id)
-}
\ No newline at end of file
+}
+// DisplayName 获取材料名称（供其他模块调用）
+func (s *MaterialService) DisplayName(ctx context.Context, id uint) (string, error) {
+	material, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	return material.Name, nil
+}
+
+// ResolveCodes 根据材料编号批量查找材料 ID（供其他模块调用）
+func (s *MaterialService) ResolveCodes(ctx context.Context, codes []string) (map[string][]uint, error) {
+	return s.repo.FindIDsByCodes(ctx, codes)
+}
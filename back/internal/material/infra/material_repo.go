@@ -86,3 +86,23 @@ func (r *MaterialRepo) FindByName(ctx context.Context, name string) (*domain.Mat
 func (r *MaterialRepo) Count(ctx context.Context) (int64, error) {
 	return r.Repo.Count(ctx, map[string]interface{}{})
 }
+
+// FindIDsByCodes 根据编号批量查询 ID（编号 → ID）
+func (r *MaterialRepo) FindIDsByCodes(ctx context.Context, codes []string) (map[string][]uint, error) {
+	var rows []struct {
+		ID   uint
+		Code string
+	}
+	err := r.db.WithContext(ctx).Model(&domain.Material{}).
+		Select("id", "code").
+		Where("code IN ?", codes).
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string][]uint, len(rows))
+	for _, row := range rows {
+		result[row.Code] = append(result[row.Code], row.ID)
+	}
+	return result, nil
+}
@@ -0,0 +1,35 @@
+package application
+
+import "time"
+
+// Message 发送通知请求（供其他模块调用）
+type Message struct {
+	Type    string
+	Level   string
+	Title   string
+	Content string
+	Payload interface{} // 附加数据，序列化为 JSON 存储
+}
+
+// NotificationResponse 通知响应
+type NotificationResponse struct {
+	ID        uint      `json:"id"`
+	Type      string    `json:"type"`
+	Level     string    `json:"level"`
+	Title     string    `json:"title"`
+	Content   string    `json:"content"`
+	Payload   string    `json:"payload,omitempty"`
+	Read      bool      `json:"read"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// NotificationListResponse 通知列表响应
+type NotificationListResponse struct {
+	Total         int64                   `json:"total"`
+	Notifications []*NotificationResponse `json:"notifications"`
+}
+
+// UnreadCountResponse 未读数量响应
+type UnreadCountResponse struct {
+	Count int64 `json:"count"`
+}
@@ -0,0 +1,132 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+
+	"back/internal/notification/domain"
+	"back/internal/notification/infra"
+)
+
+// NotificationService 站内通知服务
+type NotificationService struct {
+	repo *infra.NotificationRepo
+}
+
+// NewNotificationService 创建通知服务
+func NewNotificationService(repo *infra.NotificationRepo) *NotificationService {
+	return &NotificationService{repo: repo}
+}
+
+// NotifyRoles 向角色发送通知（每个角色一条，供其他模块调用）
+func (s *NotificationService) NotifyRoles(ctx context.Context, roles []string, msg *Message) error {
+	for _, role := range roles {
+		notification, err := s.build(msg)
+		if err != nil {
+			return err
+		}
+		notification.RecipientRole = role
+		if err := s.save(ctx, notification); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NotifyUser 向指定用户发送通知（供其他模块调用）
+func (s *NotificationService) NotifyUser(ctx context.Context, loginID string, msg *Message) error {
+	notification, err := s.build(msg)
+	if err != nil {
+		return err
+	}
+	notification.RecipientLoginID = loginID
+	return s.save(ctx, notification)
+}
+
+// ListNotifications 获取当前用户的通知列表
+func (s *NotificationService) ListNotifications(ctx context.Context, loginID, role string, unreadOnly bool, limit, offset int) (*NotificationListResponse, error) {
+	notifications, total, err := s.repo.FindVisible(ctx, loginID, role, unreadOnly, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, len(notifications))
+	for i := range notifications {
+		ids[i] = notifications[i].ID
+	}
+	read, err := s.repo.FindReadIDs(ctx, loginID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*NotificationResponse, len(notifications))
+	for i := range notifications {
+		n := &notifications[i]
+		responses[i] = &NotificationResponse{
+			ID:        n.ID,
+			Type:      n.Type,
+			Level:     n.Level,
+			Title:     n.Title,
+			Content:   n.Content,
+			Payload:   n.Payload,
+			Read:      read[n.ID],
+			CreatedAt: n.CreatedAt,
+		}
+	}
+
+	return &NotificationListResponse{
+		Total:         total,
+		Notifications: responses,
+	}, nil
+}
+
+// UnreadCount 获取当前用户未读通知数量
+func (s *NotificationService) UnreadCount(ctx context.Context, loginID, role string) (*UnreadCountResponse, error) {
+	_, total, err := s.repo.FindVisible(ctx, loginID, role, true, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &UnreadCountResponse{Count: total}, nil
+}
+
+// MarkRead 标记通知已读
+func (s *NotificationService) MarkRead(ctx context.Context, id uint, loginID, role string) error {
+	notification, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !notification.IsVisibleTo(loginID, role) {
+		return domain.ErrNotificationNotFound
+	}
+	return s.repo.MarkRead(ctx, id, loginID)
+}
+
+// build 构建通知实体
+func (s *NotificationService) build(msg *Message) (*domain.Notification, error) {
+	notification := &domain.Notification{
+		Type:    msg.Type,
+		Level:   msg.Level,
+		Title:   msg.Title,
+		Content: msg.Content,
+	}
+	if notification.Level == "" {
+		notification.Level = domain.LevelInfo
+	}
+
+	if msg.Payload != nil {
+		payload, err := json.Marshal(msg.Payload)
+		if err != nil {
+			return nil, err
+		}
+		notification.Payload = string(payload)
+	}
+	return notification, nil
+}
+
+// save 验证并保存通知
+func (s *NotificationService) save(ctx context.Context, notification *domain.Notification) error {
+	if err := notification.Validate(); err != nil {
+		return err
+	}
+	return s.repo.Create(ctx, notification)
+}
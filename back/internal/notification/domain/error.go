@@ -0,0 +1,9 @@
+package domain
+
+import "errors"
+
+var (
+	ErrNotificationNotFound = errors.New("通知不存在")
+	ErrTitleRequired        = errors.New("通知标题不能为空")
+	ErrRecipientRequired    = errors.New("接收角色和接收人至少指定一个")
+)
@@ -0,0 +1,68 @@
+package domain
+
+import "time"
+
+// 通知类型常量
+const (
+	TypeLowStock   = "low_stock"   // 低库存预警
+	TypeCostChange = "cost_change" // 产品成本变动预警
+	TypePriceAlert = "price_alert" // 报价关注提醒
+)
+
+// 通知级别常量
+const (
+	LevelInfo    = "info"
+	LevelWarning = "warning"
+)
+
+// Notification 站内通知（按角色或按用户投递）
+type Notification struct {
+	ID               uint      `gorm:"primaryKey" json:"id"`
+	Type             string    `gorm:"size:50;not null;index" json:"type"`    // 通知类型
+	Level            string    `gorm:"size:20;not null" json:"level"`         // 级别
+	Title            string    `gorm:"size:200;not null" json:"title"`        // 标题
+	Content          string    `gorm:"type:text" json:"content"`              // 内容
+	Payload          string    `gorm:"type:jsonb" json:"payload,omitempty"`   // 附加数据（JSON）
+	RecipientRole    string    `gorm:"size:50;index" json:"recipientRole"`    // 接收角色（与接收人二选一）
+	RecipientLoginID string    `gorm:"size:50;index" json:"recipientLoginId"` // 接收人登录ID
+	CreatedAt        time.Time `gorm:"autoCreateTime;index" json:"createdAt"`
+}
+
+// TableName 表名
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+// Validate 验证通知数据
+func (n *Notification) Validate() error {
+	if n.Title == "" {
+		return ErrTitleRequired
+	}
+
+	if n.RecipientRole == "" && n.RecipientLoginID == "" {
+		return ErrRecipientRequired
+	}
+
+	return nil
+}
+
+// IsVisibleTo 是否对指定用户可见
+func (n *Notification) IsVisibleTo(loginID, role string) bool {
+	if n.RecipientLoginID != "" {
+		return n.RecipientLoginID == loginID
+	}
+	return n.RecipientRole == role
+}
+
+// NotificationRead 通知已读记录（按用户）
+type NotificationRead struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	NotificationID uint      `gorm:"not null;uniqueIndex:idx_notification_reader" json:"notificationId"`
+	LoginID        string    `gorm:"size:50;not null;uniqueIndex:idx_notification_reader" json:"loginId"`
+	ReadAt         time.Time `gorm:"autoCreateTime" json:"readAt"`
+}
+
+// TableName 表名
+func (NotificationRead) TableName() string {
+	return "notification_reads"
+}
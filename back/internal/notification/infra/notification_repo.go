@@ -0,0 +1,97 @@
+package infra
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"back/internal/notification/domain"
+	"back/pkg/repo"
+)
+
+// NotificationRepo 通知仓储实现
+type NotificationRepo struct {
+	*repo.Repo[domain.Notification]
+	db *gorm.DB
+}
+
+// NewNotificationRepo 创建仓储
+func NewNotificationRepo(db *gorm.DB) *NotificationRepo {
+	return &NotificationRepo{
+		Repo: repo.NewRepo[domain.Notification](db),
+		db:   db,
+	}
+}
+
+// FindByID 根据 ID 查询
+func (r *NotificationRepo) FindByID(ctx context.Context, id uint) (*domain.Notification, error) {
+	notification, err := r.GetByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrNotificationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return notification, nil
+}
+
+// visibleTo 当前用户可见的通知（发给本人或本人角色）
+func (r *NotificationRepo) visibleTo(ctx context.Context, loginID, role string) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&domain.Notification{}).
+		Where("recipient_login_id = ? OR (recipient_login_id = '' AND recipient_role = ?)", loginID, role)
+}
+
+// FindVisible 查询用户可见的通知（按时间倒序）
+func (r *NotificationRepo) FindVisible(ctx context.Context, loginID, role string, unreadOnly bool, limit, offset int) ([]domain.Notification, int64, error) {
+	query := r.visibleTo(ctx, loginID, role)
+	if unreadOnly {
+		query = query.Where("id NOT IN (?)",
+			r.db.Model(&domain.NotificationRead{}).Select("notification_id").Where("login_id = ?", loginID))
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	var notifications []domain.Notification
+	err := query.Order("created_at DESC").Find(&notifications).Error
+	return notifications, total, err
+}
+
+// FindReadIDs 查询用户已读的通知 ID
+func (r *NotificationRepo) FindReadIDs(ctx context.Context, loginID string, ids []uint) (map[uint]bool, error) {
+	read := make(map[uint]bool)
+	if len(ids) == 0 {
+		return read, nil
+	}
+
+	var readIDs []uint
+	err := r.db.WithContext(ctx).Model(&domain.NotificationRead{}).
+		Where("login_id = ? AND notification_id IN ?", loginID, ids).
+		Pluck("notification_id", &readIDs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range readIDs {
+		read[id] = true
+	}
+	return read, nil
+}
+
+// MarkRead 标记已读（重复标记忽略）
+func (r *NotificationRepo) MarkRead(ctx context.Context, notificationID uint, loginID string) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&domain.NotificationRead{NotificationID: notificationID, LoginID: loginID}).Error
+}
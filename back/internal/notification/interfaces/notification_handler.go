@@ -0,0 +1,111 @@
+package interfaces
+
+import (
+	"net/http"
+	"strconv"
+
+	"back/internal/notification/application"
+	"back/pkg/endpoint"
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationHandler 通知 Handler
+type NotificationHandler struct {
+	service *application.NotificationService
+}
+
+// NewNotificationHandler 创建 Handler
+func NewNotificationHandler(service *application.NotificationService) *NotificationHandler {
+	return &NotificationHandler{service: service}
+}
+
+// currentUser 从上下文获取当前用户登录ID与角色
+func currentUser(c *gin.Context) (string, string) {
+	return c.GetString("loginId"), c.GetString("role")
+}
+
+// ListNotifications 获取我的通知
+// @Summary      获取我的通知
+// @Description  获取发给当前用户或当前角色的通知
+// @Tags         通知
+// @Accept       json
+// @Produce      json
+// @Param        unreadOnly query bool false "仅未读"
+// @Param        limit query int false "每页数量" default(10)
+// @Param        offset query int false "偏移量" default(0)
+// @Success      200 {object} application.NotificationListResponse "通知列表"
+// @Failure      400 {object} map[string]interface{} "参数错误"
+// @Security     Bearer
+// @Router       /notification/list [get]
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	unreadOnly := c.Query("unreadOnly") == "true"
+
+	loginID, role := currentUser(c)
+	resp, err := h.service.ListNotifications(c.Request.Context(), loginID, role, unreadOnly, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// UnreadCount 获取未读通知数量
+// @Summary      获取未读通知数量
+// @Description  获取当前用户未读通知数量
+// @Tags         通知
+// @Accept       json
+// @Produce      json
+// @Success      200 {object} application.UnreadCountResponse "未读数量"
+// @Failure      400 {object} map[string]interface{} "参数错误"
+// @Security     Bearer
+// @Router       /notification/unread-count [get]
+func (h *NotificationHandler) UnreadCount(c *gin.Context) {
+	loginID, role := currentUser(c)
+	resp, err := h.service.UnreadCount(c.Request.Context(), loginID, role)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// MarkRead 标记通知已读
+// @Summary      标记通知已读
+// @Description  标记当前用户的通知为已读
+// @Tags         通知
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "通知ID"
+// @Success      200 {object} map[string]interface{} "标记成功"
+// @Failure      400 {object} map[string]interface{} "参数错误"
+// @Failure      404 {object} map[string]interface{} "通知不存在"
+// @Security     Bearer
+// @Router       /notification/{id}/read [post]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的ID"})
+		return
+	}
+
+	loginID, role := currentUser(c)
+	if err := h.service.MarkRead(c.Request.Context(), uint(id), loginID, role); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "已读"})
+}
+
+// GetRoutes 返回路由定义
+func (h *NotificationHandler) GetRoutes() []endpoint.RouteDefinition {
+	return []endpoint.RouteDefinition{
+		{Method: "GET", Path: "/notification/list", Handler: h.ListNotifications, Domain: "notification", Action: "read"},
+		{Method: "GET", Path: "/notification/unread-count", Handler: h.UnreadCount, Domain: "notification", Action: "read"},
+		{Method: "POST", Path: "/notification/:id/read", Handler: h.MarkRead, Domain: "notification", Action: "read"},
+	}
+}
@@ -4,12 +4,13 @@ import "time"
 
 // CreateOrderRequest 创建订单请求
 type CreateOrderRequest struct {
-	OrderNo   string  `json:"order_no" binding:"required,max=50"`
-	ClientID  uint    `json:"client_id" binding:"required"`
-	ProductID uint    `json:"product_id" binding:"required"`
-	Quantity  float64 `json:"quantity" binding:"required,gt=0"`
-	UnitPrice float64 `json:"unit_price" binding:"required,gte=0"`
-	CreatedBy uint    `json:"created_by" binding:"required"`
+	OrderNo   string   `json:"order_no" binding:"required,max=50"`
+	ClientID  uint     `json:"client_id" binding:"required"`
+	ProductID uint     `json:"product_id" binding:"required"`
+	Quantity  float64  `json:"quantity" binding:"required,gt=0"`
+	UnitPrice *float64 `json:"unit_price" binding:"omitempty,gte=0"` // 为空时取客户适用的销售价目
+	Currency  string   `json:"currency"`                            // 销售币种（默认本位币；单价取自价目时默认为价目币种）
+	CreatedBy uint     `json:"created_by" binding:"required"`
 }
 
 // UpdateOrderRequest 更新订单请求
@@ -28,10 +29,14 @@ type OrderResponse struct {
 	Quantity   float64   `json:"quantity"`
 	UnitPrice  float64   `json:"unit_price"`
 	TotalPrice float64   `json:"total_price"`
+	Currency   string    `json:"currency"`
 	Status     string    `json:"status"`
 	CreatedBy  uint      `json:"created_by"`
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
+
+	SalesPriceID uint          `json:"sales_price_id,omitempty"` // 单价来源销售价目
+	PriceWarning *PriceWarning `json:"price_warning,omitempty"`  // 创建时单价低于成本加成下限
 }
 
 // OrderListResponse 订单列表响应
@@ -49,7 +54,8 @@ type CreateOrderRequestV2 struct {
 	ProductID               uint    `json:"product_id" binding:"required"`
 	RequiredQuantity        float64 `json:"required_quantity" binding:"required,gt=0"`         // 成品需求数量
 	ProductHistoryShrinkage float64 `json:"product_history_shrinkage" binding:"omitempty,gte=0"` // 历史缩率
-	UnitPrice               float64 `json:"unit_price" binding:"required,gte=0"`
+	UnitPrice               *float64 `json:"unit_price" binding:"omitempty,gte=0"` // 为空时取客户适用的销售价目
+	Currency                string   `json:"currency"`                            // 销售币种（默认本位币；单价取自价目时默认为价目币种）
 }
 
 // AssignDepartmentRequest 分配部门请求
@@ -104,6 +110,17 @@ type ProgressResponse struct {
 	UpdatedAt         time.Time `json:"updated_at"`
 }
 
+// StepProgressResponse 工序进度响应
+type StepProgressResponse struct {
+	Sequence          int       `json:"sequence"`
+	ProcessID         uint      `json:"process_id"`
+	Optional          bool      `json:"optional"`
+	TargetQuantity    float64   `json:"target_quantity"`
+	CompletedQuantity float64   `json:"completed_quantity"`
+	Progress          int       `json:"progress"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
 // EventResponse 事件响应
 type EventResponse struct {
 	ID           uint      `json:"id"`
@@ -120,28 +137,65 @@ type EventResponse struct {
 
 // OrderDetailResponse 订单详情响应（包含客户名、产品名、进度项、事件等完整信息）
 type OrderDetailResponse struct {
-	ID                      uint                `json:"id"`
-	OrderNo                 string              `json:"order_no"`
-	ClientID                uint                `json:"client_id"`
-	ClientName              string              `json:"client_name"`
-	ProductID               uint                `json:"product_id"`
-	ProductName             string              `json:"product_name"`
-	ProductCode             string              `json:"product_code"`
-	ProductHistoryShrinkage float64             `json:"product_history_shrinkage"`
-	RequiredQuantity        float64             `json:"required_quantity"`
-	UnitPrice               float64             `json:"unit_price"`
-	TotalPrice              float64             `json:"total_price"`
-	Status                  string              `json:"status"`
-	AssignedDepartment      string              `json:"assigned_department,omitempty"`
-	CreatedAt               time.Time           `json:"created_at"`
-	UpdatedAt               time.Time           `json:"updated_at"`
-	ProgressItems           []ProgressResponse  `json:"progress_items"`
-	OperationLogs           []EventResponse     `json:"operation_logs"`
-	OverallProgress         int                 `json:"overall_progress"`
+	ID                      uint                   `json:"id"`
+	OrderNo                 string                 `json:"order_no"`
+	ClientID                uint                   `json:"client_id"`
+	ClientName              string                 `json:"client_name"`
+	ProductID               uint                   `json:"product_id"`
+	ProductName             string                 `json:"product_name"`
+	ProductCode             string                 `json:"product_code"`
+	BOMVersionID            uint                   `json:"bom_version_id"`
+	BOMVersion              int                    `json:"bom_version"`
+	ProductHistoryShrinkage float64                `json:"product_history_shrinkage"`
+	RequiredQuantity        float64                `json:"required_quantity"`
+	UnitPrice               float64                `json:"unit_price"`
+	TotalPrice              float64                `json:"total_price"`
+	Currency                string                 `json:"currency"`
+	SalesPriceID            uint                   `json:"sales_price_id,omitempty"` // 单价来源销售价目
+	PriceWarning            *PriceWarning          `json:"price_warning,omitempty"`  // 创建时单价低于成本加成下限
+	ActualCost              float64                `json:"actual_cost"` // 本位币
+	Status                  string                 `json:"status"`
+	AssignedDepartment      string                 `json:"assigned_department,omitempty"`
+	CreatedAt               time.Time              `json:"created_at"`
+	UpdatedAt               time.Time              `json:"updated_at"`
+	ProgressItems           []ProgressResponse     `json:"progress_items"`
+	StepItems               []StepProgressResponse `json:"step_items"` // 工艺路线各工序进度
+	OperationLogs           []EventResponse        `json:"operation_logs"`
+	OverallProgress         int                    `json:"overall_progress"`
 }
 
 // OrderListDetailResponse 订单列表响应（带详细信息）
 type OrderListDetailResponse struct {
 	Total  int64                  `json:"total"`
 	Orders []*OrderDetailResponse `json:"orders"`
-}
\ No newline at end of file
+}
+
+// OrderMarginResponse 订单毛利（销售额与实际成本按同一日期汇率换算为同一币种）
+type OrderMarginResponse struct {
+	OrderID       uint    `json:"order_id"`
+	OrderNo       string  `json:"order_no"`
+	Currency      string  `json:"currency"`  // 报告币种
+	RateDate      string  `json:"rate_date"` // 换算所用汇率日期
+	SalesCurrency string  `json:"sales_currency"`
+	SalesAmount   float64 `json:"sales_amount"` // 订单总价（销售币种）
+	Revenue       float64 `json:"revenue"`      // 订单总价（报告币种）
+	ActualCost    float64 `json:"actual_cost"`  // 实际成本（报告币种）
+	Margin        float64 `json:"margin"`
+	MarginPercent float64 `json:"margin_percent"` // 毛利率（%，销售额为 0 时为 0）
+}
+
+// SalesPriceMatch 适用的销售价目
+type SalesPriceMatch struct {
+	PriceID   uint    `json:"price_id"`
+	UnitPrice float64 `json:"unit_price"`
+	Currency  string  `json:"currency"`
+}
+
+// PriceWarning 售价低于成本加成下限预警（金额均为销售币种，不阻止下单）
+type PriceWarning struct {
+	UnitPrice   float64 `json:"unit_price"`
+	Currency    string  `json:"currency"`
+	UnitCost    float64 `json:"unit_cost"`    // 产品当前单位成本
+	MarginFloor float64 `json:"margin_floor"` // 成本加成下限（%）
+	FloorPrice  float64 `json:"floor_price"`  // 单位成本 ×（1 + 加成下限）
+}
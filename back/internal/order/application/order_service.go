@@ -2,7 +2,9 @@ package application
 
 import (
 	"context"
+	"errors"
 	"strconv"
+	"strings"
 	"time"
 
 	"back/internal/order/domain"
@@ -16,10 +18,42 @@ type ESSync interface {
 	Delete(indexName, docID string) error
 }
 
+// PlanGenerator 生产计划生成接口（由 Plan 模块实现，避免循环依赖）
+type PlanGenerator interface {
+	// GenerateForOrder 在 ctx 携带的订单事务中生成计划
+	GenerateForOrder(ctx context.Context, req *PlanGenerationRequest) ([]string, error)
+	// SyncOrderPlans 订单事务提交后同步计划的搜索索引
+	SyncOrderPlans(ctx context.Context, orderID uint)
+}
+
+// BOMResolver BOM 版本解析接口（由 Product 模块实现）
+type BOMResolver interface {
+	ResolveBOMVersion(ctx context.Context, productID uint, at time.Time) (versionID uint, version int, err error)
+}
+
+// CurrencyConverter 币种换算接口（由 Currency 模块实现）
+type CurrencyConverter interface {
+	Base() string
+	Normalize(code string) (string, error)
+	Convert(ctx context.Context, amount float64, from, to string, asOf time.Time) (float64, error)
+}
+
+// SalesPricing 销售定价接口（由 Sales 模块实现）
+type SalesPricing interface {
+	// ApplicablePrice 客户在指定数量、币种（为空时不限）下适用的销售价目，无适用价目时返回 nil
+	ApplicablePrice(ctx context.Context, clientID, productID uint, quantity float64, currency string, at time.Time) (*SalesPriceMatch, error)
+	// CheckPrice 单价低于产品成本加成下限时返回预警（成本无法核算时返回 nil）
+	CheckPrice(ctx context.Context, productID uint, unitPrice float64, currency string, at time.Time) *PriceWarning
+}
+
 // OrderService 订单应用服务
 type OrderService struct {
-	repo   *infra.OrderRepo
-	esSync ESSync
+	repo          *infra.OrderRepo
+	esSync        ESSync
+	planGenerator PlanGenerator
+	bomResolver   BOMResolver
+	currency      CurrencyConverter
+	salesPricing  SalesPricing
 }
 
 // NewOrderService 创建订单服务
@@ -30,6 +64,83 @@ func NewOrderService(repo *infra.OrderRepo, esSync ESSync) *OrderService {
 	}
 }
 
+// SetPlanGenerator 设置生产计划生成器（分配人员后自动生成计划）
+func (s *OrderService) SetPlanGenerator(generator PlanGenerator) {
+	s.planGenerator = generator
+}
+
+// SetBOMResolver 设置 BOM 版本解析器（创建订单时记录生效的 BOM 版本）
+func (s *OrderService) SetBOMResolver(resolver BOMResolver) {
+	s.bomResolver = resolver
+}
+
+// SetCurrencyConverter 设置币种换算（订单币种校验与毛利换算）
+func (s *OrderService) SetCurrencyConverter(converter CurrencyConverter) {
+	s.currency = converter
+}
+
+// SetSalesPricing 设置销售定价（未填写单价时取适用价目，低于成本加成下限时预警）
+func (s *OrderService) SetSalesPricing(pricing SalesPricing) {
+	s.salesPricing = pricing
+}
+
+// currencyOf 规范化订单币种（为空时为本位币）
+func (s *OrderService) currencyOf(code string) (string, error) {
+	if s.currency == nil {
+		if code == "" {
+			return "CNY", nil
+		}
+		return strings.ToUpper(strings.TrimSpace(code)), nil
+	}
+	return s.currency.Normalize(code)
+}
+
+// resolveBOM 记录订单创建时生效的 BOM 版本
+func (s *OrderService) resolveBOM(ctx context.Context, order *domain.Order) error {
+	if s.bomResolver == nil {
+		return nil
+	}
+	versionID, version, err := s.bomResolver.ResolveBOMVersion(ctx, order.ProductID, time.Now())
+	if err != nil {
+		return err
+	}
+	order.BOMVersionID = versionID
+	order.BOMVersion = version
+	return nil
+}
+
+// applySalesPrice 确定订单单价：未填写时取客户适用的销售价目（未指定币种时采用价目币种），并检查是否低于成本加成下限
+func (s *OrderService) applySalesPrice(ctx context.Context, order *domain.Order, unitPrice *float64, currencyGiven bool) (*PriceWarning, error) {
+	now := time.Now()
+	if unitPrice != nil {
+		order.UnitPrice = *unitPrice
+	} else {
+		if s.salesPricing == nil {
+			return nil, domain.ErrUnitPriceRequired
+		}
+		currency := ""
+		if currencyGiven {
+			currency = order.Currency
+		}
+		match, err := s.salesPricing.ApplicablePrice(ctx, order.ClientID, order.ProductID, order.Quantity, currency, now)
+		if err != nil {
+			return nil, err
+		}
+		if match == nil {
+			return nil, domain.ErrUnitPriceRequired
+		}
+		order.UnitPrice = match.UnitPrice
+		order.Currency = match.Currency
+		order.SalesPriceID = match.PriceID
+	}
+	order.CalculateTotalPrice()
+
+	if s.salesPricing == nil {
+		return nil, nil
+	}
+	return s.salesPricing.CheckPrice(ctx, order.ProductID, order.UnitPrice, order.Currency, now), nil
+}
+
 // Create 创建订单
 func (s *OrderService) Create(ctx context.Context, req *CreateOrderRequest) (*OrderResponse, error) {
 	// 1. 检查订单编号是否重复
@@ -40,6 +151,10 @@ func (s *OrderService) Create(ctx context.Context, req *CreateOrderRequest) (*Or
 	if exists {
 		return nil, domain.ErrOrderNoDuplicate
 	}
+	currency, err := s.currencyOf(req.Currency)
+	if err != nil {
+		return nil, err
+	}
 
 	// 2. DTO → Domain Model
 	order := &domain.Order{
@@ -47,11 +162,17 @@ func (s *OrderService) Create(ctx context.Context, req *CreateOrderRequest) (*Or
 		ClientID:  req.ClientID,
 		ProductID: req.ProductID,
 		Quantity:  req.Quantity,
-		UnitPrice: req.UnitPrice,
+		Currency:  currency,
 		Status:    domain.OrderStatusPending,
 		CreatedBy: req.CreatedBy,
 	}
-	order.CalculateTotalPrice()
+	warning, err := s.applySalesPrice(ctx, order, req.UnitPrice, req.Currency != "")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.resolveBOM(ctx, order); err != nil {
+		return nil, err
+	}
 
 	// 3. 领域验证
 	if err := order.Validate(); err != nil {
@@ -77,10 +198,14 @@ func (s *OrderService) Create(ctx context.Context, req *CreateOrderRequest) (*Or
 		Quantity:   order.Quantity,
 		UnitPrice:  order.UnitPrice,
 		TotalPrice: order.TotalPrice,
+		Currency:   order.Currency,
 		Status:     order.Status,
 		CreatedBy:  order.CreatedBy,
 		CreatedAt:  order.CreatedAt,
 		UpdatedAt:  order.UpdatedAt,
+
+		SalesPriceID: order.SalesPriceID,
+		PriceWarning: warning,
 	}, nil
 }
 
@@ -99,6 +224,7 @@ func (s *OrderService) Get(ctx context.Context, id uint) (*OrderResponse, error)
 		Quantity:   order.Quantity,
 		UnitPrice:  order.UnitPrice,
 		TotalPrice: order.TotalPrice,
+		Currency:   order.Currency,
 		Status:     order.Status,
 		CreatedBy:  order.CreatedBy,
 		CreatedAt:  order.CreatedAt,
@@ -128,6 +254,7 @@ func (s *OrderService) List(ctx context.Context, limit, offset int) (*OrderListR
 			Quantity:   o.Quantity,
 			UnitPrice:  o.UnitPrice,
 			TotalPrice: o.TotalPrice,
+			Currency:   o.Currency,
 			Status:     o.Status,
 			CreatedBy:  o.CreatedBy,
 			CreatedAt:  o.CreatedAt,
@@ -245,6 +372,7 @@ func (s *OrderService) GetByClientID(ctx context.Context, clientID uint) ([]*Ord
 			Quantity:   o.Quantity,
 			UnitPrice:  o.UnitPrice,
 			TotalPrice: o.TotalPrice,
+			Currency:   o.Currency,
 			Status:     o.Status,
 			CreatedBy:  o.CreatedBy,
 			CreatedAt:  o.CreatedAt,
@@ -272,6 +400,7 @@ func (s *OrderService) GetByProductID(ctx context.Context, productID uint) ([]*O
 			Quantity:   o.Quantity,
 			UnitPrice:  o.UnitPrice,
 			TotalPrice: o.TotalPrice,
+			Currency:   o.Currency,
 			Status:     o.Status,
 			CreatedBy:  o.CreatedBy,
 			CreatedAt:  o.CreatedAt,
@@ -304,6 +433,7 @@ func (s *OrderService) GetByStatus(ctx context.Context, status string, limit, of
 			Quantity:   o.Quantity,
 			UnitPrice:  o.UnitPrice,
 			TotalPrice: o.TotalPrice,
+			Currency:   o.Currency,
 			Status:     o.Status,
 			CreatedBy:  o.CreatedBy,
 			CreatedAt:  o.CreatedAt,
@@ -332,6 +462,10 @@ func (s *OrderService) CreateV2(ctx context.Context, req *CreateOrderRequestV2,
 		if exists {
 			return domain.ErrOrderNoDuplicate
 		}
+		currency, err := s.currencyOf(req.Currency)
+		if err != nil {
+			return err
+		}
 
 		// 2. 创建订单实体
 		order := &domain.Order{
@@ -341,11 +475,17 @@ func (s *OrderService) CreateV2(ctx context.Context, req *CreateOrderRequestV2,
 			RequiredQuantity:        req.RequiredQuantity,
 			ProductHistoryShrinkage: req.ProductHistoryShrinkage,
 			Quantity:                req.RequiredQuantity, // 临时使用，后续由生产助理设定胚布数量
-			UnitPrice:               req.UnitPrice,
+			Currency:                currency,
 			Status:                  domain.OrderStatusPending,
 			CreatedBy:               creatorID,
 		}
-		order.CalculateTotalPrice()
+		warning, err := s.applySalesPrice(txCtx, order, req.UnitPrice, req.Currency != "")
+		if err != nil {
+			return err
+		}
+		if err := s.resolveBOM(txCtx, order); err != nil {
+			return err
+		}
 
 		// 3. 领域验证
 		if err := order.Validate(); err != nil {
@@ -419,6 +559,7 @@ func (s *OrderService) CreateV2(ctx context.Context, req *CreateOrderRequestV2,
 		if err != nil {
 			return err
 		}
+		detail.PriceWarning = warning
 		result = detail
 
 		return nil
@@ -501,7 +642,7 @@ func (s *OrderService) AssignDepartment(ctx context.Context, orderID uint, req *
 
 // AssignPersonnel 分配人员（生产助理操作）
 func (s *OrderService) AssignPersonnel(ctx context.Context, orderID uint, req *AssignPersonnelRequest, assistantID uint, assistantName, assistantRole string) error {
-	return s.repo.Transaction(ctx, func(txCtx context.Context) error {
+	err := s.repo.Transaction(ctx, func(txCtx context.Context) error {
 		// 1. 查询订单
 		order, err := s.repo.FindByID(txCtx, orderID)
 		if err != nil {
@@ -622,13 +763,52 @@ func (s *OrderService) AssignPersonnel(ctx context.Context, orderID uint, req *A
 			return err
 		}
 
-		// 9. 异步更新 ES
+		// 9. 按胚布目标数量生成生产计划
+		if s.planGenerator != nil {
+			planNos, err := s.planGenerator.GenerateForOrder(txCtx, &PlanGenerationRequest{
+				OrderID:      order.ID,
+				ProductID:    order.ProductID,
+				BOMVersionID: order.BOMVersionID,
+				Quantity:     req.FabricTargetQuantity,
+				CreatedBy:    assistantID,
+			})
+			if err != nil {
+				return err
+			}
+
+			planEvent := createEvent(
+				orderID,
+				domain.EventTypeGeneratePlans,
+				assistantID,
+				assistantName,
+				assistantRole,
+				nil,
+				map[string]interface{}{
+					"plan_nos": planNos,
+				},
+				"生成生产计划"+strconv.Itoa(len(planNos))+"个："+strings.Join(planNos, "、"),
+			)
+			if err := s.repo.CreateEvent(txCtx, planEvent); err != nil {
+				return err
+			}
+		}
+
+		// 10. 异步更新 ES
 		if s.esSync != nil {
 			s.esSync.Update(order)
 		}
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	// 11. 事务提交后同步生成的计划到 ES（事务回滚时计划随之回滚，不会留下索引）
+	if s.planGenerator != nil {
+		s.planGenerator.SyncOrderPlans(ctx, orderID)
+	}
+	return nil
 }
 
 // UpdateProgress 更新进度（跟单/仓管操作）
@@ -791,6 +971,93 @@ func (s *OrderService) AddDefect(ctx context.Context, orderID uint, req *AddDefe
 	})
 }
 
+// AddActualCost 累加订单实际成本（库存领料出库时由系统调用，ctx 携带库存事务时加入该事务）
+func (s *OrderService) AddActualCost(ctx context.Context, orderID uint, amount float64, description string) error {
+	return s.repo.Transaction(ctx, func(txCtx context.Context) error {
+		order, err := s.repo.FindByID(txCtx, orderID)
+		if err != nil {
+			return err
+		}
+
+		beforeData := map[string]interface{}{
+			"actual_cost": order.ActualCost,
+		}
+
+		if err := order.AddActualCost(amount); err != nil {
+			return err
+		}
+
+		if err := s.repo.Update(txCtx, order); err != nil {
+			return err
+		}
+
+		afterData := map[string]interface{}{
+			"actual_cost": order.ActualCost,
+		}
+
+		event := createEvent(
+			orderID,
+			domain.EventTypeMaterialIssue,
+			0,
+			"系统",
+			"system",
+			beforeData,
+			afterData,
+			description,
+		)
+		if err := s.repo.CreateEvent(txCtx, event); err != nil {
+			return err
+		}
+
+		if s.esSync != nil {
+			s.esSync.Update(order)
+		}
+
+		return nil
+	})
+}
+
+// GetMargin 订单毛利：销售额按订单币种、实际成本按本位币，以 asOf 当日汇率换算为报告币种（currency 为空时为本位币）
+func (s *OrderService) GetMargin(ctx context.Context, orderID uint, currency string, asOf time.Time) (*OrderMarginResponse, error) {
+	if s.currency == nil {
+		return nil, errors.New("currency converter not configured")
+	}
+
+	order, err := s.repo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.currency.Normalize(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	revenue, err := s.currency.Convert(ctx, order.TotalPrice, order.Currency, target, asOf)
+	if err != nil {
+		return nil, err
+	}
+	cost, err := s.currency.Convert(ctx, order.ActualCost, s.currency.Base(), target, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &OrderMarginResponse{
+		OrderID:       order.ID,
+		OrderNo:       order.OrderNo,
+		Currency:      target,
+		RateDate:      asOf.Format("2006-01-02"),
+		SalesCurrency: order.Currency,
+		SalesAmount:   order.TotalPrice,
+		Revenue:       revenue,
+		ActualCost:    cost,
+		Margin:        revenue - cost,
+	}
+	if revenue != 0 {
+		resp.MarginPercent = resp.Margin / revenue * 100
+	}
+	return resp, nil
+}
+
 // GetDetail 获取订单详情（含完整参与者、进度、事件流）
 func (s *OrderService) GetDetail(ctx context.Context, orderID uint, userID uint) (*OrderDetailResponse, error) {
 	// 1. 查询订单基本信息（含客户和产品名称）
@@ -824,6 +1091,19 @@ func (s *OrderService) GetDetail(ctx context.Context, orderID uint, userID uint)
 		}
 	}
 
+	stepResponses := make([]StepProgressResponse, len(order.StepProgresses))
+	for i, p := range order.StepProgresses {
+		stepResponses[i] = StepProgressResponse{
+			Sequence:          p.Sequence,
+			ProcessID:         p.ProcessID,
+			Optional:          p.Optional,
+			TargetQuantity:    p.TargetQuantity,
+			CompletedQuantity: p.CompletedQuantity,
+			Progress:          p.Progress,
+			UpdatedAt:         p.UpdatedAt,
+		}
+	}
+
 	// 4. 转换事件
 	eventResponses := make([]EventResponse, len(order.Events))
 	for i, e := range order.Events {
@@ -858,15 +1138,21 @@ func (s *OrderService) GetDetail(ctx context.Context, orderID uint, userID uint)
 		ProductID:               order.ProductID,
 		ProductName:             productName,
 		ProductCode:             productCode,
+		BOMVersionID:            order.BOMVersionID,
+		BOMVersion:              order.BOMVersion,
 		ProductHistoryShrinkage: order.ProductHistoryShrinkage,
 		RequiredQuantity:        order.RequiredQuantity,
 		UnitPrice:               order.UnitPrice,
 		TotalPrice:              order.TotalPrice,
+		Currency:                order.Currency,
+		SalesPriceID:            order.SalesPriceID,
+		ActualCost:              order.ActualCost,
 		Status:                  order.Status,
 		AssignedDepartment:      order.AssignedDepartment,
 		CreatedAt:               order.CreatedAt,
 		UpdatedAt:               order.UpdatedAt,
 		ProgressItems:           progressResponses,
+		StepItems:               stepResponses,
 		OperationLogs:           eventResponses,
 		OverallProgress:         overallProgress,
 	}, nil
@@ -947,6 +1233,8 @@ func (s *OrderService) ListWithDetail(ctx context.Context, limit, offset int) (*
 		productHistoryShrinkage, _ := data["product_history_shrinkage"].(float64)
 		unitPrice, _ := data["unit_price"].(float64)
 		totalPrice, _ := data["total_price"].(float64)
+		currency, _ := data["currency"].(string)
+		actualCost, _ := data["actual_cost"].(float64)
 		status, _ := data["status"].(string)
 		assignedDepartment, _ := data["assigned_department"].(string)
 		createdAt, _ := data["created_at"].(time.Time)
@@ -1005,6 +1293,8 @@ func (s *OrderService) ListWithDetail(ctx context.Context, limit, offset int) (*
 			RequiredQuantity:        requiredQuantity,
 			UnitPrice:               unitPrice,
 			TotalPrice:              totalPrice,
+			Currency:                currency,
+			ActualCost:              actualCost,
 			Status:                  status,
 			AssignedDepartment:      assignedDepartment,
 			CreatedAt:               createdAt,
@@ -1019,4 +1309,231 @@ func (s *OrderService) ListWithDetail(ctx context.Context, limit, offset int) (*
 		Total:  total,
 		Orders: orders,
 	}, nil
-}
\ No newline at end of file
+}
+// GetOrderTraceInfo 获取订单追溯信息（供 Inventory 模块调用）
+func (s *OrderService) GetOrderTraceInfo(ctx context.Context, id uint) (*OrderTraceInfo, error) {
+	order, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &OrderTraceInfo{
+		ID:       order.ID,
+		OrderNo:  order.OrderNo,
+		ClientID: order.ClientID,
+		Status:   order.Status,
+	}
+
+	// 次品数量累计在回修进度的目标数量中
+	rework, err := s.repo.FindProgressByType(ctx, id, domain.ProgressTypeRework)
+	if err == nil {
+		info.DefectQuantity = rework.TargetQuantity
+	}
+
+	return info, nil
+}
+
+// ListOpenDemands 获取未完结订单的成品需求（供 Inventory 模块调用）
+func (s *OrderService) ListOpenDemands(ctx context.Context) ([]*OrderDemand, error) {
+	orders, err := s.repo.FindByStatuses(ctx, domain.OpenStatuses())
+	if err != nil {
+		return nil, err
+	}
+
+	demands := make([]*OrderDemand, len(orders))
+	for i, order := range orders {
+		demands[i] = &OrderDemand{
+			OrderID:          order.ID,
+			OrderNo:          order.OrderNo,
+			ProductID:        order.ProductID,
+			RequiredQuantity: order.RequiredQuantity,
+		}
+	}
+	return demands, nil
+}
+
+// GetOrderShrinkages 获取订单的历史缩率（%），按订单 ID 索引（供 Plan 模块调用）
+func (s *OrderService) GetOrderShrinkages(ctx context.Context, orderIDs []uint) (map[uint]float64, error) {
+	orders, err := s.repo.FindByIDs(ctx, orderIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	shrinkages := make(map[uint]float64, len(orders))
+	for _, order := range orders {
+		shrinkages[order.ID] = order.ProductHistoryShrinkage
+	}
+	return shrinkages, nil
+}
+
+// GetOrderBOMVersions 获取订单记录的 BOM 版本 ID，按订单 ID 索引（供 Plan 模块调用）
+func (s *OrderService) GetOrderBOMVersions(ctx context.Context, orderIDs []uint) (map[uint]uint, error) {
+	orders, err := s.repo.FindByIDs(ctx, orderIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make(map[uint]uint, len(orders))
+	for _, order := range orders {
+		versions[order.ID] = order.BOMVersionID
+	}
+	return versions, nil
+}
+
+// GetProductDefectRates 获取产品的历史次品率（%），按产品 ID 索引（供 Plan / Product 模块调用）
+func (s *OrderService) GetProductDefectRates(ctx context.Context, productIDs []uint) (map[uint]float64, error) {
+	rows, err := s.repo.SumDefectsByProduct(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	rates := make(map[uint]float64, len(rows))
+	for _, row := range rows {
+		rates[row.ProductID] = row.DefectRate()
+	}
+	return rates, nil
+}
+
+// GetProductShrinkages 获取产品的历史平均缩率（%），按产品 ID 索引（供 Product 模块调用）
+func (s *OrderService) GetProductShrinkages(ctx context.Context, productIDs []uint) (map[uint]float64, error) {
+	rows, err := s.repo.AvgShrinkageByProduct(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	shrinkages := make(map[uint]float64, len(rows))
+	for _, row := range rows {
+		shrinkages[row.ProductID] = row.Shrinkage
+	}
+	return shrinkages, nil
+}
+
+// SyncPlanProgress 计划状态变更后同步订单进度并记录事件（供 Plan 模块调用）
+//
+// 首道工序计划的完成数量计入胚布投入进度，末道工序计划的完成比例折算为加工进度。
+func (s *OrderService) SyncPlanProgress(ctx context.Context, sync *PlanProgressSync) error {
+	order, err := s.repo.FindByID(ctx, sync.OrderID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Transaction(ctx, func(txCtx context.Context) error {
+		targets := map[string]float64{
+			domain.ProgressTypeFabricInput: sync.FirstStageCompleted,
+			domain.ProgressTypeProduction:  order.RequiredQuantity * sync.LastStageRatio,
+		}
+		for progressType, completed := range targets {
+			progress, err := s.repo.FindProgressByType(txCtx, sync.OrderID, progressType)
+			if err != nil {
+				if errors.Is(err, domain.ErrProgressNotFound) {
+					continue
+				}
+				return err
+			}
+			if !progress.Exists || progress.CompletedQuantity == completed {
+				continue
+			}
+			if err := progress.SetCompleted(completed); err != nil {
+				return err
+			}
+			if err := s.repo.UpdateProgress(txCtx, progress); err != nil {
+				return err
+			}
+		}
+
+		if err := s.syncStepProgresses(txCtx, sync.OrderID, sync.Steps); err != nil {
+			return err
+		}
+
+		event := createEvent(
+			sync.OrderID,
+			domain.EventTypePlanStatusChange,
+			sync.OperatorID,
+			sync.OperatorName,
+			sync.OperatorRole,
+			map[string]interface{}{"plan_no": sync.PlanNo, "status": sync.FromStatus},
+			map[string]interface{}{"plan_no": sync.PlanNo, "status": sync.ToStatus},
+			"计划"+sync.PlanNo+"状态由"+sync.FromStatus+"变更为"+sync.ToStatus,
+		)
+		return s.repo.CreateEvent(txCtx, event)
+	})
+}
+
+// syncStepProgresses 按计划汇总结果更新订单工序进度（不存在的工序新建）
+func (s *OrderService) syncStepProgresses(ctx context.Context, orderID uint, steps []PlanStepProgress) error {
+	existing, err := s.repo.GetStepProgresses(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	bySequence := make(map[int]*domain.OrderStepProgress, len(existing))
+	for i := range existing {
+		bySequence[existing[i].Sequence] = &existing[i]
+	}
+
+	for _, step := range steps {
+		progress, ok := bySequence[step.Sequence]
+		if !ok {
+			progress = &domain.OrderStepProgress{OrderID: orderID, Sequence: step.Sequence}
+		} else if progress.TargetQuantity == step.TargetQuantity && progress.CompletedQuantity == step.CompletedQuantity {
+			continue
+		}
+		progress.ProcessID = step.ProcessID
+		progress.Optional = step.Optional
+		if err := progress.Set(step.TargetQuantity, step.CompletedQuantity); err != nil {
+			return err
+		}
+		if err := s.repo.SaveStepProgress(ctx, progress); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PlanGenerationRequest 生产计划生成请求（供其他模块使用）
+type PlanGenerationRequest struct {
+	OrderID      uint
+	ProductID    uint
+	BOMVersionID uint // 0 为产品自身配方
+	Quantity     float64
+	CreatedBy    uint
+}
+
+// PlanProgressSync 计划状态变更同步信息（供其他模块使用）
+type PlanProgressSync struct {
+	OrderID             uint
+	PlanNo              string
+	FromStatus          string
+	ToStatus            string
+	FirstStageCompleted float64 // 首道工序已完成数量
+	LastStageRatio      float64 // 末道工序完成比例（0-1）
+	Steps               []PlanStepProgress
+	OperatorID          uint
+	OperatorName        string
+	OperatorRole        string
+}
+
+// PlanStepProgress 工序完成情况（供其他模块使用）
+type PlanStepProgress struct {
+	Sequence          int
+	ProcessID         uint
+	Optional          bool
+	TargetQuantity    float64
+	CompletedQuantity float64
+}
+
+// OrderDemand 订单需求信息（供其他模块使用）
+type OrderDemand struct {
+	OrderID          uint
+	OrderNo          string
+	ProductID        uint
+	RequiredQuantity float64
+}
+
+// OrderTraceInfo 订单追溯信息（供其他模块使用）
+type OrderTraceInfo struct {
+	ID             uint
+	OrderNo        string
+	ClientID       uint
+	Status         string
+	DefectQuantity float64
+}
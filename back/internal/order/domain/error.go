@@ -12,6 +12,7 @@ var (
 	ErrProductIDRequired       = errors.New("product_id is required")
 	ErrInvalidQuantity         = errors.New("quantity must be greater than 0")
 	ErrInvalidUnitPrice        = errors.New("unit_price cannot be negative")
+	ErrUnitPriceRequired       = errors.New("unit_price is required when no sales price applies")
 	ErrCreatedByRequired       = errors.New("created_by is required")
 	ErrInvalidOrderStatus      = errors.New("invalid order status for this operation")
 	ErrCannotConfirm           = errors.New("can only confirm pending orders")
@@ -20,6 +21,7 @@ var (
 	ErrCannotCancelCompleted   = errors.New("cannot cancel completed orders")
 	ErrCannotUpdateCompleted   = errors.New("cannot update completed or cancelled orders")
 	ErrCannotDeleteCompleted   = errors.New("cannot delete completed orders")
+	ErrInvalidActualCost       = errors.New("actual cost cannot be negative")
 
 	// 参与者相关错误
 	ErrParticipantNotFound     = errors.New("participant not found")
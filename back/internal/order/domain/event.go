@@ -16,6 +16,9 @@ const (
 	EventTypeAddDefect           = "add_defect"            // 录入次品
 	EventTypeUpdateRework        = "update_rework"         // 更新回修进度
 	EventTypeChangeParticipant   = "change_participant"    // 变更参与者
+	EventTypeMaterialIssue       = "material_issue"        // 领料出库（计入实际成本）
+	EventTypeGeneratePlans       = "generate_plans"        // 生成生产计划
+	EventTypePlanStatusChange    = "plan_status_change"    // 计划状态变更
 )
 
 // OrderEvent 订单事件实体（核心）
@@ -55,6 +58,9 @@ func GetEventTypeName(eventType string) string {
 		EventTypeAddDefect:            "录入次品",
 		EventTypeUpdateRework:         "更新回修进度",
 		EventTypeChangeParticipant:    "变更参与者",
+		EventTypeMaterialIssue:        "领料出库",
+		EventTypeGeneratePlans:        "生成生产计划",
+		EventTypePlanStatusChange:     "计划状态变更",
 	}
 
 	if name, ok := names[eventType]; ok {
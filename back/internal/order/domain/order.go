@@ -24,11 +24,16 @@ type Order struct {
 	OrderNo                 string         `gorm:"size:50;uniqueIndex;not null" json:"orderNo"`
 	ClientID                uint           `gorm:"not null;index" json:"clientId"`
 	ProductID               uint           `gorm:"not null;index" json:"productId"`
+	BOMVersionID            uint           `gorm:"default:0;index" json:"bomVersionId"`                        // 生产使用的 BOM 版本（0 为产品自身配方）
+	BOMVersion              int            `gorm:"default:0" json:"bomVersion"`                                // BOM 版本号
 	RequiredQuantity        float64        `gorm:"type:decimal(10,2);not null" json:"requiredQuantity"`         // Sales填写的成品需求数量
 	ProductHistoryShrinkage float64        `gorm:"type:decimal(5,2);default:0" json:"productHistoryShrinkage"` // 历史缩率（%）
 	Quantity                float64        `gorm:"type:decimal(10,2);not null" json:"quantity"`                // 订单数量（保留兼容）
-	UnitPrice               float64        `gorm:"type:decimal(10,2);not null" json:"unitPrice"`               // 单价
+	UnitPrice               float64        `gorm:"type:decimal(12,4);not null" json:"unitPrice"`               // 单价（与销售价目精度一致）
 	TotalPrice              float64        `gorm:"type:decimal(10,2);not null" json:"totalPrice"`              // 总价
+	Currency                string         `gorm:"size:3;not null;default:CNY" json:"currency"`                // 销售币种（单价、总价以此币种计）
+	SalesPriceID            uint           `gorm:"default:0" json:"salesPriceId"`                              // 单价来源销售价目（0 为手工录入）
+	ActualCost              float64        `gorm:"type:decimal(12,2);default:0" json:"actualCost"`             // 实际成本（领料出库累计，本位币）
 	Status                  string         `gorm:"size:20;default:pending;index" json:"status"`                // 订单状态
 	AssignedDepartment      string         `gorm:"size:100" json:"assignedDepartment"`                         // 当前分配的部门（可为空）
 	CreatedBy               uint           `gorm:"not null;index" json:"createdBy"`                            // 创建人
@@ -37,9 +42,10 @@ type Order struct {
 	DeletedAt               gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// 关联（不存储在数据库，用于查询加载）
-	Participants []OrderParticipant `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE" json:"participants,omitempty"`
-	Progresses   []OrderProgress    `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE" json:"progresses,omitempty"`
-	Events       []OrderEvent       `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE" json:"events,omitempty"`
+	Participants   []OrderParticipant  `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE" json:"participants,omitempty"`
+	Progresses     []OrderProgress     `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE" json:"progresses,omitempty"`
+	StepProgresses []OrderStepProgress `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE" json:"stepProgresses,omitempty"`
+	Events         []OrderEvent        `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE" json:"events,omitempty"`
 }
 
 // TableName 表名
@@ -150,11 +156,46 @@ func (o *Order) UpdateUnitPrice(newPrice float64) error {
 		return ErrCannotUpdateCompleted
 	}
 	
+	if newPrice != o.UnitPrice {
+		o.SalesPriceID = 0
+	}
 	o.UnitPrice = newPrice
 	o.CalculateTotalPrice()
 	return nil
 }
 
+// CanAddActualCost 是否可计入实际成本（已完成或已取消的订单不再领料）
+func (o *Order) CanAddActualCost() error {
+	if o.Status == OrderStatusCompleted || o.Status == OrderStatusCancelled {
+		return ErrCannotUpdateCompleted
+	}
+	return nil
+}
+
+// AddActualCost 累加实际成本
+func (o *Order) AddActualCost(amount float64) error {
+	if amount < 0 {
+		return ErrInvalidActualCost
+	}
+	if err := o.CanAddActualCost(); err != nil {
+		return err
+	}
+
+	o.ActualCost += amount
+	return nil
+}
+
+// OpenStatuses 未完结订单状态（仍有物料需求）
+func OpenStatuses() []string {
+	return []string{
+		OrderStatusPending,
+		OrderStatusAssigned,
+		OrderStatusInProgress,
+		OrderStatusConfirmed,
+		OrderStatusProduction,
+	}
+}
+
 // CanDelete 是否可以删除
 func (o *Order) CanDelete() bool {
 	return o.Status != OrderStatusCompleted
@@ -197,11 +238,15 @@ func (o *Order) ToDocument() map[string]interface{} {
 		"orderNo":                 o.OrderNo,
 		"clientId":                o.ClientID,
 		"productId":               o.ProductID,
+		"bomVersionId":            o.BOMVersionID,
+		"bomVersion":              o.BOMVersion,
 		"requiredQuantity":        o.RequiredQuantity,
 		"productHistoryShrinkage": o.ProductHistoryShrinkage,
 		"quantity":                o.Quantity,
 		"unitPrice":               o.UnitPrice,
 		"totalPrice":              o.TotalPrice,
+		"currency":                o.Currency,
+		"actualCost":              o.ActualCost,
 		"status":                  o.Status,
 		"assignedDepartment":      o.AssignedDepartment,
 		"createdBy":               o.CreatedBy,
@@ -84,3 +84,60 @@ func (p *OrderProgress) MarkAsNonExistent() {
 func (p *OrderProgress) MarkAsExistent() {
 	p.Exists = true
 }
+
+// OrderStepProgress 订单工序进度（按工艺路线的每道工序，由生产计划同步）
+type OrderStepProgress struct {
+	ID                uint      `gorm:"primaryKey" json:"id"`
+	OrderID           uint      `gorm:"not null;uniqueIndex:idx_order_step" json:"order_id"`      // 订单ID
+	Sequence          int       `gorm:"not null;uniqueIndex:idx_order_step" json:"sequence"`      // 工序序号
+	ProcessID         uint      `gorm:"index" json:"process_id"`                                  // 工序ID
+	Optional          bool      `gorm:"default:false" json:"optional"`                            // 是否可选工序
+	TargetQuantity    float64   `gorm:"type:decimal(10,2);not null" json:"target_quantity"`       // 计划投入数量
+	CompletedQuantity float64   `gorm:"type:decimal(10,2);default:0" json:"completed_quantity"`   // 已完成数量
+	Progress          int       `gorm:"default:0" json:"progress"`                                // 百分比（0-100）
+	CreatedAt         time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 表名
+func (OrderStepProgress) TableName() string {
+	return "order_step_progresses"
+}
+
+// Set 设置目标与完成数量并重新计算进度
+func (p *OrderStepProgress) Set(target, completed float64) error {
+	if target < 0 || completed < 0 {
+		return ErrInvalidQuantity
+	}
+	p.TargetQuantity = target
+	p.CompletedQuantity = completed
+	p.Progress = 0
+	if target > 0 {
+		p.Progress = int(completed / target * 100)
+		if p.Progress > 100 {
+			p.Progress = 100
+		}
+	}
+	return nil
+}
+
+// DefectHistoryRow 产品历史次品统计（按已完成订单汇总）
+type DefectHistoryRow struct {
+	ProductID        uint
+	RequiredQuantity float64
+	DefectQuantity   float64
+}
+
+// DefectRate 历史次品率（%）
+func (r DefectHistoryRow) DefectRate() float64 {
+	if r.RequiredQuantity <= 0 {
+		return 0
+	}
+	return r.DefectQuantity / r.RequiredQuantity * 100
+}
+
+// ShrinkageHistoryRow 产品历史平均缩率（按已完成订单汇总）
+type ShrinkageHistoryRow struct {
+	ProductID uint
+	Shrinkage float64 // %
+}
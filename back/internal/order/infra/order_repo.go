@@ -90,7 +90,7 @@ func (r *OrderRepo) ExistsByOrderNo(ctx context.Context, orderNo string) (bool,
 // FindByClientID 根据客户 ID 查询
 func (r *OrderRepo) FindByClientID(ctx context.Context, clientID uint) ([]*domain.Order, error) {
 	var result []*domain.Order
-	err := r.db.WithContext(ctx).
+	err := repo.Conn(ctx, r.db).
 		Where("client_id = ?", clientID).
 		Order("created_at DESC").
 		Find(&result).Error
@@ -100,7 +100,7 @@ func (r *OrderRepo) FindByClientID(ctx context.Context, clientID uint) ([]*domai
 // FindByProductID 根据产品 ID 查询
 func (r *OrderRepo) FindByProductID(ctx context.Context, productID uint) ([]*domain.Order, error) {
 	var result []*domain.Order
-	err := r.db.WithContext(ctx).
+	err := repo.Conn(ctx, r.db).
 		Where("product_id = ?", productID).
 		Order("created_at DESC").
 		Find(&result).Error
@@ -110,7 +110,7 @@ func (r *OrderRepo) FindByProductID(ctx context.Context, productID uint) ([]*dom
 // FindByStatus 根据状态查询
 func (r *OrderRepo) FindByStatus(ctx context.Context, status string, limit, offset int) ([]*domain.Order, error) {
 	var result []*domain.Order
-	err := r.db.WithContext(ctx).
+	err := repo.Conn(ctx, r.db).
 		Where("status = ?", status).
 		Limit(limit).
 		Offset(offset).
@@ -119,6 +119,28 @@ func (r *OrderRepo) FindByStatus(ctx context.Context, status string, limit, offs
 	return result, err
 }
 
+// FindByStatuses 根据多个状态查询（不分页）
+func (r *OrderRepo) FindByStatuses(ctx context.Context, statuses []string) ([]*domain.Order, error) {
+	var result []*domain.Order
+	err := repo.Conn(ctx, r.db).
+		Where("status IN ?", statuses).
+		Order("created_at ASC").
+		Find(&result).Error
+	return result, err
+}
+
+// FindByIDs 根据多个 ID 查询
+func (r *OrderRepo) FindByIDs(ctx context.Context, ids []uint) ([]*domain.Order, error) {
+	var result []*domain.Order
+	if len(ids) == 0 {
+		return result, nil
+	}
+	err := repo.Conn(ctx, r.db).
+		Where("id IN ?", ids).
+		Find(&result).Error
+	return result, err
+}
+
 // Count 统计数量
 func (r *OrderRepo) Count(ctx context.Context) (int64, error) {
 	return r.Repo.Count(ctx, map[string]interface{}{})
@@ -128,13 +150,13 @@ func (r *OrderRepo) Count(ctx context.Context) (int64, error) {
 
 // CreateParticipant 创建参与者
 func (r *OrderRepo) CreateParticipant(ctx context.Context, participant *domain.OrderParticipant) error {
-	return r.db.WithContext(ctx).Create(participant).Error
+	return repo.Conn(ctx, r.db).Create(participant).Error
 }
 
 // GetParticipants 获取订单的所有参与者
 func (r *OrderRepo) GetParticipants(ctx context.Context, orderID uint) ([]domain.OrderParticipant, error) {
 	var participants []domain.OrderParticipant
-	err := r.db.WithContext(ctx).
+	err := repo.Conn(ctx, r.db).
 		Where("order_id = ?", orderID).
 		Order("created_at ASC").
 		Find(&participants).Error
@@ -144,7 +166,7 @@ func (r *OrderRepo) GetParticipants(ctx context.Context, orderID uint) ([]domain
 // GetActiveParticipants 获取订单的当前激活参与者
 func (r *OrderRepo) GetActiveParticipants(ctx context.Context, orderID uint) ([]domain.OrderParticipant, error) {
 	var participants []domain.OrderParticipant
-	err := r.db.WithContext(ctx).
+	err := repo.Conn(ctx, r.db).
 		Where("order_id = ? AND is_active = ?", orderID, true).
 		Order("created_at ASC").
 		Find(&participants).Error
@@ -153,7 +175,7 @@ func (r *OrderRepo) GetActiveParticipants(ctx context.Context, orderID uint) ([]
 
 // DeactivateParticipant 停用参与者
 func (r *OrderRepo) DeactivateParticipant(ctx context.Context, participantID uint) error {
-	return r.db.WithContext(ctx).
+	return repo.Conn(ctx, r.db).
 		Model(&domain.OrderParticipant{}).
 		Where("id = ?", participantID).
 		Update("is_active", false).Error
@@ -162,7 +184,7 @@ func (r *OrderRepo) DeactivateParticipant(ctx context.Context, participantID uin
 // FindParticipantByRole 根据角色查找参与者
 func (r *OrderRepo) FindParticipantByRole(ctx context.Context, orderID uint, role string) (*domain.OrderParticipant, error) {
 	var participant domain.OrderParticipant
-	err := r.db.WithContext(ctx).
+	err := repo.Conn(ctx, r.db).
 		Where("order_id = ? AND role = ? AND is_active = ?", orderID, role, true).
 		First(&participant).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -175,18 +197,18 @@ func (r *OrderRepo) FindParticipantByRole(ctx context.Context, orderID uint, rol
 
 // CreateProgress 创建进度项
 func (r *OrderRepo) CreateProgress(ctx context.Context, progress *domain.OrderProgress) error {
-	return r.db.WithContext(ctx).Create(progress).Error
+	return repo.Conn(ctx, r.db).Create(progress).Error
 }
 
 // UpdateProgress 更新进度项
 func (r *OrderRepo) UpdateProgress(ctx context.Context, progress *domain.OrderProgress) error {
-	return r.db.WithContext(ctx).Save(progress).Error
+	return repo.Conn(ctx, r.db).Save(progress).Error
 }
 
 // GetProgresses 获取订单的所有进度项
 func (r *OrderRepo) GetProgresses(ctx context.Context, orderID uint) ([]domain.OrderProgress, error) {
 	var progresses []domain.OrderProgress
-	err := r.db.WithContext(ctx).
+	err := repo.Conn(ctx, r.db).
 		Where("order_id = ?", orderID).
 		Order("created_at ASC").
 		Find(&progresses).Error
@@ -196,7 +218,7 @@ func (r *OrderRepo) GetProgresses(ctx context.Context, orderID uint) ([]domain.O
 // FindProgressByType 根据类型查找进度项
 func (r *OrderRepo) FindProgressByType(ctx context.Context, orderID uint, progressType string) (*domain.OrderProgress, error) {
 	var progress domain.OrderProgress
-	err := r.db.WithContext(ctx).
+	err := repo.Conn(ctx, r.db).
 		Where("order_id = ? AND type = ?", orderID, progressType).
 		First(&progress).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -205,17 +227,63 @@ func (r *OrderRepo) FindProgressByType(ctx context.Context, orderID uint, progre
 	return &progress, err
 }
 
+// GetStepProgresses 获取订单的工序进度（按序号排列）
+func (r *OrderRepo) GetStepProgresses(ctx context.Context, orderID uint) ([]domain.OrderStepProgress, error) {
+	var progresses []domain.OrderStepProgress
+	err := repo.Conn(ctx, r.db).
+		Where("order_id = ?", orderID).
+		Order("sequence ASC").
+		Find(&progresses).Error
+	return progresses, err
+}
+
+// SaveStepProgress 保存工序进度
+func (r *OrderRepo) SaveStepProgress(ctx context.Context, progress *domain.OrderStepProgress) error {
+	return repo.Conn(ctx, r.db).Save(progress).Error
+}
+
+// SumDefectsByProduct 按产品汇总已完成订单的需求数量与次品数量（次品数量取回修进度的目标数量）
+func (r *OrderRepo) SumDefectsByProduct(ctx context.Context, productIDs []uint) ([]domain.DefectHistoryRow, error) {
+	var rows []domain.DefectHistoryRow
+	if len(productIDs) == 0 {
+		return rows, nil
+	}
+	err := repo.Conn(ctx, r.db).
+		Model(&domain.Order{}).
+		Select("orders.product_id, SUM(orders.required_quantity) AS required_quantity, COALESCE(SUM(p.target_quantity), 0) AS defect_quantity").
+		Joins("LEFT JOIN order_progresses p ON p.order_id = orders.id AND p.type = ?", domain.ProgressTypeRework).
+		Where("orders.status = ? AND orders.product_id IN ?", domain.OrderStatusCompleted, productIDs).
+		Group("orders.product_id").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// AvgShrinkageByProduct 按产品统计已完成订单的平均历史缩率（%），只计填写了缩率的订单
+func (r *OrderRepo) AvgShrinkageByProduct(ctx context.Context, productIDs []uint) ([]domain.ShrinkageHistoryRow, error) {
+	var rows []domain.ShrinkageHistoryRow
+	if len(productIDs) == 0 {
+		return rows, nil
+	}
+	err := repo.Conn(ctx, r.db).
+		Model(&domain.Order{}).
+		Select("product_id, AVG(product_history_shrinkage) AS shrinkage").
+		Where("status = ? AND product_id IN ? AND product_history_shrinkage > 0", domain.OrderStatusCompleted, productIDs).
+		Group("product_id").
+		Scan(&rows).Error
+	return rows, err
+}
+
 // ==================== 事件管理 ====================
 
 // CreateEvent 创建事件
 func (r *OrderRepo) CreateEvent(ctx context.Context, event *domain.OrderEvent) error {
-	return r.db.WithContext(ctx).Create(event).Error
+	return repo.Conn(ctx, r.db).Create(event).Error
 }
 
 // GetEvents 获取订单的所有事件（时间降序）
 func (r *OrderRepo) GetEvents(ctx context.Context, orderID uint) ([]domain.OrderEvent, error) {
 	var events []domain.OrderEvent
-	err := r.db.WithContext(ctx).
+	err := repo.Conn(ctx, r.db).
 		Where("order_id = ?", orderID).
 		Order("created_at DESC").
 		Find(&events).Error
@@ -227,9 +295,12 @@ func (r *OrderRepo) GetEvents(ctx context.Context, orderID uint) ([]domain.Order
 // GetOrderWithDetails 获取订单及其所有关联数据
 func (r *OrderRepo) GetOrderWithDetails(ctx context.Context, orderID uint) (*domain.Order, error) {
 	var order domain.Order
-	err := r.db.WithContext(ctx).
+	err := repo.Conn(ctx, r.db).
 		Preload("Participants").
 		Preload("Progresses").
+		Preload("StepProgresses", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sequence ASC")
+		}).
 		Preload("Events", func(db *gorm.DB) *gorm.DB {
 			return db.Order("created_at DESC")
 		}).
@@ -245,7 +316,7 @@ func (r *OrderRepo) GetOrderWithDetails(ctx context.Context, orderID uint) (*dom
 func (r *OrderRepo) GetOrderWithClientProduct(ctx context.Context, orderID uint) (map[string]interface{}, error) {
 	var result map[string]interface{}
 
-	err := r.db.WithContext(ctx).
+	err := repo.Conn(ctx, r.db).
 		Table("orders").
 		Select(`
 			orders.*,
@@ -270,10 +341,10 @@ func (r *OrderRepo) GetOrdersWithClientProduct(ctx context.Context, limit, offse
 	var total int64
 
 	// 获取总数
-	r.db.WithContext(ctx).Table("orders").Count(&total)
+	repo.Conn(ctx, r.db).Table("orders").Count(&total)
 
 	// 获取数据
-	err := r.db.WithContext(ctx).
+	err := repo.Conn(ctx, r.db).
 		Table("orders").
 		Select(`
 			orders.id,
@@ -287,6 +358,8 @@ func (r *OrderRepo) GetOrdersWithClientProduct(ctx context.Context, limit, offse
 			orders.product_history_shrinkage,
 			orders.unit_price,
 			orders.total_price,
+			orders.currency,
+			orders.actual_cost,
 			orders.status,
 			orders.assigned_department,
 			orders.created_at,
@@ -305,7 +378,7 @@ func (r *OrderRepo) GetOrdersWithClientProduct(ctx context.Context, limit, offse
 // GetProgressesByOrderIDs 批量获取订单进度
 func (r *OrderRepo) GetProgressesByOrderIDs(ctx context.Context, orderIDs []uint) ([]domain.OrderProgress, error) {
 	var progresses []domain.OrderProgress
-	err := r.db.WithContext(ctx).
+	err := repo.Conn(ctx, r.db).
 		Where("order_id IN ?", orderIDs).
 		Find(&progresses).Error
 	return progresses, err
@@ -314,18 +387,16 @@ func (r *OrderRepo) GetProgressesByOrderIDs(ctx context.Context, orderIDs []uint
 // GetEventsByOrderIDs 批量获取订单事件
 func (r *OrderRepo) GetEventsByOrderIDs(ctx context.Context, orderIDs []uint) ([]domain.OrderEvent, error) {
 	var events []domain.OrderEvent
-	err := r.db.WithContext(ctx).
+	err := repo.Conn(ctx, r.db).
 		Where("order_id IN ?", orderIDs).
 		Order("created_at DESC").
 		Find(&events).Error
 	return events, err
 }
 
-// Transaction 执行事务
+// Transaction 执行事务（上下文已携带事务时加入该事务；回调上下文携带事务，同一上下文下的仓储操作均在事务中执行）
 func (r *OrderRepo) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		// 创建新的上下文，包含事务
-		txCtx := context.WithValue(ctx, "tx", tx)
-		return fn(txCtx)
+	return repo.Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		return fn(repo.WithTx(ctx, tx))
 	})
 }
\ No newline at end of file
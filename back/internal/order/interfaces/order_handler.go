@@ -4,11 +4,13 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"back/pkg/audit"
 	"back/pkg/endpoint"
+	currencyDomain "back/internal/currency/domain"
 	"back/internal/order/application"
 	"back/internal/order/domain"
 )
@@ -75,7 +77,7 @@ func getUserInfo(c *gin.Context) (loginID uint, username string, role string, er
 
 // Create 创建订单
 // @Summary      创建订单
-// @Description  创建新的订单信息
+// @Description  创建新的订单信息；未填写单价时取客户适用的销售价目，单价低于成本加成下限时返回 price_warning（不阻止创建）
 // @Tags         订单管理
 // @Accept       json
 // @Produce      json
@@ -98,6 +100,10 @@ func (h *OrderHandler) Create(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "订单编号已存在"})
 			return
 		}
+		if errors.Is(err, domain.ErrUnitPriceRequired) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "该客户没有适用的销售价目，请填写单价"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -232,7 +238,7 @@ func (h *OrderHandler) Delete(c *gin.Context) {
 
 // CreateV2 创建订单（新版，支持协作工作流）
 // @Summary      创建订单（新版）
-// @Description  创建新订单，记录参与者、初始化进度、记录事件
+// @Description  创建新订单，记录参与者、初始化进度、记录事件；未填写单价时取客户适用的销售价目，单价低于成本加成下限时返回 price_warning（不阻止创建）
 // @Tags         订单管理
 // @Accept       json
 // @Produce      json
@@ -262,6 +268,10 @@ func (h *OrderHandler) CreateV2(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "订单编号已存在"})
 			return
 		}
+		if errors.Is(err, domain.ErrUnitPriceRequired) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "该客户没有适用的销售价目，请填写单价"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -618,6 +628,53 @@ func (h *OrderHandler) GetDetail(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+// GetMargin 获取订单毛利
+// @Summary      获取订单毛利
+// @Description  订单总价（销售币种）与实际成本（本位币）按指定日期汇率换算为同一币种后计算毛利
+// @Tags         订单管理
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "订单ID"
+// @Param        currency query string false "报告币种（默认本位币）"
+// @Param        date query string false "汇率日期 YYYY-MM-DD（默认今天）"
+// @Success      200 {object} application.OrderMarginResponse "订单毛利"
+// @Failure      400 {object} map[string]string "参数错误或缺少汇率"
+// @Failure      404 {object} map[string]string "订单不存在"
+// @Security     Bearer
+// @Router       /order/{id}/margin [get]
+func (h *OrderHandler) GetMargin(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的订单ID"})
+		return
+	}
+
+	asOf := time.Now()
+	if date := c.Query("date"); date != "" {
+		if asOf, err = time.ParseInLocation(currencyDomain.DateLayout, date, time.Local); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "日期格式应为 YYYY-MM-DD"})
+			return
+		}
+	}
+
+	resp, err := h.service.GetMargin(c.Request.Context(), uint(id), c.Query("currency"), asOf)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrOrderNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "订单不存在"})
+		case errors.Is(err, currencyDomain.ErrInvalidCurrency):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的币种代码"})
+		case errors.Is(err, currencyDomain.ErrRateNotFound):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
 // GetEvents 获取订单事件流
 // @Summary      获取订单事件流
 // @Description  获取订单的完整操作历史事件流
@@ -687,5 +744,6 @@ func (h *OrderHandler) GetRoutes() []endpoint.RouteDefinition {
 		{Method: "GET", Path: "/order/list-detail", Handler: h.ListWithDetail, Domain: "", Action: ""},
 		{Method: "GET", Path: "/order/:id/detail", Handler: h.GetDetail, Domain: "", Action: ""},
 		{Method: "GET", Path: "/order/:id/events", Handler: h.GetEvents, Domain: "", Action: ""},
+		{Method: "GET", Path: "/order/:id/margin", Handler: h.GetMargin, Domain: "order", Action: "margin"},
 	}
 }
\ No newline at end of file
@@ -9,6 +9,7 @@ type CreatePlanRequest struct {
 	ProductID   uint       `json:"product_id" binding:"required"`
 	Quantity    float64    `json:"quantity" binding:"required,gt=0"`
 	ScheduledAt *time.Time `json:"scheduled_at" binding:"omitempty"`
+	DueDate     *time.Time `json:"due_date" binding:"omitempty"`
 	CreatedBy   uint       `json:"created_by" binding:"required"`
 }
 
@@ -17,25 +18,181 @@ type UpdatePlanRequest struct {
 	Status      string     `json:"status" binding:"omitempty,oneof=planned in_progress completed cancelled"`
 	Quantity    float64    `json:"quantity" binding:"omitempty,gt=0"`
 	CompletedAt *time.Time `json:"completed_at" binding:"omitempty"`
+	DueDate     *time.Time `json:"due_date" binding:"omitempty"`
 }
 
 // PlanResponse 计划响应
 type PlanResponse struct {
-	ID          uint       `json:"id"`
-	PlanNo      string     `json:"plan_no"`
-	OrderID     uint       `json:"order_id"`
-	ProductID   uint       `json:"product_id"`
-	Quantity    float64    `json:"quantity"`
-	Status      string     `json:"status"`
-	ScheduledAt *time.Time `json:"scheduled_at,omitempty"`
-	CompletedAt *time.Time `json:"completed_at,omitempty"`
-	CreatedBy   uint       `json:"created_by"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ID           uint       `json:"id"`
+	PlanNo       string     `json:"plan_no"`
+	OrderID      uint       `json:"order_id"`
+	ProductID    uint       `json:"product_id"`
+	Quantity     float64    `json:"quantity"`
+	ProcessID    uint       `json:"process_id"`
+	Sequence     int        `json:"sequence"`
+	Predecessors []int      `json:"predecessors"`
+	Optional     bool       `json:"optional"`
+	BatchNo      int        `json:"batch_no"`
+	Status       string     `json:"status"`
+	ScheduledAt  *time.Time `json:"scheduled_at,omitempty"`
+	ScheduledEnd *time.Time `json:"scheduled_end,omitempty"`
+	DueDate      *time.Time `json:"due_date,omitempty"`
+	WorkCenterID uint       `json:"work_center_id"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+	CreatedBy    uint       `json:"created_by"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// StepProgressResponse 工序完成情况响应
+type StepProgressResponse struct {
+	Sequence     int     `json:"sequence"`
+	ProcessID    uint    `json:"process_id"`
+	Predecessors []int   `json:"predecessors"`
+	Optional     bool    `json:"optional"`
+	Quantity     float64 `json:"quantity"`
+	Completed    float64 `json:"completed"`
+	Progress     int     `json:"progress"` // 百分比（0-100）
 }
 
 // PlanListResponse 计划列表响应
 type PlanListResponse struct {
 	Total int64           `json:"total"`
 	Plans []*PlanResponse `json:"plans"`
-}
\ No newline at end of file
+}
+
+// MRPRequest 物料需求计算请求
+type MRPRequest struct {
+	DefectRate   *float64 // 次品率（%），为空时按产品历史次品率
+	ShortageOnly bool     // 仅返回缺料原料
+}
+
+// MRPLineResponse 原料需求明细（按计划）
+type MRPLineResponse struct {
+	PlanID       uint       `json:"plan_id"`
+	PlanNo       string     `json:"plan_no"`
+	OrderID      uint       `json:"order_id"`
+	RequiredBy   *time.Time `json:"required_by,omitempty"`
+	Shrinkage    float64    `json:"shrinkage"`
+	DefectRate   float64    `json:"defect_rate"`
+	Gross        float64    `json:"gross"`
+	Issued       float64    `json:"issued"` // 订单已领用
+	FromReserved float64    `json:"from_reserved"`
+	FromStock    float64    `json:"from_stock"`
+	Shortage     float64    `json:"shortage"`
+}
+
+// MRPMaterialResponse 原料需求汇总
+type MRPMaterialResponse struct {
+	MaterialID uint               `json:"material_id"`
+	Gross      float64            `json:"gross"`
+	Issued     float64            `json:"issued"`
+	OnHand     float64            `json:"on_hand"`
+	Reserved   float64            `json:"reserved"`
+	Available  float64            `json:"available"`
+	Shortage   float64            `json:"shortage"`
+	RequiredBy *time.Time         `json:"required_by,omitempty"` // 最早缺料的需求日期
+	Lines      []*MRPLineResponse `json:"lines"`
+}
+
+// MRPResponse 物料需求计算结果
+type MRPResponse struct {
+	RunAt     time.Time              `json:"run_at"`
+	PlanCount int                    `json:"plan_count"`
+	Skipped   []string               `json:"skipped"` // 无法展开 BOM 的计划编号
+	Materials []*MRPMaterialResponse `json:"materials"`
+}
+
+// SaveWorkCenterRequest 保存工作中心请求（ID 为 0 时新建）
+type SaveWorkCenterRequest struct {
+	ID            uint    `json:"id"`
+	Code          string  `json:"code" binding:"required,max=50"`
+	Name          string  `json:"name" binding:"required,max=100"`
+	ProcessID     uint    `json:"process_id"`
+	DailyCapacity float64 `json:"daily_capacity" binding:"required,gt=0"`
+	Enabled       *bool   `json:"enabled"`
+}
+
+// WorkCenterResponse 工作中心响应
+type WorkCenterResponse struct {
+	ID            uint      `json:"id"`
+	Code          string    `json:"code"`
+	Name          string    `json:"name"`
+	ProcessID     uint      `json:"process_id"`
+	DailyCapacity float64   `json:"daily_capacity"`
+	Enabled       bool      `json:"enabled"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// RunScheduleRequest 排程请求
+type RunScheduleRequest struct {
+	From *time.Time `json:"from"` // 排程起始日，默认今天
+}
+
+// RescheduleRequest 重新排程请求
+type RescheduleRequest struct {
+	StartDate time.Time `json:"start_date" binding:"required"`
+}
+
+// ScheduledPlanResponse 排程结果
+type ScheduledPlanResponse struct {
+	PlanID       uint       `json:"plan_id"`
+	PlanNo       string     `json:"plan_no"`
+	OrderID      uint       `json:"order_id"`
+	Sequence     int        `json:"sequence"`
+	WorkCenterID uint       `json:"work_center_id"`
+	Start        *time.Time `json:"start,omitempty"`
+	End          *time.Time `json:"end,omitempty"`
+	DueDate      *time.Time `json:"due_date,omitempty"`
+	Late         bool       `json:"late"`
+	Reason       string     `json:"reason,omitempty"` // 未排程原因
+}
+
+// ScheduleRunResponse 排程运行结果
+type ScheduleRunResponse struct {
+	From        time.Time                `json:"from"`
+	Scheduled   []*ScheduledPlanResponse `json:"scheduled"`
+	Unscheduled []*ScheduledPlanResponse `json:"unscheduled"`
+	LateCount   int                      `json:"late_count"`
+}
+
+// DayLoadResponse 工作中心每日负荷
+type DayLoadResponse struct {
+	Date       time.Time `json:"date"`
+	Load       float64   `json:"load"`
+	Capacity   float64   `json:"capacity"`
+	OverBooked bool      `json:"over_booked"`
+}
+
+// GanttBarResponse 甘特图条目
+type GanttBarResponse struct {
+	PlanID    uint       `json:"plan_id"`
+	PlanNo    string     `json:"plan_no"`
+	OrderID   uint       `json:"order_id"`
+	ProcessID uint       `json:"process_id"`
+	Sequence  int        `json:"sequence"`
+	BatchNo   int        `json:"batch_no"`
+	Status    string     `json:"status"`
+	Quantity  float64    `json:"quantity"`
+	Start     *time.Time `json:"start,omitempty"`
+	End       *time.Time `json:"end,omitempty"`
+	DueDate   *time.Time `json:"due_date,omitempty"`
+	Late      bool       `json:"late"`
+	DependsOn []uint     `json:"depends_on"` // 前道工序计划ID
+}
+
+// WorkCenterScheduleResponse 工作中心排程视图
+type WorkCenterScheduleResponse struct {
+	WorkCenter     *WorkCenterResponse `json:"work_center"`
+	Days           []*DayLoadResponse  `json:"days"`
+	OverBookedDays int                 `json:"over_booked_days"`
+	Bars           []*GanttBarResponse `json:"bars"`
+}
+
+// ScheduleViewResponse 排程视图（按工作中心）
+type ScheduleViewResponse struct {
+	From        time.Time                     `json:"from"`
+	To          time.Time                     `json:"to"`
+	WorkCenters []*WorkCenterScheduleResponse `json:"work_centers"`
+}
@@ -0,0 +1,247 @@
+package application
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	inventoryApp "back/internal/inventory/application"
+	orderApp "back/internal/order/application"
+	"back/internal/plan/domain"
+	"back/internal/plan/infra"
+	productApp "back/internal/product/application"
+	productDomain "back/internal/product/domain"
+)
+
+// MRPService 物料需求计划服务（按计划展开 BOM，冲销库存与预留后给出缺料）
+type MRPService struct {
+	repo                 *infra.PlanRepo
+	orderService         *orderApp.OrderService
+	productService       *productApp.ProductService
+	replenishmentService *inventoryApp.ReplenishmentService
+}
+
+// NewMRPService 创建物料需求计划服务
+func NewMRPService(
+	repo *infra.PlanRepo,
+	orderService *orderApp.OrderService,
+	productService *productApp.ProductService,
+	replenishmentService *inventoryApp.ReplenishmentService,
+) *MRPService {
+	return &MRPService{
+		repo:                 repo,
+		orderService:         orderService,
+		productService:       productService,
+		replenishmentService: replenishmentService,
+	}
+}
+
+// RunMRP 对已计划/进行中的计划运行物料需求计算
+//
+// 仅首道工序计划参与 BOM 展开；毛需求 = 计划数量 × 原料占比 ÷ (1-缩率) ÷ (1-次品率)；按需求日期先后，
+// 先扣除计划所属订单已领用的数量，再用订单的预留冲销，最后用未被预留的在库数量冲销，剩余即缺料。
+func (s *MRPService) RunMRP(ctx context.Context, req *MRPRequest) (*MRPResponse, error) {
+	if req.DefectRate != nil && (*req.DefectRate < 0 || *req.DefectRate > domain.MaxLossPercent) {
+		return nil, domain.ErrInvalidDefectRate
+	}
+
+	plans, err := s.repo.FindByStatuses(ctx, domain.MRPStatuses())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &MRPResponse{
+		RunAt:     time.Now(),
+		PlanCount: len(plans),
+		Skipped:   []string{},
+		Materials: []*MRPMaterialResponse{},
+	}
+	if len(plans) == 0 {
+		return resp, nil
+	}
+
+	requirements, lines, err := s.explode(ctx, plans, req.DefectRate, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	stocks, err := s.replenishmentService.ListMaterialStock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	orderIDs := make([]uint, len(plans))
+	for i, plan := range plans {
+		orderIDs[i] = plan.OrderID
+	}
+	issued, err := s.replenishmentService.ListIssuedMaterials(ctx, orderIDs)
+	if err != nil {
+		return nil, err
+	}
+	stockByMaterial := make(map[uint]*inventoryApp.MaterialStock, len(stocks))
+	for _, stock := range stocks {
+		stockByMaterial[stock.MaterialID] = stock
+	}
+
+	materialIDs := make([]uint, 0, len(requirements))
+	for materialID := range requirements {
+		materialIDs = append(materialIDs, materialID)
+	}
+	sort.Slice(materialIDs, func(i, j int) bool { return materialIDs[i] < materialIDs[j] })
+
+	for _, materialID := range materialIDs {
+		material := s.netMaterial(materialID, requirements[materialID], lines, stockByMaterial[materialID], issued[materialID])
+		if req.ShortageOnly && material.Shortage <= 0 {
+			continue
+		}
+		resp.Materials = append(resp.Materials, material)
+	}
+
+	// 最早缺料的原料排在前面
+	sort.SliceStable(resp.Materials, func(i, j int) bool {
+		a, b := resp.Materials[i], resp.Materials[j]
+		if (a.Shortage > 0) != (b.Shortage > 0) {
+			return a.Shortage > 0
+		}
+		if a.RequiredBy == nil || b.RequiredBy == nil {
+			return a.RequiredBy != nil
+		}
+		return a.RequiredBy.Before(*b.RequiredBy)
+	})
+
+	return resp, nil
+}
+
+// explode 按产品 BOM 展开各计划的原料毛需求
+func (s *MRPService) explode(ctx context.Context, plans []*domain.Plan, defectRate *float64, resp *MRPResponse) (map[uint][]*domain.MaterialRequirement, map[*domain.MaterialRequirement]*MRPLineResponse, error) {
+	orderIDs := make([]uint, 0, len(plans))
+	productIDs := make([]uint, 0, len(plans))
+	for _, plan := range plans {
+		orderIDs = append(orderIDs, plan.OrderID)
+		productIDs = append(productIDs, plan.ProductID)
+	}
+
+	shrinkages, err := s.orderService.GetOrderShrinkages(ctx, orderIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	bomVersions, err := s.orderService.GetOrderBOMVersions(ctx, orderIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	defectRates := make(map[uint]float64)
+	if defectRate == nil {
+		defectRates, err = s.orderService.GetProductDefectRates(ctx, productIDs)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// 按订单记录的 BOM 版本展开（同一产品的不同订单可能使用不同版本）
+	type bomKey struct{ productID, versionID uint }
+	boms := make(map[bomKey][]productDomain.MaterialConfig)
+	requirements := make(map[uint][]*domain.MaterialRequirement)
+	lines := make(map[*domain.MaterialRequirement]*MRPLineResponse)
+
+	for _, plan := range plans {
+		// 后续工序加工的是前道工序的半成品，只有首道工序投料
+		if !plan.IsFirstStage() {
+			continue
+		}
+
+		key := bomKey{plan.ProductID, bomVersions[plan.OrderID]}
+		materials, ok := boms[key]
+		if !ok {
+			materials, err = s.productService.GetMaterials(ctx, key.productID, key.versionID)
+			if err != nil {
+				// 产品或 BOM 版本已删除时无法展开
+				materials = nil
+			}
+			boms[key] = materials
+		}
+		if len(materials) == 0 {
+			resp.Skipped = append(resp.Skipped, plan.PlanNo)
+			continue
+		}
+
+		rate := defectRates[plan.ProductID]
+		if defectRate != nil {
+			rate = *defectRate
+		}
+		shrinkage := shrinkages[plan.OrderID]
+
+		for _, m := range materials {
+			requirement := &domain.MaterialRequirement{
+				PlanID:     plan.ID,
+				PlanNo:     plan.PlanNo,
+				OrderID:    plan.OrderID,
+				MaterialID: m.MaterialID,
+				RequiredBy: plan.ScheduledAt,
+				Gross:      domain.GrossRequirement(plan.Quantity, m.Ratio, shrinkage, rate),
+			}
+			requirements[m.MaterialID] = append(requirements[m.MaterialID], requirement)
+			lines[requirement] = &MRPLineResponse{
+				PlanID:     plan.ID,
+				PlanNo:     plan.PlanNo,
+				OrderID:    plan.OrderID,
+				RequiredBy: plan.ScheduledAt,
+				Shrinkage:  shrinkage,
+				DefectRate: rate,
+			}
+		}
+	}
+
+	return requirements, lines, nil
+}
+
+// netMaterial 按需求日期先后冲销单个原料的需求（issuedByOrder 为各订单已领用的数量）
+func (s *MRPService) netMaterial(materialID uint, requirements []*domain.MaterialRequirement, lines map[*domain.MaterialRequirement]*MRPLineResponse, stock *inventoryApp.MaterialStock, issuedByOrder map[uint]float64) *MRPMaterialResponse {
+	material := &MRPMaterialResponse{
+		MaterialID: materialID,
+		Lines:      make([]*MRPLineResponse, 0, len(requirements)),
+	}
+
+	issuedLeft := make(map[uint]float64, len(issuedByOrder))
+	for orderID, qty := range issuedByOrder {
+		issuedLeft[orderID] = qty
+	}
+	reservedByOrder := make(map[uint]float64)
+	if stock != nil {
+		material.OnHand = stock.OnHand
+		material.Reserved = stock.Reserved
+		for orderID, qty := range stock.ReservedByOrder {
+			reservedByOrder[orderID] = qty
+		}
+	}
+	// 其他订单的预留不可用于本次冲销
+	available := material.OnHand - material.Reserved
+	if available < 0 {
+		available = 0
+	}
+	material.Available = available
+
+	sort.SliceStable(requirements, func(i, j int) bool {
+		return requirements[i].RequiredBefore(requirements[j])
+	})
+
+	for _, requirement := range requirements {
+		issuedLeft[requirement.OrderID] = requirement.ApplyIssued(issuedLeft[requirement.OrderID])
+		reservedByOrder[requirement.OrderID], available = requirement.Net(reservedByOrder[requirement.OrderID], available)
+
+		line := lines[requirement]
+		line.Gross = requirement.Gross
+		line.Issued = requirement.Issued
+		line.FromReserved = requirement.FromReserved
+		line.FromStock = requirement.FromStock
+		line.Shortage = requirement.Shortage
+		material.Lines = append(material.Lines, line)
+
+		material.Gross += requirement.Gross
+		material.Issued += requirement.Issued
+		material.Shortage += requirement.Shortage
+		if requirement.Shortage > 0 && material.RequiredBy == nil {
+			material.RequiredBy = requirement.RequiredBy
+		}
+	}
+
+	return material
+}
@@ -3,9 +3,12 @@ package application
 import (
 	"context"
 	"strconv"
+	"time"
 	
+	orderApp "back/internal/order/application"
 	"back/internal/plan/domain"
 	"back/internal/plan/infra"
+	productApp "back/internal/product/application"
 )
 
 // ESSync ES 同步接口
@@ -17,15 +20,21 @@ type ESSync interface {
 
 // PlanService 计划应用服务
 type PlanService struct {
-	repo   *infra.PlanRepo
-	esSync ESSync
+	repo           *infra.PlanRepo
+	esSync         ESSync
+	orderService   *orderApp.OrderService
+	productService *productApp.ProductService
+	batchCapacity  float64 // 单个计划的产能上限（<=0 不拆分）
 }
 
 // NewPlanService 创建计划服务
-func NewPlanService(repo *infra.PlanRepo, esSync ESSync) *PlanService {
+func NewPlanService(repo *infra.PlanRepo, esSync ESSync, orderService *orderApp.OrderService, productService *productApp.ProductService, batchCapacity float64) *PlanService {
 	return &PlanService{
-		repo:   repo,
-		esSync: esSync,
+		repo:           repo,
+		esSync:         esSync,
+		orderService:   orderService,
+		productService: productService,
+		batchCapacity:  batchCapacity,
 	}
 }
 
@@ -48,6 +57,7 @@ func (s *PlanService) Create(ctx context.Context, req *CreatePlanRequest) (*Plan
 		Quantity:    req.Quantity,
 		Status:      domain.PlanStatusPlanned,
 		ScheduledAt: req.ScheduledAt,
+		DueDate:     req.DueDate,
 		CreatedBy:   req.CreatedBy,
 	}
 
@@ -68,17 +78,25 @@ func (s *PlanService) Create(ctx context.Context, req *CreatePlanRequest) (*Plan
 
 	// 6. Domain Model → DTO
 	return &PlanResponse{
-		ID:          plan.ID,
-		PlanNo:      plan.PlanNo,
-		OrderID:     plan.OrderID,
-		ProductID:   plan.ProductID,
-		Quantity:    plan.Quantity,
-		Status:      plan.Status,
-		ScheduledAt: plan.ScheduledAt,
-		CompletedAt: plan.CompletedAt,
-		CreatedBy:   plan.CreatedBy,
-		CreatedAt:   plan.CreatedAt,
-		UpdatedAt:   plan.UpdatedAt,
+		ID:           plan.ID,
+		PlanNo:       plan.PlanNo,
+		OrderID:      plan.OrderID,
+		ProductID:    plan.ProductID,
+		Quantity:     plan.Quantity,
+		ProcessID:    plan.ProcessID,
+		Sequence:     plan.Sequence,
+		Predecessors: plan.PredecessorSequences(),
+		Optional:     plan.Optional,
+		BatchNo:      plan.BatchNo,
+		Status:       plan.Status,
+		ScheduledAt:  plan.ScheduledAt,
+		ScheduledEnd: plan.ScheduledEnd,
+		DueDate:      plan.DueDate,
+		WorkCenterID: plan.WorkCenterID,
+		CompletedAt:  plan.CompletedAt,
+		CreatedBy:    plan.CreatedBy,
+		CreatedAt:    plan.CreatedAt,
+		UpdatedAt:    plan.UpdatedAt,
 	}, nil
 }
 
@@ -90,17 +108,25 @@ func (s *PlanService) Get(ctx context.Context, id uint) (*PlanResponse, error) {
 	}
 
 	return &PlanResponse{
-		ID:          plan.ID,
-		PlanNo:      plan.PlanNo,
-		OrderID:     plan.OrderID,
-		ProductID:   plan.ProductID,
-		Quantity:    plan.Quantity,
-		Status:      plan.Status,
-		ScheduledAt: plan.ScheduledAt,
-		CompletedAt: plan.CompletedAt,
-		CreatedBy:   plan.CreatedBy,
-		CreatedAt:   plan.CreatedAt,
-		UpdatedAt:   plan.UpdatedAt,
+		ID:           plan.ID,
+		PlanNo:       plan.PlanNo,
+		OrderID:      plan.OrderID,
+		ProductID:    plan.ProductID,
+		Quantity:     plan.Quantity,
+		ProcessID:    plan.ProcessID,
+		Sequence:     plan.Sequence,
+		Predecessors: plan.PredecessorSequences(),
+		Optional:     plan.Optional,
+		BatchNo:      plan.BatchNo,
+		Status:       plan.Status,
+		ScheduledAt:  plan.ScheduledAt,
+		ScheduledEnd: plan.ScheduledEnd,
+		DueDate:      plan.DueDate,
+		WorkCenterID: plan.WorkCenterID,
+		CompletedAt:  plan.CompletedAt,
+		CreatedBy:    plan.CreatedBy,
+		CreatedAt:    plan.CreatedAt,
+		UpdatedAt:    plan.UpdatedAt,
 	}, nil
 }
 
@@ -119,17 +145,25 @@ func (s *PlanService) List(ctx context.Context, limit, offset int) (*PlanListRes
 	responses := make([]*PlanResponse, len(plans))
 	for i, p := range plans {
 		responses[i] = &PlanResponse{
-			ID:          p.ID,
-			PlanNo:      p.PlanNo,
-			OrderID:     p.OrderID,
-			ProductID:   p.ProductID,
-			Quantity:    p.Quantity,
-			Status:      p.Status,
-			ScheduledAt: p.ScheduledAt,
-			CompletedAt: p.CompletedAt,
-			CreatedBy:   p.CreatedBy,
-			CreatedAt:   p.CreatedAt,
-			UpdatedAt:   p.UpdatedAt,
+			ID:           p.ID,
+			PlanNo:       p.PlanNo,
+			OrderID:      p.OrderID,
+			ProductID:    p.ProductID,
+			Quantity:     p.Quantity,
+			ProcessID:    p.ProcessID,
+			Sequence:     p.Sequence,
+			Predecessors: p.PredecessorSequences(),
+			Optional:     p.Optional,
+			BatchNo:      p.BatchNo,
+			Status:       p.Status,
+			ScheduledAt:  p.ScheduledAt,
+			ScheduledEnd: p.ScheduledEnd,
+			DueDate:      p.DueDate,
+			WorkCenterID: p.WorkCenterID,
+			CompletedAt:  p.CompletedAt,
+			CreatedBy:    p.CreatedBy,
+			CreatedAt:    p.CreatedAt,
+			UpdatedAt:    p.UpdatedAt,
 		}
 	}
 
@@ -140,12 +174,13 @@ func (s *PlanService) List(ctx context.Context, limit, offset int) (*PlanListRes
 }
 
 // Update 更新计划
-func (s *PlanService) Update(ctx context.Context, id uint, req *UpdatePlanRequest) error {
+func (s *PlanService) Update(ctx context.Context, id uint, req *UpdatePlanRequest, operatorID uint, operatorName, operatorRole string) error {
 	// 1. 查询计划
 	plan, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		return err
 	}
+	fromStatus := plan.Status
 	
 	// 2. 更新字段（通过领域方法）
 	if req.Status != "" {
@@ -176,6 +211,10 @@ func (s *PlanService) Update(ctx context.Context, id uint, req *UpdatePlanReques
 		plan.CompletedAt = req.CompletedAt
 	}
 	
+	if req.DueDate != nil {
+		plan.DueDate = req.DueDate
+	}
+	
 	// 3. 验证
 	if err := plan.Validate(); err != nil {
 		return err
@@ -191,9 +230,140 @@ func (s *PlanService) Update(ctx context.Context, id uint, req *UpdatePlanReques
 		s.esSync.Update(plan)
 	}
 	
+	// 6. 状态变更同步到订单进度
+	if plan.Status != fromStatus {
+		return s.syncOrderProgress(ctx, plan, fromStatus, operatorID, operatorName, operatorRole)
+	}
+	
 	return nil
 }
 
+// GenerateForOrder 按订单生成生产计划：每道工序一组，并按产能拆分批次（已生成过则直接返回）
+// 在调用方的事务中保存，事务提交后由调用方通过 SyncOrderPlans 同步搜索索引
+func (s *PlanService) GenerateForOrder(ctx context.Context, req *orderApp.PlanGenerationRequest) ([]string, error) {
+	existing, err := s.repo.FindByOrderID(ctx, req.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	var planNos []string
+	for _, p := range existing {
+		if p.Sequence > 0 && p.IsActive() {
+			planNos = append(planNos, p.PlanNo)
+		}
+	}
+	if len(planNos) > 0 {
+		return planNos, nil
+	}
+
+	routing, err := s.productService.GetRouting(ctx, req.ProductID, req.BOMVersionID)
+	if err != nil {
+		return nil, err
+	}
+	// 各工序投入数量按后续工序的损耗逐级放大；无工艺时生成单工序计划
+	stages := make([]domain.Stage, len(routing))
+	for i, step := range routing {
+		stages[i] = domain.Stage{
+			ProcessID:    step.ProcessID,
+			Sequence:     step.Sequence,
+			Predecessors: step.Predecessors,
+			Optional:     step.Optional,
+			Quantity:     req.Quantity * step.InputFactor,
+		}
+	}
+	if len(stages) == 0 {
+		stages = []domain.Stage{{Sequence: 1, Quantity: req.Quantity}}
+	}
+
+	plans := domain.GenerateStagePlans(req.OrderID, req.ProductID, stages, s.batchCapacity, req.CreatedBy, time.Now())
+	for _, plan := range plans {
+		if err := plan.Validate(); err != nil {
+			return nil, err
+		}
+		if err := s.repo.Save(ctx, plan); err != nil {
+			return nil, err
+		}
+		planNos = append(planNos, plan.PlanNo)
+	}
+
+	return planNos, nil
+}
+
+// SyncOrderPlans 将订单的生产计划同步到 ES（生成计划的事务提交后调用）
+func (s *PlanService) SyncOrderPlans(ctx context.Context, orderID uint) {
+	if s.esSync == nil {
+		return
+	}
+	plans, err := s.repo.FindByOrderID(ctx, orderID)
+	if err != nil {
+		return
+	}
+	for _, plan := range plans {
+		s.esSync.Index(plan)
+	}
+}
+
+// GetStepProgress 获取订单各工序的完成情况
+func (s *PlanService) GetStepProgress(ctx context.Context, orderID uint) ([]*StepProgressResponse, error) {
+	plans, err := s.repo.FindByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	steps := domain.StepCompletions(plans)
+	responses := make([]*StepProgressResponse, len(steps))
+	for i, step := range steps {
+		responses[i] = &StepProgressResponse{
+			Sequence:     step.Sequence,
+			ProcessID:    step.ProcessID,
+			Predecessors: step.Predecessors,
+			Optional:     step.Optional,
+			Quantity:     step.Quantity,
+			Completed:    step.Completed,
+			Progress:     int(step.Ratio() * 100),
+		}
+	}
+	return responses, nil
+}
+
+// syncOrderProgress 汇总订单下计划的完成情况并同步到订单进度
+func (s *PlanService) syncOrderProgress(ctx context.Context, plan *domain.Plan, fromStatus string, operatorID uint, operatorName, operatorRole string) error {
+	if s.orderService == nil {
+		return nil
+	}
+
+	plans, err := s.repo.FindByOrderID(ctx, plan.OrderID)
+	if err != nil {
+		return err
+	}
+	firstStageCompleted, lastStageRatio := domain.StageCompletion(plans)
+	var steps []orderApp.PlanStepProgress
+	for _, step := range domain.StepCompletions(plans) {
+		if step.Sequence == 0 {
+			continue
+		}
+		steps = append(steps, orderApp.PlanStepProgress{
+			Sequence:          step.Sequence,
+			ProcessID:         step.ProcessID,
+			Optional:          step.Optional,
+			TargetQuantity:    step.Quantity,
+			CompletedQuantity: step.Completed,
+		})
+	}
+
+	return s.orderService.SyncPlanProgress(ctx, &orderApp.PlanProgressSync{
+		OrderID:             plan.OrderID,
+		PlanNo:              plan.PlanNo,
+		FromStatus:          fromStatus,
+		ToStatus:            plan.Status,
+		FirstStageCompleted: firstStageCompleted,
+		LastStageRatio:      lastStageRatio,
+		Steps:               steps,
+		OperatorID:          operatorID,
+		OperatorName:        operatorName,
+		OperatorRole:        operatorRole,
+	})
+}
+
 // Delete 删除计划
 func (s *PlanService) Delete(ctx context.Context, id uint) error {
 	// 1. 查询计划
@@ -230,17 +400,25 @@ func (s *PlanService) GetByOrderID(ctx context.Context, orderID uint) ([]*PlanRe
 	responses := make([]*PlanResponse, len(plans))
 	for i, p := range plans {
 		responses[i] = &PlanResponse{
-			ID:          p.ID,
-			PlanNo:      p.PlanNo,
-			OrderID:     p.OrderID,
-			ProductID:   p.ProductID,
-			Quantity:    p.Quantity,
-			Status:      p.Status,
-			ScheduledAt: p.ScheduledAt,
-			CompletedAt: p.CompletedAt,
-			CreatedBy:   p.CreatedBy,
-			CreatedAt:   p.CreatedAt,
-			UpdatedAt:   p.UpdatedAt,
+			ID:           p.ID,
+			PlanNo:       p.PlanNo,
+			OrderID:      p.OrderID,
+			ProductID:    p.ProductID,
+			Quantity:     p.Quantity,
+			ProcessID:    p.ProcessID,
+			Sequence:     p.Sequence,
+			Predecessors: p.PredecessorSequences(),
+			Optional:     p.Optional,
+			BatchNo:      p.BatchNo,
+			Status:       p.Status,
+			ScheduledAt:  p.ScheduledAt,
+			ScheduledEnd: p.ScheduledEnd,
+			DueDate:      p.DueDate,
+			WorkCenterID: p.WorkCenterID,
+			CompletedAt:  p.CompletedAt,
+			CreatedBy:    p.CreatedBy,
+			CreatedAt:    p.CreatedAt,
+			UpdatedAt:    p.UpdatedAt,
 		}
 	}
 
@@ -257,17 +435,25 @@ func (s *PlanService) GetByProductID(ctx context.Context, productID uint) ([]*Pl
 	responses := make([]*PlanResponse, len(plans))
 	for i, p := range plans {
 		responses[i] = &PlanResponse{
-			ID:          p.ID,
-			PlanNo:      p.PlanNo,
-			OrderID:     p.OrderID,
-			ProductID:   p.ProductID,
-			Quantity:    p.Quantity,
-			Status:      p.Status,
-			ScheduledAt: p.ScheduledAt,
-			CompletedAt: p.CompletedAt,
-			CreatedBy:   p.CreatedBy,
-			CreatedAt:   p.CreatedAt,
-			UpdatedAt:   p.UpdatedAt,
+			ID:           p.ID,
+			PlanNo:       p.PlanNo,
+			OrderID:      p.OrderID,
+			ProductID:    p.ProductID,
+			Quantity:     p.Quantity,
+			ProcessID:    p.ProcessID,
+			Sequence:     p.Sequence,
+			Predecessors: p.PredecessorSequences(),
+			Optional:     p.Optional,
+			BatchNo:      p.BatchNo,
+			Status:       p.Status,
+			ScheduledAt:  p.ScheduledAt,
+			ScheduledEnd: p.ScheduledEnd,
+			DueDate:      p.DueDate,
+			WorkCenterID: p.WorkCenterID,
+			CompletedAt:  p.CompletedAt,
+			CreatedBy:    p.CreatedBy,
+			CreatedAt:    p.CreatedAt,
+			UpdatedAt:    p.UpdatedAt,
 		}
 	}
 
@@ -289,17 +475,25 @@ func (s *PlanService) GetByStatus(ctx context.Context, status string, limit, off
 	responses := make([]*PlanResponse, len(plans))
 	for i, p := range plans {
 		responses[i] = &PlanResponse{
-			ID:          p.ID,
-			PlanNo:      p.PlanNo,
-			OrderID:     p.OrderID,
-			ProductID:   p.ProductID,
-			Quantity:    p.Quantity,
-			Status:      p.Status,
-			ScheduledAt: p.ScheduledAt,
-			CompletedAt: p.CompletedAt,
-			CreatedBy:   p.CreatedBy,
-			CreatedAt:   p.CreatedAt,
-			UpdatedAt:   p.UpdatedAt,
+			ID:           p.ID,
+			PlanNo:       p.PlanNo,
+			OrderID:      p.OrderID,
+			ProductID:    p.ProductID,
+			Quantity:     p.Quantity,
+			ProcessID:    p.ProcessID,
+			Sequence:     p.Sequence,
+			Predecessors: p.PredecessorSequences(),
+			Optional:     p.Optional,
+			BatchNo:      p.BatchNo,
+			Status:       p.Status,
+			ScheduledAt:  p.ScheduledAt,
+			ScheduledEnd: p.ScheduledEnd,
+			DueDate:      p.DueDate,
+			WorkCenterID: p.WorkCenterID,
+			CompletedAt:  p.CompletedAt,
+			CreatedBy:    p.CreatedBy,
+			CreatedAt:    p.CreatedAt,
+			UpdatedAt:    p.UpdatedAt,
 		}
 	}
 
@@ -0,0 +1,487 @@
+package application
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"back/internal/plan/domain"
+	"back/internal/plan/infra"
+)
+
+// maxScheduleViewDays 排程视图最大查询天数
+const maxScheduleViewDays = 366
+
+// ScheduleService 排程服务（工作中心产能、计划排程与甘特视图）
+type ScheduleService struct {
+	repo   *infra.PlanRepo
+	esSync ESSync
+}
+
+// NewScheduleService 创建排程服务
+func NewScheduleService(repo *infra.PlanRepo, esSync ESSync) *ScheduleService {
+	return &ScheduleService{
+		repo:   repo,
+		esSync: esSync,
+	}
+}
+
+// ==================== 工作中心 ====================
+
+// ListWorkCenters 获取工作中心列表
+func (s *ScheduleService) ListWorkCenters(ctx context.Context) ([]*WorkCenterResponse, error) {
+	centers, err := s.repo.FindWorkCenters(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*WorkCenterResponse, len(centers))
+	for i := range centers {
+		responses[i] = toWorkCenterResponse(&centers[i])
+	}
+	return responses, nil
+}
+
+// SaveWorkCenter 新建或更新工作中心
+func (s *ScheduleService) SaveWorkCenter(ctx context.Context, req *SaveWorkCenterRequest) (*WorkCenterResponse, error) {
+	center := &domain.WorkCenter{Enabled: true}
+	if req.ID > 0 {
+		var err error
+		center, err = s.repo.FindWorkCenterByID(ctx, req.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	center.Code = req.Code
+	center.Name = req.Name
+	center.ProcessID = req.ProcessID
+	center.DailyCapacity = req.DailyCapacity
+	if req.Enabled != nil {
+		center.Enabled = *req.Enabled
+	}
+	if err := center.Validate(); err != nil {
+		return nil, err
+	}
+
+	exists, err := s.repo.ExistsWorkCenterCode(ctx, center.Code, center.ID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, domain.ErrWorkCenterCodeDuplicate
+	}
+
+	if err := s.repo.SaveWorkCenter(ctx, center); err != nil {
+		return nil, err
+	}
+	return toWorkCenterResponse(center), nil
+}
+
+// DeleteWorkCenter 删除工作中心（仍有未完成计划排在其上时不允许删除）
+func (s *ScheduleService) DeleteWorkCenter(ctx context.Context, id uint) error {
+	count, err := s.repo.CountOpenSlots(ctx, id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return domain.ErrWorkCenterInUse
+	}
+	return s.repo.DeleteWorkCenter(ctx, id)
+}
+
+// ==================== 排程 ====================
+
+// RunSchedule 对已计划的计划重新排程：进行中的计划保持原排程并占用产能，
+// 其余按订单交期先后、工序顺序依次排入最早完工的工作中心
+func (s *ScheduleService) RunSchedule(ctx context.Context, req *RunScheduleRequest) (*ScheduleRunResponse, error) {
+	from := domain.DayOf(time.Now())
+	if req.From != nil {
+		from = domain.DayOf(*req.From)
+	}
+
+	resp := &ScheduleRunResponse{
+		From:        from,
+		Scheduled:   []*ScheduledPlanResponse{},
+		Unscheduled: []*ScheduledPlanResponse{},
+	}
+	var changed []*domain.Plan
+
+	err := s.repo.Transaction(ctx, func(txRepo *infra.PlanRepo) error {
+		centers, err := txRepo.FindWorkCenters(ctx, false)
+		if err != nil {
+			return err
+		}
+		plans, err := txRepo.FindSchedulable(ctx)
+		if err != nil {
+			return err
+		}
+
+		calendar := domain.NewCapacityCalendar(centers)
+		var fixedIDs []uint
+		var pending []*domain.Plan
+		for _, plan := range plans {
+			if plan.Status == domain.PlanStatusInProgress {
+				fixedIDs = append(fixedIDs, plan.ID)
+				continue
+			}
+			plan.ApplySchedule(0, nil)
+			pending = append(pending, plan)
+		}
+
+		fixedSlots, err := txRepo.FindSlotsByPlanIDs(ctx, fixedIDs)
+		if err != nil {
+			return err
+		}
+		for _, slot := range fixedSlots {
+			calendar.AddLoad(slot)
+		}
+
+		sortByDueDate(pending)
+		for _, plan := range pending {
+			item := toScheduledPlan(plan)
+
+			var slots []domain.ScheduleSlot
+			start, ok := domain.EarliestStart(plan, plans, from)
+			if !ok {
+				item.Reason = "前道工序未排程"
+				resp.Unscheduled = append(resp.Unscheduled, item)
+			} else {
+				var workCenterID uint
+				workCenterID, slots = calendar.AllocateEarliest(plan, start)
+				plan.ApplySchedule(workCenterID, slots)
+				if slots == nil {
+					item.Reason = "无可用工作中心产能"
+					resp.Unscheduled = append(resp.Unscheduled, item)
+				}
+			}
+
+			if err := txRepo.ReplaceSlots(ctx, plan.ID, slots); err != nil {
+				return err
+			}
+			if err := txRepo.Update(ctx, plan); err != nil {
+				return err
+			}
+			changed = append(changed, plan)
+
+			if plan.ScheduledAt != nil {
+				item = toScheduledPlan(plan)
+				resp.Scheduled = append(resp.Scheduled, item)
+				if item.Late {
+					resp.LateCount++
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.syncIndex(changed)
+	return resp, nil
+}
+
+// Reschedule 计划延误时重新排程：计划从新的开工日起按产能重排，
+// 同一订单的后道工序顺延到前道完工之后（不会提前）
+func (s *ScheduleService) Reschedule(ctx context.Context, id uint, req *RescheduleRequest) ([]*ScheduledPlanResponse, error) {
+	start := domain.DayOf(req.StartDate)
+	var moved []*domain.Plan
+
+	err := s.repo.Transaction(ctx, func(txRepo *infra.PlanRepo) error {
+		plan, err := txRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if !plan.CanSchedule() {
+			return domain.ErrCannotSchedulePlan
+		}
+
+		orderPlans, err := txRepo.FindByOrderID(ctx, plan.OrderID)
+		if err != nil {
+			return err
+		}
+		// 使用同一份计划实例，便于后道工序读取刚排好的前道完工日
+		for i, p := range orderPlans {
+			if p.ID == plan.ID {
+				orderPlans[i] = plan
+			}
+		}
+
+		moved = []*domain.Plan{plan}
+		if plan.Sequence > 0 {
+			for _, p := range orderPlans {
+				if p.Sequence > plan.Sequence && p.CanSchedule() {
+					moved = append(moved, p)
+				}
+			}
+		}
+		sort.SliceStable(moved, func(i, j int) bool {
+			if moved[i].Sequence != moved[j].Sequence {
+				return moved[i].Sequence < moved[j].Sequence
+			}
+			return moved[i].BatchNo < moved[j].BatchNo
+		})
+
+		calendar, err := s.loadCalendar(ctx, txRepo, start, moved)
+		if err != nil {
+			return err
+		}
+
+		for i, p := range moved {
+			earliest, ok := domain.EarliestStart(p, orderPlans, start)
+			if i == 0 && !ok {
+				// 指定的计划本身按新开工日排程，不受未排程的前道工序限制
+				earliest = start
+			}
+			if i > 0 {
+				if !ok {
+					p.ApplySchedule(0, nil)
+					if err := txRepo.ReplaceSlots(ctx, p.ID, nil); err != nil {
+						return err
+					}
+					if err := txRepo.Update(ctx, p); err != nil {
+						return err
+					}
+					continue
+				}
+				if p.ScheduledAt != nil && domain.DayOf(*p.ScheduledAt).After(earliest) {
+					earliest = domain.DayOf(*p.ScheduledAt)
+				}
+			}
+
+			var slots []domain.ScheduleSlot
+			workCenterID := p.WorkCenterID
+			if workCenterID > 0 {
+				slots = calendar.Allocate(p.ID, workCenterID, p.Quantity, earliest, true)
+			}
+			if slots == nil {
+				workCenterID, slots = calendar.AllocateEarliest(p, earliest)
+			}
+			if slots == nil {
+				return domain.ErrNoCapacity
+			}
+
+			p.ApplySchedule(workCenterID, slots)
+			if err := txRepo.ReplaceSlots(ctx, p.ID, slots); err != nil {
+				return err
+			}
+			if err := txRepo.Update(ctx, p); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.syncIndex(moved)
+
+	responses := make([]*ScheduledPlanResponse, len(moved))
+	for i, p := range moved {
+		responses[i] = toScheduledPlan(p)
+	}
+	return responses, nil
+}
+
+// GetScheduleView 获取工作中心在日期区间内的排程视图（每日负荷、超负荷标记与甘特条目）
+func (s *ScheduleService) GetScheduleView(ctx context.Context, from, to time.Time, workCenterID uint) (*ScheduleViewResponse, error) {
+	from, to = domain.DayOf(from), domain.DayOf(to)
+	if to.Before(from) || to.Sub(from) > maxScheduleViewDays*24*time.Hour {
+		return nil, domain.ErrInvalidDateRange
+	}
+
+	var centers []domain.WorkCenter
+	if workCenterID > 0 {
+		center, err := s.repo.FindWorkCenterByID(ctx, workCenterID)
+		if err != nil {
+			return nil, err
+		}
+		centers = []domain.WorkCenter{*center}
+	} else {
+		var err error
+		centers, err = s.repo.FindWorkCenters(ctx, false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	slots, err := s.repo.FindSlots(ctx, workCenterID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	planIDs := make([]uint, 0)
+	seen := make(map[uint]bool)
+	for _, slot := range slots {
+		if !seen[slot.PlanID] {
+			seen[slot.PlanID] = true
+			planIDs = append(planIDs, slot.PlanID)
+		}
+	}
+	plans, err := s.repo.FindByIDs(ctx, planIDs)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(plans, func(i, j int) bool {
+		a, b := plans[i], plans[j]
+		if a.ScheduledAt != nil && b.ScheduledAt != nil && !a.ScheduledAt.Equal(*b.ScheduledAt) {
+			return a.ScheduledAt.Before(*b.ScheduledAt)
+		}
+		return a.ID < b.ID
+	})
+
+	resp := &ScheduleViewResponse{
+		From:        from,
+		To:          to,
+		WorkCenters: make([]*WorkCenterScheduleResponse, len(centers)),
+	}
+	for i := range centers {
+		center := &centers[i]
+		view := &WorkCenterScheduleResponse{
+			WorkCenter: toWorkCenterResponse(center),
+			Days:       []*DayLoadResponse{},
+			Bars:       []*GanttBarResponse{},
+		}
+
+		for _, day := range domain.DailyLoads(center, slots, from, to) {
+			view.Days = append(view.Days, &DayLoadResponse{
+				Date:       day.Date,
+				Load:       day.Load,
+				Capacity:   day.Capacity,
+				OverBooked: day.OverBooked,
+			})
+			if day.OverBooked {
+				view.OverBookedDays++
+			}
+		}
+
+		for _, plan := range plans {
+			if plan.WorkCenterID == center.ID {
+				view.Bars = append(view.Bars, toGanttBar(plan, plans))
+			}
+		}
+		resp.WorkCenters[i] = view
+	}
+
+	return resp, nil
+}
+
+// loadCalendar 加载产能日历，排除即将重排的计划
+func (s *ScheduleService) loadCalendar(ctx context.Context, repo *infra.PlanRepo, from time.Time, excluded []*domain.Plan) (*domain.CapacityCalendar, error) {
+	centers, err := repo.FindWorkCenters(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	calendar := domain.NewCapacityCalendar(centers)
+
+	skip := make(map[uint]bool, len(excluded))
+	for _, p := range excluded {
+		skip[p.ID] = true
+	}
+
+	// 后道工序可能顺延，预留两倍视界的已占用产能
+	slots, err := repo.FindSlots(ctx, 0, from, from.AddDate(0, 0, 2*domain.ScheduleHorizonDays))
+	if err != nil {
+		return nil, err
+	}
+	for _, slot := range slots {
+		if !skip[slot.PlanID] {
+			calendar.AddLoad(slot)
+		}
+	}
+	return calendar, nil
+}
+
+// syncIndex 同步排程结果到 ES
+func (s *ScheduleService) syncIndex(plans []*domain.Plan) {
+	if s.esSync == nil {
+		return
+	}
+	for _, plan := range plans {
+		s.esSync.Update(plan)
+	}
+}
+
+// sortByDueDate 按订单最早交期（无交期排最后）、订单、工序顺序、批次排序
+func sortByDueDate(plans []*domain.Plan) {
+	orderDue := make(map[uint]*time.Time)
+	for _, p := range plans {
+		if p.DueDate == nil {
+			continue
+		}
+		if due, ok := orderDue[p.OrderID]; !ok || due == nil || p.DueDate.Before(*due) {
+			orderDue[p.OrderID] = p.DueDate
+		}
+	}
+
+	sort.SliceStable(plans, func(i, j int) bool {
+		a, b := plans[i], plans[j]
+		dueA, dueB := orderDue[a.OrderID], orderDue[b.OrderID]
+		if (dueA == nil) != (dueB == nil) {
+			return dueA != nil
+		}
+		if dueA != nil && !dueA.Equal(*dueB) {
+			return dueA.Before(*dueB)
+		}
+		if a.OrderID != b.OrderID {
+			return a.OrderID < b.OrderID
+		}
+		if a.Sequence != b.Sequence {
+			return a.Sequence < b.Sequence
+		}
+		return a.BatchNo < b.BatchNo
+	})
+}
+
+func toWorkCenterResponse(center *domain.WorkCenter) *WorkCenterResponse {
+	return &WorkCenterResponse{
+		ID:            center.ID,
+		Code:          center.Code,
+		Name:          center.Name,
+		ProcessID:     center.ProcessID,
+		DailyCapacity: center.DailyCapacity,
+		Enabled:       center.Enabled,
+		CreatedAt:     center.CreatedAt,
+		UpdatedAt:     center.UpdatedAt,
+	}
+}
+
+func toScheduledPlan(plan *domain.Plan) *ScheduledPlanResponse {
+	return &ScheduledPlanResponse{
+		PlanID:       plan.ID,
+		PlanNo:       plan.PlanNo,
+		OrderID:      plan.OrderID,
+		Sequence:     plan.Sequence,
+		WorkCenterID: plan.WorkCenterID,
+		Start:        plan.ScheduledAt,
+		End:          plan.ScheduledEnd,
+		DueDate:      plan.DueDate,
+		Late:         plan.IsLate(),
+	}
+}
+
+func toGanttBar(plan *domain.Plan, plans []*domain.Plan) *GanttBarResponse {
+	bar := &GanttBarResponse{
+		PlanID:    plan.ID,
+		PlanNo:    plan.PlanNo,
+		OrderID:   plan.OrderID,
+		ProcessID: plan.ProcessID,
+		Sequence:  plan.Sequence,
+		BatchNo:   plan.BatchNo,
+		Status:    plan.Status,
+		Quantity:  plan.Quantity,
+		Start:     plan.ScheduledAt,
+		End:       plan.ScheduledEnd,
+		DueDate:   plan.DueDate,
+		Late:      plan.IsLate(),
+		DependsOn: []uint{},
+	}
+	for _, other := range plans {
+		if other.IsPredecessorOf(plan) {
+			bar.DependsOn = append(bar.DependsOn, other.ID)
+		}
+	}
+	return bar
+}
@@ -16,4 +16,14 @@ var (
 	ErrCannotCancelCompletedPlan = errors.New("cannot cancel completed plan")
 	ErrCannotUpdateCompletedPlan = errors.New("cannot update completed plan")
 	ErrCannotDeleteCompletedPlan = errors.New("cannot delete completed plan")
+	ErrInvalidDefectRate         = errors.New("defect rate must be between 0 and 95")
+	ErrWorkCenterNotFound        = errors.New("work center not found")
+	ErrWorkCenterCodeEmpty       = errors.New("work center code cannot be empty")
+	ErrWorkCenterNameEmpty       = errors.New("work center name cannot be empty")
+	ErrWorkCenterCodeDuplicate   = errors.New("work center code already exists")
+	ErrInvalidDailyCapacity      = errors.New("daily capacity must be greater than 0")
+	ErrCannotSchedulePlan        = errors.New("can only schedule planned or in_progress plan")
+	ErrNoCapacity                = errors.New("no work center capacity available within the scheduling horizon")
+	ErrInvalidDateRange          = errors.New("invalid date range")
+	ErrWorkCenterInUse           = errors.New("work center has scheduled plans")
 )
\ No newline at end of file
@@ -0,0 +1,82 @@
+package domain
+
+import "time"
+
+// MaxLossPercent 损耗率上限（%），避免缩率/次品率接近 100% 时需求量失真
+const MaxLossPercent = 95.0
+
+// MRPStatuses 参与物料需求计算的计划状态
+func MRPStatuses() []string {
+	return []string{PlanStatusPlanned, PlanStatusInProgress}
+}
+
+// GrossRequirement 计算原料毛需求：计划数量 × 原料占比，再按缩率与次品率放大
+func GrossRequirement(quantity, ratio, shrinkage, defectRate float64) float64 {
+	return quantity * ratio / yieldOf(shrinkage) / yieldOf(defectRate)
+}
+
+// yieldOf 损耗率（%）对应的成品率
+func yieldOf(lossPercent float64) float64 {
+	if lossPercent <= 0 {
+		return 1
+	}
+	if lossPercent > MaxLossPercent {
+		lossPercent = MaxLossPercent
+	}
+	return 1 - lossPercent/100
+}
+
+// MaterialRequirement 计划对某原料的需求及冲销结果
+type MaterialRequirement struct {
+	PlanID       uint
+	PlanNo       string
+	OrderID      uint
+	MaterialID   uint
+	RequiredBy   *time.Time
+	Gross        float64
+	Issued       float64 // 由订单已领用数量冲销（已领出的原料不在库存中）
+	FromReserved float64 // 由订单预留冲销
+	FromStock    float64 // 由可用库存冲销
+	Shortage     float64
+}
+
+// ApplyIssued 用订单已领用数量冲销毛需求，返回剩余的已领用数量
+func (r *MaterialRequirement) ApplyIssued(issued float64) float64 {
+	r.Issued = minFloat(r.Gross, issued)
+	return issued - r.Issued
+}
+
+// Net 在扣除已领用后依次用订单预留与可用库存冲销毛需求，返回消耗后的剩余量
+func (r *MaterialRequirement) Net(reserved, available float64) (float64, float64) {
+	remaining := r.Gross - r.Issued
+
+	r.FromReserved = minFloat(remaining, reserved)
+	remaining -= r.FromReserved
+
+	r.FromStock = minFloat(remaining, available)
+	remaining -= r.FromStock
+
+	r.Shortage = remaining
+	return reserved - r.FromReserved, available - r.FromStock
+}
+
+// RequiredBefore 需求日期是否早于另一需求（未排期的排在最后）
+func (r *MaterialRequirement) RequiredBefore(other *MaterialRequirement) bool {
+	if r.RequiredBy == nil {
+		return false
+	}
+	if other.RequiredBy == nil {
+		return true
+	}
+	return r.RequiredBy.Before(*other.RequiredBy)
+}
+
+func minFloat(a, b float64) float64 {
+	if b < 0 {
+		return 0
+	}
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -1,10 +1,16 @@
 package domain
 
 import (
+	"database/sql/driver"
+	"encoding/json"
 	"fmt"
+	"math"
+	"sort"
 	"time"
 
 	"gorm.io/gorm"
+
+	"back/pkg/serial"
 )
 
 // 计划状态常量
@@ -15,20 +21,58 @@ const (
 	PlanStatusCancelled  = "cancelled"   // 已取消
 )
 
+// SequenceList 工序序号列表（用于 GORM JSONB）
+type SequenceList []int
+
+// Scan 实现 sql.Scanner 接口
+func (l *SequenceList) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, l)
+}
+
+// Value 实现 driver.Valuer 接口
+func (l SequenceList) Value() (driver.Value, error) {
+	if len(l) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(l)
+}
+
+// Contains 是否包含某序号
+func (l SequenceList) Contains(sequence int) bool {
+	for _, s := range l {
+		if s == sequence {
+			return true
+		}
+	}
+	return false
+}
+
 // Plan 计划聚合根
 type Plan struct {
-	ID          uint           `gorm:"primaryKey" json:"id"`
-	PlanNo      string         `gorm:"size:50;uniqueIndex;not null" json:"planNo"`
-	OrderID     uint           `gorm:"not null;index" json:"orderId"`
-	ProductID   uint           `gorm:"not null;index" json:"productId"`
-	Quantity    float64        `gorm:"type:decimal(10,2);not null" json:"quantity"`
-	Status      string         `gorm:"size:20;default:planned;index" json:"status"`
-	ScheduledAt *time.Time     `gorm:"type:timestamp" json:"scheduledAt,omitempty"`
-	CompletedAt *time.Time     `gorm:"type:timestamp" json:"completedAt,omitempty"`
-	CreatedBy   uint           `gorm:"not null;index" json:"createdBy"`
-	CreatedAt   time.Time      `gorm:"autoCreateTime" json:"createdAt"`
-	UpdatedAt   time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	ID           uint           `gorm:"primaryKey" json:"id"`
+	PlanNo       string         `gorm:"size:50;uniqueIndex;not null" json:"planNo"`
+	OrderID      uint           `gorm:"not null;index" json:"orderId"`
+	ProductID    uint           `gorm:"not null;index" json:"productId"`
+	Quantity     float64        `gorm:"type:decimal(10,2);not null" json:"quantity"`
+	ProcessID    uint           `gorm:"index" json:"processId"`                   // 工序（自动生成时按产品工艺拆分）
+	Sequence     int            `gorm:"default:0" json:"sequence"`                // 工序顺序（从1开始，手工创建为0）
+	Predecessors SequenceList   `gorm:"type:jsonb" json:"predecessors,omitempty"` // 前道工序序号（为空时为上一序号）
+	Optional     bool           `gorm:"default:false" json:"optional"`            // 是否可选工序
+	BatchNo      int            `gorm:"default:0" json:"batchNo"`                 // 批次序号（按产能拆分，从1开始）
+	Status       string         `gorm:"size:20;default:planned;index" json:"status"`
+	ScheduledAt  *time.Time     `gorm:"type:timestamp" json:"scheduledAt,omitempty"`
+	ScheduledEnd *time.Time     `gorm:"type:timestamp" json:"scheduledEnd,omitempty"` // 排程完工日
+	DueDate      *time.Time     `gorm:"type:timestamp" json:"dueDate,omitempty"`      // 交期
+	WorkCenterID uint           `gorm:"index" json:"workCenterId"`                    // 排程的工作中心
+	CompletedAt  *time.Time     `gorm:"type:timestamp" json:"completedAt,omitempty"`
+	CreatedBy    uint           `gorm:"not null;index" json:"createdBy"`
+	CreatedAt    time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt    time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 // TableName 表名
@@ -122,6 +166,159 @@ func (p *Plan) CanDelete() bool {
 	return p.Status != PlanStatusCompleted
 }
 
+// IsFirstStage 是否首道工序（投料工序；手工创建的计划视为单工序）
+func (p *Plan) IsFirstStage() bool {
+	return p.Sequence <= 1
+}
+
+// IsActive 是否有效（未取消）
+func (p *Plan) IsActive() bool {
+	return p.Status != PlanStatusCancelled
+}
+
+// Stage 工艺路线中的一道工序（用于生成计划）
+type Stage struct {
+	ProcessID    uint
+	Sequence     int
+	Predecessors []int
+	Optional     bool
+	Quantity     float64 // 本工序投入数量（已按后续损耗放大）
+}
+
+// PredecessorSequences 前道工序序号：未记录前道工序的按上一序号处理
+func (p *Plan) PredecessorSequences() SequenceList {
+	if len(p.Predecessors) > 0 {
+		return p.Predecessors
+	}
+	if p.Sequence > 1 {
+		return SequenceList{p.Sequence - 1}
+	}
+	return nil
+}
+
+// GenerateStagePlans 按工序与产能拆分生成计划：每道工序一组，单批数量不超过 capacity（capacity<=0 不拆分）
+// 计划编号带随机后缀，订单的计划全部取消或删除后重新生成不会与旧编号冲突
+func GenerateStagePlans(orderID, productID uint, stages []Stage, capacity float64, createdBy uint, now time.Time) []*Plan {
+	var plans []*Plan
+	for _, stage := range stages {
+		for j, qty := range splitQuantity(stage.Quantity, capacity) {
+			plans = append(plans, &Plan{
+				PlanNo:       serial.New(fmt.Sprintf("PL%d-%02d-%02d-", orderID, stage.Sequence, j+1), now),
+				OrderID:      orderID,
+				ProductID:    productID,
+				Quantity:     qty,
+				ProcessID:    stage.ProcessID,
+				Sequence:     stage.Sequence,
+				Predecessors: stage.Predecessors,
+				Optional:     stage.Optional,
+				BatchNo:      j + 1,
+				Status:       PlanStatusPlanned,
+				CreatedBy:    createdBy,
+			})
+		}
+	}
+	return plans
+}
+
+// splitQuantity 按产能拆分数量：先按 0.01 取整再拆分，避免浮点余数拆出接近 0 的批次
+func splitQuantity(quantity, capacity float64) []float64 {
+	total := math.Round(quantity * 100)
+	batch := math.Round(capacity * 100)
+	if batch <= 0 || total <= batch {
+		return []float64{total / 100}
+	}
+
+	var quantities []float64
+	for remaining := total; remaining > 0; remaining -= batch {
+		quantities = append(quantities, math.Min(remaining, batch)/100)
+	}
+	return quantities
+}
+
+// StepCompletion 单道工序的完成情况
+type StepCompletion struct {
+	Sequence     int
+	ProcessID    uint
+	Predecessors SequenceList
+	Optional     bool
+	Quantity     float64
+	Completed    float64
+}
+
+// Ratio 完成比例（0-1）
+func (s StepCompletion) Ratio() float64 {
+	if s.Quantity <= 0 {
+		return 0
+	}
+	return s.Completed / s.Quantity
+}
+
+// StepCompletions 按工序汇总订单下有效计划的完成情况（按序号排列）
+func StepCompletions(plans []*Plan) []StepCompletion {
+	bySequence := make(map[int]*StepCompletion)
+	var sequences []int
+	for _, p := range plans {
+		if !p.IsActive() {
+			continue
+		}
+		step, ok := bySequence[p.Sequence]
+		if !ok {
+			step = &StepCompletion{
+				Sequence:     p.Sequence,
+				ProcessID:    p.ProcessID,
+				Predecessors: p.PredecessorSequences(),
+				Optional:     p.Optional,
+			}
+			bySequence[p.Sequence] = step
+			sequences = append(sequences, p.Sequence)
+		}
+		step.Quantity += p.Quantity
+		if p.IsCompleted() {
+			step.Completed += p.Quantity
+		}
+	}
+
+	sort.Ints(sequences)
+	steps := make([]StepCompletion, len(sequences))
+	for i, seq := range sequences {
+		steps[i] = *bySequence[seq]
+	}
+	return steps
+}
+
+// StageCompletion 根据订单下的计划汇总完成情况：首道工序完成数量（胚布投入）与末道工序完成比例（成品产出）
+//
+// 末道工序为没有后续工序的工序；并行收尾时取完成比例最低的一道。
+func StageCompletion(plans []*Plan) (firstStageCompleted, lastStageRatio float64) {
+	hasSuccessor := make(map[int]bool)
+	for _, p := range plans {
+		if !p.IsActive() {
+			continue
+		}
+		if p.IsFirstStage() && p.IsCompleted() {
+			firstStageCompleted += p.Quantity
+		}
+		for _, pred := range p.PredecessorSequences() {
+			hasSuccessor[pred] = true
+		}
+	}
+
+	steps := StepCompletions(plans)
+	staged := len(steps) > 0 && steps[len(steps)-1].Sequence > 0
+	first := true
+	for _, step := range steps {
+		// 有工序拆分时忽略手工创建的计划
+		if hasSuccessor[step.Sequence] || (staged && step.Sequence == 0) {
+			continue
+		}
+		if first || step.Ratio() < lastStageRatio {
+			lastStageRatio = step.Ratio()
+			first = false
+		}
+	}
+	return firstStageCompleted, lastStageRatio
+}
+
 // ToDocument 转换为 ES 文档
 func (p *Plan) ToDocument() map[string]interface{} {
 	doc := map[string]interface{}{
@@ -130,6 +327,10 @@ func (p *Plan) ToDocument() map[string]interface{} {
 		"orderId":   p.OrderID,
 		"productId": p.ProductID,
 		"quantity":  p.Quantity,
+		"processId": p.ProcessID,
+		"sequence":  p.Sequence,
+		"optional":  p.Optional,
+		"batchNo":   p.BatchNo,
 		"status":    p.Status,
 		"createdBy": p.CreatedBy,
 		"createdAt": p.CreatedAt,
@@ -142,6 +343,18 @@ func (p *Plan) ToDocument() map[string]interface{} {
 	if p.CompletedAt != nil {
 		doc["completedAt"] = p.CompletedAt
 	}
+	if p.ScheduledEnd != nil {
+		doc["scheduledEnd"] = p.ScheduledEnd
+	}
+	if p.DueDate != nil {
+		doc["dueDate"] = p.DueDate
+	}
+	if p.WorkCenterID > 0 {
+		doc["workCenterId"] = p.WorkCenterID
+	}
+	if len(p.Predecessors) > 0 {
+		doc["predecessors"] = p.Predecessors
+	}
 
 	return doc
 }
@@ -0,0 +1,247 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// ScheduleHorizonDays 排程向后查找产能的最大天数
+const ScheduleHorizonDays = 365
+
+// capacityEpsilon 产能比较容差
+const capacityEpsilon = 1e-6
+
+// WorkCenter 工作中心（机台/产线），按工序提供日产能
+type WorkCenter struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	Code          string    `gorm:"size:50;uniqueIndex;not null" json:"code"`
+	Name          string    `gorm:"size:100;not null" json:"name"`
+	ProcessID     uint      `gorm:"index" json:"processId"` // 可加工的工序（0 表示通用）
+	DailyCapacity float64   `gorm:"type:decimal(12,2);not null" json:"dailyCapacity"`
+	Enabled       bool      `gorm:"default:true" json:"enabled"`
+	CreatedAt     time.Time `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+// TableName 表名
+func (WorkCenter) TableName() string {
+	return "plan_work_centers"
+}
+
+// Validate 验证工作中心
+func (w *WorkCenter) Validate() error {
+	w.Code = strings.TrimSpace(w.Code)
+	w.Name = strings.TrimSpace(w.Name)
+	if w.Code == "" {
+		return ErrWorkCenterCodeEmpty
+	}
+	if w.Name == "" {
+		return ErrWorkCenterNameEmpty
+	}
+	if w.DailyCapacity <= 0 {
+		return ErrInvalidDailyCapacity
+	}
+	return nil
+}
+
+// ScheduleSlot 排程时段：计划在某工作中心某天占用的产能
+type ScheduleSlot struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	PlanID       uint      `gorm:"not null;index" json:"planId"`
+	WorkCenterID uint      `gorm:"not null;index:idx_slot_center_date" json:"workCenterId"`
+	Date         time.Time `gorm:"type:date;not null;index:idx_slot_center_date" json:"date"`
+	Quantity     float64   `gorm:"type:decimal(12,2);not null" json:"quantity"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+// TableName 表名
+func (ScheduleSlot) TableName() string {
+	return "plan_schedule_slots"
+}
+
+// DayOf 截取到自然日（统一为 UTC 零点，与数据库 date 类型一致）
+func DayOf(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// dayKey 日期键
+func dayKey(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+// IsPredecessorOf 是否为另一计划的前道工序（同一订单中被其工艺路线引用的工序）
+func (p *Plan) IsPredecessorOf(other *Plan) bool {
+	return p.OrderID == other.OrderID && p.Sequence > 0 && other.PredecessorSequences().Contains(p.Sequence)
+}
+
+// IsLate 排程完工日是否晚于交期
+func (p *Plan) IsLate() bool {
+	if p.DueDate == nil || p.ScheduledEnd == nil {
+		return false
+	}
+	return DayOf(*p.ScheduledEnd).After(DayOf(*p.DueDate))
+}
+
+// CanSchedule 是否可以（重新）排程
+func (p *Plan) CanSchedule() bool {
+	return p.Status == PlanStatusPlanned || p.Status == PlanStatusInProgress
+}
+
+// ApplySchedule 按排程时段回写工作中心与起止日期（时段为空时清空排程）
+func (p *Plan) ApplySchedule(workCenterID uint, slots []ScheduleSlot) {
+	if len(slots) == 0 {
+		p.WorkCenterID = 0
+		p.ScheduledAt = nil
+		p.ScheduledEnd = nil
+		return
+	}
+	start, end := slots[0].Date, slots[len(slots)-1].Date
+	p.WorkCenterID = workCenterID
+	p.ScheduledAt = &start
+	p.ScheduledEnd = &end
+}
+
+// CapacityCalendar 工作中心产能日历（记录每日已占用产能）
+type CapacityCalendar struct {
+	centers map[uint]*WorkCenter
+	load    map[uint]map[string]float64
+}
+
+// NewCapacityCalendar 创建产能日历
+func NewCapacityCalendar(centers []WorkCenter) *CapacityCalendar {
+	calendar := &CapacityCalendar{
+		centers: make(map[uint]*WorkCenter, len(centers)),
+		load:    make(map[uint]map[string]float64, len(centers)),
+	}
+	for i := range centers {
+		calendar.centers[centers[i].ID] = &centers[i]
+		calendar.load[centers[i].ID] = make(map[string]float64)
+	}
+	return calendar
+}
+
+// AddLoad 计入已占用产能
+func (c *CapacityCalendar) AddLoad(slot ScheduleSlot) {
+	if load, ok := c.load[slot.WorkCenterID]; ok {
+		load[dayKey(slot.Date)] += slot.Quantity
+	}
+}
+
+// CentersFor 可加工某工序的启用工作中心（含通用工作中心）
+func (c *CapacityCalendar) CentersFor(processID uint) []*WorkCenter {
+	var result []*WorkCenter
+	for _, center := range c.centers {
+		if center.Enabled && (center.ProcessID == processID || center.ProcessID == 0) {
+			result = append(result, center)
+		}
+	}
+	return result
+}
+
+// Allocate 从 start 起按剩余产能逐日分配数量，commit 为 false 时仅试算；视界内无法排完返回 nil
+func (c *CapacityCalendar) Allocate(planID, workCenterID uint, quantity float64, start time.Time, commit bool) []ScheduleSlot {
+	center, ok := c.centers[workCenterID]
+	if !ok || quantity <= 0 {
+		return nil
+	}
+	load := c.load[workCenterID]
+
+	var slots []ScheduleSlot
+	remaining := quantity
+	day := DayOf(start)
+	for i := 0; i < ScheduleHorizonDays && remaining > capacityEpsilon; i++ {
+		free := center.DailyCapacity - load[dayKey(day)]
+		if free > capacityEpsilon {
+			qty := free
+			if remaining < qty {
+				qty = remaining
+			}
+			slots = append(slots, ScheduleSlot{PlanID: planID, WorkCenterID: workCenterID, Date: day, Quantity: qty})
+			remaining -= qty
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	if remaining > capacityEpsilon {
+		return nil
+	}
+
+	if commit {
+		for _, slot := range slots {
+			load[dayKey(slot.Date)] += slot.Quantity
+		}
+	}
+	return slots
+}
+
+// AllocateEarliest 在可加工该工序的工作中心中选择最早完工的一个并分配
+func (c *CapacityCalendar) AllocateEarliest(plan *Plan, start time.Time) (uint, []ScheduleSlot) {
+	var bestCenter uint
+	var best []ScheduleSlot
+	for _, center := range c.CentersFor(plan.ProcessID) {
+		slots := c.Allocate(plan.ID, center.ID, plan.Quantity, start, false)
+		if slots == nil {
+			continue
+		}
+		end := slots[len(slots)-1].Date
+		if best == nil || end.Before(best[len(best)-1].Date) || (end.Equal(best[len(best)-1].Date) && center.ID < bestCenter) {
+			bestCenter, best = center.ID, slots
+		}
+	}
+	if best == nil {
+		return 0, nil
+	}
+	return bestCenter, c.Allocate(plan.ID, bestCenter, plan.Quantity, start, true)
+}
+
+// EarliestStart 计划的最早开工日：不早于 from，且在所有前道工序完工的次日之后；前道工序未排程时返回 false
+func EarliestStart(plan *Plan, plans []*Plan, from time.Time) (time.Time, bool) {
+	start := DayOf(from)
+	for _, other := range plans {
+		if !other.IsPredecessorOf(plan) || !other.IsActive() || other.IsCompleted() {
+			continue
+		}
+		if other.ScheduledEnd == nil {
+			// 进行中但未排程的前道工序视为不占用排程
+			if other.Status == PlanStatusInProgress {
+				continue
+			}
+			return time.Time{}, false
+		}
+		next := DayOf(*other.ScheduledEnd).AddDate(0, 0, 1)
+		if next.After(start) {
+			start = next
+		}
+	}
+	return start, true
+}
+
+// DayLoad 工作中心某天的负荷
+type DayLoad struct {
+	Date       time.Time
+	Load       float64
+	Capacity   float64
+	OverBooked bool
+}
+
+// DailyLoads 汇总工作中心在日期区间内的每日负荷，并标记超负荷日
+func DailyLoads(center *WorkCenter, slots []ScheduleSlot, from, to time.Time) []DayLoad {
+	load := make(map[string]float64)
+	for _, slot := range slots {
+		if slot.WorkCenterID == center.ID {
+			load[dayKey(slot.Date)] += slot.Quantity
+		}
+	}
+
+	var days []DayLoad
+	for day := DayOf(from); !day.After(DayOf(to)); day = day.AddDate(0, 0, 1) {
+		qty := load[dayKey(day)]
+		days = append(days, DayLoad{
+			Date:       day,
+			Load:       qty,
+			Capacity:   center.DailyCapacity,
+			OverBooked: qty > center.DailyCapacity+capacityEpsilon,
+		})
+	}
+	return days
+}
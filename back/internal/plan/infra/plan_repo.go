@@ -90,7 +90,7 @@ func (r *PlanRepo) ExistsByPlanNo(ctx context.Context, planNo string) (bool, err
 // FindByOrderID 根据订单 ID 查询
 func (r *PlanRepo) FindByOrderID(ctx context.Context, orderID uint) ([]*domain.Plan, error) {
 	var result []*domain.Plan
-	err := r.db.WithContext(ctx).
+	err := repo.Conn(ctx, r.db).
 		Where("order_id = ?", orderID).
 		Order("created_at DESC").
 		Find(&result).Error
@@ -100,7 +100,7 @@ func (r *PlanRepo) FindByOrderID(ctx context.Context, orderID uint) ([]*domain.P
 // FindByProductID 根据产品 ID 查询
 func (r *PlanRepo) FindByProductID(ctx context.Context, productID uint) ([]*domain.Plan, error) {
 	var result []*domain.Plan
-	err := r.db.WithContext(ctx).
+	err := repo.Conn(ctx, r.db).
 		Where("product_id = ?", productID).
 		Order("created_at DESC").
 		Find(&result).Error
@@ -110,7 +110,7 @@ func (r *PlanRepo) FindByProductID(ctx context.Context, productID uint) ([]*doma
 // FindByStatus 根据状态查询
 func (r *PlanRepo) FindByStatus(ctx context.Context, status string, limit, offset int) ([]*domain.Plan, error) {
 	var result []*domain.Plan
-	err := r.db.WithContext(ctx).
+	err := repo.Conn(ctx, r.db).
 		Where("status = ?", status).
 		Limit(limit).
 		Offset(offset).
@@ -119,6 +119,16 @@ func (r *PlanRepo) FindByStatus(ctx context.Context, status string, limit, offse
 	return result, err
 }
 
+// FindByStatuses 根据多个状态查询（不分页，按排期升序，未排期的排在最后）
+func (r *PlanRepo) FindByStatuses(ctx context.Context, statuses []string) ([]*domain.Plan, error) {
+	var result []*domain.Plan
+	err := repo.Conn(ctx, r.db).
+		Where("status IN ?", statuses).
+		Order("scheduled_at ASC NULLS LAST, id ASC").
+		Find(&result).Error
+	return result, err
+}
+
 // Count 统计数量
 func (r *PlanRepo) Count(ctx context.Context) (int64, error) {
 	return r.Repo.Count(ctx, map[string]interface{}{})
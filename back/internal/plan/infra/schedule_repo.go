@@ -0,0 +1,143 @@
+package infra
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"back/internal/plan/domain"
+	"back/pkg/repo"
+)
+
+// Transaction 在事务中执行（上下文已携带事务时加入该事务）
+func (r *PlanRepo) Transaction(ctx context.Context, fn func(txRepo *PlanRepo) error) error {
+	return repo.Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		return fn(NewPlanRepo(tx))
+	})
+}
+
+// ==================== 工作中心 ====================
+
+// FindWorkCenters 查询工作中心列表
+func (r *PlanRepo) FindWorkCenters(ctx context.Context, enabledOnly bool) ([]domain.WorkCenter, error) {
+	var centers []domain.WorkCenter
+	query := repo.Conn(ctx, r.db)
+	if enabledOnly {
+		query = query.Where("enabled = ?", true)
+	}
+	err := query.Order("process_id ASC, code ASC").Find(&centers).Error
+	return centers, err
+}
+
+// FindWorkCenterByID 根据 ID 查询工作中心
+func (r *PlanRepo) FindWorkCenterByID(ctx context.Context, id uint) (*domain.WorkCenter, error) {
+	var center domain.WorkCenter
+	err := repo.Conn(ctx, r.db).First(&center, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrWorkCenterNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &center, nil
+}
+
+// ExistsWorkCenterCode 检查工作中心编码是否已被其他工作中心使用
+func (r *PlanRepo) ExistsWorkCenterCode(ctx context.Context, code string, excludeID uint) (bool, error) {
+	var count int64
+	err := repo.Conn(ctx, r.db).
+		Model(&domain.WorkCenter{}).
+		Where("code = ? AND id <> ?", code, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// SaveWorkCenter 保存工作中心
+func (r *PlanRepo) SaveWorkCenter(ctx context.Context, center *domain.WorkCenter) error {
+	return repo.Conn(ctx, r.db).Save(center).Error
+}
+
+// CountOpenSlots 统计工作中心上未完成计划的排程时段数
+func (r *PlanRepo) CountOpenSlots(ctx context.Context, workCenterID uint) (int64, error) {
+	var count int64
+	err := repo.Conn(ctx, r.db).
+		Model(&domain.ScheduleSlot{}).
+		Joins("JOIN plans p ON p.id = plan_schedule_slots.plan_id AND p.deleted_at IS NULL").
+		Where("plan_schedule_slots.work_center_id = ? AND p.status IN ?", workCenterID, []string{domain.PlanStatusPlanned, domain.PlanStatusInProgress}).
+		Count(&count).Error
+	return count, err
+}
+
+// DeleteWorkCenter 删除工作中心
+func (r *PlanRepo) DeleteWorkCenter(ctx context.Context, id uint) error {
+	result := repo.Conn(ctx, r.db).Delete(&domain.WorkCenter{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrWorkCenterNotFound
+	}
+	return nil
+}
+
+// ==================== 排程 ====================
+
+// FindSchedulable 查询可排程的计划（已计划/进行中）
+func (r *PlanRepo) FindSchedulable(ctx context.Context) ([]*domain.Plan, error) {
+	var result []*domain.Plan
+	err := repo.Conn(ctx, r.db).
+		Where("status IN ?", []string{domain.PlanStatusPlanned, domain.PlanStatusInProgress}).
+		Order("order_id ASC, sequence ASC, batch_no ASC, id ASC").
+		Find(&result).Error
+	return result, err
+}
+
+// FindByIDs 根据多个 ID 查询计划
+func (r *PlanRepo) FindByIDs(ctx context.Context, ids []uint) ([]*domain.Plan, error) {
+	var result []*domain.Plan
+	if len(ids) == 0 {
+		return result, nil
+	}
+	err := repo.Conn(ctx, r.db).Where("id IN ?", ids).Find(&result).Error
+	return result, err
+}
+
+// FindSlots 查询日期区间内的有效排程时段（排除已取消、已删除计划），workCenterID 为 0 时查询全部
+func (r *PlanRepo) FindSlots(ctx context.Context, workCenterID uint, from, to time.Time) ([]domain.ScheduleSlot, error) {
+	var slots []domain.ScheduleSlot
+	query := repo.Conn(ctx, r.db).
+		Joins("JOIN plans p ON p.id = plan_schedule_slots.plan_id AND p.deleted_at IS NULL").
+		Where("p.status <> ?", domain.PlanStatusCancelled).
+		Where("plan_schedule_slots.date BETWEEN ? AND ?", from, to)
+	if workCenterID > 0 {
+		query = query.Where("plan_schedule_slots.work_center_id = ?", workCenterID)
+	}
+	err := query.Order("plan_schedule_slots.date ASC, plan_schedule_slots.id ASC").Find(&slots).Error
+	return slots, err
+}
+
+// FindSlotsByPlanIDs 查询计划的排程时段
+func (r *PlanRepo) FindSlotsByPlanIDs(ctx context.Context, planIDs []uint) ([]domain.ScheduleSlot, error) {
+	var slots []domain.ScheduleSlot
+	if len(planIDs) == 0 {
+		return slots, nil
+	}
+	err := repo.Conn(ctx, r.db).
+		Where("plan_id IN ?", planIDs).
+		Order("date ASC, id ASC").
+		Find(&slots).Error
+	return slots, err
+}
+
+// ReplaceSlots 替换计划的排程时段
+func (r *PlanRepo) ReplaceSlots(ctx context.Context, planID uint, slots []domain.ScheduleSlot) error {
+	if err := repo.Conn(ctx, r.db).Where("plan_id = ?", planID).Delete(&domain.ScheduleSlot{}).Error; err != nil {
+		return err
+	}
+	if len(slots) == 0 {
+		return nil
+	}
+	return repo.Conn(ctx, r.db).Create(&slots).Error
+}
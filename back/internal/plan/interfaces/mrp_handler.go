@@ -0,0 +1,63 @@
+package interfaces
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"back/internal/plan/application"
+	"back/pkg/endpoint"
+)
+
+// MRPHandler 物料需求计划 Handler
+type MRPHandler struct {
+	service *application.MRPService
+}
+
+// NewMRPHandler 创建 Handler
+func NewMRPHandler(service *application.MRPService) *MRPHandler {
+	return &MRPHandler{service: service}
+}
+
+// RunMRP 运行物料需求计算
+// @Summary      运行物料需求计算（MRP）
+// @Description  按已计划/进行中的计划展开产品 BOM，计入订单缩率与次品率，冲销可用库存与订单预留后给出各原料缺料及需求日期
+// @Tags         计划管理
+// @Accept       json
+// @Produce      json
+// @Param        defectRate query number false "次品率（%），不填按产品历史次品率"
+// @Param        shortageOnly query bool false "仅返回缺料原料"
+// @Success      200 {object} application.MRPResponse "物料需求计算结果"
+// @Failure      400 {object} map[string]string "请求参数错误"
+// @Failure      500 {object} map[string]string "服务器错误"
+// @Security     Bearer
+// @Router       /plan/mrp [get]
+func (h *MRPHandler) RunMRP(c *gin.Context) {
+	req := &application.MRPRequest{
+		ShortageOnly: c.Query("shortageOnly") == "true",
+	}
+	if raw := c.Query("defectRate"); raw != "" {
+		rate, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的次品率"})
+			return
+		}
+		req.DefectRate = &rate
+	}
+
+	resp, err := h.service.RunMRP(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetRoutes 返回路由定义
+func (h *MRPHandler) GetRoutes() []endpoint.RouteDefinition {
+	return []endpoint.RouteDefinition{
+		{Method: "GET", Path: "/plan/mrp", Handler: h.RunMRP, Domain: "plan", Action: "mrp"},
+	}
+}
@@ -22,6 +22,31 @@ func NewPlanHandler(service *application.PlanService) *PlanHandler {
 	return &PlanHandler{service: service}
 }
 
+// getUserInfo 从上下文获取用户信息
+func getUserInfo(c *gin.Context) (loginID uint, username string, role string, err error) {
+	loginIDVal, exists := c.Get("loginId")
+	if !exists {
+		err = errors.New("未找到用户登录信息")
+		return
+	}
+
+	loginIDStr, ok := loginIDVal.(string)
+	if !ok {
+		err = errors.New("用户登录信息格式错误")
+		return
+	}
+	id, parseErr := strconv.Atoi(loginIDStr)
+	if parseErr != nil {
+		err = errors.New("用户登录信息格式错误")
+		return
+	}
+	loginID = uint(id)
+
+	username = c.GetString("username")
+	role = c.GetString("role")
+	return
+}
+
 // Create 创建计划
 // @Summary      创建计划
 // @Description  创建新的生产计划
@@ -108,7 +133,7 @@ func (h *PlanHandler) List(c *gin.Context) {
 
 // Update 更新计划
 // @Summary      更新计划
-// @Description  根据计划ID更新计划信息
+// @Description  根据计划ID更新计划信息，状态变更会同步订单的胚布投入与加工进度并记录订单事件
 // @Tags         计划管理
 // @Accept       json
 // @Produce      json
@@ -128,7 +153,13 @@ func (h *PlanHandler) Update(c *gin.Context) {
 		return
 	}
 	
-	if err := h.service.Update(c.Request.Context(), uint(id), &req); err != nil {
+	operatorID, operatorName, role, err := getUserInfo(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	
+	if err := h.service.Update(c.Request.Context(), uint(id), &req, operatorID, operatorName, role); err != nil {
 		if errors.Is(err, domain.ErrPlanNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "计划不存在"})
 			return
@@ -176,6 +207,33 @@ func (h *PlanHandler) Delete(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
 }
 
+// GetStepProgress 获取订单各工序完成情况
+// @Summary      获取订单各工序完成情况
+// @Description  按工艺路线汇总订单下各工序计划的投入与完成数量
+// @Tags         计划管理
+// @Accept       json
+// @Produce      json
+// @Param        orderId path int true "订单ID"
+// @Success      200 {array} application.StepProgressResponse "获取成功"
+// @Failure      400 {object} map[string]string "请求参数错误"
+// @Security     Bearer
+// @Router       /plan/order/{orderId}/steps [get]
+func (h *PlanHandler) GetStepProgress(c *gin.Context) {
+	orderID, err := strconv.ParseUint(c.Param("orderId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的订单ID"})
+		return
+	}
+
+	resp, err := h.service.GetStepProgress(c.Request.Context(), uint(orderID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
 // GetRoutes 返回路由定义
 func (h *PlanHandler) GetRoutes() []endpoint.RouteDefinition {
 	return []endpoint.RouteDefinition{
@@ -184,5 +242,6 @@ func (h *PlanHandler) GetRoutes() []endpoint.RouteDefinition {
 		{Method: "GET", Path: "/plan", Handler: h.List, Domain: "", Action: ""},
 		{Method: "PUT", Path: "/plan/:id", Handler: h.Update, Domain: "plan", Action: "update"},
 		{Method: "DELETE", Path: "/plan/:id", Handler: h.Delete, Domain: "plan", Action: "delete"},
+		{Method: "GET", Path: "/plan/order/:orderId/steps", Handler: h.GetStepProgress, Domain: "plan", Action: "read"},
 	}
 }
\ No newline at end of file
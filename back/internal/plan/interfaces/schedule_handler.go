@@ -0,0 +1,250 @@
+package interfaces
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"back/internal/plan/application"
+	"back/internal/plan/domain"
+	"back/pkg/audit"
+	"back/pkg/endpoint"
+)
+
+// ScheduleHandler 排程 Handler
+type ScheduleHandler struct {
+	service *application.ScheduleService
+}
+
+// NewScheduleHandler 创建 Handler
+func NewScheduleHandler(service *application.ScheduleService) *ScheduleHandler {
+	return &ScheduleHandler{service: service}
+}
+
+// ListWorkCenters 获取工作中心列表
+// @Summary      获取工作中心列表
+// @Description  获取所有工作中心（机台/产线）及其工序与日产能
+// @Tags         生产排程
+// @Accept       json
+// @Produce      json
+// @Success      200 {array} application.WorkCenterResponse "工作中心列表"
+// @Failure      500 {object} map[string]string "服务器错误"
+// @Security     Bearer
+// @Router       /plan/work-center/list [get]
+func (h *ScheduleHandler) ListWorkCenters(c *gin.Context) {
+	resp, err := h.service.ListWorkCenters(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// SaveWorkCenter 保存工作中心
+// @Summary      保存工作中心
+// @Description  新建（id 为空）或更新工作中心
+// @Tags         生产排程
+// @Accept       json
+// @Produce      json
+// @Param        request body application.SaveWorkCenterRequest true "工作中心"
+// @Success      200 {object} application.WorkCenterResponse "保存成功"
+// @Failure      400 {object} map[string]string "请求参数错误"
+// @Failure      404 {object} map[string]string "工作中心不存在"
+// @Security     Bearer
+// @Router       /plan/work-center [put]
+func (h *ScheduleHandler) SaveWorkCenter(c *gin.Context) {
+	var req application.SaveWorkCenterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.service.SaveWorkCenter(c.Request.Context(), &req)
+	if err != nil {
+		if errors.Is(err, domain.ErrWorkCenterNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "工作中心不存在"})
+			return
+		}
+		if errors.Is(err, domain.ErrWorkCenterCodeDuplicate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "工作中心编码已存在"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if recorder := audit.Get(c); recorder != nil {
+		recorder.SetResourceID(resp.ID)
+		recorder.SetNew(req)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteWorkCenter 删除工作中心
+// @Summary      删除工作中心
+// @Description  删除工作中心（仍有未完成计划排在其上时不允许删除）
+// @Tags         生产排程
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "工作中心ID"
+// @Success      200 {object} map[string]string "删除成功"
+// @Failure      400 {object} map[string]string "请求参数错误"
+// @Failure      404 {object} map[string]string "工作中心不存在"
+// @Security     Bearer
+// @Router       /plan/work-center/{id} [delete]
+func (h *ScheduleHandler) DeleteWorkCenter(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的ID"})
+		return
+	}
+
+	if err := h.service.DeleteWorkCenter(c.Request.Context(), uint(id)); err != nil {
+		if errors.Is(err, domain.ErrWorkCenterNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "工作中心不存在"})
+			return
+		}
+		if errors.Is(err, domain.ErrWorkCenterInUse) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "工作中心仍有未完成的排程计划"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if recorder := audit.Get(c); recorder != nil {
+		recorder.SetResourceID(id)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
+
+// RunSchedule 运行排程
+// @Summary      运行排程
+// @Description  按工序顺序、交期与工作中心日产能对已计划的计划重新排程（进行中的计划保持原排程）
+// @Tags         生产排程
+// @Accept       json
+// @Produce      json
+// @Param        request body application.RunScheduleRequest false "排程起始日"
+// @Success      200 {object} application.ScheduleRunResponse "排程结果"
+// @Failure      400 {object} map[string]string "请求参数错误"
+// @Failure      500 {object} map[string]string "服务器错误"
+// @Security     Bearer
+// @Router       /plan/schedule/run [post]
+func (h *ScheduleHandler) RunSchedule(c *gin.Context) {
+	var req application.RunScheduleRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	resp, err := h.service.RunSchedule(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Reschedule 重新排程计划
+// @Summary      重新排程计划
+// @Description  计划延误时指定新的开工日重新排程，同一订单的后道工序自动顺延
+// @Tags         生产排程
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "计划ID"
+// @Param        request body application.RescheduleRequest true "新的开工日"
+// @Success      200 {array} application.ScheduledPlanResponse "重排后的计划"
+// @Failure      400 {object} map[string]string "请求参数错误"
+// @Failure      404 {object} map[string]string "计划不存在"
+// @Security     Bearer
+// @Router       /plan/{id}/reschedule [post]
+func (h *ScheduleHandler) Reschedule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的ID"})
+		return
+	}
+
+	var req application.RescheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.service.Reschedule(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		if errors.Is(err, domain.ErrPlanNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "计划不存在"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if recorder := audit.Get(c); recorder != nil {
+		recorder.SetResourceID(id)
+		recorder.SetNew(req)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetScheduleView 获取排程视图
+// @Summary      获取排程视图
+// @Description  按工作中心返回日期区间内的每日负荷（标记超负荷日）与甘特图条目
+// @Tags         生产排程
+// @Accept       json
+// @Produce      json
+// @Param        from query string true "开始日期（YYYY-MM-DD）"
+// @Param        to query string true "结束日期（YYYY-MM-DD）"
+// @Param        workCenterId query int false "工作中心ID"
+// @Success      200 {object} application.ScheduleViewResponse "排程视图"
+// @Failure      400 {object} map[string]string "请求参数错误"
+// @Security     Bearer
+// @Router       /plan/schedule [get]
+func (h *ScheduleHandler) GetScheduleView(c *gin.Context) {
+	from, err := time.Parse("2006-01-02", c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的开始日期"})
+		return
+	}
+	to, err := time.Parse("2006-01-02", c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的结束日期"})
+		return
+	}
+	workCenterID, _ := strconv.ParseUint(c.Query("workCenterId"), 10, 32)
+
+	resp, err := h.service.GetScheduleView(c.Request.Context(), from, to, uint(workCenterID))
+	if err != nil {
+		if errors.Is(err, domain.ErrWorkCenterNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "工作中心不存在"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetRoutes 返回路由定义
+func (h *ScheduleHandler) GetRoutes() []endpoint.RouteDefinition {
+	return []endpoint.RouteDefinition{
+		{Method: "GET", Path: "/plan/work-center/list", Handler: h.ListWorkCenters, Domain: "plan", Action: "read"},
+		{Method: "PUT", Path: "/plan/work-center", Handler: h.SaveWorkCenter, Domain: "plan", Action: "schedule"},
+		{Method: "DELETE", Path: "/plan/work-center/:id", Handler: h.DeleteWorkCenter, Domain: "plan", Action: "schedule"},
+		{Method: "POST", Path: "/plan/schedule/run", Handler: h.RunSchedule, Domain: "plan", Action: "schedule"},
+		{Method: "POST", Path: "/plan/:id/reschedule", Handler: h.Reschedule, Domain: "plan", Action: "schedule"},
+		{Method: "GET", Path: "/plan/schedule", Handler: h.GetScheduleView, Domain: "plan", Action: "read"},
+	}
+}
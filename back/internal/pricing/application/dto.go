@@ -1,8 +1,85 @@
 package application
 
+import (
+	"time"
+
+	"back/internal/pricing/domain"
+)
+
 // QuoteRequest 报价请求
 type QuoteRequest struct {
-	TargetID   uint    `json:"target_id" binding:"required"`
-	SupplierID uint    `json:"supplier_id" binding:"required"`
-	Price      float64 `json:"price" binding:"required,gt=0"`
-}
\ No newline at end of file
+	TargetID    uint               `json:"target_id" binding:"required"`
+	SupplierID  uint               `json:"supplier_id" binding:"required"`
+	Price       float64            `json:"price" binding:"required,gt=0"` // 首个阶梯以下（或不分阶梯）的单价
+	Currency    string             `json:"currency"`                      // 报价币种（默认本位币）
+	MinOrderQty float64            `json:"min_order_qty" binding:"gte=0"` // 起订量（默认不限）
+	PriceTiers  []PriceTierRequest `json:"tiers" binding:"omitempty,dive"`
+	Freight     float64            `json:"freight" binding:"gte=0"` // 每次采购的运杂费（报价币种）
+	ValidFrom   *time.Time         `json:"valid_from"`              // 生效日期（默认立即生效）
+	ValidTo     *time.Time         `json:"valid_to"`                // 失效日期（默认长期有效）
+
+	rfqID      uint // 来源询价单（询价回复时由询价服务填写）
+	rfqItemID  uint
+	invitation *domain.RFQInvitation // 询价回复时与报价在同一事务中更新的邀请记录
+}
+
+// PriceTierRequest 阶梯价：采购数量达到 min_quantity 时适用 price
+type PriceTierRequest struct {
+	MinQuantity float64 `json:"min_quantity" binding:"required,gt=0"`
+	Price       float64 `json:"price" binding:"required,gt=0"`
+}
+
+// Tiers 转换为阶梯价
+func (r *QuoteRequest) Tiers() []domain.PriceTier {
+	if len(r.PriceTiers) == 0 {
+		return nil
+	}
+	tiers := make([]domain.PriceTier, len(r.PriceTiers))
+	for i, t := range r.PriceTiers {
+		tiers[i] = domain.PriceTier{MinQuantity: t.MinQuantity, Price: t.Price}
+	}
+	return tiers
+}
+
+// CacheRebuildResponse 价格缓存重建结果
+type CacheRebuildResponse struct {
+	Targets  int      `json:"targets"`          // 重建的目标数
+	Cached   int      `json:"cached"`           // 写入缓存的目标数
+	Empty    int      `json:"empty"`            // 当前没有有效报价的目标数
+	Failed   int      `json:"failed"`           // 失败的目标数
+	Errors   []string `json:"errors,omitempty"` // 失败原因
+	Duration string   `json:"duration"`
+}
+
+// TargetTypeResponse 已注册的报价目标类型
+type TargetTypeResponse struct {
+	Name       string `json:"name"`                // 类型标识
+	Label      string `json:"label"`               // 显示名称
+	Importable bool   `json:"importable"`          // 是否支持导入报价单
+	KeyLabel   string `json:"key_label,omitempty"` // 导入时匹配目标的业务键，如 材料编号
+}
+
+// ImportQuotesRequest 导入供应商报价单参数（multipart 表单字段）
+type ImportQuotesRequest struct {
+	SupplierID uint   `form:"supplier_id" binding:"required"`
+	TargetType string `form:"target_type"` // 表中没有类型列时的目标类型（默认 material）
+	Currency   string `form:"currency"`    // 表中没有币种列或币种为空时的报价币种（默认本位币）
+	DryRun     bool   `form:"dry_run"`     // 只校验不保存
+}
+
+// ImportRowError 导入失败的行
+type ImportRowError struct {
+	Line  int    `json:"line"`
+	Key   string `json:"key,omitempty"` // 行中的材料编号 / 工序名称
+	Error string `json:"error"`
+}
+
+// ImportQuotesResponse 报价单导入结果（有误的行不导入，其余行在一个事务中保存）
+type ImportQuotesResponse struct {
+	DryRun   bool             `json:"dry_run"`
+	Total    int              `json:"total"`    // 数据行数
+	Valid    int              `json:"valid"`    // 校验通过的行数
+	Imported int              `json:"imported"` // 保存的报价数（试运行时为 0）
+	Targets  int              `json:"targets"`  // 报价变化的目标数
+	Errors   []ImportRowError `json:"errors,omitempty"`
+}
@@ -0,0 +1,17 @@
+package application
+
+import (
+	"context"
+
+	"back/internal/pricing/domain"
+)
+
+// QuoteListener 报价监听接口（由 Product 模块实现，报价保存后生成成本快照）
+type QuoteListener interface {
+	OnPriceQuoted(ctx context.Context, targetType string, targetID uint) error
+}
+
+// QuoteWatcher 报价关注接口（报价保存或撤回后，按变化前的有效最低价评估关注规则并发送提醒）
+type QuoteWatcher interface {
+	OnQuoteChanged(ctx context.Context, price *domain.SupplierPrice, before *domain.PriceData)
+}
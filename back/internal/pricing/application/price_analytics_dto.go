@@ -0,0 +1,39 @@
+package application
+
+import (
+	"time"
+
+	"back/internal/pricing/domain"
+)
+
+// PriceAnalyticsResponse 单个目标的比价与价格趋势
+type PriceAnalyticsResponse struct {
+	TargetType     string                  `json:"target_type"`
+	TargetID       uint                    `json:"target_id"`
+	TargetName     string                  `json:"target_name"`
+	BaseCurrency   string                  `json:"base_currency"`
+	From           time.Time               `json:"from"` // 分析区间起点（时间序列与最低价天数占比）
+	To             time.Time               `json:"to"`
+	Series         []SupplierPriceSeries   `json:"series"`                // 各供应商报价时间序列
+	Current        []domain.QuotePoint     `json:"current"`               // 各供应商当前有效的最新报价（按今日汇率换算）
+	Spread         *domain.PriceSpread     `json:"spread"`                // 当前价差（没有可换算的当前报价时为空）
+	Changes        []domain.PriceChange    `json:"changes"`               // 最低价 30 / 90 / 365 天涨跌幅
+	BestPriceShare []domain.BestPriceShare `json:"best_price_share"`      // 各供应商报价为最低价的天数占比
+	Unconverted    []string                `json:"unconverted,omitempty"` // 缺少到本位币汇率的币种（相关报价不参与比较）
+}
+
+// SupplierPriceSeries 供应商报价时间序列
+type SupplierPriceSeries struct {
+	SupplierID   uint                `json:"supplier_id"`
+	SupplierName string              `json:"supplier_name"`
+	Points       []domain.QuotePoint `json:"points"`
+}
+
+// CostIndexResponse 采购成本指数
+type CostIndexResponse struct {
+	TargetType   string              `json:"target_type"`
+	BaseCurrency string              `json:"base_currency"`
+	BaseDate     time.Time           `json:"base_date"` // 基期（指数 100）
+	Interval     string              `json:"interval"`
+	Points       []domain.IndexPoint `json:"points"`
+}
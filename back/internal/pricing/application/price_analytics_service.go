@@ -0,0 +1,158 @@
+package application
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	currencyApp "back/internal/currency/application"
+	"back/internal/pricing/domain"
+	"back/internal/pricing/infra"
+)
+
+// 分析区间
+const (
+	DefaultAnalyticsDays = 365
+	MaxAnalyticsDays     = 1095
+)
+
+// costIndexSteps 成本指数取样间隔
+var costIndexSteps = map[string]string{
+	"day":   "1 day",
+	"week":  "7 days",
+	"month": "1 month",
+}
+
+// PriceAnalyticsService 比价与价格趋势分析服务（数据由聚合 SQL 一次查出，不逐条解析供应商）
+type PriceAnalyticsService struct {
+	repo            *infra.PriceAnalyticsRepo
+	priceService    *PriceService
+	currencyService *currencyApp.CurrencyService
+}
+
+// NewPriceAnalyticsService 创建分析服务
+func NewPriceAnalyticsService(repo *infra.PriceAnalyticsRepo, priceService *PriceService, currencyService *currencyApp.CurrencyService) *PriceAnalyticsService {
+	return &PriceAnalyticsService{repo: repo, priceService: priceService, currencyService: currencyService}
+}
+
+// analyticsWindow 分析区间：days 天前至今天（days 超出范围时取默认值）
+func analyticsWindow(days int) (time.Time, time.Time) {
+	if days <= 0 || days > MaxAnalyticsDays {
+		days = DefaultAnalyticsDays
+	}
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return today.AddDate(0, 0, -days), today
+}
+
+// Analyze 目标的报价时间序列、当前价差、最低价涨跌幅与各供应商最低价天数占比
+func (s *PriceAnalyticsService) Analyze(ctx context.Context, targetType string, targetID uint, days int) (*PriceAnalyticsResponse, error) {
+	if err := s.priceService.CheckTarget(ctx, targetType, targetID); err != nil {
+		return nil, err
+	}
+	name, err := s.priceService.DisplayName(ctx, targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+	base := s.currencyService.Base()
+	from, today := analyticsWindow(days)
+
+	points, err := s.repo.FindQuoteSeries(ctx, targetType, targetID, from, base)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.repo.FindCurrentQuotes(ctx, targetType, targetID, base)
+	if err != nil {
+		return nil, err
+	}
+
+	// 涨跌幅需要最长周期的起点
+	bestFrom := from
+	if longest := today.AddDate(0, 0, -domain.ChangePeriods[len(domain.ChangePeriods)-1]); longest.Before(bestFrom) {
+		bestFrom = longest
+	}
+	best, err := s.repo.FindDailyBest(ctx, targetType, targetID, bestFrom, today, base)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(current, func(i, j int) bool { return lessBase(current[i].BasePrice, current[j].BasePrice) })
+	resp := &PriceAnalyticsResponse{
+		TargetType:     targetType,
+		TargetID:       targetID,
+		TargetName:     name,
+		BaseCurrency:   base,
+		From:           from,
+		To:             today,
+		Series:         groupSeries(points),
+		Current:        current,
+		Spread:         domain.SpreadOf(current),
+		BestPriceShare: domain.BestPriceShares(best, from),
+		Unconverted:    unconverted(points, current),
+	}
+	for _, period := range domain.ChangePeriods {
+		resp.Changes = append(resp.Changes, domain.ChangeOver(best, today, period))
+	}
+	return resp, nil
+}
+
+// CostIndex 目标类型的采购成本指数（以区间起点为基期 100，interval 为 day / week / month）
+func (s *PriceAnalyticsService) CostIndex(ctx context.Context, targetType string, days int, interval string) (*CostIndexResponse, error) {
+	if _, err := s.priceService.TargetLabel(targetType); err != nil {
+		return nil, err
+	}
+	step, ok := costIndexSteps[interval]
+	if !ok {
+		return nil, domain.ErrInvalidInterval
+	}
+	base := s.currencyService.Base()
+	from, today := analyticsWindow(days)
+
+	points, err := s.repo.FindCostIndex(ctx, targetType, from, today, step, base)
+	if err != nil {
+		return nil, err
+	}
+	return &CostIndexResponse{
+		TargetType:   targetType,
+		BaseCurrency: base,
+		BaseDate:     from,
+		Interval:     interval,
+		Points:       points,
+	}, nil
+}
+
+// groupSeries 按供应商分组报价（查询结果已按供应商、生效时间排序）
+func groupSeries(points []domain.QuotePoint) []SupplierPriceSeries {
+	var series []SupplierPriceSeries
+	for _, p := range points {
+		if n := len(series); n == 0 || series[n-1].SupplierID != p.SupplierID {
+			series = append(series, SupplierPriceSeries{SupplierID: p.SupplierID, SupplierName: p.SupplierName})
+		}
+		series[len(series)-1].Points = append(series[len(series)-1].Points, p)
+	}
+	return series
+}
+
+// unconverted 缺少到本位币汇率的币种
+func unconverted(lists ...[]domain.QuotePoint) []string {
+	seen := make(map[string]bool)
+	var currencies []string
+	for _, points := range lists {
+		for _, p := range points {
+			if p.BasePrice == nil && !seen[p.Currency] {
+				seen[p.Currency] = true
+				currencies = append(currencies, p.Currency)
+			}
+		}
+	}
+	sort.Strings(currencies)
+	return currencies
+}
+
+// lessBase 本位币价格升序，无法换算的排在最后
+func lessBase(a, b *float64) bool {
+	if a == nil {
+		return false
+	}
+	return b == nil || *a < *b
+}
@@ -0,0 +1,187 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	currencyApp "back/internal/currency/application"
+	"back/internal/pricing/domain"
+	"back/internal/pricing/infra"
+	supplierApp "back/internal/supplier/application"
+)
+
+// DefaultPriceCacheTTL 价格缓存默认过期时间
+const DefaultPriceCacheTTL = time.Hour
+
+// PriceCacheService 最低价 / 最高价缓存服务（所有目标类型共用）
+//
+// 缓存未命中时从 supplier_prices 计算当前有效的最低价与最高价（换算为本位币后比较）并一起写回；
+// 过期时间取配置的 TTL、下一次报价生效 / 失效时间与次日零点（汇率按日生效）中最早者，到期后下次读取重新计算。
+type PriceCacheService struct {
+	repo  *infra.SupplierPriceRepo
+	cache domain.PriceCache
+	view  *priceView
+	ttl   time.Duration
+}
+
+// NewPriceCacheService 创建价格缓存服务
+func NewPriceCacheService(
+	repo *infra.SupplierPriceRepo,
+	cache domain.PriceCache,
+	supplierService *supplierApp.SupplierService,
+	currencyService *currencyApp.CurrencyService,
+	ttl time.Duration,
+) *PriceCacheService {
+	if ttl <= 0 {
+		ttl = DefaultPriceCacheTTL
+	}
+	return &PriceCacheService{
+		repo:  repo,
+		cache: cache,
+		view:  &priceView{supplierService: supplierService, currencyService: currencyService},
+		ttl:   ttl,
+	}
+}
+
+// Min 获取当前有效报价中的最低价
+//
+// quantity 为 0 时按报价单价比较并走缓存；大于 0 时按阶梯价与起订量实时计算（结果与数量相关，不缓存）。
+func (s *PriceCacheService) Min(ctx context.Context, targetType string, targetID uint, quantity float64) (*domain.PriceData, error) {
+	if quantity != 0 {
+		lowest, _, err := s.forQuantity(ctx, targetType, targetID, quantity)
+		return lowest, err
+	}
+	if cached, err := s.cache.GetMin(ctx, targetType, targetID); err == nil {
+		return cached, nil
+	}
+
+	lowest, _, err := s.load(ctx, targetType, targetID)
+	return lowest, err
+}
+
+// Max 获取当前有效报价中的最高价（quantity 含义同 Min）
+func (s *PriceCacheService) Max(ctx context.Context, targetType string, targetID uint, quantity float64) (*domain.PriceData, error) {
+	if quantity != 0 {
+		_, highest, err := s.forQuantity(ctx, targetType, targetID, quantity)
+		return highest, err
+	}
+	if cached, err := s.cache.GetMax(ctx, targetType, targetID); err == nil {
+		return cached, nil
+	}
+
+	_, highest, err := s.load(ctx, targetType, targetID)
+	return highest, err
+}
+
+// forQuantity 按需求数量计算当前有效报价的最低价与最高价
+func (s *PriceCacheService) forQuantity(ctx context.Context, targetType string, targetID uint, quantity float64) (*domain.PriceData, *domain.PriceData, error) {
+	if quantity < 0 {
+		return nil, nil, domain.ErrInvalidQuantity
+	}
+	prices, err := s.repo.FindEffective(ctx, targetType, targetID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.view.extremes(ctx, prices, quantity, atToday)
+}
+
+// Invalidate 报价变化后清除目标缓存
+func (s *PriceCacheService) Invalidate(ctx context.Context, targetType string, targetID uint) error {
+	return s.cache.Invalidate(ctx, targetType, targetID)
+}
+
+// Refresh 批量报价后一次性重新计算涉及目标的缓存（目标没有有效报价时仅清除缓存）
+func (s *PriceCacheService) Refresh(ctx context.Context, targets []domain.TargetRef) error {
+	for _, t := range targets {
+		if err := s.cache.Invalidate(ctx, t.TargetType, t.TargetID); err != nil {
+			return err
+		}
+		if _, _, err := s.load(ctx, t.TargetType, t.TargetID); err != nil && !errors.Is(err, domain.ErrPriceNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// load 从数据库计算最低价与最高价并写回缓存
+//
+// 查询前先取版本号，期间有新报价或撤回（版本号变化）时放弃写回，由下次读取重新计算。
+// Redis 不可用时直接返回数据库结果。
+func (s *PriceCacheService) load(ctx context.Context, targetType string, targetID uint) (*domain.PriceData, *domain.PriceData, error) {
+	version, versionErr := s.cache.Version(ctx, targetType, targetID)
+
+	prices, err := s.repo.FindEffective(ctx, targetType, targetID)
+	if err != nil {
+		return nil, nil, err
+	}
+	lowest, highest, err := s.view.extremes(ctx, prices, 0, atToday)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if versionErr == nil {
+		s.cache.SetExtremes(ctx, targetType, targetID, version, lowest, highest, s.ttlFor(ctx, targetType, targetID))
+	}
+
+	return lowest, highest, nil
+}
+
+// ttlFor 缓存过期时间：不晚于下一次报价生效 / 失效，也不跨日
+func (s *PriceCacheService) ttlFor(ctx context.Context, targetType string, targetID uint) time.Duration {
+	ttl := s.ttl
+	now := time.Now()
+	if untilTomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location()).Sub(now); untilTomorrow < ttl {
+		ttl = untilTomorrow
+	}
+	next, err := s.repo.NextValidityChange(ctx, targetType, targetID)
+	if err == nil && next != nil {
+		if until := time.Until(*next); until < ttl {
+			ttl = until
+		}
+	}
+	if ttl < time.Second {
+		ttl = time.Second
+	}
+	return ttl
+}
+
+// Rebuild 按 supplier_prices 重建全部目标的缓存
+func (s *PriceCacheService) Rebuild(ctx context.Context) (*CacheRebuildResponse, error) {
+	start := time.Now()
+
+	targets, err := s.repo.FindTargets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &CacheRebuildResponse{Targets: len(targets)}
+	for _, t := range targets {
+		if err := s.cache.Invalidate(ctx, t.TargetType, t.TargetID); err != nil {
+			return nil, err
+		}
+		_, _, err := s.load(ctx, t.TargetType, t.TargetID)
+		switch {
+		case err == nil:
+			resp.Cached++
+		case errors.Is(err, domain.ErrPriceNotFound):
+			resp.Empty++
+		default:
+			resp.Failed++
+			resp.Errors = append(resp.Errors, fmt.Sprintf("%s %d: %v", t.TargetType, t.TargetID, err))
+		}
+	}
+
+	if err := s.cache.RecordRebuild(ctx, len(targets)); err != nil {
+		return nil, err
+	}
+
+	resp.Duration = time.Since(start).String()
+	return resp, nil
+}
+
+// Stats 获取缓存命中 / 未命中 / 重建统计
+func (s *PriceCacheService) Stats(ctx context.Context) (*domain.CacheStats, error) {
+	return s.cache.Stats(ctx)
+}
@@ -0,0 +1,372 @@
+package application
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+
+	currencyDomain "back/internal/currency/domain"
+	"back/internal/pricing/domain"
+	"back/internal/pricing/infra"
+)
+
+// importColumns 报价单表头（英文列名或中文列名）→ 字段
+var importColumns = map[string]string{
+	"target_type": "target_type", "type": "target_type", "类型": "target_type", "目标类型": "target_type",
+	"code": "key", "name": "key", "key": "key", "编号": "key", "编码": "key", "名称": "key",
+	"材料编号": "key", "材料编码": "key", "工序": "key", "工序名称": "key",
+	"price": "price", "单价": "price", "价格": "price",
+	"currency": "currency", "币种": "currency",
+	"min_order_qty": "min_order_qty", "moq": "min_order_qty", "起订量": "min_order_qty",
+	"freight": "freight", "运费": "freight", "运杂费": "freight",
+	"tiers": "tiers", "阶梯价": "tiers",
+	"valid_from": "valid_from", "生效日期": "valid_from",
+	"valid_to": "valid_to", "失效日期": "valid_to",
+}
+
+// importDateLayouts 报价单中可识别的日期格式（含 Excel 默认日期格式）
+var importDateLayouts = []string{"2006-01-02", "2006/01/02", "2006-1-2", "2006/1/2", "01-02-06", "1/2/06", "1/2/2006"}
+
+// importRow 报价单中的一行
+type importRow struct {
+	line       int
+	targetType string
+	key        string
+	req        QuoteRequest
+}
+
+// ReadSheet 读取报价单全部行（按文件扩展名识别 CSV 或 XLSX，XLSX 取第一个工作表）
+func ReadSheet(r io.Reader, filename string) ([][]string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		reader := csv.NewReader(r)
+		reader.TrimLeadingSpace = true
+		reader.FieldsPerRecord = -1
+		records, err := reader.ReadAll()
+		if err != nil {
+			return nil, err
+		}
+		// 去掉 Excel 另存为 CSV 时带的 BOM
+		if len(records) > 0 && len(records[0]) > 0 {
+			records[0][0] = strings.TrimPrefix(records[0][0], "\ufeff")
+		}
+		return records, nil
+	case ".xlsx":
+		book, err := excelize.OpenReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer book.Close()
+		sheets := book.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, domain.ErrImportEmpty
+		}
+		return book.GetRows(sheets[0])
+	default:
+		return nil, domain.ErrImportFormat
+	}
+}
+
+// Import 导入供应商报价单
+//
+// 首行为表头，材料按材料编号、工序按名称匹配（由目标类型注册的 Resolve 批量查找）；
+// 有误的行逐行报告且不导入，其余行在一个事务中保存（同时取代该供应商的旧报价），
+// 保存后对涉及的目标一次性重算最低价 / 最高价缓存。dry_run 时只校验不保存。
+func (s *PriceService) Import(ctx context.Context, records [][]string, opts *ImportQuotesRequest) (*ImportQuotesResponse, error) {
+	if _, err := s.supplierService.GetSupplierInfo(ctx, opts.SupplierID); err != nil {
+		return nil, err
+	}
+	defaultType := opts.TargetType
+	if defaultType == "" {
+		defaultType = domain.TargetTypeMaterial
+	}
+
+	// 1. 解析表头与各行
+	if len(records) == 0 {
+		return nil, domain.ErrImportEmpty
+	}
+	columns, err := importHeader(records[0])
+	if err != nil {
+		return nil, err
+	}
+	resp := &ImportQuotesResponse{DryRun: opts.DryRun}
+	var rows []*importRow
+	for i, record := range records[1:] {
+		if blank(record) {
+			continue
+		}
+		resp.Total++
+		row, err := parseImportRow(record, columns, i+2, defaultType, opts)
+		if err != nil {
+			resp.Errors = append(resp.Errors, ImportRowError{Line: i + 2, Key: row.key, Error: err.Error()})
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	// 2. 按目标类型批量匹配目标
+	ids, err := s.resolveImportKeys(ctx, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 逐行生成报价并校验（同一目标在表中只能出现一次，币种须有到本位币的汇率）
+	now := time.Now()
+	rates := make(map[string]error)
+	seen := make(map[domain.TargetRef]int)
+	var quotes []*domain.SupplierPrice
+	for _, row := range rows {
+		price, err := s.importQuote(ctx, row, ids, seen, rates, now)
+		if err != nil {
+			resp.Errors = append(resp.Errors, ImportRowError{Line: row.line, Key: row.key, Error: err.Error()})
+			continue
+		}
+		quotes = append(quotes, price)
+	}
+	resp.Valid = len(quotes)
+	resp.Targets = len(quotes)
+	if opts.DryRun || len(quotes) == 0 {
+		return resp, nil
+	}
+
+	// 4. 在一个事务中保存（保存前记录各目标的有效最低价，供关注规则比较）
+	before := make([]*domain.PriceData, len(quotes))
+	for i, price := range quotes {
+		before[i] = s.minBefore(ctx, price.TargetType, price.TargetID)
+	}
+	err = s.repo.Transaction(ctx, func(txRepo *infra.SupplierPriceRepo) error {
+		for _, price := range quotes {
+			if err := saveQuote(ctx, txRepo, price); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp.Imported = len(quotes)
+
+	// 5. 一次性重算缓存并通知监听方、评估关注规则
+	targets := make([]domain.TargetRef, len(quotes))
+	for i, price := range quotes {
+		targets[i] = domain.TargetRef{TargetType: price.TargetType, TargetID: price.TargetID}
+	}
+	_ = s.cacheService.Refresh(ctx, targets)
+	if s.listener != nil {
+		for _, t := range targets {
+			_ = s.listener.OnPriceQuoted(ctx, t.TargetType, t.TargetID)
+		}
+	}
+	for i, price := range quotes {
+		s.watch(ctx, price, before[i])
+	}
+	return resp, nil
+}
+
+// importHeader 解析表头，返回字段 → 列号（缺少编号 / 名称列或单价列时返回错误）
+func importHeader(header []string) (map[string]int, error) {
+	columns := make(map[string]int)
+	for i, name := range header {
+		if field, ok := importColumns[strings.ToLower(strings.TrimSpace(name))]; ok {
+			if _, dup := columns[field]; !dup {
+				columns[field] = i
+			}
+		}
+	}
+	if _, ok := columns["key"]; !ok {
+		return nil, domain.ErrImportColumns
+	}
+	if _, ok := columns["price"]; !ok {
+		return nil, domain.ErrImportColumns
+	}
+	return columns, nil
+}
+
+// blank 是否空行
+func blank(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// parseImportRow 解析一行（返回的 row 始终非空，出错时也带有业务键便于报告）
+func parseImportRow(record []string, columns map[string]int, line int, defaultType string, opts *ImportQuotesRequest) (*importRow, error) {
+	cell := func(field string) string {
+		i, ok := columns[field]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	row := &importRow{line: line, targetType: strings.ToLower(cell("target_type")), key: cell("key")}
+	if row.targetType == "" {
+		row.targetType = defaultType
+	}
+	if row.key == "" {
+		return row, errors.New("code / name is required")
+	}
+
+	row.req.SupplierID = opts.SupplierID
+	row.req.Currency = cell("currency")
+	if row.req.Currency == "" {
+		row.req.Currency = opts.Currency
+	}
+
+	var err error
+	if row.req.Price, err = parseNumber(cell("price"), "price"); err != nil {
+		return row, err
+	}
+	if row.req.MinOrderQty, err = parseNumber(cell("min_order_qty"), "min_order_qty"); err != nil {
+		return row, err
+	}
+	if row.req.Freight, err = parseNumber(cell("freight"), "freight"); err != nil {
+		return row, err
+	}
+	if row.req.PriceTiers, err = parseTiers(cell("tiers")); err != nil {
+		return row, err
+	}
+	if row.req.ValidFrom, err = parseImportDate(cell("valid_from"), "valid_from"); err != nil {
+		return row, err
+	}
+	if row.req.ValidTo, err = parseImportDate(cell("valid_to"), "valid_to"); err != nil {
+		return row, err
+	}
+	return row, nil
+}
+
+// parseNumber 解析数字（允许千分位逗号，空值为 0）
+func parseNumber(value, field string) (float64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseFloat(strings.ReplaceAll(value, ",", ""), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q", field, value)
+	}
+	return n, nil
+}
+
+// parseTiers 解析阶梯价，格式为 起始数量:单价，多个阶梯以分号分隔，如 100:9.5;500:9
+func parseTiers(value string) ([]PriceTierRequest, error) {
+	if value == "" {
+		return nil, nil
+	}
+	value = strings.NewReplacer("；", ";", "：", ":").Replace(value)
+	var tiers []PriceTierRequest
+	for _, part := range strings.Split(value, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		pair := strings.SplitN(part, ":", 2)
+		if len(pair) != 2 {
+			return nil, fmt.Errorf("invalid tier %q, expected min_quantity:price", part)
+		}
+		qty, err := parseNumber(strings.TrimSpace(pair[0]), "tier quantity")
+		if err != nil {
+			return nil, err
+		}
+		price, err := parseNumber(strings.TrimSpace(pair[1]), "tier price")
+		if err != nil {
+			return nil, err
+		}
+		tiers = append(tiers, PriceTierRequest{MinQuantity: qty, Price: price})
+	}
+	return tiers, nil
+}
+
+// parseImportDate 解析日期（空值返回 nil）
+func parseImportDate(value, field string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	for _, layout := range importDateLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid %s %q, expected %s", field, value, currencyDomain.DateLayout)
+}
+
+// resolveImportKeys 按目标类型批量查找业务键对应的目标 ID（类型 → 业务键 → ID）
+func (s *PriceService) resolveImportKeys(ctx context.Context, rows []*importRow) (map[string]map[string][]uint, error) {
+	keys := make(map[string][]string)
+	for _, row := range rows {
+		keys[row.targetType] = append(keys[row.targetType], row.key)
+	}
+
+	ids := make(map[string]map[string][]uint, len(keys))
+	for targetType, list := range keys {
+		t, err := s.targets.Get(targetType)
+		if err != nil || t.Resolve == nil {
+			continue // 逐行报告
+		}
+		resolved, err := t.Resolve(ctx, list)
+		if err != nil {
+			return nil, err
+		}
+		ids[targetType] = resolved
+	}
+	return ids, nil
+}
+
+// importQuote 为一行生成报价记录并校验
+func (s *PriceService) importQuote(ctx context.Context, row *importRow, ids map[string]map[string][]uint, seen map[domain.TargetRef]int, rates map[string]error, now time.Time) (*domain.SupplierPrice, error) {
+	t, err := s.targets.Get(row.targetType)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q", domain.ErrInvalidTargetType, row.targetType)
+	}
+	if t.Resolve == nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrImportUnsupported, row.targetType)
+	}
+
+	matched := ids[row.targetType][row.key]
+	switch len(matched) {
+	case 0:
+		return nil, fmt.Errorf("%w: %s %q", domain.ErrTargetNotFound, t.Name, row.key)
+	case 1:
+	default:
+		return nil, fmt.Errorf("%w: %q matches %d %s targets", domain.ErrImportAmbiguous, row.key, len(matched), t.Name)
+	}
+	row.req.TargetID = matched[0]
+
+	ref := domain.TargetRef{TargetType: row.targetType, TargetID: row.req.TargetID}
+	if line, ok := seen[ref]; ok {
+		return nil, fmt.Errorf("%w: same target as line %d", domain.ErrImportDuplicate, line)
+	}
+
+	currency, err := s.currencyService.Normalize(row.req.Currency)
+	if err != nil {
+		return nil, err
+	}
+	price, err := newQuote(row.targetType, &row.req, currency, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// 每个币种只查一次到本位币的汇率
+	rateErr, ok := rates[currency]
+	if !ok {
+		_, rateErr = s.currencyService.Rate(ctx, currency, s.currencyService.Base(), now)
+		rates[currency] = rateErr
+	}
+	if rateErr != nil {
+		return nil, rateErr
+	}
+
+	seen[ref] = row.line
+	return price, nil
+}
@@ -0,0 +1,303 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	currencyApp "back/internal/currency/application"
+	"back/internal/pricing/domain"
+	"back/internal/pricing/infra"
+	supplierApp "back/internal/supplier/application"
+)
+
+// PriceService 报价服务（所有已注册的目标类型共用）
+//
+// 目标类型由所属领域注册到 TargetRegistry（存在性检查与显示名称解析），
+// 报价、撤回、最低价 / 最高价、历史价格均按 target_type + target_id 处理。
+type PriceService struct {
+	repo            *infra.SupplierPriceRepo
+	cacheService    *PriceCacheService
+	targets         *domain.TargetRegistry
+	supplierService *supplierApp.SupplierService
+	currencyService *currencyApp.CurrencyService
+	view            *priceView
+	listener        QuoteListener
+	watcher         QuoteWatcher
+}
+
+// NewPriceService 创建报价服务
+func NewPriceService(
+	repo *infra.SupplierPriceRepo,
+	cacheService *PriceCacheService,
+	targets *domain.TargetRegistry,
+	supplierService *supplierApp.SupplierService,
+	currencyService *currencyApp.CurrencyService,
+) *PriceService {
+	return &PriceService{
+		repo:            repo,
+		cacheService:    cacheService,
+		targets:         targets,
+		supplierService: supplierService,
+		currencyService: currencyService,
+		view:            &priceView{supplierService: supplierService, currencyService: currencyService},
+	}
+}
+
+// SetQuoteListener 设置报价监听（报价保存后触发）
+func (s *PriceService) SetQuoteListener(listener QuoteListener) {
+	s.listener = listener
+}
+
+// SetQuoteWatcher 设置报价关注（报价保存或撤回后评估关注规则）
+func (s *PriceService) SetQuoteWatcher(watcher QuoteWatcher) {
+	s.watcher = watcher
+}
+
+// For 绑定目标类型的报价服务（供按单一目标类型调用的模块使用）
+func (s *PriceService) For(targetType string) *TargetPriceService {
+	return &TargetPriceService{service: s, targetType: targetType}
+}
+
+// TargetTypes 已注册的目标类型
+func (s *PriceService) TargetTypes() []TargetTypeResponse {
+	types := s.targets.Types()
+	result := make([]TargetTypeResponse, len(types))
+	for i, t := range types {
+		result[i] = TargetTypeResponse{Name: t.Name, Label: t.Label, Importable: t.Resolve != nil, KeyLabel: t.KeyLabel}
+	}
+	return result
+}
+
+// TargetLabel 目标类型显示名称（未注册时返回 ErrInvalidTargetType）
+func (s *PriceService) TargetLabel(targetType string) (string, error) {
+	t, err := s.targets.Get(targetType)
+	if err != nil {
+		return "", err
+	}
+	return t.Label, nil
+}
+
+// CheckTarget 校验目标类型已注册且目标存在
+func (s *PriceService) CheckTarget(ctx context.Context, targetType string, targetID uint) error {
+	return s.targets.Check(ctx, domain.TargetRef{TargetType: targetType, TargetID: targetID})
+}
+
+// DisplayName 目标显示名称
+func (s *PriceService) DisplayName(ctx context.Context, targetType string, targetID uint) (string, error) {
+	t, err := s.targets.Get(targetType)
+	if err != nil {
+		return "", err
+	}
+	return t.DisplayName(ctx, targetID)
+}
+
+// Quote 供应商报价（同一供应商对该目标的旧报价在新报价生效时失效）
+func (s *PriceService) Quote(ctx context.Context, targetType string, req *QuoteRequest) (*domain.PriceData, error) {
+	// 1. 验证目标类型已注册且目标存在
+	if err := s.CheckTarget(ctx, targetType, req.TargetID); err != nil {
+		return nil, err
+	}
+	currency, err := s.currencyService.Normalize(req.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 创建价格记录并做领域验证
+	price, err := newQuote(targetType, req, currency, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 确认供应商存在，且报价币种有到本位币的汇率（否则无法参与最低价 / 最高价比较）
+	if _, err := s.supplierService.GetSupplierInfo(ctx, req.SupplierID); err != nil {
+		return nil, err
+	}
+	base, err := s.view.basePrice(ctx, price, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. 保存并取代该供应商的旧报价（保存前记录有效最低价，供关注规则比较）
+	before := s.minBefore(ctx, targetType, req.TargetID)
+	err = s.repo.Transaction(ctx, func(txRepo *infra.SupplierPriceRepo) error {
+		if err := saveQuote(ctx, txRepo, price); err != nil {
+			return err
+		}
+		if req.invitation != nil {
+			return txRepo.UpdateInvitation(ctx, req.invitation)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 5. 有效报价变化，清除最低价 / 最高价缓存并通知监听方
+	s.changed(ctx, targetType, req.TargetID)
+	s.watch(ctx, price, before)
+
+	return s.view.withSupplier(ctx, price, base)
+}
+
+// newQuote 由报价请求创建报价记录并做领域验证（不校验目标与供应商是否存在）
+func newQuote(targetType string, req *QuoteRequest, currency string, now time.Time) (*domain.SupplierPrice, error) {
+	price := &domain.SupplierPrice{
+		TargetType:  targetType,
+		TargetID:    req.TargetID,
+		SupplierID:  req.SupplierID,
+		Price:       req.Price,
+		Currency:    currency,
+		MinOrderQty: req.MinOrderQty,
+		Tiers:       req.Tiers(),
+		Freight:     req.Freight,
+		RFQID:       req.rfqID,
+		RFQItemID:   req.rfqItemID,
+		QuotedAt:    now,
+		Status:      domain.QuoteStatusActive,
+		ValidFrom:   req.ValidFrom,
+		ValidTo:     req.ValidTo,
+	}
+	if err := price.Validate(); err != nil {
+		return nil, err
+	}
+	return price, nil
+}
+
+// saveQuote 保存报价并取代该供应商对同一目标的旧报价（在事务中调用）
+func saveQuote(ctx context.Context, txRepo *infra.SupplierPriceRepo, price *domain.SupplierPrice) error {
+	if err := txRepo.Save(ctx, price); err != nil {
+		return err
+	}
+	previous, err := txRepo.FindActiveBySupplier(ctx, price.TargetType, price.TargetID, price.SupplierID, price.ID)
+	if err != nil {
+		return err
+	}
+	for _, old := range previous {
+		old.Supersede(price)
+		if err := txRepo.UpdateQuote(ctx, old); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Withdraw 撤回报价（报价不属于该目标类型时视为不存在）
+func (s *PriceService) Withdraw(ctx context.Context, targetType string, quoteID uint) error {
+	price, err := s.repo.FindQuoteByID(ctx, quoteID)
+	if err != nil {
+		return err
+	}
+	if price.TargetType != targetType {
+		return domain.ErrQuoteNotFound
+	}
+
+	if err := price.Withdraw(time.Now()); err != nil {
+		return err
+	}
+	before := s.minBefore(ctx, targetType, price.TargetID)
+	if err := s.repo.UpdateQuote(ctx, price); err != nil {
+		return err
+	}
+
+	s.changed(ctx, targetType, price.TargetID)
+	s.watch(ctx, price, before)
+	return nil
+}
+
+// changed 报价变化后清除缓存并通知监听方（快照失败不影响报价）
+func (s *PriceService) changed(ctx context.Context, targetType string, targetID uint) {
+	s.cacheService.Invalidate(ctx, targetType, targetID)
+	if s.listener != nil {
+		_ = s.listener.OnPriceQuoted(ctx, targetType, targetID)
+	}
+}
+
+// minBefore 报价变化前的有效最低价（未设置报价关注或没有有效报价时为 nil）
+func (s *PriceService) minBefore(ctx context.Context, targetType string, targetID uint) *domain.PriceData {
+	if s.watcher == nil {
+		return nil
+	}
+	min, err := s.cacheService.Min(ctx, targetType, targetID, 0)
+	if err != nil {
+		return nil
+	}
+	return min
+}
+
+// watch 报价变化后评估关注规则（提醒失败不影响报价）
+func (s *PriceService) watch(ctx context.Context, price *domain.SupplierPrice, before *domain.PriceData) {
+	if s.watcher != nil {
+		s.watcher.OnQuoteChanged(ctx, price, before)
+	}
+}
+
+// GetMinPrice 获取当前有效报价中的最低价（quantity 为需求数量，大于 0 时按阶梯价与起订量取价）
+func (s *PriceService) GetMinPrice(ctx context.Context, targetType string, targetID uint, quantity float64) (*domain.PriceData, error) {
+	return s.cacheService.Min(ctx, targetType, targetID, quantity)
+}
+
+// GetMaxPrice 获取当前有效报价中的最高价（quantity 含义同 GetMinPrice）
+func (s *PriceService) GetMaxPrice(ctx context.Context, targetType string, targetID uint, quantity float64) (*domain.PriceData, error) {
+	return s.cacheService.Max(ctx, targetType, targetID, quantity)
+}
+
+// GetHistoricalMinPrice 获取历史最低报价（含已失效的报价，按生效日汇率换算后比较）
+func (s *PriceService) GetHistoricalMinPrice(ctx context.Context, targetType string, targetID uint) (*domain.PriceData, error) {
+	prices, err := s.repo.FindHistorical(ctx, targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+	lowest, _, err := s.view.extremes(ctx, prices, 0, atEffectiveDate)
+	return lowest, err
+}
+
+// GetHistoricalMaxPrice 获取历史最高报价（含已失效的报价，按生效日汇率换算后比较）
+func (s *PriceService) GetHistoricalMaxPrice(ctx context.Context, targetType string, targetID uint) (*domain.PriceData, error) {
+	prices, err := s.repo.FindHistorical(ctx, targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+	_, highest, err := s.view.extremes(ctx, prices, 0, atEffectiveDate)
+	return highest, err
+}
+
+// GetCurrentPrice 获取当前价格（当前有效报价中最新的一条，quantity 大于 0 时取适用的阶梯价）
+func (s *PriceService) GetCurrentPrice(ctx context.Context, targetType string, targetID uint, quantity float64) (*domain.PriceData, error) {
+	if quantity < 0 {
+		return nil, domain.ErrInvalidQuantity
+	}
+	price, err := s.repo.FindLatestEffective(ctx, targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+	return s.view.forQuantity(ctx, price, quantity, time.Now())
+}
+
+// GetSupplierPrice 获取指定供应商当前有效的最新报价（quantity 大于 0 时取适用的阶梯价）
+func (s *PriceService) GetSupplierPrice(ctx context.Context, targetType string, targetID, supplierID uint, quantity float64) (*domain.PriceData, error) {
+	if quantity < 0 {
+		return nil, domain.ErrInvalidQuantity
+	}
+	price, err := s.repo.FindLatestBySupplier(ctx, targetType, targetID, supplierID)
+	if err != nil {
+		return nil, err
+	}
+	return s.view.forQuantity(ctx, price, quantity, time.Now())
+}
+
+// GetHistory 获取报价历史
+func (s *PriceService) GetHistory(ctx context.Context, targetType string, targetID uint, limit int) ([]*domain.PriceData, error) {
+	prices, err := s.repo.FindHistory(ctx, targetType, targetID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.PriceData, len(prices))
+	for i, p := range prices {
+		if result[i], err = s.view.toPriceData(ctx, p, p.EffectiveFrom()); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
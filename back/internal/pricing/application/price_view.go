@@ -0,0 +1,124 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	currencyApp "back/internal/currency/application"
+	"back/internal/pricing/domain"
+	supplierApp "back/internal/supplier/application"
+)
+
+// priceView 报价转换为价格数据（附供应商名称与本位币价格，报价服务与缓存服务共用）
+type priceView struct {
+	supplierService *supplierApp.SupplierService
+	currencyService *currencyApp.CurrencyService
+}
+
+// basePrice 按 asOf 当日汇率换算为本位币价格
+func (v *priceView) basePrice(ctx context.Context, price *domain.SupplierPrice, asOf time.Time) (float64, error) {
+	return v.currencyService.Convert(ctx, price.Price, price.Currency, v.currencyService.Base(), asOf)
+}
+
+// toPriceData 转换为价格数据，本位币价格按 asOf 当日汇率换算
+func (v *priceView) toPriceData(ctx context.Context, price *domain.SupplierPrice, asOf time.Time) (*domain.PriceData, error) {
+	return v.forQuantity(ctx, price, 0, asOf)
+}
+
+// forQuantity 按需求数量取阶梯单价并转换为价格数据（quantity 为 0 时取报价单价）
+func (v *priceView) forQuantity(ctx context.Context, price *domain.SupplierPrice, quantity float64, asOf time.Time) (*domain.PriceData, error) {
+	qp, base, err := v.quote(ctx, price, quantity, asOf)
+	if err != nil {
+		return nil, err
+	}
+	return v.build(ctx, price, qp, base)
+}
+
+// quote 按需求数量取价，并将适用单价换算为本位币
+func (v *priceView) quote(ctx context.Context, price *domain.SupplierPrice, quantity float64, asOf time.Time) (domain.QuantityPrice, float64, error) {
+	qp := price.PriceFor(quantity)
+	base, err := v.currencyService.Convert(ctx, qp.Price, price.Currency, v.currencyService.Base(), asOf)
+	return qp, base, err
+}
+
+// build 附供应商名称与本位币价格，按数量取价时填写采购数量与折算单价
+func (v *priceView) build(ctx context.Context, price *domain.SupplierPrice, qp domain.QuantityPrice, base float64) (*domain.PriceData, error) {
+	data, err := v.withSupplier(ctx, price, base)
+	if err != nil {
+		return nil, err
+	}
+	if qp.Quantity > 0 {
+		data.Price = qp.Price
+		data.Quantity = qp.Quantity
+		data.PurchaseQuantity = qp.PurchaseQuantity
+		data.EffectivePrice = qp.EffectivePrice()
+	}
+	return data, nil
+}
+
+// withSupplier 附供应商名称与已换算的本位币价格
+func (v *priceView) withSupplier(ctx context.Context, price *domain.SupplierPrice, base float64) (*domain.PriceData, error) {
+	supplier, err := v.supplierService.GetSupplierInfo(ctx, price.SupplierID)
+	if err != nil {
+		return nil, err
+	}
+	data := price.ToPriceData(supplier.Name)
+	data.BasePrice = base
+	data.BaseCurrency = v.currencyService.Base()
+	return data, nil
+}
+
+// extremes 按需求数量取价并换算为本位币后，取折算单价最低与最高的报价（相同时取靠前的报价），asOf 为各报价的换算日期
+//
+// quantity 为 0 时按报价单价比较；名称取自最低价 / 最高价报价自身的供应商；没有报价时返回 ErrPriceNotFound。
+func (v *priceView) extremes(ctx context.Context, prices []*domain.SupplierPrice, quantity float64, asOf func(*domain.SupplierPrice) time.Time) (*domain.PriceData, *domain.PriceData, error) {
+	if len(prices) == 0 {
+		return nil, nil, domain.ErrPriceNotFound
+	}
+
+	type candidate struct {
+		price *domain.SupplierPrice
+		qp    domain.QuantityPrice
+		base  float64
+	}
+	var lowest, highest *candidate
+	var lowCost, highCost float64
+	for _, p := range prices {
+		qp, base, err := v.quote(ctx, p, quantity, asOf(p))
+		if err != nil {
+			return nil, nil, err
+		}
+		// 起订量多采的部分计入需求数量的成本
+		cost := base
+		if qp.Quantity > 0 {
+			cost = base * qp.PurchaseQuantity / qp.Quantity
+		}
+		c := &candidate{price: p, qp: qp, base: base}
+		if lowest == nil || cost < lowCost {
+			lowest, lowCost = c, cost
+		}
+		if highest == nil || cost > highCost {
+			highest, highCost = c, cost
+		}
+	}
+
+	low, err := v.build(ctx, lowest.price, lowest.qp, lowest.base)
+	if err != nil {
+		return nil, nil, err
+	}
+	high, err := v.build(ctx, highest.price, highest.qp, highest.base)
+	if err != nil {
+		return nil, nil, err
+	}
+	return low, high, nil
+}
+
+// atToday 当前有效报价按今天的汇率换算
+func atToday(*domain.SupplierPrice) time.Time {
+	return time.Now()
+}
+
+// atEffectiveDate 历史报价按生效日的汇率换算
+func atEffectiveDate(p *domain.SupplierPrice) time.Time {
+	return p.EffectiveFrom()
+}
@@ -0,0 +1,49 @@
+package application
+
+import (
+	"time"
+
+	"back/internal/pricing/domain"
+)
+
+// PriceWatchRules 关注提醒规则
+type PriceWatchRules struct {
+	NotifyMinChange  bool    `json:"notify_min_change"`                       // 有效最低价变动时提醒
+	MinChangePercent float64 `json:"min_change_percent" binding:"gte=0"`      // 变动达到该比例（%）才提醒（0 表示任何变动）
+	NotifyUndercut   bool    `json:"notify_undercut"`                         // 新报价低于现任供应商时提醒
+	NotifyExpiring   bool    `json:"notify_expiring"`                         // 报价即将到期时提醒
+	ExpiryDays       int     `json:"expiry_days" binding:"gte=0,lte=90"`      // 到期前多少天提醒（默认 7）
+	WebhookURL       string  `json:"webhook_url" binding:"omitempty,max=500"` // 同时推送到该地址（为空时仅站内通知）
+}
+
+// PriceWatchRequest 关注报价目标请求
+type PriceWatchRequest struct {
+	TargetType string `json:"target_type" binding:"required"` // 已注册的目标类型，如 material、process
+	TargetID   uint   `json:"target_id" binding:"required"`
+	PriceWatchRules
+}
+
+// PriceWatchResponse 关注规则（附目标名称）
+type PriceWatchResponse struct {
+	domain.PriceWatch
+	TargetName string `json:"target_name"`
+}
+
+// PriceWatchListResponse 关注列表
+type PriceWatchListResponse struct {
+	Watches []PriceWatchResponse `json:"watches"`
+}
+
+// PriceAlertListResponse 提醒列表
+type PriceAlertListResponse struct {
+	Total  int64               `json:"total"`
+	Alerts []domain.PriceAlert `json:"alerts"`
+}
+
+// ExpiryScanResponse 报价到期扫描结果
+type ExpiryScanResponse struct {
+	RunAt   time.Time `json:"run_at"`
+	Watches int       `json:"watches"` // 开启到期提醒的关注数
+	Alerts  int       `json:"alerts"`  // 本次发出的提醒数
+	Failed  int       `json:"failed"`
+}
@@ -0,0 +1,350 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	notificationApp "back/internal/notification/application"
+	notificationDomain "back/internal/notification/domain"
+	"back/internal/pricing/domain"
+	"back/internal/pricing/infra"
+	"back/pkg/webhook"
+)
+
+// PriceWatchService 报价关注服务：用户关注报价目标，在报价变化时（最低价变动、低于现任供应商）
+// 及定时扫描中（报价即将到期）发送站内通知，并可同时推送到用户配置的 Webhook
+type PriceWatchService struct {
+	repo                *infra.PriceWatchRepo
+	priceRepo           *infra.SupplierPriceRepo
+	rfqRepo             *infra.RFQRepo
+	priceService        *PriceService
+	notificationService *notificationApp.NotificationService
+	sender              *webhook.Sender
+}
+
+// NewPriceWatchService 创建报价关注服务
+func NewPriceWatchService(
+	repo *infra.PriceWatchRepo,
+	priceRepo *infra.SupplierPriceRepo,
+	rfqRepo *infra.RFQRepo,
+	priceService *PriceService,
+	notificationService *notificationApp.NotificationService,
+	sender *webhook.Sender,
+) *PriceWatchService {
+	return &PriceWatchService{
+		repo:                repo,
+		priceRepo:           priceRepo,
+		rfqRepo:             rfqRepo,
+		priceService:        priceService,
+		notificationService: notificationService,
+		sender:              sender,
+	}
+}
+
+// Watch 关注报价目标（同一用户对同一目标只能关注一次）
+func (s *PriceWatchService) Watch(ctx context.Context, loginID string, req *PriceWatchRequest) (*PriceWatchResponse, error) {
+	if err := s.priceService.CheckTarget(ctx, req.TargetType, req.TargetID); err != nil {
+		return nil, err
+	}
+	exists, err := s.repo.Exists(ctx, loginID, req.TargetType, req.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, domain.ErrWatchExists
+	}
+
+	watch := &domain.PriceWatch{LoginID: loginID, TargetType: req.TargetType, TargetID: req.TargetID}
+	return s.save(ctx, watch, &req.PriceWatchRules)
+}
+
+// Update 更新关注规则
+func (s *PriceWatchService) Update(ctx context.Context, loginID string, id uint, rules *PriceWatchRules) (*PriceWatchResponse, error) {
+	watch, err := s.repo.FindByID(ctx, id, loginID)
+	if err != nil {
+		return nil, err
+	}
+	return s.save(ctx, watch, rules)
+}
+
+// save 写入规则、校验并保存
+func (s *PriceWatchService) save(ctx context.Context, watch *domain.PriceWatch, rules *PriceWatchRules) (*PriceWatchResponse, error) {
+	watch.NotifyMinChange = rules.NotifyMinChange
+	watch.MinChangePercent = rules.MinChangePercent
+	watch.NotifyUndercut = rules.NotifyUndercut
+	watch.NotifyExpiring = rules.NotifyExpiring
+	watch.ExpiryDays = rules.ExpiryDays
+	if watch.ExpiryDays == 0 {
+		watch.ExpiryDays = domain.DefaultExpiryDays
+	}
+	watch.WebhookURL = rules.WebhookURL
+
+	if err := watch.Validate(); err != nil {
+		return nil, err
+	}
+	if watch.WebhookURL != "" {
+		if err := webhook.ValidateURL(ctx, watch.WebhookURL); errors.Is(err, webhook.ErrForbiddenAddress) {
+			return nil, domain.ErrWebhookForbidden
+		} else if err != nil {
+			return nil, domain.ErrInvalidWebhookURL
+		}
+	}
+	if err := s.repo.Save(ctx, watch); err != nil {
+		return nil, err
+	}
+	return &PriceWatchResponse{PriceWatch: *watch, TargetName: s.targetName(ctx, watch.TargetType, watch.TargetID)}, nil
+}
+
+// Unwatch 取消关注
+func (s *PriceWatchService) Unwatch(ctx context.Context, loginID string, id uint) error {
+	return s.repo.Delete(ctx, id, loginID)
+}
+
+// List 用户的关注列表
+func (s *PriceWatchService) List(ctx context.Context, loginID string) (*PriceWatchListResponse, error) {
+	watches, err := s.repo.FindByUser(ctx, loginID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &PriceWatchListResponse{Watches: make([]PriceWatchResponse, len(watches))}
+	for i, watch := range watches {
+		resp.Watches[i] = PriceWatchResponse{PriceWatch: watch, TargetName: s.targetName(ctx, watch.TargetType, watch.TargetID)}
+	}
+	return resp, nil
+}
+
+// Alerts 用户收到的提醒（含 Webhook 投递结果）
+func (s *PriceWatchService) Alerts(ctx context.Context, loginID string, limit, offset int) (*PriceAlertListResponse, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	alerts, total, err := s.repo.FindAlerts(ctx, loginID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return &PriceAlertListResponse{Total: total, Alerts: alerts}, nil
+}
+
+// OnQuoteChanged 报价保存或撤回后评估关注该目标的规则（提醒失败不影响报价）
+func (s *PriceWatchService) OnQuoteChanged(ctx context.Context, price *domain.SupplierPrice, before *domain.PriceData) {
+	watches, err := s.repo.FindByTarget(ctx, price.TargetType, price.TargetID)
+	if err != nil || len(watches) == 0 {
+		return
+	}
+
+	now := time.Now()
+	after, err := s.priceService.GetMinPrice(ctx, price.TargetType, price.TargetID, 0)
+	if err != nil && !errors.Is(err, domain.ErrPriceNotFound) {
+		return
+	}
+	quote, incumbent := s.undercut(ctx, price, before, now)
+	name := s.targetName(ctx, price.TargetType, price.TargetID)
+
+	for i := range watches {
+		watch := &watches[i]
+		if watch.NotifyMinChange && watch.MinPriceChanged(before, after) {
+			_ = s.notifyMinChange(ctx, watch, name, price, before, after)
+		}
+		if watch.NotifyUndercut && quote != nil {
+			_ = s.notifyUndercut(ctx, watch, name, quote, incumbent)
+		}
+	}
+}
+
+// undercut 新报价（当前有效）低于现任供应商时返回新报价与现任报价
+//
+// 现任供应商为询价定标的首选供应商（其当前有效的最新报价），未定标或首选供应商没有有效报价时为报价前的最低价供应商。
+func (s *PriceWatchService) undercut(ctx context.Context, price *domain.SupplierPrice, before *domain.PriceData, now time.Time) (*domain.PriceData, *domain.PriceData) {
+	if price.Status != domain.QuoteStatusActive || !price.IsEffective(now) {
+		return nil, nil
+	}
+
+	incumbent := before
+	if preferred, err := s.rfqRepo.FindPreferred(ctx, price.TargetType, price.TargetID); err == nil {
+		if preferred.SupplierID == price.SupplierID {
+			return nil, nil
+		}
+		if current, err := s.priceRepo.FindLatestBySupplier(ctx, price.TargetType, price.TargetID, preferred.SupplierID); err == nil {
+			if data, err := s.priceService.view.toPriceData(ctx, current, now); err == nil {
+				incumbent = data
+			}
+		}
+	}
+	if incumbent == nil || incumbent.SupplierID == price.SupplierID {
+		return nil, nil
+	}
+
+	quote, err := s.priceService.view.toPriceData(ctx, price, now)
+	if err != nil || quote.BasePrice >= incumbent.BasePrice {
+		return nil, nil
+	}
+	return quote, incumbent
+}
+
+// ScanExpiring 扫描关注目标中即将到期的报价并提醒（每条报价对每个关注只提醒一次）
+func (s *PriceWatchService) ScanExpiring(ctx context.Context) (*ExpiryScanResponse, error) {
+	watches, err := s.repo.FindExpiryWatches(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	resp := &ExpiryScanResponse{RunAt: now, Watches: len(watches)}
+	expiring := make(map[domain.TargetRef][]*domain.SupplierPrice)
+	for i := range watches {
+		watch := &watches[i]
+		ref := domain.TargetRef{TargetType: watch.TargetType, TargetID: watch.TargetID}
+		quotes, ok := expiring[ref]
+		if !ok {
+			quotes, err = s.priceRepo.FindExpiring(ctx, ref.TargetType, ref.TargetID, now.AddDate(0, 0, domain.MaxExpiryDays))
+			if err != nil {
+				return nil, err
+			}
+			expiring[ref] = quotes
+		}
+
+		for _, quote := range quotes {
+			if !watch.ExpiresWithin(quote.ValidTo, now) {
+				continue
+			}
+			sent, err := s.repo.AlertExists(ctx, watch.ID, domain.WatchEventExpiring, quote.ID)
+			if err != nil {
+				return nil, err
+			}
+			if sent {
+				continue
+			}
+			if err := s.notifyExpiring(ctx, watch, quote, now); err != nil {
+				resp.Failed++
+				continue
+			}
+			resp.Alerts++
+		}
+	}
+	return resp, nil
+}
+
+// notifyMinChange 有效最低价变动提醒（上涨或无有效报价时为预警级别）
+func (s *PriceWatchService) notifyMinChange(ctx context.Context, watch *domain.PriceWatch, name string, price *domain.SupplierPrice, before, after *domain.PriceData) error {
+	level := notificationDomain.LevelWarning
+	if after != nil && before != nil && after.BasePrice < before.BasePrice {
+		level = notificationDomain.LevelInfo
+	}
+	alert := &domain.PriceAlert{
+		Event:      domain.WatchEventMinPriceChange,
+		QuoteID:    price.ID,
+		SupplierID: price.SupplierID,
+		Title:      fmt.Sprintf("最低价变动：%s", name),
+		Content:    fmt.Sprintf("%s 当前有效最低价由 %s 变为 %s", name, describePrice(before), describePrice(after)),
+	}
+	return s.dispatch(ctx, watch, alert, level, map[string]interface{}{"before": before, "after": after})
+}
+
+// notifyUndercut 新报价低于现任供应商提醒
+func (s *PriceWatchService) notifyUndercut(ctx context.Context, watch *domain.PriceWatch, name string, quote, incumbent *domain.PriceData) error {
+	alert := &domain.PriceAlert{
+		Event:      domain.WatchEventUndercut,
+		QuoteID:    quote.QuoteID,
+		SupplierID: quote.SupplierID,
+		Title:      fmt.Sprintf("供应商报价低于现任：%s", name),
+		Content: fmt.Sprintf("%s 新报价 %s，低于现任供应商 %s（%.2f%%）", name, describePrice(quote), describePrice(incumbent),
+			(quote.BasePrice-incumbent.BasePrice)/incumbent.BasePrice*100),
+	}
+	return s.dispatch(ctx, watch, alert, notificationDomain.LevelWarning, map[string]interface{}{"quote": quote, "incumbent": incumbent})
+}
+
+// notifyExpiring 报价即将到期提醒
+func (s *PriceWatchService) notifyExpiring(ctx context.Context, watch *domain.PriceWatch, quote *domain.SupplierPrice, now time.Time) error {
+	data, err := s.priceService.view.toPriceData(ctx, quote, now)
+	if err != nil {
+		return err
+	}
+	name := s.targetName(ctx, quote.TargetType, quote.TargetID)
+	alert := &domain.PriceAlert{
+		Event:      domain.WatchEventExpiring,
+		QuoteID:    quote.ID,
+		SupplierID: quote.SupplierID,
+		Title:      fmt.Sprintf("报价即将到期：%s", name),
+		Content:    fmt.Sprintf("%s 报价 %s 将于 %s 到期", name, describePrice(data), quote.ValidTo.Format("2006-01-02 15:04")),
+	}
+	return s.dispatch(ctx, watch, alert, notificationDomain.LevelWarning, map[string]interface{}{"quote": data})
+}
+
+// dispatch 记录提醒并发送站内通知，配置了 Webhook 时异步推送并记录投递结果
+func (s *PriceWatchService) dispatch(ctx context.Context, watch *domain.PriceWatch, alert *domain.PriceAlert, level string, detail map[string]interface{}) error {
+	alert.WatchID = watch.ID
+	alert.LoginID = watch.LoginID
+	alert.TargetType = watch.TargetType
+	alert.TargetID = watch.TargetID
+	if watch.WebhookURL != "" {
+		alert.WebhookStatus = domain.WebhookStatusPending
+	}
+	if err := s.repo.CreateAlert(ctx, alert); err != nil {
+		return err
+	}
+
+	payload := map[string]interface{}{
+		"alertId":    alert.ID,
+		"event":      alert.Event,
+		"targetType": alert.TargetType,
+		"targetId":   alert.TargetID,
+		"quoteId":    alert.QuoteID,
+		"detail":     detail,
+	}
+	err := s.notificationService.NotifyUser(ctx, watch.LoginID, &notificationApp.Message{
+		Type:    notificationDomain.TypePriceAlert,
+		Level:   level,
+		Title:   alert.Title,
+		Content: alert.Content,
+		Payload: payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	if watch.WebhookURL != "" && s.sender != nil {
+		go s.deliver(watch.WebhookURL, alert, detail)
+	}
+	return nil
+}
+
+// deliver 推送提醒到 Webhook（脱离请求上下文执行）
+func (s *PriceWatchService) deliver(url string, alert *domain.PriceAlert, detail map[string]interface{}) {
+	ctx := context.Background()
+	status, message := domain.WebhookStatusDelivered, ""
+	if err := s.sender.Post(ctx, url, alert.Event, map[string]interface{}{"alert": alert, "detail": detail}); errors.Is(err, webhook.ErrForbiddenAddress) {
+		// 不记录解析出的内网地址
+		status, message = domain.WebhookStatusFailed, domain.ErrWebhookForbidden.Error()
+	} else if err != nil {
+		status, message = domain.WebhookStatusFailed, truncate(err.Error(), 500)
+	}
+	_ = s.repo.UpdateWebhookResult(ctx, alert.ID, status, message)
+}
+
+// targetName 目标显示名称（解析失败时为 类型#ID）
+func (s *PriceWatchService) targetName(ctx context.Context, targetType string, targetID uint) string {
+	name, err := s.priceService.DisplayName(ctx, targetType, targetID)
+	if err != nil || name == "" {
+		return fmt.Sprintf("%s#%d", targetType, targetID)
+	}
+	return name
+}
+
+// describePrice 价格描述（本位币价格及供应商）
+func describePrice(data *domain.PriceData) string {
+	if data == nil {
+		return "无有效报价"
+	}
+	return fmt.Sprintf("%.4f %s（%s）", data.BasePrice, data.BaseCurrency, data.SupplierName)
+}
+
+// truncate 截断过长的文本
+func truncate(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return string(runes[:max])
+}
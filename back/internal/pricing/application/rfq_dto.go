@@ -0,0 +1,93 @@
+package application
+
+import (
+	"time"
+
+	"back/internal/pricing/domain"
+)
+
+// CreateRFQRequest 创建询价单请求
+type CreateRFQRequest struct {
+	Title       string           `json:"title" binding:"required,max=200"`
+	Currency    string           `json:"currency"` // 比价币种（默认本位币）
+	Deadline    time.Time        `json:"deadline" binding:"required"`
+	Remark      string           `json:"remark" binding:"max=500"`
+	Items       []RFQItemRequest `json:"items" binding:"required,min=1,dive"`
+	SupplierIDs []uint           `json:"supplier_ids" binding:"required,min=1"`
+}
+
+// RFQItemRequest 询价明细
+type RFQItemRequest struct {
+	TargetType string  `json:"target_type" binding:"required"` // 已注册的目标类型，如 material、process
+	TargetID   uint    `json:"target_id" binding:"required"`
+	Quantity   float64 `json:"quantity" binding:"required,gt=0"`
+}
+
+// RFQResponseRequest 录入供应商对询价明细的报价（生成一条关联询价单的供应商报价）
+type RFQResponseRequest struct {
+	ItemID      uint               `json:"item_id" binding:"required"`
+	SupplierID  uint               `json:"supplier_id" binding:"required"`
+	Price       float64            `json:"price" binding:"required,gt=0"`
+	Currency    string             `json:"currency"` // 报价币种（默认本位币）
+	MinOrderQty float64            `json:"min_order_qty" binding:"gte=0"`
+	PriceTiers  []PriceTierRequest `json:"tiers" binding:"omitempty,dive"`
+	Freight     float64            `json:"freight" binding:"gte=0"` // 每次采购的运杂费（报价币种）
+	ValidFrom   *time.Time         `json:"valid_from"`
+	ValidTo     *time.Time         `json:"valid_to"`
+}
+
+// DeclineRFQRequest 供应商谢绝报价
+type DeclineRFQRequest struct {
+	SupplierID uint   `json:"supplier_id" binding:"required"`
+	Reason     string `json:"reason" binding:"max=500"`
+}
+
+// AwardRFQRequest 明细定标
+type AwardRFQRequest struct {
+	ItemID  uint `json:"item_id" binding:"required"`
+	QuoteID uint `json:"quote_id" binding:"required"` // 中标的回复报价
+}
+
+// RFQListResponse 询价单列表
+type RFQListResponse struct {
+	Total int64        `json:"total"`
+	RFQs  []domain.RFQ `json:"rfqs"`
+}
+
+// RFQComparisonResponse 询价比价矩阵
+type RFQComparisonResponse struct {
+	RFQID    uint                 `json:"rfq_id"`
+	RFQNo    string               `json:"rfq_no"`
+	Status   string               `json:"status"`
+	Currency string               `json:"currency"`  // 比价币种
+	RateDate string               `json:"rate_date"` // 换算所用汇率日期
+	Items    []*RFQItemComparison `json:"items"`
+}
+
+// RFQItemComparison 单个询价明细的比价（按到岸单价从低到高排名，未报价的供应商排在最后）
+type RFQItemComparison struct {
+	ItemID            uint                `json:"item_id"`
+	TargetType        string              `json:"target_type"`
+	TargetID          uint                `json:"target_id"`
+	Quantity          float64             `json:"quantity"`
+	AwardedSupplierID uint                `json:"awarded_supplier_id,omitempty"`
+	AwardedQuoteID    uint                `json:"awarded_quote_id,omitempty"`
+	Responses         []*RFQComparisonRow `json:"responses"`
+}
+
+// RFQComparisonRow 供应商回复
+type RFQComparisonRow struct {
+	Rank             int     `json:"rank"` // 0 表示未报价
+	SupplierID       uint    `json:"supplier_id"`
+	SupplierName     string  `json:"supplier_name"`
+	InvitationStatus string  `json:"invitation_status"`
+	QuoteID          uint    `json:"quote_id,omitempty"`
+	Price            float64 `json:"price,omitempty"`    // 需求数量适用的阶梯单价（报价币种）
+	Currency         string  `json:"currency,omitempty"` // 报价币种
+	MinOrderQty      float64 `json:"min_order_qty,omitempty"`
+	PurchaseQuantity float64 `json:"purchase_quantity,omitempty"` // 按起订量调整后的采购数量
+	Freight          float64 `json:"freight,omitempty"`
+	LandedPrice      float64 `json:"landed_price,omitempty"` // 到岸单价（比价币种，含起订量多采部分与运杂费）
+	LandedTotal      float64 `json:"landed_total,omitempty"` // 到岸总价（比价币种）
+	Awarded          bool    `json:"awarded"`
+}
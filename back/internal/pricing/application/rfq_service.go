@@ -0,0 +1,338 @@
+package application
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	currencyApp "back/internal/currency/application"
+	currencyDomain "back/internal/currency/domain"
+	"back/internal/pricing/domain"
+	"back/internal/pricing/infra"
+	supplierApp "back/internal/supplier/application"
+)
+
+// RFQService 询价服务：向多家供应商询价、录入回复、比价定标并更新首选供应商
+//
+// 供应商回复通过报价服务生成报价（与直接录入的报价一样参与最低价 / 最高价），
+// 并记录来源询价单与明细。
+type RFQService struct {
+	repo            *infra.RFQRepo
+	priceRepo       *infra.SupplierPriceRepo
+	priceService    *PriceService
+	supplierService *supplierApp.SupplierService
+	currencyService *currencyApp.CurrencyService
+}
+
+// NewRFQService 创建询价服务
+func NewRFQService(
+	repo *infra.RFQRepo,
+	priceRepo *infra.SupplierPriceRepo,
+	priceService *PriceService,
+	supplierService *supplierApp.SupplierService,
+	currencyService *currencyApp.CurrencyService,
+) *RFQService {
+	return &RFQService{
+		repo:            repo,
+		priceRepo:       priceRepo,
+		priceService:    priceService,
+		supplierService: supplierService,
+		currencyService: currencyService,
+	}
+}
+
+// Create 创建询价单并邀请供应商
+func (s *RFQService) Create(ctx context.Context, req *CreateRFQRequest, creatorID uint, creatorName string) (*domain.RFQ, error) {
+	currency, err := s.currencyService.Normalize(req.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]domain.RFQItem, len(req.Items))
+	for i, item := range req.Items {
+		if err := s.priceService.CheckTarget(ctx, item.TargetType, item.TargetID); err != nil {
+			return nil, err
+		}
+		items[i] = domain.RFQItem{TargetType: item.TargetType, TargetID: item.TargetID, Quantity: item.Quantity}
+	}
+	for _, id := range req.SupplierIDs {
+		if _, err := s.supplierService.GetSupplierInfo(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	rfq, err := domain.NewRFQ(req.Title, currency, req.Deadline, items, req.SupplierIDs, creatorID, creatorName, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	rfq.Remark = req.Remark
+
+	if err := s.repo.Create(ctx, rfq); err != nil {
+		return nil, err
+	}
+	return rfq, nil
+}
+
+// List 询价单列表
+func (s *RFQService) List(ctx context.Context, status string, limit, offset int) (*RFQListResponse, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	rfqs, total, err := s.repo.FindList(ctx, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return &RFQListResponse{Total: total, RFQs: rfqs}, nil
+}
+
+// Get 询价单详情（到达截止时间的询价单转为比价中）
+func (s *RFQService) Get(ctx context.Context, id uint) (*domain.RFQ, error) {
+	rfq, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rfq.Refresh(time.Now()) {
+		if err := s.repo.Update(ctx, rfq); err != nil {
+			return nil, err
+		}
+	}
+	return rfq, nil
+}
+
+// Respond 录入供应商对询价明细的报价（截止前可多次报价，以最新一次为准）
+func (s *RFQService) Respond(ctx context.Context, rfqID uint, req *RFQResponseRequest) (*domain.PriceData, error) {
+	rfq, err := s.Get(ctx, rfqID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	item, err := rfq.CanRespond(req.ItemID, req.SupplierID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	invitation, err := rfq.RecordResponse(req.SupplierID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// 报价与邀请状态在同一事务中保存
+	quote := &QuoteRequest{
+		TargetID:    item.TargetID,
+		SupplierID:  req.SupplierID,
+		Price:       req.Price,
+		Currency:    req.Currency,
+		MinOrderQty: req.MinOrderQty,
+		PriceTiers:  req.PriceTiers,
+		Freight:     req.Freight,
+		ValidFrom:   req.ValidFrom,
+		ValidTo:     req.ValidTo,
+		rfqID:       rfq.ID,
+		rfqItemID:   item.ID,
+		invitation:  invitation,
+	}
+	return s.priceService.Quote(ctx, item.TargetType, quote)
+}
+
+// Decline 记录供应商谢绝报价
+func (s *RFQService) Decline(ctx context.Context, rfqID uint, req *DeclineRFQRequest) (*domain.RFQ, error) {
+	rfq, err := s.Get(ctx, rfqID)
+	if err != nil {
+		return nil, err
+	}
+	invitation, err := rfq.Decline(req.SupplierID, req.Reason, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateInvitation(ctx, invitation); err != nil {
+		return nil, err
+	}
+	return rfq, nil
+}
+
+// StopBidding 提前截止报价，进入比价
+func (s *RFQService) StopBidding(ctx context.Context, rfqID uint) (*domain.RFQ, error) {
+	return s.transition(ctx, rfqID, (*domain.RFQ).StopBidding)
+}
+
+// Close 关闭询价单
+func (s *RFQService) Close(ctx context.Context, rfqID uint) (*domain.RFQ, error) {
+	return s.transition(ctx, rfqID, (*domain.RFQ).Close)
+}
+
+// Cancel 取消询价单
+func (s *RFQService) Cancel(ctx context.Context, rfqID uint) (*domain.RFQ, error) {
+	return s.transition(ctx, rfqID, (*domain.RFQ).Cancel)
+}
+
+// transition 执行状态变更并保存
+func (s *RFQService) transition(ctx context.Context, rfqID uint, change func(*domain.RFQ, time.Time) error) (*domain.RFQ, error) {
+	rfq, err := s.Get(ctx, rfqID)
+	if err != nil {
+		return nil, err
+	}
+	if err := change(rfq, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, rfq); err != nil {
+		return nil, err
+	}
+	return rfq, nil
+}
+
+// Compare 比价矩阵：每个明细取各供应商最新的回复，按到岸单价（比价币种，当日汇率）排名
+func (s *RFQService) Compare(ctx context.Context, rfqID uint) (*RFQComparisonResponse, error) {
+	rfq, err := s.Get(ctx, rfqID)
+	if err != nil {
+		return nil, err
+	}
+	responses, err := s.repo.FindResponses(ctx, rfq.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 每个明细、每个供应商取最新一次回复（已按报价时间倒序）
+	type responseKey struct{ itemID, supplierID uint }
+	latest := make(map[responseKey]*domain.SupplierPrice, len(responses))
+	for _, p := range responses {
+		key := responseKey{p.RFQItemID, p.SupplierID}
+		if _, ok := latest[key]; !ok {
+			latest[key] = p
+		}
+	}
+
+	names := make(map[uint]string, len(rfq.Invitations))
+	for _, inv := range rfq.Invitations {
+		supplier, err := s.supplierService.GetSupplierInfo(ctx, inv.SupplierID)
+		if err != nil {
+			return nil, err
+		}
+		names[inv.SupplierID] = supplier.Name
+	}
+
+	now := time.Now()
+	resp := &RFQComparisonResponse{
+		RFQID:    rfq.ID,
+		RFQNo:    rfq.RFQNo,
+		Status:   rfq.Status,
+		Currency: rfq.Currency,
+		RateDate: now.Format(currencyDomain.DateLayout),
+		Items:    make([]*RFQItemComparison, 0, len(rfq.Items)),
+	}
+	for _, item := range rfq.Items {
+		comparison := &RFQItemComparison{
+			ItemID:            item.ID,
+			TargetType:        item.TargetType,
+			TargetID:          item.TargetID,
+			Quantity:          item.Quantity,
+			AwardedSupplierID: item.AwardedSupplierID,
+			AwardedQuoteID:    item.AwardedQuoteID,
+			Responses:         make([]*RFQComparisonRow, 0, len(rfq.Invitations)),
+		}
+		for _, inv := range rfq.Invitations {
+			row := &RFQComparisonRow{
+				SupplierID:       inv.SupplierID,
+				SupplierName:     names[inv.SupplierID],
+				InvitationStatus: inv.Status,
+			}
+			if quote, ok := latest[responseKey{item.ID, inv.SupplierID}]; ok {
+				if err := s.fillLanded(ctx, row, quote, item.Quantity, rfq.Currency, now); err != nil {
+					return nil, err
+				}
+				row.Awarded = quote.ID == item.AwardedQuoteID
+			}
+			comparison.Responses = append(comparison.Responses, row)
+		}
+		rank(comparison.Responses)
+		resp.Items = append(resp.Items, comparison)
+	}
+	return resp, nil
+}
+
+// fillLanded 按需求数量计算回复的到岸单价与总价，并换算为比价币种
+func (s *RFQService) fillLanded(ctx context.Context, row *RFQComparisonRow, quote *domain.SupplierPrice, quantity float64, currency string, asOf time.Time) error {
+	landed, err := s.currencyService.Convert(ctx, quote.LandedPrice(quantity), quote.Currency, currency, asOf)
+	if err != nil {
+		return err
+	}
+	qp := quote.PriceFor(quantity)
+
+	row.QuoteID = quote.ID
+	row.Price = qp.Price
+	row.Currency = quote.Currency
+	row.MinOrderQty = quote.MinOrderQty
+	row.PurchaseQuantity = qp.PurchaseQuantity
+	row.Freight = quote.Freight
+	row.LandedPrice = landed
+	row.LandedTotal = landed * quantity
+	return nil
+}
+
+// rank 已报价的按到岸单价升序排名，未报价的排在最后
+func rank(rows []*RFQComparisonRow) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		if (rows[i].QuoteID == 0) != (rows[j].QuoteID == 0) {
+			return rows[i].QuoteID != 0
+		}
+		return rows[i].LandedPrice < rows[j].LandedPrice
+	})
+	for i, row := range rows {
+		if row.QuoteID != 0 {
+			row.Rank = i + 1
+		}
+	}
+}
+
+// Award 明细定标：中标报价须为该明细的回复，定标后更新该报价目标的首选供应商
+func (s *RFQService) Award(ctx context.Context, rfqID uint, req *AwardRFQRequest, username string) (*domain.PreferredSupplier, error) {
+	rfq, err := s.Get(ctx, rfqID)
+	if err != nil {
+		return nil, err
+	}
+	quote, err := s.priceRepo.FindQuoteByID(ctx, req.QuoteID)
+	if err != nil {
+		return nil, err
+	}
+	if quote.RFQID != rfq.ID || quote.RFQItemID != req.ItemID || quote.Status == domain.QuoteStatusWithdrawn {
+		return nil, domain.ErrRFQResponseNotFound
+	}
+
+	now := time.Now()
+	item, err := rfq.Award(req.ItemID, quote.SupplierID, quote.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	preferred := &domain.PreferredSupplier{
+		TargetType: item.TargetType,
+		TargetID:   item.TargetID,
+		SupplierID: quote.SupplierID,
+		QuoteID:    quote.ID,
+		RFQID:      rfq.ID,
+		Price:      quote.PriceFor(item.Quantity).Price,
+		Currency:   quote.Currency,
+		AwardedBy:  username,
+		AwardedAt:  now,
+	}
+
+	err = s.repo.Transaction(ctx, func(txRepo *infra.RFQRepo) error {
+		if err := txRepo.UpdateItem(ctx, item); err != nil {
+			return err
+		}
+		if err := txRepo.Update(ctx, rfq); err != nil {
+			return err
+		}
+		return txRepo.SavePreferred(ctx, preferred)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return preferred, nil
+}
+
+// GetPreferred 查询报价目标的首选供应商
+func (s *RFQService) GetPreferred(ctx context.Context, targetType string, targetID uint) (*domain.PreferredSupplier, error) {
+	if _, err := s.priceService.TargetLabel(targetType); err != nil {
+		return nil, err
+	}
+	return s.repo.FindPreferred(ctx, targetType, targetID)
+}
@@ -0,0 +1,63 @@
+package application
+
+import (
+	"context"
+
+	"back/internal/pricing/domain"
+)
+
+// TargetPriceService 绑定单一目标类型的报价服务（如产品核算使用的材料价格、工艺价格）
+type TargetPriceService struct {
+	service    *PriceService
+	targetType string
+}
+
+// TargetType 目标类型
+func (s *TargetPriceService) TargetType() string {
+	return s.targetType
+}
+
+// Quote 供应商报价
+func (s *TargetPriceService) Quote(ctx context.Context, req *QuoteRequest) (*domain.PriceData, error) {
+	return s.service.Quote(ctx, s.targetType, req)
+}
+
+// Withdraw 撤回报价
+func (s *TargetPriceService) Withdraw(ctx context.Context, quoteID uint) error {
+	return s.service.Withdraw(ctx, s.targetType, quoteID)
+}
+
+// GetMinPrice 获取当前有效报价中的最低价
+func (s *TargetPriceService) GetMinPrice(ctx context.Context, targetID uint, quantity float64) (*domain.PriceData, error) {
+	return s.service.GetMinPrice(ctx, s.targetType, targetID, quantity)
+}
+
+// GetMaxPrice 获取当前有效报价中的最高价
+func (s *TargetPriceService) GetMaxPrice(ctx context.Context, targetID uint, quantity float64) (*domain.PriceData, error) {
+	return s.service.GetMaxPrice(ctx, s.targetType, targetID, quantity)
+}
+
+// GetHistoricalMinPrice 获取历史最低报价
+func (s *TargetPriceService) GetHistoricalMinPrice(ctx context.Context, targetID uint) (*domain.PriceData, error) {
+	return s.service.GetHistoricalMinPrice(ctx, s.targetType, targetID)
+}
+
+// GetHistoricalMaxPrice 获取历史最高报价
+func (s *TargetPriceService) GetHistoricalMaxPrice(ctx context.Context, targetID uint) (*domain.PriceData, error) {
+	return s.service.GetHistoricalMaxPrice(ctx, s.targetType, targetID)
+}
+
+// GetCurrentPrice 获取当前价格
+func (s *TargetPriceService) GetCurrentPrice(ctx context.Context, targetID uint, quantity float64) (*domain.PriceData, error) {
+	return s.service.GetCurrentPrice(ctx, s.targetType, targetID, quantity)
+}
+
+// GetSupplierPrice 获取指定供应商当前有效的最新报价
+func (s *TargetPriceService) GetSupplierPrice(ctx context.Context, targetID, supplierID uint, quantity float64) (*domain.PriceData, error) {
+	return s.service.GetSupplierPrice(ctx, s.targetType, targetID, supplierID, quantity)
+}
+
+// GetHistory 获取报价历史
+func (s *TargetPriceService) GetHistory(ctx context.Context, targetID uint, limit int) ([]*domain.PriceData, error) {
+	return s.service.GetHistory(ctx, s.targetType, targetID, limit)
+}
@@ -6,20 +6,60 @@ import (
 )
 
 // PriceData 价格数据
+//
+// 按数量取价时 Price 为采购数量适用的阶梯单价，EffectivePrice 为计入起订量后折算到需求数量的单价。
 type PriceData struct {
-	Price        float64   `json:"price"`
-	SupplierID   uint      `json:"supplier_id"`
-	SupplierName string    `json:"supplier_name"`
-	QuotedAt     time.Time `json:"quoted_at"`
+	QuoteID          uint        `json:"quote_id"`
+	Price            float64     `json:"price"`
+	Currency         string      `json:"currency"`      // 报价币种
+	BasePrice        float64     `json:"base_price"`    // 换算为本位币的价格（当前有效报价按当日汇率，历史报价按生效日汇率）
+	BaseCurrency     string      `json:"base_currency"` // 本位币
+	MinOrderQty      float64     `json:"min_order_qty,omitempty"`
+	Tiers            []PriceTier `json:"tiers,omitempty"`
+	Quantity         float64     `json:"quantity,omitempty"`          // 需求数量（为 0 表示未按数量取价）
+	PurchaseQuantity float64     `json:"purchase_quantity,omitempty"` // 按起订量调整后的采购数量
+	EffectivePrice   float64     `json:"effective_price,omitempty"`   // 折算到需求数量的单价（报价币种）
+	SupplierID       uint        `json:"supplier_id"`
+	SupplierName     string      `json:"supplier_name"`
+	QuotedAt         time.Time   `json:"quoted_at"`
+	Status           string      `json:"status"`
+	ValidFrom        time.Time   `json:"valid_from"`
+	ValidTo          *time.Time  `json:"valid_to,omitempty"`
+}
+
+// UnitCost 计入成本的单价（报价币种）：按数量取价时为折算单价，否则为报价单价
+func (d *PriceData) UnitCost() float64 {
+	if d.EffectivePrice > 0 {
+		return d.EffectivePrice
+	}
+	return d.Price
+}
+
+// CacheStats 价格缓存统计
+type CacheStats struct {
+	Hits           int64      `json:"hits"`
+	Misses         int64      `json:"misses"`
+	HitRate        float64    `json:"hit_rate"` // 命中率（%）
+	Rebuilds       int64      `json:"rebuilds"` // 全量重建次数
+	LastRebuildAt  *time.Time `json:"last_rebuild_at,omitempty"`
+	LastRebuildNum int64      `json:"last_rebuild_targets"` // 最近一次重建的目标数
 }
 
 // PriceCache 价格缓存接口
+//
+// 每个目标有一个版本号，报价变化时 Invalidate 递增版本并删除缓存；
+// 读取方在查询数据库前取得版本号，写回时版本号已变化则放弃写入，避免并发报价时写入过期数据。
 type PriceCache interface {
 	GetMin(ctx context.Context, targetType string, targetID uint) (*PriceData, error)
-	SetMin(ctx context.Context, targetType string, targetID uint, data *PriceData) error
-	UpdateMin(ctx context.Context, targetType string, targetID uint, newPrice *PriceData) error
-
 	GetMax(ctx context.Context, targetType string, targetID uint) (*PriceData, error)
-	SetMax(ctx context.Context, targetType string, targetID uint, data *PriceData) error
-	UpdateMax(ctx context.Context, targetType string, targetID uint, newPrice *PriceData) error
-}
\ No newline at end of file
+
+	// Version 获取目标当前的缓存版本号
+	Version(ctx context.Context, targetType string, targetID uint) (int64, error)
+	// SetExtremes 版本号未变化时原子写入最低价与最高价（ttl 为 0 表示不过期），返回是否写入
+	SetExtremes(ctx context.Context, targetType string, targetID uint, version int64, lowest, highest *PriceData, ttl time.Duration) (bool, error)
+	// Invalidate 原子递增版本号并删除目标的最低价与最高价缓存
+	Invalidate(ctx context.Context, targetType string, targetID uint) error
+
+	Stats(ctx context.Context) (*CacheStats, error)
+	RecordRebuild(ctx context.Context, targets int) error
+}
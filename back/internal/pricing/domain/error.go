@@ -9,5 +9,48 @@ var (
 	ErrTargetIDRequired    = errors.New("target_id is required")
 	ErrSupplierIDRequired  = errors.New("supplier_id is required")
 	ErrInvalidPrice        = errors.New("price must be greater than 0")
+	ErrCurrencyRequired    = errors.New("currency is required")
 	ErrPriceNotFound       = errors.New("price not found")
+	ErrInvalidValidity     = errors.New("valid_to must be after valid_from")
+	ErrQuoteNotFound       = errors.New("quote not found")
+	ErrQuoteWithdrawn      = errors.New("quote already withdrawn")
+	ErrInvalidTier         = errors.New("price tiers must have distinct positive min_quantity and positive price")
+	ErrInvalidMinOrderQty  = errors.New("min_order_qty must not be negative")
+	ErrInvalidQuantity     = errors.New("quantity must not be negative")
+	ErrInvalidFreight      = errors.New("freight must not be negative")
+
+	ErrTargetTypeRegistered   = errors.New("target_type already registered")
+	ErrTargetResolverRequired = errors.New("target_type must provide existence checker and display-name resolver")
+	ErrInvalidInterval        = errors.New("interval must be day, week or month")
+
+	ErrImportFormat      = errors.New("price sheet must be a .csv or .xlsx file")
+	ErrImportEmpty       = errors.New("price sheet is empty")
+	ErrImportColumns     = errors.New("price sheet header must contain code/name and price columns")
+	ErrImportUnsupported = errors.New("target_type does not support import")
+	ErrImportAmbiguous   = errors.New("ambiguous target")
+	ErrImportDuplicate   = errors.New("duplicate target in price sheet")
+
+	ErrRFQNotFound          = errors.New("rfq not found")
+	ErrRFQItemNotFound      = errors.New("rfq item not found")
+	ErrRFQItemsRequired     = errors.New("rfq must contain at least one item")
+	ErrRFQSuppliersRequired = errors.New("rfq must invite at least one supplier")
+	ErrRFQDuplicateItem     = errors.New("rfq items must not repeat the same target")
+	ErrRFQQuantityRequired  = errors.New("rfq item quantity must be greater than 0")
+	ErrRFQDeadlinePassed    = errors.New("rfq deadline must be in the future")
+	ErrRFQNotOpen           = errors.New("rfq is not accepting responses")
+	ErrInvalidRFQTransition = errors.New("invalid rfq status transition")
+	ErrSupplierNotInvited   = errors.New("supplier is not invited to this rfq")
+	ErrSupplierDeclined     = errors.New("supplier has declined this rfq")
+	ErrSupplierResponded    = errors.New("supplier has already responded")
+	ErrRFQResponseNotFound  = errors.New("rfq response not found")
+	ErrPreferredNotFound    = errors.New("preferred supplier not found")
+
+	ErrWatchNotFound        = errors.New("price watch not found")
+	ErrWatchExists          = errors.New("target already watched")
+	ErrWatchOwnerRequired   = errors.New("watch owner is required")
+	ErrWatchNoRule          = errors.New("at least one notification rule must be enabled")
+	ErrInvalidChangePercent = errors.New("min_change_percent must not be negative")
+	ErrInvalidExpiryDays    = errors.New("expiry_days must be between 1 and 90")
+	ErrInvalidWebhookURL    = errors.New("webhook_url must be an absolute http or https url")
+	ErrWebhookForbidden     = errors.New("webhook_url must not point to a loopback, link-local or private address")
 )
\ No newline at end of file
@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// 比价分析的涨跌幅周期（天）
+var ChangePeriods = []int{30, 90, 365}
+
+// QuotePoint 报价时间序列中的一条报价（本位币价格按生效日汇率换算，缺少汇率时为空）
+type QuotePoint struct {
+	QuoteID       uint       `json:"quote_id"`
+	SupplierID    uint       `json:"supplier_id"`
+	SupplierName  string     `json:"supplier_name"`
+	Price         float64    `json:"price"`
+	Currency      string     `json:"currency"`
+	BasePrice     *float64   `json:"base_price"`
+	Status        string     `json:"status"`
+	EffectiveFrom time.Time  `json:"effective_from"`
+	ValidTo       *time.Time `json:"valid_to,omitempty"`
+}
+
+// DailyBest 某日有效报价中的最低价（按当日汇率换算为本位币）
+type DailyBest struct {
+	Day          time.Time `json:"day"`
+	SupplierID   uint      `json:"supplier_id"`
+	SupplierName string    `json:"supplier_name"`
+	BasePrice    float64   `json:"base_price"`
+}
+
+// IndexPoint 采购成本指数（基期为 100）
+type IndexPoint struct {
+	Day     time.Time `json:"day"`
+	Index   float64   `json:"index"`
+	Targets int       `json:"targets"` // 参与计算的目标数（基期与当期都有报价）
+}
+
+// PriceSpread 当前各供应商报价的价差（本位币）
+type PriceSpread struct {
+	Lowest        float64 `json:"lowest"`
+	Highest       float64 `json:"highest"`
+	Spread        float64 `json:"spread"`         // 最高价 - 最低价
+	SpreadPercent float64 `json:"spread_percent"` // 价差占最低价的百分比
+	Suppliers     int     `json:"suppliers"`
+}
+
+// PriceChange 最低价在一个周期内的涨跌幅
+type PriceChange struct {
+	Days          int       `json:"days"`
+	From          time.Time `json:"from"`
+	FromPrice     *float64  `json:"from_price"` // 周期起点的最低价（当日没有报价时为空）
+	ToPrice       *float64  `json:"to_price"`
+	ChangePercent *float64  `json:"change_percent"` // 两端都有报价时才计算
+}
+
+// BestPriceShare 供应商报价为最低价的天数占比
+type BestPriceShare struct {
+	SupplierID   uint    `json:"supplier_id"`
+	SupplierName string  `json:"supplier_name"`
+	Days         int     `json:"days"`
+	Share        float64 `json:"share"` // 占有报价天数的百分比
+}
+
+// SpreadOf 按本位币价格计算价差（没有可换算的报价时返回 nil）
+func SpreadOf(quotes []QuotePoint) *PriceSpread {
+	var spread *PriceSpread
+	for _, q := range quotes {
+		if q.BasePrice == nil {
+			continue
+		}
+		price := *q.BasePrice
+		if spread == nil {
+			spread = &PriceSpread{Lowest: price, Highest: price}
+		}
+		if price < spread.Lowest {
+			spread.Lowest = price
+		}
+		if price > spread.Highest {
+			spread.Highest = price
+		}
+		spread.Suppliers++
+	}
+	if spread == nil {
+		return nil
+	}
+	spread.Spread = spread.Highest - spread.Lowest
+	if spread.Lowest > 0 {
+		spread.SpreadPercent = spread.Spread / spread.Lowest * 100
+	}
+	return spread
+}
+
+// ChangeOver 最低价在 days 天内的涨跌幅（best 为按日期升序的每日最低价，today 为周期终点）
+func ChangeOver(best []DailyBest, today time.Time, days int) PriceChange {
+	from := today.AddDate(0, 0, -days)
+	change := PriceChange{Days: days, From: from}
+	change.FromPrice = bestOn(best, from)
+	change.ToPrice = bestOn(best, today)
+	if change.FromPrice != nil && change.ToPrice != nil && *change.FromPrice > 0 {
+		pct := (*change.ToPrice - *change.FromPrice) / *change.FromPrice * 100
+		change.ChangePercent = &pct
+	}
+	return change
+}
+
+// bestOn 某日的最低价（按日历日比较，数据库返回的日期不带时区）
+func bestOn(best []DailyBest, day time.Time) *float64 {
+	key := dayKey(day)
+	i := sort.Search(len(best), func(i int) bool { return dayKey(best[i].Day) >= key })
+	if i < len(best) && dayKey(best[i].Day) == key {
+		price := best[i].BasePrice
+		return &price
+	}
+	return nil
+}
+
+// dayKey 日历日（yyyymmdd）
+func dayKey(t time.Time) int {
+	y, m, d := t.Date()
+	return y*10000 + int(m)*100 + d
+}
+
+// BestPriceShares 统计 since 之后（含）各供应商报价为最低价的天数占比，按天数降序
+func BestPriceShares(best []DailyBest, since time.Time) []BestPriceShare {
+	index := make(map[uint]int)
+	var shares []BestPriceShare
+	total := 0
+	for _, b := range best {
+		if dayKey(b.Day) < dayKey(since) {
+			continue
+		}
+		total++
+		i, ok := index[b.SupplierID]
+		if !ok {
+			i = len(shares)
+			index[b.SupplierID] = i
+			shares = append(shares, BestPriceShare{SupplierID: b.SupplierID, SupplierName: b.SupplierName})
+		}
+		shares[i].Days++
+	}
+	for i := range shares {
+		shares[i].Share = float64(shares[i].Days) / float64(total) * 100
+	}
+	sort.SliceStable(shares, func(i, j int) bool { return shares[i].Days > shares[j].Days })
+	return shares
+}
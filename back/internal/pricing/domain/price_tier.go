@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"math"
+	"sort"
+)
+
+// PriceTier 阶梯价：采购数量达到 MinQuantity 时适用的单价（直到下一阶梯起点）
+type PriceTier struct {
+	ID          uint    `gorm:"primaryKey" json:"id"`
+	QuoteID     uint    `gorm:"not null;index" json:"quote_id"`
+	MinQuantity float64 `gorm:"type:decimal(12,2);not null" json:"min_quantity"`
+	Price       float64 `gorm:"type:decimal(10,2);not null" json:"price"`
+}
+
+// TableName 表名
+func (PriceTier) TableName() string {
+	return "supplier_price_tiers"
+}
+
+// QuantityPrice 按采购数量取得的报价
+type QuantityPrice struct {
+	Quantity         float64 // 需求数量
+	PurchaseQuantity float64 // 按起订量调整后的采购数量
+	Price            float64 // 采购数量适用的单价
+}
+
+// EffectivePrice 折算到需求数量的单价（起订量多采部分计入需求数量的成本）
+func (q QuantityPrice) EffectivePrice() float64 {
+	if q.Quantity <= 0 {
+		return q.Price
+	}
+	return q.Price * q.PurchaseQuantity / q.Quantity
+}
+
+// SortTiers 阶梯按起点升序排列
+func SortTiers(tiers []PriceTier) {
+	sort.SliceStable(tiers, func(i, j int) bool {
+		return tiers[i].MinQuantity < tiers[j].MinQuantity
+	})
+}
+
+// validateTiers 校验阶梯：起点与单价为正，起点不重复（调用前已排序）
+func validateTiers(tiers []PriceTier) error {
+	for i, t := range tiers {
+		if t.MinQuantity <= 0 || t.Price <= 0 {
+			return ErrInvalidTier
+		}
+		if i > 0 && t.MinQuantity == tiers[i-1].MinQuantity {
+			return ErrInvalidTier
+		}
+	}
+	return nil
+}
+
+// PriceFor 按需求数量取价：采购数量不低于起订量，取采购数量落入的阶梯单价，低于首个阶梯时取报价单价
+//
+// quantity 为 0 时不考虑数量，返回报价单价。
+func (sp *SupplierPrice) PriceFor(quantity float64) QuantityPrice {
+	if quantity <= 0 {
+		return QuantityPrice{Price: sp.Price}
+	}
+
+	result := QuantityPrice{
+		Quantity:         quantity,
+		PurchaseQuantity: math.Max(quantity, sp.MinOrderQty),
+		Price:            sp.Price,
+	}
+	for _, t := range sp.Tiers {
+		if result.PurchaseQuantity >= t.MinQuantity {
+			result.Price = t.Price
+		}
+	}
+	return result
+}
+
+// LandedPrice 到岸单价（报价币种）：按需求数量的采购金额加运杂费，折算到需求数量
+func (sp *SupplierPrice) LandedPrice(quantity float64) float64 {
+	qp := sp.PriceFor(quantity)
+	if qp.Quantity <= 0 {
+		return sp.Price
+	}
+	return (qp.Price*qp.PurchaseQuantity + sp.Freight) / qp.Quantity
+}
@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// 关注提醒事件
+const (
+	WatchEventMinPriceChange = "min_price_change" // 有效最低价变动
+	WatchEventUndercut       = "undercut"         // 新报价低于现任供应商
+	WatchEventExpiring       = "expiring"         // 报价即将到期
+)
+
+// Webhook 投递状态
+const (
+	WebhookStatusPending   = "pending"
+	WebhookStatusDelivered = "delivered"
+	WebhookStatusFailed    = "failed"
+)
+
+// DefaultExpiryDays 默认提前提醒到期的天数
+const DefaultExpiryDays = 7
+
+// MaxExpiryDays 到期提醒最多提前的天数
+const MaxExpiryDays = 90
+
+// PriceWatch 用户关注的报价目标及提醒规则
+type PriceWatch struct {
+	ID               uint      `gorm:"primaryKey" json:"id"`
+	LoginID          string    `gorm:"size:50;not null;uniqueIndex:idx_watch_user_target" json:"login_id"`
+	TargetType       string    `gorm:"size:20;not null;uniqueIndex:idx_watch_user_target;index:idx_watch_target" json:"target_type"`
+	TargetID         uint      `gorm:"not null;uniqueIndex:idx_watch_user_target;index:idx_watch_target" json:"target_id"`
+	NotifyMinChange  bool      `gorm:"not null" json:"notify_min_change"`
+	MinChangePercent float64   `gorm:"type:decimal(6,2);default:0" json:"min_change_percent"` // 最低价变动达到该比例（%）才提醒（0 表示任何变动）
+	NotifyUndercut   bool      `gorm:"not null" json:"notify_undercut"`
+	NotifyExpiring   bool      `gorm:"not null" json:"notify_expiring"`
+	ExpiryDays       int       `gorm:"default:7" json:"expiry_days"`          // 报价到期前多少天提醒
+	WebhookURL       string    `gorm:"size:500" json:"webhook_url,omitempty"` // 同时推送到该地址（为空时仅站内通知）
+	CreatedAt        time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 表名
+func (PriceWatch) TableName() string {
+	return "pricing_price_watches"
+}
+
+// Validate 验证关注规则（目标类型是否已注册、目标是否存在、Webhook 地址由服务校验）
+func (w *PriceWatch) Validate() error {
+	if w.LoginID == "" {
+		return ErrWatchOwnerRequired
+	}
+	if !targetTypePattern.MatchString(w.TargetType) {
+		return ErrInvalidTargetType
+	}
+	if w.TargetID == 0 {
+		return ErrTargetIDRequired
+	}
+	if !w.NotifyMinChange && !w.NotifyUndercut && !w.NotifyExpiring {
+		return ErrWatchNoRule
+	}
+	if w.MinChangePercent < 0 {
+		return ErrInvalidChangePercent
+	}
+	if w.NotifyExpiring && (w.ExpiryDays <= 0 || w.ExpiryDays > MaxExpiryDays) {
+		return ErrInvalidExpiryDays
+	}
+	return nil
+}
+
+// MinPriceChanged 有效最低价（本位币）变动是否达到提醒阈值（最低价出现或消失均视为变动）
+func (w *PriceWatch) MinPriceChanged(before, after *PriceData) bool {
+	if before == nil || after == nil {
+		return before != after
+	}
+	if before.QuoteID == after.QuoteID && before.BasePrice == after.BasePrice {
+		return false
+	}
+	if before.BasePrice <= 0 {
+		return after.BasePrice != before.BasePrice
+	}
+	change := math.Abs(after.BasePrice-before.BasePrice) / before.BasePrice * 100
+	return change > 0 && change >= w.MinChangePercent
+}
+
+// ExpiresWithin 报价是否在提醒范围内到期（已到期的不提醒）
+func (w *PriceWatch) ExpiresWithin(validTo *time.Time, now time.Time) bool {
+	if validTo == nil || !validTo.After(now) {
+		return false
+	}
+	return !validTo.After(now.AddDate(0, 0, w.ExpiryDays))
+}
+
+// PriceAlert 已发出的关注提醒（到期提醒按报价去重，并记录 Webhook 投递结果）
+type PriceAlert struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	WatchID       uint      `gorm:"not null;index:idx_alert_watch_event" json:"watch_id"`
+	LoginID       string    `gorm:"size:50;not null;index" json:"login_id"`
+	Event         string    `gorm:"size:30;not null;index:idx_alert_watch_event" json:"event"`
+	TargetType    string    `gorm:"size:20;not null" json:"target_type"`
+	TargetID      uint      `gorm:"not null" json:"target_id"`
+	QuoteID       uint      `gorm:"default:0;index:idx_alert_watch_event" json:"quote_id,omitempty"` // 触发提醒的报价
+	SupplierID    uint      `gorm:"default:0" json:"supplier_id,omitempty"`
+	Title         string    `gorm:"size:200;not null" json:"title"`
+	Content       string    `gorm:"type:text" json:"content"`
+	WebhookStatus string    `gorm:"size:20" json:"webhook_status,omitempty"` // 未配置 Webhook 时为空
+	WebhookError  string    `gorm:"size:500" json:"webhook_error,omitempty"`
+	CreatedAt     time.Time `gorm:"autoCreateTime;index" json:"created_at"`
+}
+
+// TableName 表名
+func (PriceAlert) TableName() string {
+	return "pricing_price_alerts"
+}
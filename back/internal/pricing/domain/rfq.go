@@ -0,0 +1,297 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"back/pkg/serial"
+)
+
+// 询价单状态常量
+const (
+	RFQStatusOpen       = "open"       // 询价中（截止前可录入供应商回复）
+	RFQStatusEvaluating = "evaluating" // 已截止报价，比价定标中
+	RFQStatusAwarded    = "awarded"    // 全部明细已定标
+	RFQStatusClosed     = "closed"     // 已关闭
+	RFQStatusCancelled  = "cancelled"  // 已取消
+)
+
+// 受邀供应商回复状态常量
+const (
+	InvitationStatusInvited   = "invited"   // 已邀请，未回复
+	InvitationStatusResponded = "responded" // 已报价
+	InvitationStatusDeclined  = "declined"  // 已谢绝
+)
+
+// RFQ 询价单聚合根
+type RFQ struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	RFQNo       string     `gorm:"size:50;uniqueIndex;not null" json:"rfq_no"`
+	Title       string     `gorm:"size:200;not null" json:"title"`
+	Status      string     `gorm:"size:20;not null;index" json:"status"`
+	Currency    string     `gorm:"size:3;not null" json:"currency"` // 比价币种
+	Deadline    time.Time  `gorm:"not null;index" json:"deadline"`  // 报价截止时间
+	Remark      string     `gorm:"size:500" json:"remark"`
+	CreatedBy   uint       `gorm:"not null" json:"created_by"`
+	CreatorName string     `gorm:"size:100" json:"creator_name"`
+	AwardedAt   *time.Time `json:"awarded_at,omitempty"`
+	ClosedAt    *time.Time `json:"closed_at,omitempty"` // 关闭或取消时间
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	Items       []RFQItem       `gorm:"foreignKey:RFQID;constraint:OnDelete:CASCADE" json:"items,omitempty"`
+	Invitations []RFQInvitation `gorm:"foreignKey:RFQID;constraint:OnDelete:CASCADE" json:"invitations,omitempty"`
+}
+
+// TableName 表名
+func (RFQ) TableName() string {
+	return "pricing_rfqs"
+}
+
+// RFQItem 询价明细（一个报价目标及需求数量）
+type RFQItem struct {
+	ID                uint       `gorm:"primaryKey" json:"id"`
+	RFQID             uint       `gorm:"not null;index" json:"rfq_id"`
+	TargetType        string     `gorm:"size:20;not null" json:"target_type"`
+	TargetID          uint       `gorm:"not null" json:"target_id"`
+	Quantity          float64    `gorm:"type:decimal(12,2);not null" json:"quantity"`
+	AwardedSupplierID uint       `gorm:"default:0" json:"awarded_supplier_id,omitempty"`
+	AwardedQuoteID    uint       `gorm:"default:0" json:"awarded_quote_id,omitempty"`
+	AwardedAt         *time.Time `json:"awarded_at,omitempty"`
+}
+
+// TableName 表名
+func (RFQItem) TableName() string {
+	return "pricing_rfq_items"
+}
+
+// IsAwarded 是否已定标
+func (i *RFQItem) IsAwarded() bool {
+	return i.AwardedQuoteID != 0
+}
+
+// RFQInvitation 受邀供应商
+type RFQInvitation struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	RFQID         uint       `gorm:"not null;uniqueIndex:idx_rfq_supplier" json:"rfq_id"`
+	SupplierID    uint       `gorm:"not null;uniqueIndex:idx_rfq_supplier" json:"supplier_id"`
+	Status        string     `gorm:"size:20;not null" json:"status"`
+	DeclineReason string     `gorm:"size:500" json:"decline_reason,omitempty"`
+	RespondedAt   *time.Time `json:"responded_at,omitempty"`
+}
+
+// TableName 表名
+func (RFQInvitation) TableName() string {
+	return "pricing_rfq_invitations"
+}
+
+// NewRFQ 创建询价单：明细不能重复，需求数量为正，截止时间晚于当前时间，至少邀请一家供应商
+func NewRFQ(title, currency string, deadline time.Time, items []RFQItem, supplierIDs []uint, creatorID uint, creatorName string, now time.Time) (*RFQ, error) {
+	if !deadline.After(now) {
+		return nil, ErrRFQDeadlinePassed
+	}
+	if len(items) == 0 {
+		return nil, ErrRFQItemsRequired
+	}
+
+	seen := make(map[TargetRef]bool, len(items))
+	for _, item := range items {
+		if !targetTypePattern.MatchString(item.TargetType) {
+			return nil, ErrInvalidTargetType
+		}
+		if item.TargetID == 0 {
+			return nil, ErrTargetIDRequired
+		}
+		if item.Quantity <= 0 {
+			return nil, ErrRFQQuantityRequired
+		}
+		ref := TargetRef{TargetType: item.TargetType, TargetID: item.TargetID}
+		if seen[ref] {
+			return nil, ErrRFQDuplicateItem
+		}
+		seen[ref] = true
+	}
+
+	invitations := make([]RFQInvitation, 0, len(supplierIDs))
+	invited := make(map[uint]bool, len(supplierIDs))
+	for _, id := range supplierIDs {
+		if id == 0 || invited[id] {
+			continue
+		}
+		invited[id] = true
+		invitations = append(invitations, RFQInvitation{SupplierID: id, Status: InvitationStatusInvited})
+	}
+	if len(invitations) == 0 {
+		return nil, ErrRFQSuppliersRequired
+	}
+
+	return &RFQ{
+		RFQNo:       serial.New("RFQ", now),
+		Title:       title,
+		Status:      RFQStatusOpen,
+		Currency:    currency,
+		Deadline:    deadline,
+		CreatedBy:   creatorID,
+		CreatorName: creatorName,
+		Items:       items,
+		Invitations: invitations,
+	}, nil
+}
+
+// Refresh 到达截止时间的询价单转为比价中，返回状态是否变化
+func (r *RFQ) Refresh(now time.Time) bool {
+	if r.Status == RFQStatusOpen && !now.Before(r.Deadline) {
+		r.Status = RFQStatusEvaluating
+		return true
+	}
+	return false
+}
+
+// Item 查找询价明细
+func (r *RFQ) Item(itemID uint) (*RFQItem, error) {
+	for i := range r.Items {
+		if r.Items[i].ID == itemID {
+			return &r.Items[i], nil
+		}
+	}
+	return nil, ErrRFQItemNotFound
+}
+
+// Invitation 查找受邀供应商
+func (r *RFQ) Invitation(supplierID uint) (*RFQInvitation, error) {
+	for i := range r.Invitations {
+		if r.Invitations[i].SupplierID == supplierID {
+			return &r.Invitations[i], nil
+		}
+	}
+	return nil, ErrSupplierNotInvited
+}
+
+// acceptingResponses 截止前且仍在询价中的供应商
+func (r *RFQ) acceptingResponses(supplierID uint, now time.Time) (*RFQInvitation, error) {
+	r.Refresh(now)
+	if r.Status != RFQStatusOpen {
+		return nil, ErrRFQNotOpen
+	}
+	return r.Invitation(supplierID)
+}
+
+// CanRespond 校验供应商是否可对明细报价
+func (r *RFQ) CanRespond(itemID, supplierID uint, now time.Time) (*RFQItem, error) {
+	invitation, err := r.acceptingResponses(supplierID, now)
+	if err != nil {
+		return nil, err
+	}
+	if invitation.Status == InvitationStatusDeclined {
+		return nil, ErrSupplierDeclined
+	}
+	return r.Item(itemID)
+}
+
+// RecordResponse 记录供应商已报价
+func (r *RFQ) RecordResponse(supplierID uint, now time.Time) (*RFQInvitation, error) {
+	invitation, err := r.Invitation(supplierID)
+	if err != nil {
+		return nil, err
+	}
+	invitation.Status = InvitationStatusResponded
+	invitation.RespondedAt = &now
+	return invitation, nil
+}
+
+// Decline 供应商谢绝报价（已报价的供应商不能谢绝）
+func (r *RFQ) Decline(supplierID uint, reason string, now time.Time) (*RFQInvitation, error) {
+	invitation, err := r.acceptingResponses(supplierID, now)
+	if err != nil {
+		return nil, err
+	}
+	if invitation.Status == InvitationStatusResponded {
+		return nil, ErrSupplierResponded
+	}
+	invitation.Status = InvitationStatusDeclined
+	invitation.DeclineReason = reason
+	invitation.RespondedAt = &now
+	return invitation, nil
+}
+
+// StopBidding 提前截止报价，进入比价
+func (r *RFQ) StopBidding(now time.Time) error {
+	if r.Refresh(now) {
+		return nil
+	}
+	if r.Status != RFQStatusOpen {
+		return fmt.Errorf("%w: cannot stop bidding in status %s", ErrInvalidRFQTransition, r.Status)
+	}
+	r.Status = RFQStatusEvaluating
+	return nil
+}
+
+// Award 明细定标给某供应商的回复报价，全部明细定标后询价单转为已定标
+func (r *RFQ) Award(itemID, supplierID, quoteID uint, now time.Time) (*RFQItem, error) {
+	r.Refresh(now)
+	if r.Status != RFQStatusEvaluating && r.Status != RFQStatusAwarded {
+		return nil, fmt.Errorf("%w: cannot award in status %s", ErrInvalidRFQTransition, r.Status)
+	}
+	item, err := r.Item(itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	item.AwardedSupplierID = supplierID
+	item.AwardedQuoteID = quoteID
+	item.AwardedAt = &now
+
+	for i := range r.Items {
+		if !r.Items[i].IsAwarded() {
+			return item, nil
+		}
+	}
+	r.Status = RFQStatusAwarded
+	r.AwardedAt = &now
+	return item, nil
+}
+
+// Close 关闭询价单（比价中或已定标）
+func (r *RFQ) Close(now time.Time) error {
+	r.Refresh(now)
+	if r.Status != RFQStatusEvaluating && r.Status != RFQStatusAwarded {
+		return fmt.Errorf("%w: cannot close in status %s", ErrInvalidRFQTransition, r.Status)
+	}
+	r.Status = RFQStatusClosed
+	r.ClosedAt = &now
+	return nil
+}
+
+// Cancel 取消询价单（未定标前）
+func (r *RFQ) Cancel(now time.Time) error {
+	if r.Status != RFQStatusOpen && r.Status != RFQStatusEvaluating {
+		return fmt.Errorf("%w: cannot cancel in status %s", ErrInvalidRFQTransition, r.Status)
+	}
+	for i := range r.Items {
+		if r.Items[i].IsAwarded() {
+			return fmt.Errorf("%w: items already awarded", ErrInvalidRFQTransition)
+		}
+	}
+	r.Status = RFQStatusCancelled
+	r.ClosedAt = &now
+	return nil
+}
+
+// PreferredSupplier 报价目标的首选供应商（询价定标时更新）
+type PreferredSupplier struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	TargetType string    `gorm:"size:20;not null;uniqueIndex:idx_preferred_target" json:"target_type"`
+	TargetID   uint      `gorm:"not null;uniqueIndex:idx_preferred_target" json:"target_id"`
+	SupplierID uint      `gorm:"not null;index" json:"supplier_id"`
+	QuoteID    uint      `gorm:"not null" json:"quote_id"` // 中标报价
+	RFQID      uint      `gorm:"not null" json:"rfq_id"`
+	Price      float64   `gorm:"type:decimal(10,2);not null" json:"price"` // 中标单价（报价币种）
+	Currency   string    `gorm:"size:3;not null" json:"currency"`
+	AwardedBy  string    `gorm:"size:50" json:"awarded_by"`
+	AwardedAt  time.Time `gorm:"not null" json:"awarded_at"`
+}
+
+// TableName 表名
+func (PreferredSupplier) TableName() string {
+	return "preferred_suppliers"
+}
@@ -4,21 +4,39 @@ import (
 	"time"
 )
 
-// 目标类型常量
+// 内置目标类型（其他类型由所属领域注册到 TargetRegistry）
 const (
 	TargetTypeMaterial = "material"
 	TargetTypeProcess  = "process"
 )
 
+// 报价状态常量
+const (
+	QuoteStatusActive     = "active"     // 有效
+	QuoteStatusSuperseded = "superseded" // 已被同一供应商的新报价取代（在新报价生效前仍有效）
+	QuoteStatusWithdrawn  = "withdrawn"  // 已撤回
+)
+
 // SupplierPrice 供应商价格聚合根
 type SupplierPrice struct {
-	ID         uint      `gorm:"primaryKey" json:"id"`
-	TargetType string    `gorm:"size:20;not null;index:idx_target_price" json:"target_type"`
-	TargetID   uint      `gorm:"not null;index:idx_target_price" json:"target_id"`
-	SupplierID uint      `gorm:"not null;index" json:"supplier_id"`
-	Price      float64   `gorm:"type:decimal(10,2);not null;index:idx_target_price" json:"price"`
-	QuotedAt   time.Time `gorm:"not null;index:idx_target_time" json:"quoted_at"`
-	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+	ID           uint        `gorm:"primaryKey" json:"id"`
+	TargetType   string      `gorm:"size:20;not null;index:idx_target_price" json:"target_type"`
+	TargetID     uint        `gorm:"not null;index:idx_target_price" json:"target_id"`
+	SupplierID   uint        `gorm:"not null;index" json:"supplier_id"`
+	Price        float64     `gorm:"type:decimal(10,2);not null;index:idx_target_price" json:"price"`
+	Currency     string      `gorm:"size:3;not null;default:CNY" json:"currency"`                           // 报价币种
+	MinOrderQty  float64     `gorm:"type:decimal(12,2);default:0" json:"min_order_qty"`                     // 起订量（0 表示不限）
+	Tiers        []PriceTier `gorm:"foreignKey:QuoteID;constraint:OnDelete:CASCADE" json:"tiers,omitempty"` // 数量阶梯价（Price 为首个阶梯以下的单价）
+	Freight      float64     `gorm:"type:decimal(12,2);default:0" json:"freight,omitempty"`                 // 每次采购的运杂费（报价币种），用于计算到岸价
+	RFQID        uint        `gorm:"default:0;index" json:"rfq_id,omitempty"`                               // 来源询价单（0 表示直接录入）
+	RFQItemID    uint        `gorm:"default:0" json:"rfq_item_id,omitempty"`                                // 来源询价明细
+	QuotedAt     time.Time   `gorm:"not null;index:idx_target_time" json:"quoted_at"`
+	Status       string      `gorm:"size:20;default:active;index" json:"status"`
+	ValidFrom    *time.Time  `json:"valid_from"`                               // 生效日期（为空时自报价时起）
+	ValidTo      *time.Time  `json:"valid_to"`                                 // 失效日期（为空时长期有效）
+	SupersededBy uint        `gorm:"default:0" json:"superseded_by,omitempty"` // 取代本报价的新报价 ID
+	WithdrawnAt  *time.Time  `json:"withdrawn_at,omitempty"`
+	CreatedAt    time.Time   `gorm:"autoCreateTime" json:"created_at"`
 }
 
 // TableName 表名
@@ -26,13 +44,13 @@ func (SupplierPrice) TableName() string {
 	return "supplier_prices"
 }
 
-// Validate 验证价格数据
+// Validate 验证价格数据（目标类型是否已注册、目标是否存在由价格服务通过 TargetRegistry 校验）
 func (sp *SupplierPrice) Validate() error {
 	if sp.TargetType == "" {
 		return ErrTargetTypeRequired
 	}
 
-	if sp.TargetType != TargetTypeMaterial && sp.TargetType != TargetTypeProcess {
+	if !targetTypePattern.MatchString(sp.TargetType) {
 		return ErrInvalidTargetType
 	}
 
@@ -48,9 +66,90 @@ func (sp *SupplierPrice) Validate() error {
 		return ErrInvalidPrice
 	}
 
+	if sp.Currency == "" {
+		return ErrCurrencyRequired
+	}
+
+	if sp.MinOrderQty < 0 {
+		return ErrInvalidMinOrderQty
+	}
+
+	if sp.Freight < 0 {
+		return ErrInvalidFreight
+	}
+
+	SortTiers(sp.Tiers)
+	if err := validateTiers(sp.Tiers); err != nil {
+		return err
+	}
+
+	if sp.ValidTo != nil && !sp.ValidTo.After(sp.EffectiveFrom()) {
+		return ErrInvalidValidity
+	}
+
 	return nil
 }
 
+// EffectiveFrom 生效时间（未指定生效日期时为报价时间）
+func (sp *SupplierPrice) EffectiveFrom() time.Time {
+	if sp.ValidFrom != nil {
+		return *sp.ValidFrom
+	}
+	return sp.QuotedAt
+}
+
+// IsEffective 是否在指定时间有效（未撤回且处于有效期内）
+func (sp *SupplierPrice) IsEffective(at time.Time) bool {
+	if sp.Status == QuoteStatusWithdrawn {
+		return false
+	}
+	if at.Before(sp.EffectiveFrom()) {
+		return false
+	}
+	return sp.ValidTo == nil || at.Before(*sp.ValidTo)
+}
+
+// Supersede 被同一供应商的新报价取代：在新报价生效时失效
+func (sp *SupplierPrice) Supersede(next *SupplierPrice) {
+	sp.Status = QuoteStatusSuperseded
+	sp.SupersededBy = next.ID
+
+	until := next.EffectiveFrom()
+	if until.Before(sp.EffectiveFrom()) {
+		until = sp.EffectiveFrom()
+	}
+	if sp.ValidTo == nil || until.Before(*sp.ValidTo) {
+		sp.ValidTo = &until
+	}
+}
+
+// Withdraw 撤回报价
+func (sp *SupplierPrice) Withdraw(at time.Time) error {
+	if sp.Status == QuoteStatusWithdrawn {
+		return ErrQuoteWithdrawn
+	}
+	sp.Status = QuoteStatusWithdrawn
+	sp.WithdrawnAt = &at
+	return nil
+}
+
+// ToPriceData 转换为价格数据
+func (sp *SupplierPrice) ToPriceData(supplierName string) *PriceData {
+	return &PriceData{
+		QuoteID:      sp.ID,
+		Price:        sp.Price,
+		Currency:     sp.Currency,
+		MinOrderQty:  sp.MinOrderQty,
+		Tiers:        sp.Tiers,
+		SupplierID:   sp.SupplierID,
+		SupplierName: supplierName,
+		QuotedAt:     sp.QuotedAt,
+		Status:       sp.Status,
+		ValidFrom:    sp.EffectiveFrom(),
+		ValidTo:      sp.ValidTo,
+	}
+}
+
 // IsLowerThan 是否低于指定价格
 func (sp *SupplierPrice) IsLowerThan(price float64) bool {
 	return sp.Price < price
@@ -59,4 +158,10 @@ func (sp *SupplierPrice) IsLowerThan(price float64) bool {
 // IsHigherThan 是否高于指定价格
 func (sp *SupplierPrice) IsHigherThan(price float64) bool {
 	return sp.Price > price
-}
\ No newline at end of file
+}
+
+// TargetRef 报价目标（已注册目标类型中的一个目标）
+type TargetRef struct {
+	TargetType string `json:"target_type"`
+	TargetID   uint   `json:"target_id"`
+}
@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// targetTypePattern 目标类型标识：小写字母开头，由小写字母、数字和下划线组成（supplier_prices.target_type 最长 20 位）
+var targetTypePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,19}$`)
+
+// TargetType 报价目标类型（由目标所属领域注册，如材料、工艺、染料配方、运输线路、包装）
+type TargetType struct {
+	Name        string                                             // 类型标识，写入 supplier_prices.target_type
+	Label       string                                             // 显示名称，如 材料
+	Exists      func(ctx context.Context, id uint) (bool, error)   // 目标是否存在
+	DisplayName func(ctx context.Context, id uint) (string, error) // 目标显示名称
+
+	// Resolve 按业务键（如材料编号、工序名称）批量查找目标 ID，导入报价单时使用；未提供时该类型不支持导入
+	Resolve  func(ctx context.Context, keys []string) (map[string][]uint, error)
+	KeyLabel string // 业务键名称，如 材料编号
+}
+
+// TargetRegistry 报价目标类型注册表
+type TargetRegistry struct {
+	types map[string]*TargetType
+	mu    sync.RWMutex
+}
+
+// NewTargetRegistry 创建注册表
+func NewTargetRegistry() *TargetRegistry {
+	return &TargetRegistry{types: make(map[string]*TargetType)}
+}
+
+// Register 注册目标类型（标识不合法、缺少存在性检查或名称解析、重复注册时返回错误）
+func (r *TargetRegistry) Register(t TargetType) error {
+	if !targetTypePattern.MatchString(t.Name) {
+		return ErrInvalidTargetType
+	}
+	if t.Exists == nil || t.DisplayName == nil {
+		return ErrTargetResolverRequired
+	}
+	if t.Label == "" {
+		t.Label = t.Name
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.types[t.Name]; ok {
+		return ErrTargetTypeRegistered
+	}
+	r.types[t.Name] = &t
+	return nil
+}
+
+// Get 获取已注册的目标类型
+func (r *TargetRegistry) Get(name string) (*TargetType, error) {
+	if name == "" {
+		return nil, ErrTargetTypeRequired
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.types[name]
+	if !ok {
+		return nil, ErrInvalidTargetType
+	}
+	return t, nil
+}
+
+// Types 已注册的目标类型（按标识排序）
+func (r *TargetRegistry) Types() []*TargetType {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	types := make([]*TargetType, 0, len(r.types))
+	for _, t := range r.types {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+	return types
+}
+
+// Check 校验目标类型已注册且目标存在（不存在时返回 ErrTargetNotFound）
+func (r *TargetRegistry) Check(ctx context.Context, ref TargetRef) error {
+	t, err := r.Get(ref.TargetType)
+	if err != nil {
+		return err
+	}
+	if ref.TargetID == 0 {
+		return ErrTargetIDRequired
+	}
+	exists, err := t.Exists(ctx, ref.TargetID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrTargetNotFound
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	currencyDomain "back/internal/currency/domain"
+	"back/internal/pricing/domain"
+)
+
+// PriceAnalyticsRepo 比价与价格趋势分析（聚合 SQL，供应商名称与本位币换算在查询中完成）
+type PriceAnalyticsRepo struct {
+	db *gorm.DB
+}
+
+// NewPriceAnalyticsRepo 创建仓储实现
+func NewPriceAnalyticsRepo(db *gorm.DB) *PriceAnalyticsRepo {
+	return &PriceAnalyticsRepo{db: db}
+}
+
+// baseRate 报价币种到本位币的汇率：day 当日生效的直接汇率，没有时取反向汇率的倒数，都没有时为 NULL
+func baseRate(currency, day string) string {
+	return fmt.Sprintf(`(CASE WHEN %[1]s = @base THEN 1 ELSE COALESCE(
+		(SELECT er.rate FROM exchange_rates er
+			WHERE er.from_currency = %[1]s AND er.to_currency = @base AND er.effective_date <= %[2]s
+			ORDER BY er.effective_date DESC LIMIT 1),
+		(SELECT 1 / er.rate FROM exchange_rates er
+			WHERE er.from_currency = @base AND er.to_currency = %[1]s AND er.effective_date <= %[2]s
+			ORDER BY er.effective_date DESC LIMIT 1)
+	) END)`, currency, day)
+}
+
+// FindQuoteSeries 查询 since 之后仍有效过的全部报价（不含撤回），按供应商、生效时间排序，本位币价格按生效日汇率换算
+func (r *PriceAnalyticsRepo) FindQuoteSeries(ctx context.Context, targetType string, targetID uint, since time.Time, base string) ([]domain.QuotePoint, error) {
+	var results []domain.QuotePoint
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT sp.id AS quote_id, sp.supplier_id, COALESCE(s.name, '') AS supplier_name,
+			sp.price, sp.currency, sp.status, sp.valid_to,
+			COALESCE(sp.valid_from, sp.quoted_at) AS effective_from,
+			sp.price * `+baseRate("sp.currency", "CAST(COALESCE(sp.valid_from, sp.quoted_at) AS date)")+` AS base_price
+		FROM supplier_prices sp
+		LEFT JOIN suppliers s ON s.id = sp.supplier_id
+		WHERE sp.target_type = @type AND sp.target_id = @id AND sp.status <> @withdrawn
+			AND (sp.valid_to IS NULL OR sp.valid_to > @since)
+		ORDER BY sp.supplier_id, effective_from, sp.id`,
+		map[string]interface{}{
+			"type": targetType, "id": targetID, "withdrawn": domain.QuoteStatusWithdrawn,
+			"since": since, "base": base,
+		}).Scan(&results).Error
+	return results, err
+}
+
+// FindCurrentQuotes 查询各供应商当前有效的最新报价，本位币价格按今日汇率换算
+func (r *PriceAnalyticsRepo) FindCurrentQuotes(ctx context.Context, targetType string, targetID uint, base string) ([]domain.QuotePoint, error) {
+	var results []domain.QuotePoint
+	now := time.Now()
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT DISTINCT ON (sp.supplier_id)
+			sp.id AS quote_id, sp.supplier_id, COALESCE(s.name, '') AS supplier_name,
+			sp.price, sp.currency, sp.status, sp.valid_to,
+			COALESCE(sp.valid_from, sp.quoted_at) AS effective_from,
+			sp.price * `+baseRate("sp.currency", "CAST(@today AS date)")+` AS base_price
+		FROM supplier_prices sp
+		LEFT JOIN suppliers s ON s.id = sp.supplier_id
+		WHERE sp.target_type = @type AND sp.target_id = @id AND sp.status <> @withdrawn
+			AND COALESCE(sp.valid_from, sp.quoted_at) <= @now AND (sp.valid_to IS NULL OR sp.valid_to > @now)
+		ORDER BY sp.supplier_id, sp.quoted_at DESC, sp.id DESC`,
+		map[string]interface{}{
+			"type": targetType, "id": targetID, "withdrawn": domain.QuoteStatusWithdrawn,
+			"now": now, "today": now.Format(currencyDomain.DateLayout), "base": base,
+		}).Scan(&results).Error
+	return results, err
+}
+
+// effectiveOnDays 逐日展开当日有效过的报价（days 为日期序列，报价在当天任一时刻有效即计入），本位币价格按当日汇率换算
+const effectiveOnDays = `
+	SELECT days.day, sp.id, sp.target_id, sp.supplier_id,
+		sp.price * %s AS base_price
+	FROM days
+	JOIN supplier_prices sp ON sp.target_type = @type AND sp.status <> @withdrawn %s
+		AND COALESCE(sp.valid_from, sp.quoted_at) < days.day + INTERVAL '1 day'
+		AND (sp.valid_to IS NULL OR sp.valid_to > days.day)`
+
+// FindDailyBest 查询 [from, to] 每天有效报价中的最低价及其供应商（没有可换算报价的日期不返回），按日期升序
+func (r *PriceAnalyticsRepo) FindDailyBest(ctx context.Context, targetType string, targetID uint, from, to time.Time, base string) ([]domain.DailyBest, error) {
+	var results []domain.DailyBest
+	err := r.db.WithContext(ctx).Raw(`
+		WITH days AS (
+			SELECT CAST(d AS date) AS day FROM generate_series(CAST(@from AS date), CAST(@to AS date), INTERVAL '1 day') d
+		), quotes AS (`+fmt.Sprintf(effectiveOnDays, baseRate("sp.currency", "days.day"), "AND sp.target_id = @id")+`
+		)
+		SELECT DISTINCT ON (q.day) q.day, q.supplier_id, COALESCE(s.name, '') AS supplier_name, q.base_price
+		FROM quotes q
+		LEFT JOIN suppliers s ON s.id = q.supplier_id
+		WHERE q.base_price IS NOT NULL
+		ORDER BY q.day, q.base_price, q.id`,
+		map[string]interface{}{
+			"type": targetType, "id": targetID, "withdrawn": domain.QuoteStatusWithdrawn,
+			"from": from.Format(currencyDomain.DateLayout), "to": to.Format(currencyDomain.DateLayout), "base": base,
+		}).Scan(&results).Error
+	return results, err
+}
+
+// FindCostIndex 查询目标类型的采购成本指数：各目标取当期最低价与基期（from）最低价之比，
+// 对基期与当期都有报价的目标取平均后乘以 100；step 为取样间隔（如 1 day、7 days、1 month）
+func (r *PriceAnalyticsRepo) FindCostIndex(ctx context.Context, targetType string, from, to time.Time, step, base string) ([]domain.IndexPoint, error) {
+	var results []domain.IndexPoint
+	err := r.db.WithContext(ctx).Raw(`
+		WITH days AS (
+			SELECT CAST(d AS date) AS day FROM generate_series(CAST(@from AS date), CAST(@to AS date), CAST(@step AS interval)) d
+		), quotes AS (`+fmt.Sprintf(effectiveOnDays, baseRate("sp.currency", "days.day"), "")+`
+		), best AS (
+			SELECT day, target_id, MIN(base_price) AS price
+			FROM quotes
+			WHERE base_price IS NOT NULL
+			GROUP BY day, target_id
+		), basis AS (
+			SELECT target_id, price FROM best WHERE day = CAST(@from AS date) AND price > 0
+		)
+		SELECT b.day, 100 * AVG(b.price / basis.price) AS index, COUNT(*) AS targets
+		FROM best b
+		JOIN basis ON basis.target_id = b.target_id
+		GROUP BY b.day
+		ORDER BY b.day`,
+		map[string]interface{}{
+			"type": targetType, "withdrawn": domain.QuoteStatusWithdrawn, "step": step,
+			"from": from.Format(currencyDomain.DateLayout), "to": to.Format(currencyDomain.DateLayout), "base": base,
+		}).Scan(&results).Error
+	return results, err
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 
@@ -13,6 +15,43 @@ import (
 
 var ErrCacheMiss = errors.New("cache miss")
 
+// 缓存统计键
+const (
+	statsHitsKey        = "price:stats:hits"
+	statsMissesKey      = "price:stats:misses"
+	statsRebuildsKey    = "price:stats:rebuilds"
+	statsLastRebuildKey = "price:stats:last_rebuild"
+	statsLastTargetsKey = "price:stats:last_rebuild_targets"
+)
+
+// setExtremesScript 版本号一致时写入最低价与最高价
+// KEYS: ver, min, max  ARGV: version, min json, max json, ttl(ms)
+var setExtremesScript = redis.NewScript(`
+local current = redis.call('GET', KEYS[1]) or '0'
+if current ~= ARGV[1] then
+	return 0
+end
+local ttl = tonumber(ARGV[4])
+for i = 2, 3 do
+	if ARGV[i] ~= '' then
+		if ttl > 0 then
+			redis.call('SET', KEYS[i], ARGV[i], 'PX', ttl)
+		else
+			redis.call('SET', KEYS[i], ARGV[i])
+		end
+	end
+end
+return 1
+`)
+
+// invalidateScript 递增版本号并删除缓存
+// KEYS: ver, min, max
+var invalidateScript = redis.NewScript(`
+redis.call('INCR', KEYS[1])
+redis.call('DEL', KEYS[2], KEYS[3])
+return 1
+`)
+
 // PriceCacheImpl Redis 缓存实现
 type PriceCacheImpl struct {
 	rdb *redis.Client
@@ -23,104 +62,124 @@ func NewPriceCacheImpl(rdb *redis.Client) domain.PriceCache {
 	return &PriceCacheImpl{rdb: rdb}
 }
 
+// keys 目标的版本号、最低价、最高价键
+func keys(targetType string, targetID uint) []string {
+	prefix := fmt.Sprintf("price:%s:%d", targetType, targetID)
+	return []string{prefix + ":ver", prefix + ":min", prefix + ":max"}
+}
+
 // GetMin 获取最低价
 func (c *PriceCacheImpl) GetMin(ctx context.Context, targetType string, targetID uint) (*domain.PriceData, error) {
-	key := fmt.Sprintf("price:%s:%d:min", targetType, targetID)
-	
+	return c.get(ctx, keys(targetType, targetID)[1])
+}
+
+// GetMax 获取最高价
+func (c *PriceCacheImpl) GetMax(ctx context.Context, targetType string, targetID uint) (*domain.PriceData, error) {
+	return c.get(ctx, keys(targetType, targetID)[2])
+}
+
+// get 读取缓存并记录命中 / 未命中
+func (c *PriceCacheImpl) get(ctx context.Context, key string) (*domain.PriceData, error) {
 	val, err := c.rdb.Get(ctx, key).Result()
 	if errors.Is(err, redis.Nil) {
+		c.rdb.Incr(ctx, statsMissesKey)
 		return nil, ErrCacheMiss
 	}
 	if err != nil {
 		return nil, err
 	}
-	
+
 	var data domain.PriceData
 	if err := json.Unmarshal([]byte(val), &data); err != nil {
 		return nil, err
 	}
-	
+
+	c.rdb.Incr(ctx, statsHitsKey)
 	return &data, nil
 }
 
-// SetMin 设置最低价
-func (c *PriceCacheImpl) SetMin(ctx context.Context, targetType string, targetID uint, data *domain.PriceData) error {
-	key := fmt.Sprintf("price:%s:%d:min", targetType, targetID)
-	
-	jsonData, err := json.Marshal(data)
-	if err != nil {
-		return err
+// Version 获取目标当前的缓存版本号（未设置时为 0）
+func (c *PriceCacheImpl) Version(ctx context.Context, targetType string, targetID uint) (int64, error) {
+	version, err := c.rdb.Get(ctx, keys(targetType, targetID)[0]).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
 	}
-	
-	return c.rdb.Set(ctx, key, jsonData, 0).Err()
+	return version, err
 }
 
-// UpdateMin 更新最低价
-func (c *PriceCacheImpl) UpdateMin(ctx context.Context, targetType string, targetID uint, newPrice *domain.PriceData) error {
-	current, err := c.GetMin(ctx, targetType, targetID)
-	if errors.Is(err, ErrCacheMiss) {
-		// 没有缓存，直接写入
-		return c.SetMin(ctx, targetType, targetID, newPrice)
+// SetExtremes 版本号未变化时原子写入最低价与最高价
+func (c *PriceCacheImpl) SetExtremes(ctx context.Context, targetType string, targetID uint, version int64, lowest, highest *domain.PriceData, ttl time.Duration) (bool, error) {
+	minJSON, err := marshal(lowest)
+	if err != nil {
+		return false, err
 	}
+	maxJSON, err := marshal(highest)
 	if err != nil {
-		return err
+		return false, err
 	}
-	
-	// 比对
-	if newPrice.Price < current.Price {
-		return c.SetMin(ctx, targetType, targetID, newPrice)
+
+	written, err := setExtremesScript.Run(ctx, c.rdb, keys(targetType, targetID),
+		strconv.FormatInt(version, 10), minJSON, maxJSON, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, err
 	}
-	
-	return nil
+	return written == 1, nil
 }
 
-// GetMax 获取最高价
-func (c *PriceCacheImpl) GetMax(ctx context.Context, targetType string, targetID uint) (*domain.PriceData, error) {
-	key := fmt.Sprintf("price:%s:%d:max", targetType, targetID)
-	
-	val, err := c.rdb.Get(ctx, key).Result()
-	if errors.Is(err, redis.Nil) {
-		return nil, ErrCacheMiss
+// marshal 序列化价格数据（nil 为空串，表示不写入）
+func marshal(data *domain.PriceData) (string, error) {
+	if data == nil {
+		return "", nil
 	}
+	jsonData, err := json.Marshal(data)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
-	
-	var data domain.PriceData
-	if err := json.Unmarshal([]byte(val), &data); err != nil {
-		return nil, err
-	}
-	
-	return &data, nil
+	return string(jsonData), nil
 }
 
-// SetMax 设置最高价
-func (c *PriceCacheImpl) SetMax(ctx context.Context, targetType string, targetID uint, data *domain.PriceData) error {
-	key := fmt.Sprintf("price:%s:%d:max", targetType, targetID)
-	
-	jsonData, err := json.Marshal(data)
+// Invalidate 递增版本号并删除目标的最低价与最高价缓存（报价变更后由下次读取重新计算）
+func (c *PriceCacheImpl) Invalidate(ctx context.Context, targetType string, targetID uint) error {
+	return invalidateScript.Run(ctx, c.rdb, keys(targetType, targetID)).Err()
+}
+
+// Stats 获取缓存统计
+func (c *PriceCacheImpl) Stats(ctx context.Context) (*domain.CacheStats, error) {
+	values, err := c.rdb.MGet(ctx, statsHitsKey, statsMissesKey, statsRebuildsKey, statsLastRebuildKey, statsLastTargetsKey).Result()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	
-	return c.rdb.Set(ctx, key, jsonData, 0).Err()
-}
 
-// UpdateMax 更新最高价
-func (c *PriceCacheImpl) UpdateMax(ctx context.Context, targetType string, targetID uint, newPrice *domain.PriceData) error {
-	current, err := c.GetMax(ctx, targetType, targetID)
-	if errors.Is(err, ErrCacheMiss) {
-		// 没有缓存，直接写入
-		return c.SetMax(ctx, targetType, targetID, newPrice)
+	counter := func(v interface{}) int64 {
+		s, _ := v.(string)
+		n, _ := strconv.ParseInt(s, 10, 64)
+		return n
 	}
-	if err != nil {
-		return err
+
+	stats := &domain.CacheStats{
+		Hits:           counter(values[0]),
+		Misses:         counter(values[1]),
+		Rebuilds:       counter(values[2]),
+		LastRebuildNum: counter(values[4]),
 	}
-	
-	// 比对
-	if newPrice.Price > current.Price {
-		return c.SetMax(ctx, targetType, targetID, newPrice)
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(total) * 100
 	}
-	
-	return nil
-}
\ No newline at end of file
+	if ts := counter(values[3]); ts > 0 {
+		t := time.Unix(ts, 0)
+		stats.LastRebuildAt = &t
+	}
+
+	return stats, nil
+}
+
+// RecordRebuild 记录一次全量重建
+func (c *PriceCacheImpl) RecordRebuild(ctx context.Context, targets int) error {
+	_, err := c.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Incr(ctx, statsRebuildsKey)
+		pipe.Set(ctx, statsLastRebuildKey, time.Now().Unix(), 0)
+		pipe.Set(ctx, statsLastTargetsKey, targets, 0)
+		return nil
+	})
+	return err
+}
@@ -0,0 +1,114 @@
+package infra
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"back/internal/pricing/domain"
+)
+
+// PriceWatchRepo 报价关注仓储
+type PriceWatchRepo struct {
+	db *gorm.DB
+}
+
+// NewPriceWatchRepo 创建报价关注仓储
+func NewPriceWatchRepo(db *gorm.DB) *PriceWatchRepo {
+	return &PriceWatchRepo{db: db}
+}
+
+// Save 保存关注规则
+func (r *PriceWatchRepo) Save(ctx context.Context, watch *domain.PriceWatch) error {
+	return r.db.WithContext(ctx).Save(watch).Error
+}
+
+// FindByID 查询用户的关注规则（不属于该用户时视为不存在）
+func (r *PriceWatchRepo) FindByID(ctx context.Context, id uint, loginID string) (*domain.PriceWatch, error) {
+	var watch domain.PriceWatch
+	err := r.db.WithContext(ctx).Where("login_id = ?", loginID).First(&watch, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrWatchNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &watch, nil
+}
+
+// Exists 用户是否已关注该目标
+func (r *PriceWatchRepo) Exists(ctx context.Context, loginID, targetType string, targetID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.PriceWatch{}).
+		Where("login_id = ? AND target_type = ? AND target_id = ?", loginID, targetType, targetID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Delete 删除用户的关注规则
+func (r *PriceWatchRepo) Delete(ctx context.Context, id uint, loginID string) error {
+	result := r.db.WithContext(ctx).Where("login_id = ?", loginID).Delete(&domain.PriceWatch{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrWatchNotFound
+	}
+	return nil
+}
+
+// FindByUser 查询用户的全部关注规则
+func (r *PriceWatchRepo) FindByUser(ctx context.Context, loginID string) ([]domain.PriceWatch, error) {
+	var watches []domain.PriceWatch
+	err := r.db.WithContext(ctx).Where("login_id = ?", loginID).Order("target_type, target_id").Find(&watches).Error
+	return watches, err
+}
+
+// FindByTarget 查询关注该目标的全部规则
+func (r *PriceWatchRepo) FindByTarget(ctx context.Context, targetType string, targetID uint) ([]domain.PriceWatch, error) {
+	var watches []domain.PriceWatch
+	err := r.db.WithContext(ctx).Where("target_type = ? AND target_id = ?", targetType, targetID).Find(&watches).Error
+	return watches, err
+}
+
+// FindExpiryWatches 查询开启到期提醒的全部规则
+func (r *PriceWatchRepo) FindExpiryWatches(ctx context.Context) ([]domain.PriceWatch, error) {
+	var watches []domain.PriceWatch
+	err := r.db.WithContext(ctx).Where("notify_expiring = ?", true).Order("target_type, target_id").Find(&watches).Error
+	return watches, err
+}
+
+// CreateAlert 记录提醒
+func (r *PriceWatchRepo) CreateAlert(ctx context.Context, alert *domain.PriceAlert) error {
+	return r.db.WithContext(ctx).Create(alert).Error
+}
+
+// UpdateWebhookResult 记录提醒的 Webhook 投递结果
+func (r *PriceWatchRepo) UpdateWebhookResult(ctx context.Context, alertID uint, status, message string) error {
+	return r.db.WithContext(ctx).Model(&domain.PriceAlert{}).Where("id = ?", alertID).
+		Updates(map[string]interface{}{"webhook_status": status, "webhook_error": message}).Error
+}
+
+// AlertExists 关注规则是否已就该报价发出过指定事件的提醒
+func (r *PriceWatchRepo) AlertExists(ctx context.Context, watchID uint, event string, quoteID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.PriceAlert{}).
+		Where("watch_id = ? AND event = ? AND quote_id = ?", watchID, event, quoteID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// FindAlerts 查询用户收到的提醒，按时间倒序
+func (r *PriceWatchRepo) FindAlerts(ctx context.Context, loginID string, limit, offset int) ([]domain.PriceAlert, int64, error) {
+	var alerts []domain.PriceAlert
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&domain.PriceAlert{}).Where("login_id = ?", loginID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&alerts).Error
+	return alerts, total, err
+}
@@ -0,0 +1,120 @@
+package infra
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"back/internal/pricing/domain"
+)
+
+// RFQRepo 询价单仓储
+type RFQRepo struct {
+	db *gorm.DB
+}
+
+// NewRFQRepo 创建询价单仓储
+func NewRFQRepo(db *gorm.DB) *RFQRepo {
+	return &RFQRepo{db: db}
+}
+
+// Transaction 在事务中执行
+func (r *RFQRepo) Transaction(ctx context.Context, fn func(txRepo *RFQRepo) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(NewRFQRepo(tx))
+	})
+}
+
+// Create 创建询价单（含明细与受邀供应商）
+func (r *RFQRepo) Create(ctx context.Context, rfq *domain.RFQ) error {
+	return r.db.WithContext(ctx).Create(rfq).Error
+}
+
+// FindByID 根据 ID 查询询价单（含明细与受邀供应商）
+func (r *RFQRepo) FindByID(ctx context.Context, id uint) (*domain.RFQ, error) {
+	var rfq domain.RFQ
+	err := r.db.WithContext(ctx).
+		Preload("Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id ASC")
+		}).
+		Preload("Invitations", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id ASC")
+		}).
+		First(&rfq, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrRFQNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rfq, nil
+}
+
+// FindList 查询询价单列表（不含明细）
+func (r *RFQRepo) FindList(ctx context.Context, status string, limit, offset int) ([]domain.RFQ, int64, error) {
+	var rfqs []domain.RFQ
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&domain.RFQ{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&rfqs).Error
+	return rfqs, total, err
+}
+
+// Update 更新询价单主表
+func (r *RFQRepo) Update(ctx context.Context, rfq *domain.RFQ) error {
+	return r.db.WithContext(ctx).Omit("Items", "Invitations").Save(rfq).Error
+}
+
+// UpdateItem 更新询价明细
+func (r *RFQRepo) UpdateItem(ctx context.Context, item *domain.RFQItem) error {
+	return r.db.WithContext(ctx).Save(item).Error
+}
+
+// UpdateInvitation 更新受邀供应商
+func (r *RFQRepo) UpdateInvitation(ctx context.Context, invitation *domain.RFQInvitation) error {
+	return r.db.WithContext(ctx).Save(invitation).Error
+}
+
+// FindResponses 查询询价单的全部回复报价（不含撤回的报价），按报价时间倒序
+func (r *RFQRepo) FindResponses(ctx context.Context, rfqID uint) ([]*domain.SupplierPrice, error) {
+	var results []*domain.SupplierPrice
+	err := withTiers(r.db.WithContext(ctx)).
+		Where("rfq_id = ? AND status <> ?", rfqID, domain.QuoteStatusWithdrawn).
+		Order("quoted_at DESC, id DESC").
+		Find(&results).Error
+	return results, err
+}
+
+// SavePreferred 保存首选供应商（同一目标覆盖）
+func (r *RFQRepo) SavePreferred(ctx context.Context, preferred *domain.PreferredSupplier) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "target_type"}, {Name: "target_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"supplier_id", "quote_id", "rfq_id", "price", "currency", "awarded_by", "awarded_at"}),
+		}).
+		Create(preferred).Error
+}
+
+// FindPreferred 查询目标的首选供应商
+func (r *RFQRepo) FindPreferred(ctx context.Context, targetType string, targetID uint) (*domain.PreferredSupplier, error) {
+	var preferred domain.PreferredSupplier
+	err := r.db.WithContext(ctx).
+		Where("target_type = ? AND target_id = ?", targetType, targetID).
+		First(&preferred).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrPreferredNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &preferred, nil
+}
@@ -2,6 +2,8 @@ package infra
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"gorm.io/gorm"
 
@@ -23,51 +25,152 @@ func NewSupplierPriceRepo(db *gorm.DB) *SupplierPriceRepo {
 	}
 }
 
+// Transaction 在事务中执行
+func (r *SupplierPriceRepo) Transaction(ctx context.Context, fn func(txRepo *SupplierPriceRepo) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(NewSupplierPriceRepo(tx))
+	})
+}
+
 // Save 保存价格
 func (r *SupplierPriceRepo) Save(ctx context.Context, price *domain.SupplierPrice) error {
 	return r.Create(ctx, price)
 }
 
-// FindMinPrice 查找最低价
-func (r *SupplierPriceRepo) FindMinPrice(ctx context.Context, targetType string, targetID uint) (*domain.SupplierPrice, error) {
-	var result domain.SupplierPrice
-	err := r.db.WithContext(ctx).
-		Where("target_type = ? AND target_id = ?", targetType, targetID).
-		Order("price ASC").
-		First(&result).Error
+// UpdateInvitation 更新询价邀请状态（询价回复时与报价同一事务保存）
+func (r *SupplierPriceRepo) UpdateInvitation(ctx context.Context, invitation *domain.RFQInvitation) error {
+	return r.db.WithContext(ctx).Save(invitation).Error
+}
 
+// withTiers 附带按起点升序的阶梯价
+func withTiers(db *gorm.DB) *gorm.DB {
+	return db.Preload("Tiers", func(db *gorm.DB) *gorm.DB {
+		return db.Order("min_quantity ASC")
+	})
+}
+
+// FindQuoteByID 根据 ID 查找报价
+func (r *SupplierPriceRepo) FindQuoteByID(ctx context.Context, id uint) (*domain.SupplierPrice, error) {
+	var result domain.SupplierPrice
+	err := withTiers(r.db.WithContext(ctx)).First(&result, id).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, domain.ErrPriceNotFound
+			return nil, domain.ErrQuoteNotFound
 		}
 		return nil, err
 	}
-
 	return &result, nil
 }
 
-// FindMaxPrice 查找最高价
-func (r *SupplierPriceRepo) FindMaxPrice(ctx context.Context, targetType string, targetID uint) (*domain.SupplierPrice, error) {
-	var result domain.SupplierPrice
+// UpdateQuote 更新报价（状态、有效期，阶梯价不变）
+func (r *SupplierPriceRepo) UpdateQuote(ctx context.Context, price *domain.SupplierPrice) error {
+	return r.db.WithContext(ctx).Omit("Tiers").Save(price).Error
+}
+
+// FindActiveBySupplier 查找某供应商对目标仍为有效状态的报价（不含 excludeID），用于新报价取代旧报价
+func (r *SupplierPriceRepo) FindActiveBySupplier(ctx context.Context, targetType string, targetID, supplierID, excludeID uint) ([]*domain.SupplierPrice, error) {
+	var results []*domain.SupplierPrice
 	err := r.db.WithContext(ctx).
+		Where("target_type = ? AND target_id = ? AND supplier_id = ? AND status = ? AND id <> ?",
+			targetType, targetID, supplierID, domain.QuoteStatusActive, excludeID).
+		Find(&results).Error
+	return results, err
+}
+
+// effective 当前有效报价条件：未撤回且处于有效期内
+func (r *SupplierPriceRepo) effective(ctx context.Context, targetType string, targetID uint) *gorm.DB {
+	now := time.Now()
+	return withTiers(r.db.WithContext(ctx)).
 		Where("target_type = ? AND target_id = ?", targetType, targetID).
-		Order("price DESC").
-		First(&result).Error
+		Where("status <> ? AND COALESCE(valid_from, quoted_at) <= ? AND (valid_to IS NULL OR valid_to > ?)",
+			domain.QuoteStatusWithdrawn, now, now)
+}
 
-	if err != nil {
+// first 取查询的第一条，无记录时返回 ErrPriceNotFound
+func first(query *gorm.DB) (*domain.SupplierPrice, error) {
+	var result domain.SupplierPrice
+	if err := query.First(&result).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, domain.ErrPriceNotFound
 		}
 		return nil, err
 	}
-
 	return &result, nil
 }
 
-// FindHistory 查找报价历史
-func (r *SupplierPriceRepo) FindHistory(ctx context.Context, targetType string, targetID uint, limit int) ([]*domain.SupplierPrice, error) {
+// FindEffective 查找当前全部有效报价（不同币种的报价由调用方换算后比较）
+func (r *SupplierPriceRepo) FindEffective(ctx context.Context, targetType string, targetID uint) ([]*domain.SupplierPrice, error) {
+	var results []*domain.SupplierPrice
+	err := r.effective(ctx, targetType, targetID).Order("quoted_at DESC").Find(&results).Error
+	return results, err
+}
+
+// FindLatestEffective 查找当前有效报价中最新的一条
+func (r *SupplierPriceRepo) FindLatestEffective(ctx context.Context, targetType string, targetID uint) (*domain.SupplierPrice, error) {
+	return first(r.effective(ctx, targetType, targetID).Order("quoted_at DESC, id DESC"))
+}
+
+// FindLatestBySupplier 查找某供应商对目标当前有效的最新报价
+func (r *SupplierPriceRepo) FindLatestBySupplier(ctx context.Context, targetType string, targetID, supplierID uint) (*domain.SupplierPrice, error) {
+	return first(r.effective(ctx, targetType, targetID).Where("supplier_id = ?", supplierID).Order("quoted_at DESC, id DESC"))
+}
+
+// FindExpiring 查找当前有效、将在 until 前到期的报价（不含已被取代的报价）
+func (r *SupplierPriceRepo) FindExpiring(ctx context.Context, targetType string, targetID uint, until time.Time) ([]*domain.SupplierPrice, error) {
 	var results []*domain.SupplierPrice
+	err := r.effective(ctx, targetType, targetID).
+		Where("status = ? AND valid_to <= ?", domain.QuoteStatusActive, until).
+		Order("valid_to ASC").
+		Find(&results).Error
+	return results, err
+}
+
+// NextValidityChange 目标下一次报价生效或失效的时间（没有待生效 / 待失效的报价时返回 nil），用于设置缓存过期时间
+func (r *SupplierPriceRepo) NextValidityChange(ctx context.Context, targetType string, targetID uint) (*time.Time, error) {
+	var next sql.NullTime
+	now := time.Now()
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT MIN(t) FROM (
+			SELECT valid_from AS t FROM supplier_prices
+			WHERE target_type = ? AND target_id = ? AND status <> ? AND valid_from > ?
+			UNION ALL
+			SELECT valid_to AS t FROM supplier_prices
+			WHERE target_type = ? AND target_id = ? AND status <> ? AND valid_to > ?
+		) changes`,
+		targetType, targetID, domain.QuoteStatusWithdrawn, now,
+		targetType, targetID, domain.QuoteStatusWithdrawn, now).
+		Scan(&next).Error
+	if err != nil || !next.Valid {
+		return nil, err
+	}
+	return &next.Time, nil
+}
+
+// FindTargets 查找有报价的全部目标
+func (r *SupplierPriceRepo) FindTargets(ctx context.Context) ([]domain.TargetRef, error) {
+	var results []domain.TargetRef
 	err := r.db.WithContext(ctx).
+		Model(&domain.SupplierPrice{}).
+		Distinct("target_type", "target_id").
+		Order("target_type, target_id").
+		Find(&results).Error
+	return results, err
+}
+
+// FindHistorical 查找全部历史报价（不论是否仍有效，不含撤回的报价）
+func (r *SupplierPriceRepo) FindHistorical(ctx context.Context, targetType string, targetID uint) ([]*domain.SupplierPrice, error) {
+	var results []*domain.SupplierPrice
+	err := withTiers(r.db.WithContext(ctx)).
+		Where("target_type = ? AND target_id = ? AND status <> ?", targetType, targetID, domain.QuoteStatusWithdrawn).
+		Order("quoted_at DESC").
+		Find(&results).Error
+	return results, err
+}
+
+// FindHistory 查找报价历史（含已取代与撤回的报价）
+func (r *SupplierPriceRepo) FindHistory(ctx context.Context, targetType string, targetID uint, limit int) ([]*domain.SupplierPrice, error) {
+	var results []*domain.SupplierPrice
+	err := withTiers(r.db.WithContext(ctx)).
 		Where("target_type = ? AND target_id = ?", targetType, targetID).
 		Order("quoted_at DESC").
 		Limit(limit).
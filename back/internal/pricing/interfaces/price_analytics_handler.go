@@ -0,0 +1,101 @@
+package interfaces
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"back/internal/pricing/application"
+	"back/internal/pricing/domain"
+	"back/pkg/endpoint"
+)
+
+// PriceAnalyticsHandler 比价与价格趋势 Handler
+type PriceAnalyticsHandler struct {
+	service *application.PriceAnalyticsService
+}
+
+// NewPriceAnalyticsHandler 创建 Handler
+func NewPriceAnalyticsHandler(service *application.PriceAnalyticsService) *PriceAnalyticsHandler {
+	return &PriceAnalyticsHandler{service: service}
+}
+
+// handleError 统一错误响应
+func (h *PriceAnalyticsHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidTargetType), errors.Is(err, domain.ErrTargetTypeRequired):
+		c.JSON(http.StatusNotFound, gin.H{"error": "未注册的报价目标类型"})
+	case errors.Is(err, domain.ErrTargetNotFound), errors.Is(err, domain.ErrTargetIDRequired):
+		c.JSON(http.StatusNotFound, gin.H{"error": "报价目标不存在"})
+	case errors.Is(err, domain.ErrInvalidInterval):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "取样间隔只能为 day、week 或 month"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// Analyze godoc
+// @Summary      比价与价格趋势
+// @Description  查询目标各供应商的报价时间序列、当前价差、最低价 30 / 90 / 365 天涨跌幅与各供应商最低价天数占比（均按本位币比较）
+// @Tags         报价管理
+// @Accept       json
+// @Produce      json
+// @Param        type path string true "目标类型（如 material、process）"
+// @Param        id path int true "目标ID"
+// @Param        days query int false "分析区间天数（时间序列与最低价天数占比）" default(365)
+// @Success      200 {object} application.PriceAnalyticsResponse "比价分析"
+// @Failure      404 {object} map[string]string "目标不存在"
+// @Security     Bearer
+// @Router       /pricing/targets/{type}/{id}/analytics [get]
+func (h *PriceAnalyticsHandler) Analyze(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的ID"})
+		return
+	}
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "365"))
+
+	resp, err := h.service.Analyze(c.Request.Context(), c.Param("type"), uint(id), days)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// CostIndex godoc
+// @Summary      采购成本指数
+// @Description  以区间起点为基期 100，按各目标最低价相对基期的平均涨跌计算该类型的整体采购成本趋势（基期没有报价的目标不参与）
+// @Tags         报价管理
+// @Accept       json
+// @Produce      json
+// @Param        target_type query string false "目标类型" default(material)
+// @Param        days query int false "区间天数" default(365)
+// @Param        interval query string false "取样间隔（day/week/month）" default(week)
+// @Success      200 {object} application.CostIndexResponse "成本指数"
+// @Failure      400 {object} map[string]string "请求参数错误"
+// @Security     Bearer
+// @Router       /pricing/cost-index [get]
+func (h *PriceAnalyticsHandler) CostIndex(c *gin.Context) {
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "365"))
+
+	resp, err := h.service.CostIndex(c.Request.Context(),
+		c.DefaultQuery("target_type", domain.TargetTypeMaterial), days, c.DefaultQuery("interval", "week"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetRoutes 获取路由定义
+func (h *PriceAnalyticsHandler) GetRoutes() []endpoint.RouteDefinition {
+	return []endpoint.RouteDefinition{
+		{Method: "GET", Path: "/pricing/targets/:type/:id/analytics", Handler: h.Analyze, Domain: "", Action: ""},
+		{Method: "GET", Path: "/pricing/cost-index", Handler: h.CostIndex, Domain: "", Action: ""},
+	}
+}
@@ -0,0 +1,68 @@
+package interfaces
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"back/internal/pricing/application"
+	"back/pkg/endpoint"
+)
+
+// PriceCacheHandler 价格缓存管理 Handler
+type PriceCacheHandler struct {
+	service *application.PriceCacheService
+}
+
+// NewPriceCacheHandler 创建 Handler
+func NewPriceCacheHandler(service *application.PriceCacheService) *PriceCacheHandler {
+	return &PriceCacheHandler{service: service}
+}
+
+// Rebuild godoc
+// @Summary      重建价格缓存
+// @Description  按 supplier_prices 重新计算全部材料与工艺的当前最低价 / 最高价并写入缓存
+// @Tags         价格缓存管理
+// @Accept       json
+// @Produce      json
+// @Success      200 {object} application.CacheRebuildResponse "重建结果"
+// @Failure      500 {object} map[string]string "服务器错误"
+// @Security     Bearer
+// @Router       /pricing/cache/rebuild [post]
+func (h *PriceCacheHandler) Rebuild(c *gin.Context) {
+	resp, err := h.service.Rebuild(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Stats godoc
+// @Summary      价格缓存统计
+// @Description  查询价格缓存的命中、未命中与重建次数
+// @Tags         价格缓存管理
+// @Accept       json
+// @Produce      json
+// @Success      200 {object} domain.CacheStats "缓存统计"
+// @Failure      500 {object} map[string]string "服务器错误"
+// @Security     Bearer
+// @Router       /pricing/cache/stats [get]
+func (h *PriceCacheHandler) Stats(c *gin.Context) {
+	stats, err := h.service.Stats(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetRoutes 获取路由定义
+func (h *PriceCacheHandler) GetRoutes() []endpoint.RouteDefinition {
+	return []endpoint.RouteDefinition{
+		{Method: "POST", Path: "/pricing/cache/rebuild", Handler: h.Rebuild, Domain: "pricing", Action: "cache"},
+		{Method: "GET", Path: "/pricing/cache/stats", Handler: h.Stats, Domain: "pricing", Action: "cache"},
+	}
+}
@@ -0,0 +1,390 @@
+package interfaces
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	currencyDomain "back/internal/currency/domain"
+	"back/internal/pricing/application"
+	"back/internal/pricing/domain"
+	supplierDomain "back/internal/supplier/domain"
+	"back/pkg/audit"
+	"back/pkg/endpoint"
+)
+
+// PriceHandler 报价 Handler（所有已注册的目标类型共用）
+//
+// 通用路由为 /pricing/targets/:type/...；绑定目标类型后（见 NewMaterialPriceHandler）
+// 提供 /pricing/<type>/... 路由，作为旧接口的别名。
+type PriceHandler struct {
+	service    *application.PriceService
+	targetType string // 绑定的目标类型（为空时取路径参数 :type）
+	action     string // 绑定目标类型时报价 / 撤回的权限动作
+}
+
+// NewPriceHandler 创建通用报价 Handler
+func NewPriceHandler(service *application.PriceService) *PriceHandler {
+	return &PriceHandler{service: service}
+}
+
+// newAliasHandler 创建绑定目标类型的报价 Handler
+func newAliasHandler(service *application.PriceService, targetType, action string) *PriceHandler {
+	return &PriceHandler{service: service, targetType: targetType, action: action}
+}
+
+// target 当前请求的目标类型
+func (h *PriceHandler) target(c *gin.Context) string {
+	if h.targetType != "" {
+		return h.targetType
+	}
+	return c.Param("type")
+}
+
+// label 目标类型显示名称（未注册时返回 false 并响应 404）
+func (h *PriceHandler) label(c *gin.Context) (string, string, bool) {
+	targetType := h.target(c)
+	label, err := h.service.TargetLabel(targetType)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "未注册的报价目标类型"})
+		return "", "", false
+	}
+	return targetType, label, true
+}
+
+// ListTargetTypes godoc
+// @Summary      报价目标类型
+// @Description  查询已注册的报价目标类型（材料、工艺及其他领域注册的类型）
+// @Tags         报价管理
+// @Accept       json
+// @Produce      json
+// @Success      200 {array} application.TargetTypeResponse "目标类型"
+// @Security     Bearer
+// @Router       /pricing/targets [get]
+func (h *PriceHandler) ListTargetTypes(c *gin.Context) {
+	c.JSON(http.StatusOK, h.service.TargetTypes())
+}
+
+// Quote godoc
+// @Summary      厂商报价
+// @Description  厂商为指定目标提交报价
+// @Tags         报价管理
+// @Accept       json
+// @Produce      json
+// @Param        type path string true "目标类型（如 material、process）"
+// @Param        request body application.QuoteRequest true "报价信息"
+// @Success      200 {object} domain.PriceData "报价成功"
+// @Failure      400 {object} map[string]string "请求参数错误"
+// @Failure      404 {object} map[string]string "目标不存在"
+// @Failure      500 {object} map[string]string "服务器错误"
+// @Security     Bearer
+// @Router       /pricing/targets/{type} [post]
+// @Router       /pricing/material [post]
+// @Router       /pricing/process [post]
+func (h *PriceHandler) Quote(c *gin.Context) {
+	targetType, label, ok := h.label(c)
+	if !ok {
+		return
+	}
+	var req application.QuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	price, err := h.service.Quote(c.Request.Context(), targetType, &req)
+	if err != nil {
+		if errors.Is(err, domain.ErrTargetNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": label + "不存在"})
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidValidity) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "失效日期必须晚于生效日期"})
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidTier) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "阶梯价的起始数量和单价必须大于0且起始数量不能重复"})
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidMinOrderQty) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "起订量不能为负数"})
+			return
+		}
+		if errors.Is(err, currencyDomain.ErrInvalidCurrency) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的币种代码"})
+			return
+		}
+		if errors.Is(err, currencyDomain.ErrRateNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "缺少该币种到本位币的汇率，请先维护汇率"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, price)
+}
+
+// Withdraw godoc
+// @Summary      撤回报价
+// @Description  撤回一条报价，撤回后不再参与最低价 / 最高价计算
+// @Tags         报价管理
+// @Accept       json
+// @Produce      json
+// @Param        type path string true "目标类型（如 material、process）"
+// @Param        quoteId path int true "报价ID"
+// @Success      200 {object} map[string]string "撤回成功"
+// @Failure      400 {object} map[string]string "报价已撤回"
+// @Failure      404 {object} map[string]string "报价不存在"
+// @Security     Bearer
+// @Router       /pricing/targets/{type}/quote/{quoteId}/withdraw [post]
+// @Router       /pricing/material/quote/{quoteId}/withdraw [post]
+// @Router       /pricing/process/quote/{quoteId}/withdraw [post]
+func (h *PriceHandler) Withdraw(c *gin.Context) {
+	targetType, _, ok := h.label(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("quoteId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的ID"})
+		return
+	}
+
+	if err := h.service.Withdraw(c.Request.Context(), targetType, uint(id)); err != nil {
+		if errors.Is(err, domain.ErrQuoteNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "报价不存在"})
+			return
+		}
+		if errors.Is(err, domain.ErrQuoteWithdrawn) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "报价已撤回"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "撤回成功"})
+}
+
+// GetPrice godoc
+// @Summary      查询目标价格
+// @Description  查询指定目标当前有效报价中的最低价格和最高价格
+// @Tags         报价管理
+// @Accept       json
+// @Produce      json
+// @Param        type path string true "目标类型（如 material、process）"
+// @Param        id path int true "目标ID"
+// @Param        quantity query number false "需求数量（按阶梯价与起订量取价，默认按报价单价）"
+// @Success      200 {object} map[string]interface{} "价格信息"
+// @Failure      404 {object} map[string]string "暂无有效报价"
+// @Failure      500 {object} map[string]string "服务器错误"
+// @Security     Bearer
+// @Router       /pricing/targets/{type}/{id} [get]
+// @Router       /pricing/material/{id} [get]
+// @Router       /pricing/process/{id} [get]
+func (h *PriceHandler) GetPrice(c *gin.Context) {
+	targetType, _, ok := h.label(c)
+	if !ok {
+		return
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	targetID := uint(id)
+	quantity, err := strconv.ParseFloat(c.DefaultQuery("quantity", "0"), 64)
+	if err != nil || quantity < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的需求数量"})
+		return
+	}
+
+	minPrice, err := h.service.GetMinPrice(c.Request.Context(), targetType, targetID, quantity)
+	if err != nil {
+		if errors.Is(err, domain.ErrPriceNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "暂无有效报价"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	maxPrice, err := h.service.GetMaxPrice(c.Request.Context(), targetType, targetID, quantity)
+	if err != nil {
+		if errors.Is(err, domain.ErrPriceNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "暂无有效报价"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"min": minPrice,
+		"max": maxPrice,
+	})
+}
+
+// GetHistoricalExtremes godoc
+// @Summary      查询历史最高 / 最低价
+// @Description  查询指定目标全部报价（含已失效、不含撤回）中的历史最低价和最高价
+// @Tags         报价管理
+// @Accept       json
+// @Produce      json
+// @Param        type path string true "目标类型（如 material、process）"
+// @Param        id path int true "目标ID"
+// @Success      200 {object} map[string]interface{} "历史价格"
+// @Failure      404 {object} map[string]string "暂无报价"
+// @Security     Bearer
+// @Router       /pricing/targets/{type}/{id}/extremes [get]
+// @Router       /pricing/material/{id}/extremes [get]
+// @Router       /pricing/process/{id}/extremes [get]
+func (h *PriceHandler) GetHistoricalExtremes(c *gin.Context) {
+	targetType, _, ok := h.label(c)
+	if !ok {
+		return
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	targetID := uint(id)
+
+	lowest, err := h.service.GetHistoricalMinPrice(c.Request.Context(), targetType, targetID)
+	if err != nil {
+		if errors.Is(err, domain.ErrPriceNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "暂无报价"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	highest, err := h.service.GetHistoricalMaxPrice(c.Request.Context(), targetType, targetID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"historical_min": lowest,
+		"historical_max": highest,
+	})
+}
+
+// GetHistory godoc
+// @Summary      查询价格历史
+// @Description  查询指定目标的价格历史记录
+// @Tags         报价管理
+// @Accept       json
+// @Produce      json
+// @Param        type path string true "目标类型（如 material、process）"
+// @Param        id path int true "目标ID"
+// @Success      200 {array} domain.PriceData "价格历史记录"
+// @Failure      500 {object} map[string]string "服务器错误"
+// @Security     Bearer
+// @Router       /pricing/targets/{type}/{id}/history [get]
+// @Router       /pricing/material/{id}/history [get]
+// @Router       /pricing/process/{id}/history [get]
+func (h *PriceHandler) GetHistory(c *gin.Context) {
+	targetType, _, ok := h.label(c)
+	if !ok {
+		return
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	targetID := uint(id)
+
+	history, err := h.service.GetHistory(c.Request.Context(), targetType, targetID, 100)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// Import godoc
+// @Summary      导入供应商报价单
+// @Description  上传供应商价格表（CSV 或 XLSX 第一个工作表），首行为表头：编号/名称（材料按材料编号、工序按名称匹配）、单价必填，可选类型、币种、起订量、运费、阶梯价（如 100:9.5;500:9）、生效日期、失效日期。
+// @Description  有误的行逐行返回且不导入，其余行在一个事务中保存为该供应商的报价；dry_run=true 时只校验不保存
+// @Tags         报价管理
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file formData file true "CSV / XLSX 文件"
+// @Param        supplier_id formData int true "供应商ID"
+// @Param        target_type formData string false "表中没有类型列时的目标类型" default(material)
+// @Param        currency formData string false "表中没有币种列时的报价币种（默认本位币）"
+// @Param        dry_run formData bool false "只校验不保存"
+// @Success      200 {object} application.ImportQuotesResponse "导入结果"
+// @Failure      400 {object} map[string]string "文件格式或表头错误"
+// @Failure      404 {object} map[string]string "供应商不存在"
+// @Security     Bearer
+// @Router       /pricing/import [post]
+func (h *PriceHandler) Import(c *gin.Context) {
+	var req application.ImportQuotesRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请上传 CSV 或 XLSX 文件"})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	records, err := application.ReadSheet(file, header.Filename)
+	if err != nil {
+		if errors.Is(err, domain.ErrImportFormat) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "仅支持 .csv 或 .xlsx 文件"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "文件读取失败: " + err.Error()})
+		return
+	}
+
+	resp, err := h.service.Import(c.Request.Context(), records, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, supplierDomain.ErrSupplierNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "供应商不存在"})
+		case errors.Is(err, domain.ErrImportEmpty):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "价格表为空"})
+		case errors.Is(err, domain.ErrImportColumns):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "表头须包含编号 / 名称列和单价列"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	if recorder := audit.Get(c); recorder != nil {
+		recorder.SetResourceID(req.SupplierID)
+		recorder.SetNew(resp)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetRoutes 获取路由定义
+func (h *PriceHandler) GetRoutes() []endpoint.RouteDefinition {
+	if h.targetType != "" {
+		prefix := "/pricing/" + h.targetType
+		return []endpoint.RouteDefinition{
+			{Method: "POST", Path: prefix, Handler: h.Quote, Domain: "pricing", Action: h.action},
+			{Method: "GET", Path: prefix + "/:id", Handler: h.GetPrice, Domain: "", Action: ""},
+			{Method: "GET", Path: prefix + "/:id/history", Handler: h.GetHistory, Domain: "", Action: ""},
+			{Method: "GET", Path: prefix + "/:id/extremes", Handler: h.GetHistoricalExtremes, Domain: "", Action: ""},
+			{Method: "POST", Path: prefix + "/quote/:quoteId/withdraw", Handler: h.Withdraw, Domain: "pricing", Action: h.action},
+		}
+	}
+	return []endpoint.RouteDefinition{
+		{Method: "GET", Path: "/pricing/targets", Handler: h.ListTargetTypes, Domain: "", Action: ""},
+		{Method: "POST", Path: "/pricing/import", Handler: h.Import, Domain: "pricing", Action: "quoteImport"},
+		{Method: "POST", Path: "/pricing/targets/:type", Handler: h.Quote, Domain: "pricing", Action: "quoteUpsert"},
+		{Method: "GET", Path: "/pricing/targets/:type/:id", Handler: h.GetPrice, Domain: "", Action: ""},
+		{Method: "GET", Path: "/pricing/targets/:type/:id/history", Handler: h.GetHistory, Domain: "", Action: ""},
+		{Method: "GET", Path: "/pricing/targets/:type/:id/extremes", Handler: h.GetHistoricalExtremes, Domain: "", Action: ""},
+		{Method: "POST", Path: "/pricing/targets/:type/quote/:quoteId/withdraw", Handler: h.Withdraw, Domain: "pricing", Action: "quoteUpsert"},
+	}
+}
@@ -0,0 +1,217 @@
+package interfaces
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"back/internal/pricing/application"
+	"back/internal/pricing/domain"
+	"back/pkg/endpoint"
+)
+
+// PriceWatchHandler 报价关注 Handler
+type PriceWatchHandler struct {
+	service *application.PriceWatchService
+}
+
+// NewPriceWatchHandler 创建 Handler
+func NewPriceWatchHandler(service *application.PriceWatchService) *PriceWatchHandler {
+	return &PriceWatchHandler{service: service}
+}
+
+// handleError 统一错误响应
+func (h *PriceWatchHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrWatchNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "关注不存在"})
+	case errors.Is(err, domain.ErrTargetNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "报价目标不存在"})
+	case errors.Is(err, domain.ErrWatchExists):
+		c.JSON(http.StatusConflict, gin.H{"error": "已关注该报价目标"})
+	case errors.Is(err, domain.ErrWatchOwnerRequired):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "未登录"})
+	case errors.Is(err, domain.ErrWatchNoRule):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "至少开启一项提醒规则"})
+	case errors.Is(err, domain.ErrInvalidChangePercent):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "变动比例不能为负数"})
+	case errors.Is(err, domain.ErrInvalidExpiryDays):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "到期提醒天数必须在 1 到 90 之间"})
+	case errors.Is(err, domain.ErrInvalidWebhookURL):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Webhook 地址必须是完整的 http 或 https 地址"})
+	case errors.Is(err, domain.ErrWebhookForbidden):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Webhook 地址不能指向本机、链路本地或内网地址"})
+	case errors.Is(err, domain.ErrInvalidTargetType), errors.Is(err, domain.ErrTargetTypeRequired):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的目标类型"})
+	case errors.Is(err, domain.ErrTargetIDRequired):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// Watch godoc
+// @Summary      关注报价目标
+// @Description  关注材料、工艺等报价目标，在有效最低价变动、供应商报价低于现任供应商或报价即将到期时发送站内通知，配置 Webhook 地址时同时推送
+// @Tags         报价关注
+// @Accept       json
+// @Produce      json
+// @Param        request body application.PriceWatchRequest true "关注规则"
+// @Success      200 {object} application.PriceWatchResponse "关注成功"
+// @Failure      400 {object} map[string]string "请求参数错误"
+// @Failure      404 {object} map[string]string "报价目标不存在"
+// @Failure      409 {object} map[string]string "已关注"
+// @Security     Bearer
+// @Router       /pricing/watches [post]
+func (h *PriceWatchHandler) Watch(c *gin.Context) {
+	var req application.PriceWatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	watch, err := h.service.Watch(c.Request.Context(), c.GetString("loginId"), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	recordAudit(c, watch.ID, watch)
+	c.JSON(http.StatusOK, watch)
+}
+
+// Update godoc
+// @Summary      更新关注规则
+// @Description  更新当前用户某个关注的提醒规则与 Webhook 地址
+// @Tags         报价关注
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "关注ID"
+// @Param        request body application.PriceWatchRules true "提醒规则"
+// @Success      200 {object} application.PriceWatchResponse "更新成功"
+// @Failure      400 {object} map[string]string "请求参数错误"
+// @Failure      404 {object} map[string]string "关注不存在"
+// @Security     Bearer
+// @Router       /pricing/watches/{id} [put]
+func (h *PriceWatchHandler) Update(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	var req application.PriceWatchRules
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	watch, err := h.service.Update(c.Request.Context(), c.GetString("loginId"), id, &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	recordAudit(c, id, watch)
+	c.JSON(http.StatusOK, watch)
+}
+
+// Unwatch godoc
+// @Summary      取消关注
+// @Description  取消当前用户的关注（历史提醒保留）
+// @Tags         报价关注
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "关注ID"
+// @Success      200 {object} map[string]string "取消成功"
+// @Failure      404 {object} map[string]string "关注不存在"
+// @Security     Bearer
+// @Router       /pricing/watches/{id} [delete]
+func (h *PriceWatchHandler) Unwatch(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Unwatch(c.Request.Context(), c.GetString("loginId"), id); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	recordAudit(c, id, nil)
+	c.JSON(http.StatusOK, gin.H{"message": "已取消关注"})
+}
+
+// List godoc
+// @Summary      我的关注
+// @Description  查询当前用户关注的报价目标及提醒规则
+// @Tags         报价关注
+// @Accept       json
+// @Produce      json
+// @Success      200 {object} application.PriceWatchListResponse "关注列表"
+// @Security     Bearer
+// @Router       /pricing/watches [get]
+func (h *PriceWatchHandler) List(c *gin.Context) {
+	resp, err := h.service.List(c.Request.Context(), c.GetString("loginId"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Alerts godoc
+// @Summary      我的报价提醒
+// @Description  分页查询当前用户收到的报价提醒及 Webhook 投递结果，按时间倒序
+// @Tags         报价关注
+// @Accept       json
+// @Produce      json
+// @Param        limit query int false "每页数量" default(20)
+// @Param        offset query int false "偏移量" default(0)
+// @Success      200 {object} application.PriceAlertListResponse "提醒列表"
+// @Security     Bearer
+// @Router       /pricing/watches/alerts [get]
+func (h *PriceWatchHandler) Alerts(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	resp, err := h.service.Alerts(c.Request.Context(), c.GetString("loginId"), limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ScanExpiring godoc
+// @Summary      扫描即将到期的报价
+// @Description  立即执行报价到期扫描（每日定时任务同样执行），对开启到期提醒的关注发送提醒，每条报价对每个关注只提醒一次
+// @Tags         报价关注
+// @Accept       json
+// @Produce      json
+// @Success      200 {object} application.ExpiryScanResponse "扫描结果"
+// @Security     Bearer
+// @Router       /pricing/watches/expiry-scan [post]
+func (h *PriceWatchHandler) ScanExpiring(c *gin.Context) {
+	resp, err := h.service.ScanExpiring(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetRoutes 获取路由定义
+func (h *PriceWatchHandler) GetRoutes() []endpoint.RouteDefinition {
+	return []endpoint.RouteDefinition{
+		{Method: "GET", Path: "/pricing/watches", Handler: h.List, Domain: "", Action: ""},
+		{Method: "POST", Path: "/pricing/watches", Handler: h.Watch, Domain: "pricing", Action: "watch"},
+		{Method: "GET", Path: "/pricing/watches/alerts", Handler: h.Alerts, Domain: "", Action: ""},
+		{Method: "POST", Path: "/pricing/watches/expiry-scan", Handler: h.ScanExpiring, Domain: "pricing", Action: "watchScan"},
+		{Method: "PUT", Path: "/pricing/watches/:id", Handler: h.Update, Domain: "pricing", Action: "watch"},
+		{Method: "DELETE", Path: "/pricing/watches/:id", Handler: h.Unwatch, Domain: "pricing", Action: "watch"},
+	}
+}
@@ -0,0 +1,417 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	currencyDomain "back/internal/currency/domain"
+	"back/internal/pricing/application"
+	"back/internal/pricing/domain"
+	supplierDomain "back/internal/supplier/domain"
+	"back/pkg/audit"
+	"back/pkg/endpoint"
+)
+
+// RFQHandler 询价 Handler
+type RFQHandler struct {
+	service *application.RFQService
+}
+
+// NewRFQHandler 创建 Handler
+func NewRFQHandler(service *application.RFQService) *RFQHandler {
+	return &RFQHandler{service: service}
+}
+
+// handleError 统一错误响应
+func (h *RFQHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrRFQNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "询价单不存在"})
+	case errors.Is(err, domain.ErrRFQItemNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "询价明细不存在"})
+	case errors.Is(err, domain.ErrRFQResponseNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "该明细下没有此报价"})
+	case errors.Is(err, domain.ErrPreferredNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "暂无首选供应商"})
+	case errors.Is(err, domain.ErrTargetNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "报价目标不存在"})
+	case errors.Is(err, supplierDomain.ErrSupplierNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "供应商不存在"})
+	case errors.Is(err, domain.ErrRFQDeadlinePassed):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "截止时间必须晚于当前时间"})
+	case errors.Is(err, domain.ErrRFQDuplicateItem):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "询价明细不能重复"})
+	case errors.Is(err, domain.ErrRFQNotOpen):
+		c.JSON(http.StatusConflict, gin.H{"error": "询价单已截止报价"})
+	case errors.Is(err, domain.ErrSupplierNotInvited):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "该供应商未受邀参与此询价"})
+	case errors.Is(err, domain.ErrSupplierDeclined):
+		c.JSON(http.StatusConflict, gin.H{"error": "该供应商已谢绝报价"})
+	case errors.Is(err, domain.ErrSupplierResponded):
+		c.JSON(http.StatusConflict, gin.H{"error": "该供应商已报价"})
+	case errors.Is(err, domain.ErrInvalidRFQTransition):
+		c.JSON(http.StatusConflict, gin.H{"error": "询价单当前状态不允许此操作: " + err.Error()})
+	case errors.Is(err, domain.ErrInvalidValidity):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "失效日期必须晚于生效日期"})
+	case errors.Is(err, domain.ErrInvalidTier):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "阶梯价的起始数量和单价必须大于0且起始数量不能重复"})
+	case errors.Is(err, domain.ErrInvalidTargetType), errors.Is(err, domain.ErrTargetTypeRequired):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的目标类型"})
+	case errors.Is(err, domain.ErrTargetIDRequired),
+		errors.Is(err, domain.ErrRFQItemsRequired), errors.Is(err, domain.ErrRFQSuppliersRequired),
+		errors.Is(err, domain.ErrRFQQuantityRequired):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, currencyDomain.ErrInvalidCurrency):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的币种代码"})
+	case errors.Is(err, currencyDomain.ErrRateNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "缺少币种汇率，请先维护汇率: " + err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// parseID 解析路径中的询价单 ID
+func parseID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的ID"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// getOperator 从上下文获取当前操作人
+func getOperator(c *gin.Context) (uint, string) {
+	id, _ := strconv.Atoi(c.GetString("loginId"))
+	return uint(id), c.GetString("username")
+}
+
+// recordAudit 记录审计
+func recordAudit(c *gin.Context, id uint, value interface{}) {
+	if recorder := audit.Get(c); recorder != nil {
+		recorder.SetResourceID(id)
+		recorder.SetNew(value)
+	}
+}
+
+// Create godoc
+// @Summary      创建询价单
+// @Description  对一个或多个报价目标（材料、工艺等已注册类型）按需求数量向多家供应商询价，截止时间前可录入供应商回复
+// @Tags         询价管理
+// @Accept       json
+// @Produce      json
+// @Param        request body application.CreateRFQRequest true "询价单"
+// @Success      200 {object} domain.RFQ "创建成功"
+// @Failure      400 {object} map[string]string "请求参数错误"
+// @Failure      404 {object} map[string]string "报价目标或供应商不存在"
+// @Security     Bearer
+// @Router       /pricing/rfq [post]
+func (h *RFQHandler) Create(c *gin.Context) {
+	var req application.CreateRFQRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, username := getOperator(c)
+	rfq, err := h.service.Create(c.Request.Context(), &req, userID, username)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	recordAudit(c, rfq.ID, rfq)
+	c.JSON(http.StatusOK, rfq)
+}
+
+// List godoc
+// @Summary      询价单列表
+// @Description  按状态分页查询询价单，按创建时间倒序
+// @Tags         询价管理
+// @Accept       json
+// @Produce      json
+// @Param        status query string false "状态（open/evaluating/awarded/closed/cancelled）"
+// @Param        limit query int false "每页数量" default(20)
+// @Param        offset query int false "偏移量" default(0)
+// @Success      200 {object} application.RFQListResponse "询价单列表"
+// @Security     Bearer
+// @Router       /pricing/rfq [get]
+func (h *RFQHandler) List(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	resp, err := h.service.List(c.Request.Context(), c.Query("status"), limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Get godoc
+// @Summary      询价单详情
+// @Description  查询询价单的明细与受邀供应商回复状态（已到截止时间的询价单自动转为比价中）
+// @Tags         询价管理
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "询价单ID"
+// @Success      200 {object} domain.RFQ "询价单"
+// @Failure      404 {object} map[string]string "询价单不存在"
+// @Security     Bearer
+// @Router       /pricing/rfq/{id} [get]
+func (h *RFQHandler) Get(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	rfq, err := h.service.Get(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rfq)
+}
+
+// Respond godoc
+// @Summary      录入供应商回复
+// @Description  录入受邀供应商对询价明细的报价，生成关联询价单的供应商报价；截止前可重复报价，以最新一次为准
+// @Tags         询价管理
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "询价单ID"
+// @Param        request body application.RFQResponseRequest true "供应商报价"
+// @Success      200 {object} domain.PriceData "报价成功"
+// @Failure      400 {object} map[string]string "请求参数错误"
+// @Failure      409 {object} map[string]string "询价单已截止"
+// @Security     Bearer
+// @Router       /pricing/rfq/{id}/responses [post]
+func (h *RFQHandler) Respond(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	var req application.RFQResponseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	price, err := h.service.Respond(c.Request.Context(), id, &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	recordAudit(c, id, price)
+	c.JSON(http.StatusOK, price)
+}
+
+// Decline godoc
+// @Summary      供应商谢绝报价
+// @Description  记录受邀供应商谢绝本次询价（已报价的供应商不能谢绝）
+// @Tags         询价管理
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "询价单ID"
+// @Param        request body application.DeclineRFQRequest true "谢绝信息"
+// @Success      200 {object} domain.RFQ "询价单"
+// @Failure      409 {object} map[string]string "询价单已截止或供应商已报价"
+// @Security     Bearer
+// @Router       /pricing/rfq/{id}/decline [post]
+func (h *RFQHandler) Decline(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	var req application.DeclineRFQRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rfq, err := h.service.Decline(c.Request.Context(), id, &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	recordAudit(c, id, req)
+	c.JSON(http.StatusOK, rfq)
+}
+
+// StopBidding godoc
+// @Summary      截止报价
+// @Description  在截止时间前提前结束报价，询价单进入比价中
+// @Tags         询价管理
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "询价单ID"
+// @Success      200 {object} domain.RFQ "询价单"
+// @Failure      409 {object} map[string]string "状态不允许"
+// @Security     Bearer
+// @Router       /pricing/rfq/{id}/stop-bidding [post]
+func (h *RFQHandler) StopBidding(c *gin.Context) {
+	h.transition(c, h.service.StopBidding)
+}
+
+// Close godoc
+// @Summary      关闭询价单
+// @Description  比价中或已定标的询价单关闭归档
+// @Tags         询价管理
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "询价单ID"
+// @Success      200 {object} domain.RFQ "询价单"
+// @Failure      409 {object} map[string]string "状态不允许"
+// @Security     Bearer
+// @Router       /pricing/rfq/{id}/close [post]
+func (h *RFQHandler) Close(c *gin.Context) {
+	h.transition(c, h.service.Close)
+}
+
+// Cancel godoc
+// @Summary      取消询价单
+// @Description  取消尚未定标的询价单（已录入的供应商报价保留）
+// @Tags         询价管理
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "询价单ID"
+// @Success      200 {object} domain.RFQ "询价单"
+// @Failure      409 {object} map[string]string "状态不允许"
+// @Security     Bearer
+// @Router       /pricing/rfq/{id}/cancel [post]
+func (h *RFQHandler) Cancel(c *gin.Context) {
+	h.transition(c, h.service.Cancel)
+}
+
+// transition 执行询价单状态变更
+func (h *RFQHandler) transition(c *gin.Context, change func(ctx context.Context, id uint) (*domain.RFQ, error)) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	rfq, err := change(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	recordAudit(c, id, rfq)
+	c.JSON(http.StatusOK, rfq)
+}
+
+// Compare godoc
+// @Summary      询价比价
+// @Description  各明细按供应商最新回复计算到岸单价（阶梯价、起订量多采部分与运杂费，按当日汇率换算为比价币种）并排名
+// @Tags         询价管理
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "询价单ID"
+// @Success      200 {object} application.RFQComparisonResponse "比价矩阵"
+// @Failure      400 {object} map[string]string "缺少汇率"
+// @Failure      404 {object} map[string]string "询价单不存在"
+// @Security     Bearer
+// @Router       /pricing/rfq/{id}/comparison [get]
+func (h *RFQHandler) Compare(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.Compare(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Award godoc
+// @Summary      定标
+// @Description  截止报价后将询价明细定标给某条回复报价，并更新该报价目标的首选供应商；全部明细定标后询价单转为已定标
+// @Tags         询价管理
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "询价单ID"
+// @Param        request body application.AwardRFQRequest true "定标信息"
+// @Success      200 {object} domain.PreferredSupplier "首选供应商"
+// @Failure      404 {object} map[string]string "明细或报价不存在"
+// @Failure      409 {object} map[string]string "状态不允许"
+// @Security     Bearer
+// @Router       /pricing/rfq/{id}/award [post]
+func (h *RFQHandler) Award(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	var req application.AwardRFQRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	_, username := getOperator(c)
+	preferred, err := h.service.Award(c.Request.Context(), id, &req, username)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	recordAudit(c, id, preferred)
+	c.JSON(http.StatusOK, preferred)
+}
+
+// GetPreferred godoc
+// @Summary      查询首选供应商
+// @Description  查询报价目标最近一次询价定标的首选供应商
+// @Tags         询价管理
+// @Accept       json
+// @Produce      json
+// @Param        target_type query string true "目标类型（已注册的类型，如 material、process）"
+// @Param        target_id query int true "目标ID"
+// @Success      200 {object} domain.PreferredSupplier "首选供应商"
+// @Failure      404 {object} map[string]string "暂无首选供应商"
+// @Security     Bearer
+// @Router       /pricing/preferred-supplier [get]
+func (h *RFQHandler) GetPreferred(c *gin.Context) {
+	targetType := c.Query("target_type")
+	targetID, err := strconv.ParseUint(c.Query("target_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的ID"})
+		return
+	}
+
+	preferred, err := h.service.GetPreferred(c.Request.Context(), targetType, uint(targetID))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, preferred)
+}
+
+// GetRoutes 获取路由定义
+func (h *RFQHandler) GetRoutes() []endpoint.RouteDefinition {
+	return []endpoint.RouteDefinition{
+		{Method: "POST", Path: "/pricing/rfq", Handler: h.Create, Domain: "pricing", Action: "rfq"},
+		{Method: "GET", Path: "/pricing/rfq", Handler: h.List, Domain: "", Action: ""},
+		{Method: "GET", Path: "/pricing/rfq/:id", Handler: h.Get, Domain: "", Action: ""},
+		{Method: "POST", Path: "/pricing/rfq/:id/responses", Handler: h.Respond, Domain: "pricing", Action: "rfq"},
+		{Method: "POST", Path: "/pricing/rfq/:id/decline", Handler: h.Decline, Domain: "pricing", Action: "rfq"},
+		{Method: "POST", Path: "/pricing/rfq/:id/stop-bidding", Handler: h.StopBidding, Domain: "pricing", Action: "rfq"},
+		{Method: "GET", Path: "/pricing/rfq/:id/comparison", Handler: h.Compare, Domain: "", Action: ""},
+		{Method: "POST", Path: "/pricing/rfq/:id/award", Handler: h.Award, Domain: "pricing", Action: "rfqAward"},
+		{Method: "POST", Path: "/pricing/rfq/:id/close", Handler: h.Close, Domain: "pricing", Action: "rfq"},
+		{Method: "POST", Path: "/pricing/rfq/:id/cancel", Handler: h.Cancel, Domain: "pricing", Action: "rfq"},
+		{Method: "GET", Path: "/pricing/preferred-supplier", Handler: h.GetPreferred, Domain: "", Action: ""},
+	}
+}
@@ -150,4 +150,17 @@ func (s *ProcessService) Delete(ctx context.Context, id uint) error {
 // Exists 检查工序是否存在（供其他模块调用）
 func (s *ProcessService) Exists(ctx context.Context, id uint) (bool, error) {
 	return s.repo.ExistsByID(ctx, id)
-}
\ No newline at end of file
+}
+// DisplayName 获取工序名称（供其他模块调用）
+func (s *ProcessService) DisplayName(ctx context.Context, id uint) (string, error) {
+	process, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	return process.Name, nil
+}
+
+// ResolveNames 根据工序名称批量查找工序 ID（供其他模块调用）
+func (s *ProcessService) ResolveNames(ctx context.Context, names []string) (map[string][]uint, error) {
+	return s.repo.FindIDsByNames(ctx, names)
+}
@@ -86,3 +86,24 @@ func (r *ProcessRepo) FindByName(ctx context.Context, name string) (*domain.Proc
 func (r *ProcessRepo) Count(ctx context.Context) (int64, error) {
 	return r.Repo.Count(ctx, map[string]interface{}{})
 }
+
+// FindIDsByNames 根据名称批量查询 ID（名称 → ID，同名工序会返回多个 ID）
+func (r *ProcessRepo) FindIDsByNames(ctx context.Context, names []string) (map[string][]uint, error) {
+	var rows []struct {
+		ID   uint
+		Name string
+	}
+	err := r.db.WithContext(ctx).Model(&domain.Process{}).
+		Select("id", "name").
+		Where("name IN ?", names).
+		Order("id").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string][]uint, len(rows))
+	for _, row := range rows {
+		result[row.Name] = append(result[row.Name], row.ID)
+	}
+	return result, nil
+}
@@ -0,0 +1,225 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"back/internal/product/domain"
+	"back/internal/product/infra"
+)
+
+// BOMService 产品 BOM 版本服务
+type BOMService struct {
+	repo  *infra.ProductRepo
+	chain domain.ApprovalChain
+}
+
+// NewBOMService 创建 BOM 版本服务（审批链与产品审批共用）
+func NewBOMService(repo *infra.ProductRepo, approvalChain []string) *BOMService {
+	return &BOMService{repo: repo, chain: approvalChain}
+}
+
+// Create 为产品新建草稿版本
+func (s *BOMService) Create(ctx context.Context, productID uint, req *CreateBOMVersionRequest, createdBy uint) (*BOMVersionResponse, error) {
+	current, err := effectiveBOM(ctx, s.repo, productID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	bom := &domain.BOMVersion{
+		ProductID: productID,
+		Status:    domain.ProductStatusDraft,
+		Materials: current.Materials,
+		Processes: current.Processes,
+		Remark:    req.Remark,
+		CreatedBy: createdBy,
+	}
+	if err := bom.UpdateRecipe(req.Materials, req.Processes); err != nil {
+		return nil, err
+	}
+
+	// 锁定产品后分配版本号，并发新建同一产品的版本时不会取到相同版本号
+	err = s.repo.Transaction(ctx, func(txRepo *infra.ProductRepo) error {
+		if err := txRepo.LockProduct(ctx, productID); err != nil {
+			return err
+		}
+		version, err := txRepo.NextBOMVersion(ctx, productID)
+		if err != nil {
+			return err
+		}
+		bom.Version = version
+		return txRepo.SaveBOMVersion(ctx, bom)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.toBOMVersionResponse(bom), nil
+}
+
+// Update 修改草稿或被拒绝的版本
+func (s *BOMService) Update(ctx context.Context, id uint, req *UpdateBOMVersionRequest) (*BOMVersionResponse, error) {
+	bom, err := s.repo.FindBOMVersionByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.Materials) > 0 || len(req.Processes) > 0 {
+		if err := bom.UpdateRecipe(req.Materials, req.Processes); err != nil {
+			return nil, err
+		}
+	}
+	if req.Remark != nil {
+		bom.Remark = *req.Remark
+	}
+
+	if err := s.repo.SaveBOMVersion(ctx, bom); err != nil {
+		return nil, err
+	}
+	return s.toBOMVersionResponse(bom), nil
+}
+
+// Transition 提交 / 审批 / 拒绝 BOM 版本（status 为目标状态，并记录审批）
+func (s *BOMService) Transition(ctx context.Context, id uint, status string, req *BOMApprovalRequest, actor domain.Approver) (*ApprovalResponse, error) {
+	bom, err := s.repo.FindBOMVersionByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var approval *domain.ProductApproval
+	switch status {
+	case domain.ProductStatusSubmitted:
+		approval, err = bom.Submit(actor)
+	case domain.ProductStatusApproved:
+		effectiveFrom := time.Now()
+		if req.EffectiveFrom != nil {
+			effectiveFrom = *req.EffectiveFrom
+		}
+		approval, err = bom.Approve(actor, s.chain, effectiveFrom)
+	case domain.ProductStatusRejected:
+		approval, err = bom.Reject(actor, s.chain, req.Reason)
+	default:
+		return nil, domain.ErrInvalidTargetStatus
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Transaction(ctx, func(txRepo *infra.ProductRepo) error {
+		if err := txRepo.SaveBOMVersion(ctx, bom); err != nil {
+			return err
+		}
+		approval.ProductID = bom.ProductID
+		approval.BOMVersionID = bom.ID
+		approval.Comment = req.Comment
+		return txRepo.SaveApproval(ctx, approval)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toApprovalResponse(approval), nil
+}
+
+// List 产品的所有 BOM 版本
+func (s *BOMService) List(ctx context.Context, productID uint) ([]*BOMVersionResponse, error) {
+	if _, err := s.repo.FindByID(ctx, productID); err != nil {
+		return nil, err
+	}
+	versions, err := s.repo.FindBOMVersions(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*BOMVersionResponse, len(versions))
+	for i, bom := range versions {
+		result[i] = s.toBOMVersionResponse(bom)
+	}
+	return result, nil
+}
+
+// GetEffective 获取某日期生效的 BOM（无生效版本时返回产品自身配方）
+func (s *BOMService) GetEffective(ctx context.Context, productID uint, at time.Time) (*BOMVersionResponse, error) {
+	bom, err := effectiveBOM(ctx, s.repo, productID, at)
+	if err != nil {
+		return nil, err
+	}
+	return s.toBOMVersionResponse(bom), nil
+}
+
+// Delete 删除草稿或被拒绝的版本
+func (s *BOMService) Delete(ctx context.Context, id uint) error {
+	bom, err := s.repo.FindBOMVersionByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !bom.IsEditable() {
+		return domain.ErrCannotDeleteBOMVersion
+	}
+	return s.repo.DeleteBOMVersion(ctx, id)
+}
+
+// ResolveBOMVersion 获取某时间点生效的 BOM 版本 ID 与版本号（供 order 模块调用）
+func (s *BOMService) ResolveBOMVersion(ctx context.Context, productID uint, at time.Time) (uint, int, error) {
+	bom, err := effectiveBOM(ctx, s.repo, productID, at)
+	if err != nil {
+		return 0, 0, err
+	}
+	return bom.ID, bom.Version, nil
+}
+
+// effectiveBOM 某时间点生效的 BOM（无生效版本时为产品自身配方）
+func effectiveBOM(ctx context.Context, repo *infra.ProductRepo, productID uint, at time.Time) (*domain.BOMVersion, error) {
+	product, err := repo.FindByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	bom, err := repo.FindEffectiveBOMVersion(ctx, productID, at)
+	if err != nil {
+		return nil, err
+	}
+	if bom == nil {
+		return domain.BaseBOM(product), nil
+	}
+	return bom, nil
+}
+
+// loadBOM 按版本 ID 获取 BOM（0 为产品自身配方）
+func loadBOM(ctx context.Context, repo *infra.ProductRepo, productID, versionID uint) (*domain.BOMVersion, error) {
+	if versionID == 0 {
+		product, err := repo.FindByID(ctx, productID)
+		if err != nil {
+			return nil, err
+		}
+		return domain.BaseBOM(product), nil
+	}
+
+	bom, err := repo.FindBOMVersionByID(ctx, versionID)
+	if err != nil {
+		return nil, err
+	}
+	if bom.ProductID != productID {
+		return nil, domain.ErrBOMVersionNotFound
+	}
+	return bom, nil
+}
+
+func (s *BOMService) toBOMVersionResponse(bom *domain.BOMVersion) *BOMVersionResponse {
+	steps := bom.Routing()
+	return &BOMVersionResponse{
+		ID:            bom.ID,
+		ProductID:     bom.ProductID,
+		Version:       bom.Version,
+		Status:        bom.Status,
+		Materials:     bom.Materials,
+		Processes:     bom.Processes,
+		EffectiveFrom: bom.EffectiveFrom,
+		Remark:        bom.Remark,
+		LeadTimeDays:  domain.LeadTimeDays(steps),
+		Yield:         domain.OverallYield(steps),
+		CreatedBy:     bom.CreatedBy,
+		SubmittedBy:   bom.SubmittedBy,
+		ApprovalStep:  bom.ApprovalStep,
+		PendingRole:   domain.PendingRole(bom.Status, bom.ApprovalStep, s.chain),
+		ApprovedAt:    bom.ApprovedAt,
+		CreatedAt:     bom.CreatedAt,
+		UpdatedAt:     bom.UpdatedAt,
+	}
+}
@@ -2,9 +2,14 @@ package application
 
 import (
 	"context"
+	"errors"
+	"math"
+	"time"
 
 	"back/internal/product/domain"
 	"back/internal/product/infra"
+	currencyApp "back/internal/currency/application"
+	currencyDomain "back/internal/currency/domain"
 	materialApp "back/internal/material/application"
 	processApp "back/internal/process/application"
 	pricingDomain "back/internal/pricing/domain"
@@ -12,32 +17,51 @@ import (
 
 // MaterialPriceServiceInterface Material 价格服务接口
 type MaterialPriceServiceInterface interface {
-	GetMinPrice(ctx context.Context, materialID uint) (*pricingDomain.PriceData, error)
-	GetMaxPrice(ctx context.Context, materialID uint) (*pricingDomain.PriceData, error)
+	GetMinPrice(ctx context.Context, materialID uint, quantity float64) (*pricingDomain.PriceData, error)
+	GetMaxPrice(ctx context.Context, materialID uint, quantity float64) (*pricingDomain.PriceData, error)
+	GetCurrentPrice(ctx context.Context, materialID uint, quantity float64) (*pricingDomain.PriceData, error)
+	GetHistoricalMinPrice(ctx context.Context, materialID uint) (*pricingDomain.PriceData, error)
+	GetHistoricalMaxPrice(ctx context.Context, materialID uint) (*pricingDomain.PriceData, error)
+	GetSupplierPrice(ctx context.Context, materialID, supplierID uint, quantity float64) (*pricingDomain.PriceData, error)
 }
 
 // ProcessPriceServiceInterface Process 价格服务接口
 type ProcessPriceServiceInterface interface {
-	GetMinPrice(ctx context.Context, processID uint) (*pricingDomain.PriceData, error)
-	GetMaxPrice(ctx context.Context, processID uint) (*pricingDomain.PriceData, error)
+	GetMinPrice(ctx context.Context, processID uint, quantity float64) (*pricingDomain.PriceData, error)
+	GetMaxPrice(ctx context.Context, processID uint, quantity float64) (*pricingDomain.PriceData, error)
+	GetCurrentPrice(ctx context.Context, processID uint, quantity float64) (*pricingDomain.PriceData, error)
+	GetHistoricalMinPrice(ctx context.Context, processID uint) (*pricingDomain.PriceData, error)
+	GetHistoricalMaxPrice(ctx context.Context, processID uint) (*pricingDomain.PriceData, error)
+	GetSupplierPrice(ctx context.Context, processID, supplierID uint, quantity float64) (*pricingDomain.PriceData, error)
 }
 
-// CostCalculator 成本计算器
+// CostHistoryProvider 产品历史缩率与次品率接口（由 Order 模块实现）
+type CostHistoryProvider interface {
+	GetProductShrinkages(ctx context.Context, productIDs []uint) (map[uint]float64, error)
+	GetProductDefectRates(ctx context.Context, productIDs []uint) (map[uint]float64, error)
+}
+
+// CostCalculator 成本计算器（产品价格服务共用同一套核算）
 type CostCalculator struct {
 	productRepo      *infra.ProductRepo
 	materialService  *materialApp.MaterialService
 	processService   *processApp.ProcessService
 	materialPriceSvc MaterialPriceServiceInterface
 	processPriceSvc  ProcessPriceServiceInterface
+	currencyService  *currencyApp.CurrencyService
+	defaults         domain.CostPolicy
+	history          CostHistoryProvider
 }
 
-// NewCostCalculator 创建成本计算器
+// NewCostCalculator 创建成本计算器（defaults 为无历史数据时的回修率与默认间接费用）
 func NewCostCalculator(
 	productRepo *infra.ProductRepo,
 	materialService *materialApp.MaterialService,
 	processService *processApp.ProcessService,
 	materialPriceSvc MaterialPriceServiceInterface,
 	processPriceSvc ProcessPriceServiceInterface,
+	currencyService *currencyApp.CurrencyService,
+	defaults domain.CostPolicy,
 ) *CostCalculator {
 	return &CostCalculator{
 		productRepo:      productRepo,
@@ -45,9 +69,38 @@ func NewCostCalculator(
 		processService:   processService,
 		materialPriceSvc: materialPriceSvc,
 		processPriceSvc:  processPriceSvc,
+		currencyService:  currencyService,
+		defaults:         defaults,
 	}
 }
 
+// SetHistoryProvider 设置历史缩率与次品率来源（未设置时只用默认参数）
+func (c *CostCalculator) SetHistoryProvider(provider CostHistoryProvider) {
+	c.history = provider
+}
+
+// priceLookup 按需求数量取原料 / 工艺单价（quantity 为该原料 / 工艺的实际需求量）
+type priceLookup func(ctx context.Context, targetType string, targetID uint, quantity float64) (*pricingDomain.PriceData, error)
+
+// valuation 核算币种与汇率日期（报价按该日汇率换算为核算币种）
+type valuation struct {
+	currency string
+	asOf     time.Time
+}
+
+// valuationFor 确定核算币种（默认本位币）与汇率日期（默认今天）
+func (c *CostCalculator) valuationFor(currency string, date *time.Time) (valuation, error) {
+	code, err := c.currencyService.Normalize(currency)
+	if err != nil {
+		return valuation{}, err
+	}
+	v := valuation{currency: code, asOf: time.Now()}
+	if date != nil {
+		v.asOf = *date
+	}
+	return v, nil
+}
+
 // Calculate 计算产品成本
 func (c *CostCalculator) Calculate(ctx context.Context, req *CalculateCostRequest) (*domain.CostResult, error) {
 	// 1. 查询产品
@@ -56,93 +109,335 @@ func (c *CostCalculator) Calculate(ctx context.Context, req *CalculateCostReques
 		return nil, err
 	}
 	
-	// 2. 验证产品配置
-	if err := product.Validate(); err != nil {
+	// 2. 取计算日期生效的 BOM 版本并验证配置
+	bom, err := c.effectiveBOM(ctx, product.ID, req.Date)
+	if err != nil {
 		return nil, err
 	}
 	
-	// 3. 计算原料成本
-	materialCost := 0.0
-	materialItems := []domain.MaterialCostItem{}
+	// 3. 核算参数：请求指定 > 产品历史 > 默认
+	policy, err := c.policyFor(ctx, product.ID, &req.CostPolicyRequest)
+	if err != nil {
+		return nil, err
+	}
+	money, err := c.valuationFor(req.Currency, req.Date)
+	if err != nil {
+		return nil, err
+	}
+	
+	// 4. 按最低价或最高价计算
+	return c.compute(ctx, product, bom, req.Quantity, c.basePrice(req.UseMinPrice), policy, money)
+}
+
+// Simulate 成本模拟：对每个产品按基准价与场景（价格覆盖、配比调整）分别计算并对比
+func (c *CostCalculator) Simulate(ctx context.Context, req *SimulateCostRequest) (*CostSimulationResponse, error) {
+	scenario := &domain.CostScenario{Prices: req.Prices, Ratios: req.Ratios}
+	if err := scenario.Validate(); err != nil {
+		return nil, err
+	}
+
+	money, err := c.valuationFor(req.Currency, req.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	base := c.basePrice(req.UseMinPrice)
+	lookup := c.scenarioPrice(scenario, base, money.currency)
+
+	resp := &CostSimulationResponse{Quantity: req.Quantity, Items: []*CostComparison{}}
+	for _, productID := range uniqueIDs(req.ProductIDs) {
+		item := &CostComparison{ProductID: productID}
+		if err := c.simulateProduct(ctx, item, req, scenario, base, lookup, money); err != nil {
+			if errors.Is(err, domain.ErrProductNotFound) && len(req.ProductIDs) == 1 {
+				return nil, err
+			}
+			item.Error = err.Error()
+		}
+		resp.Items = append(resp.Items, item)
+	}
+	return resp, nil
+}
+
+// simulateProduct 计算单个产品的基准与场景成本
+func (c *CostCalculator) simulateProduct(ctx context.Context, item *CostComparison, req *SimulateCostRequest, scenario *domain.CostScenario, base, lookup priceLookup, money valuation) error {
+	product, err := c.productRepo.FindByID(ctx, item.ProductID)
+	if err != nil {
+		return err
+	}
+	item.ProductName = product.Name
+
+	bom, err := c.effectiveBOM(ctx, product.ID, req.Date)
+	if err != nil {
+		return err
+	}
+	policy, err := c.policyFor(ctx, product.ID, &req.CostPolicyRequest)
+	if err != nil {
+		return err
+	}
+	if item.Baseline, err = c.compute(ctx, product, bom, req.Quantity, base, policy, money); err != nil {
+		return err
+	}
+
+	// 场景使用调整后的配比，工艺路线不变
+	materials, err := scenario.ApplyRatios(product.ID, bom.Materials)
+	if err != nil {
+		return err
+	}
+	simulated := *bom
+	simulated.Materials = materials
+	if item.Scenario, err = c.compute(ctx, product, &simulated, req.Quantity, lookup, policy, money); err != nil {
+		return err
+	}
+
+	item.UnitCostDiff = item.Scenario.UnitCost - item.Baseline.UnitCost
+	item.TotalCostDiff = item.Scenario.TotalCost - item.Baseline.TotalCost
+	if item.Baseline.UnitCost > 0 {
+		item.UnitCostDiffPercent = item.UnitCostDiff / item.Baseline.UnitCost * 100
+	}
+	return nil
+}
+
+// effectiveBOM 取日期生效的 BOM 并校验（date 为空时取当前）
+func (c *CostCalculator) effectiveBOM(ctx context.Context, productID uint, date *time.Time) (*domain.BOMVersion, error) {
+	at := time.Now()
+	if date != nil {
+		at = *date
+	}
+	bom, err := effectiveBOM(ctx, c.productRepo, productID, at)
+	if err != nil {
+		return nil, err
+	}
+	if err := bom.Validate(); err != nil {
+		return nil, err
+	}
+	return bom, nil
+}
+
+// policyFor 确定产品的核算参数：请求指定的优先，缩率与回修率其次取产品历史订单，最后取默认值
+func (c *CostCalculator) policyFor(ctx context.Context, productID uint, req *CostPolicyRequest) (domain.CostPolicy, error) {
+	policy := c.defaults
+	if req.Overheads != nil {
+		policy.Overheads = req.Overheads
+	}
+
+	if c.history != nil {
+		ids := []uint{productID}
+		if req.Shrinkage == nil {
+			shrinkages, err := c.history.GetProductShrinkages(ctx, ids)
+			if err != nil {
+				return policy, err
+			}
+			if v, ok := shrinkages[productID]; ok {
+				policy.Shrinkage = math.Min(v, domain.MaxLossPercent-1)
+			}
+		}
+		if req.ReworkRate == nil {
+			rates, err := c.history.GetProductDefectRates(ctx, ids)
+			if err != nil {
+				return policy, err
+			}
+			if v, ok := rates[productID]; ok && v > 0 {
+				policy.ReworkRate = math.Min(v, 100)
+			}
+		}
+	}
+
+	if req.Shrinkage != nil {
+		policy.Shrinkage = *req.Shrinkage
+	}
+	if req.ReworkRate != nil {
+		policy.ReworkRate = *req.ReworkRate
+	}
+	return policy, policy.Validate()
+}
+
+// currentPrice 当前价：当前有效报价中最新的一条（产品单价按报价单价核算，不取阶梯价）
+func (c *CostCalculator) currentPrice() priceLookup {
+	return func(ctx context.Context, targetType string, targetID uint, _ float64) (*pricingDomain.PriceData, error) {
+		if targetType == domain.OverrideTargetMaterial {
+			return c.materialPriceSvc.GetCurrentPrice(ctx, targetID, 0)
+		}
+		return c.processPriceSvc.GetCurrentPrice(ctx, targetID, 0)
+	}
+}
+
+// historicalPrice 历史价：全部报价（含已失效）中的最低价或最高价（按报价单价，不取阶梯价）
+func (c *CostCalculator) historicalPrice(useMin bool) priceLookup {
+	return func(ctx context.Context, targetType string, targetID uint, _ float64) (*pricingDomain.PriceData, error) {
+		switch {
+		case targetType == domain.OverrideTargetMaterial && useMin:
+			return c.materialPriceSvc.GetHistoricalMinPrice(ctx, targetID)
+		case targetType == domain.OverrideTargetMaterial:
+			return c.materialPriceSvc.GetHistoricalMaxPrice(ctx, targetID)
+		case useMin:
+			return c.processPriceSvc.GetHistoricalMinPrice(ctx, targetID)
+		default:
+			return c.processPriceSvc.GetHistoricalMaxPrice(ctx, targetID)
+		}
+	}
+}
+
+// basePrice 基准价：按需求量取当前有效报价中的最低价或最高价（计入阶梯价与起订量）
+func (c *CostCalculator) basePrice(useMin bool) priceLookup {
+	return func(ctx context.Context, targetType string, targetID uint, quantity float64) (*pricingDomain.PriceData, error) {
+		switch {
+		case targetType == domain.OverrideTargetMaterial && useMin:
+			return c.materialPriceSvc.GetMinPrice(ctx, targetID, quantity)
+		case targetType == domain.OverrideTargetMaterial:
+			return c.materialPriceSvc.GetMaxPrice(ctx, targetID, quantity)
+		case useMin:
+			return c.processPriceSvc.GetMinPrice(ctx, targetID, quantity)
+		default:
+			return c.processPriceSvc.GetMaxPrice(ctx, targetID, quantity)
+		}
+	}
+}
+
+// scenarioPrice 场景价：有覆盖时按覆盖方式取价，否则取基准价（指定单价以核算币种计，不受阶梯价与起订量影响）
+func (c *CostCalculator) scenarioPrice(scenario *domain.CostScenario, base priceLookup, currency string) priceLookup {
+	return func(ctx context.Context, targetType string, targetID uint, quantity float64) (*pricingDomain.PriceData, error) {
+		override, ok := scenario.PriceOverride(targetType, targetID)
+		if !ok {
+			return base(ctx, targetType, targetID, quantity)
+		}
+
+		if override.Mode == domain.OverrideModeSupplier {
+			if targetType == domain.OverrideTargetMaterial {
+				return c.materialPriceSvc.GetSupplierPrice(ctx, targetID, override.SupplierID, quantity)
+			}
+			return c.processPriceSvc.GetSupplierPrice(ctx, targetID, override.SupplierID, quantity)
+		}
+
+		priceData, err := base(ctx, targetType, targetID, quantity)
+		if err != nil && override.Mode != domain.OverrideModeAbsolute {
+			return nil, err
+		}
+		// 指定单价时不依赖已有报价
+		result := &pricingDomain.PriceData{}
+		if priceData != nil {
+			*result = *priceData
+		}
+		result.Price = override.Apply(result.Price)
+		if result.EffectivePrice > 0 {
+			result.EffectivePrice = override.Apply(result.EffectivePrice)
+		}
+		if override.Mode == domain.OverrideModeAbsolute {
+			result.Currency = currency
+			result.PurchaseQuantity = 0
+			result.EffectivePrice = 0
+		}
+		return result, nil
+	}
+}
+
+// compute 按给定 BOM、取价方式与核算参数计算成本，报价按汇率日期换算为核算币种
+//
+// quantity 为订单 / 计划的成品数量，各原料与工艺按含损耗与缩率的实际需求量取阶梯价，
+// 起订量多采的部分计入成本。
+func (c *CostCalculator) compute(ctx context.Context, product *domain.Product, bom *domain.BOMVersion, quantity float64, lookup priceLookup, policy domain.CostPolicy, money valuation) (*domain.CostResult, error) {
+	steps, factors, yield, err := routingFactors(bom.Processes)
+	if err != nil {
+		return nil, err
+	}
+	shrinkFactor := policy.ShrinkFactor()
 	
-	for _, m := range product.Materials {
-		// 获取价格
-		var priceData *pricingDomain.PriceData
-		if req.UseMinPrice {
-			priceData, err = c.materialPriceSvc.GetMinPrice(ctx, m.MaterialID)
-		} else {
-			priceData, err = c.materialPriceSvc.GetMaxPrice(ctx, m.MaterialID)
+	// 1. 原料单价
+	materialItems := []domain.MaterialCostItem{}
+	for _, m := range bom.Materials {
+		required := quantity * m.Ratio * shrinkFactor / yield
+		priceData, err := lookup(ctx, domain.OverrideTargetMaterial, m.MaterialID, required)
+		if err != nil {
+			return nil, err
 		}
+		price, err := c.convert(ctx, priceData, money)
 		if err != nil {
 			return nil, err
 		}
-		
-		// 获取材料信息
 		mat, err := c.materialService.Get(ctx, m.MaterialID)
 		if err != nil {
 			return nil, err
 		}
 		
-		// 计算成本
-		cost := priceData.Price * m.Ratio
-		materialCost += cost
-		
 		materialItems = append(materialItems, domain.MaterialCostItem{
-			MaterialID:   m.MaterialID,
-			MaterialName: mat.Name,
-			Ratio:        m.Ratio,
-			Price:        priceData.Price,
-			Cost:         cost,
+			MaterialID:       m.MaterialID,
+			MaterialName:     mat.Name,
+			Ratio:            m.Ratio,
+			RequiredQuantity: required,
+			PurchaseQuantity: priceData.PurchaseQuantity,
+			Price:            price,
+			QuotePrice:       priceData.Price,
+			QuoteCurrency:    priceData.Currency,
+			SupplierID:       priceData.SupplierID,
+			SupplierName:     priceData.SupplierName,
 		})
 	}
 	
-	// 4. 计算工艺成本
-	processCost := 0.0
+	// 2. 工艺单价（工序加工的是投入数量，后续损耗越多投入越大）
 	processItems := []domain.ProcessCostItem{}
-	
-	for _, p := range product.Processes {
-		// 获取价格
-		var priceData *pricingDomain.PriceData
-		if req.UseMinPrice {
-			priceData, err = c.processPriceSvc.GetMinPrice(ctx, p.ProcessID)
-		} else {
-			priceData, err = c.processPriceSvc.GetMaxPrice(ctx, p.ProcessID)
+	for _, p := range steps {
+		required := quantity * domain.ProcessQuantity(p.Quantity) * factors[p.Sequence] * shrinkFactor
+		priceData, err := lookup(ctx, domain.OverrideTargetProcess, p.ProcessID, required)
+		if err != nil {
+			return nil, err
 		}
+		price, err := c.convert(ctx, priceData, money)
 		if err != nil {
 			return nil, err
 		}
-		
-		// 获取工艺信息
 		proc, err := c.processService.Get(ctx, p.ProcessID)
 		if err != nil {
 			return nil, err
 		}
 		
-		processCost += priceData.Price
-		
 		processItems = append(processItems, domain.ProcessCostItem{
-			ProcessID:   p.ProcessID,
-			ProcessName: proc.Name,
-			Price:       priceData.Price,
-			Cost:        priceData.Price,
+			ProcessID:        p.ProcessID,
+			ProcessName:      proc.Name,
+			Sequence:         p.Sequence,
+			Quantity:         p.Quantity,
+			YieldLoss:        p.YieldLoss,
+			InputFactor:      factors[p.Sequence],
+			RequiredQuantity: required,
+			PurchaseQuantity: priceData.PurchaseQuantity,
+			Price:            price,
+			QuotePrice:       priceData.Price,
+			QuoteCurrency:    priceData.Currency,
+			SupplierID:       priceData.SupplierID,
+			SupplierName:     priceData.SupplierName,
 		})
 	}
 	
-	// 5. 计算总成本
-	unitCost := materialCost + processCost
-	totalCost := unitCost * req.Quantity
-	
-	return &domain.CostResult{
+	// 3. 按核算参数计算各项成本
+	result := &domain.CostResult{
 		ProductID:    product.ID,
 		ProductName:  product.Name,
-		Quantity:     req.Quantity,
-		MaterialCost: materialCost,
-		ProcessCost:  processCost,
-		UnitCost:     unitCost,
-		TotalCost:    totalCost,
+		BOMVersionID: bom.ID,
+		BOMVersion:   bom.Version,
+		Quantity:     quantity,
+		Currency:     money.currency,
+		RateDate:     money.asOf.Format(currencyDomain.DateLayout),
 		Breakdown: &domain.CostBreakdown{
 			Materials: materialItems,
 			Processes: processItems,
 		},
-	}, nil
+	}
+	result.Apply(policy, yield)
+	return result, nil
+}
+
+// convert 将计入成本的单价（含起订量折算）换算为核算币种
+func (c *CostCalculator) convert(ctx context.Context, priceData *pricingDomain.PriceData, money valuation) (float64, error) {
+	return c.currencyService.Convert(ctx, priceData.UnitCost(), priceData.Currency, money.currency, money.asOf)
+}
+
+// uniqueIDs 去重并保持顺序
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	result := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
 }
\ No newline at end of file
@@ -0,0 +1,190 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	notificationApp "back/internal/notification/application"
+	notificationDomain "back/internal/notification/domain"
+	"back/internal/product/domain"
+	"back/internal/product/infra"
+	userDomain "back/internal/user/domain"
+)
+
+// CostAlertRoles 产品成本变动预警接收角色
+var CostAlertRoles = []string{userDomain.RoleSalesManager, userDomain.RoleSalesAssistant}
+
+// CostSnapshotService 产品成本快照服务（报价触发与每日快照、成本趋势、变动预警）
+type CostSnapshotService struct {
+	repo                *infra.ProductRepo
+	priceService        *ProductPriceService
+	notificationService *notificationApp.NotificationService
+	alertThreshold      float64
+}
+
+// NewCostSnapshotService 创建成本快照服务（alertThreshold 为预警阈值（%），0 表示不预警）
+func NewCostSnapshotService(
+	repo *infra.ProductRepo,
+	priceService *ProductPriceService,
+	notificationService *notificationApp.NotificationService,
+	alertThreshold float64,
+) *CostSnapshotService {
+	return &CostSnapshotService{
+		repo:                repo,
+		priceService:        priceService,
+		notificationService: notificationService,
+		alertThreshold:      alertThreshold,
+	}
+}
+
+// OnPriceQuoted 供应商报价后为用到该原料 / 工艺的产品生成快照（供 Pricing 模块调用）
+func (s *CostSnapshotService) OnPriceQuoted(ctx context.Context, targetType string, targetID uint) error {
+	products, err := s.repo.FindByComponent(ctx, targetType, targetID)
+	if err != nil {
+		return err
+	}
+
+	for _, product := range products {
+		// 配方不完整或缺少报价的产品无法核算，跳过
+		_, _ = s.snapshot(ctx, product, domain.SnapshotTriggerQuote, targetType, targetID)
+	}
+	return nil
+}
+
+// RunDailySnapshots 为所有产品生成每日快照
+func (s *CostSnapshotService) RunDailySnapshots(ctx context.Context) (*SnapshotRunResponse, error) {
+	products, err := s.repo.FindAll(ctx, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &SnapshotRunResponse{RunAt: time.Now(), Products: len(products)}
+	for _, product := range products {
+		snapshot, err := s.snapshot(ctx, product, domain.SnapshotTriggerDaily, "", 0)
+		if err != nil {
+			resp.Failed++
+			continue
+		}
+		resp.Snapshots++
+		if snapshot.Alerted {
+			resp.Alerts++
+		}
+	}
+	return resp, nil
+}
+
+// GetTrend 获取产品在时间范围内的成本趋势
+func (s *CostSnapshotService) GetTrend(ctx context.Context, productID uint, from, to time.Time) (*CostTrendResponse, error) {
+	product, err := s.repo.FindByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	snapshots, err := s.repo.FindCostSnapshots(ctx, productID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &CostTrendResponse{
+		ProductID:   product.ID,
+		ProductName: product.Name,
+		From:        from,
+		To:          to,
+		Points:      make([]*CostSnapshotResponse, len(snapshots)),
+	}
+	for i, snapshot := range snapshots {
+		resp.Points[i] = toCostSnapshotResponse(snapshot)
+		if i == 0 || snapshot.CurrentCost < resp.LowestCost {
+			resp.LowestCost = snapshot.CurrentCost
+		}
+		if snapshot.CurrentCost > resp.HighestCost {
+			resp.HighestCost = snapshot.CurrentCost
+		}
+	}
+	if n := len(snapshots); n > 0 {
+		resp.FirstCost = snapshots[0].CurrentCost
+		resp.LastCost = snapshots[n-1].CurrentCost
+		if resp.FirstCost > 0 {
+			resp.ChangePercent = (resp.LastCost - resp.FirstCost) / resp.FirstCost * 100
+		}
+	}
+	return resp, nil
+}
+
+// snapshot 核算产品当前成本并保存快照，已审批产品变动超过阈值时发送预警
+func (s *CostSnapshotService) snapshot(ctx context.Context, product *domain.Product, trigger, targetType string, targetID uint) (*domain.CostSnapshot, error) {
+	price, bom, err := s.priceService.priceOf(ctx, product)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := s.repo.FindLatestCostSnapshot(ctx, product.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &domain.CostSnapshot{
+		ProductID:    product.ID,
+		BOMVersionID: bom.ID,
+		Trigger:      trigger,
+		TargetType:   targetType,
+		TargetID:     targetID,
+		CurrentCost:  price.CurrentPrice,
+		MinCost:      price.HistoricalLow,
+		MaxCost:      price.HistoricalHigh,
+	}
+	baseline := 0.0
+	if previous != nil {
+		baseline = previous.NextBaseline()
+	}
+	exceeded := snapshot.Compare(baseline, s.alertThreshold)
+	snapshot.Alerted = exceeded && product.Status == domain.ProductStatusApproved
+
+	if err := s.repo.SaveCostSnapshot(ctx, snapshot); err != nil {
+		return nil, err
+	}
+
+	if snapshot.Alerted && s.notificationService != nil {
+		if err := s.notificationService.NotifyRoles(ctx, CostAlertRoles, costChangeMessage(product, snapshot)); err != nil {
+			return snapshot, err
+		}
+	}
+	return snapshot, nil
+}
+
+// costChangeMessage 构建成本变动预警通知
+func costChangeMessage(product *domain.Product, snapshot *domain.CostSnapshot) *notificationApp.Message {
+	direction := "上涨"
+	if snapshot.ChangePercent < 0 {
+		direction = "下降"
+	}
+	return &notificationApp.Message{
+		Type:  notificationDomain.TypeCostChange,
+		Level: notificationDomain.LevelWarning,
+		Title: fmt.Sprintf("产品成本%s：%s", direction, product.Name),
+		Content: fmt.Sprintf("%s 单位成本由 %.4f 变为 %.4f（%+.2f%%），请复核相关报价",
+			product.Name, snapshot.BaselineCost, snapshot.CurrentCost, snapshot.ChangePercent),
+		Payload: map[string]interface{}{
+			"productId":     product.ID,
+			"snapshotId":    snapshot.ID,
+			"baselineCost":  snapshot.BaselineCost,
+			"currentCost":   snapshot.CurrentCost,
+			"changePercent": snapshot.ChangePercent,
+		},
+	}
+}
+
+func toCostSnapshotResponse(snapshot *domain.CostSnapshot) *CostSnapshotResponse {
+	return &CostSnapshotResponse{
+		ID:            snapshot.ID,
+		BOMVersionID:  snapshot.BOMVersionID,
+		Trigger:       snapshot.Trigger,
+		TargetType:    snapshot.TargetType,
+		TargetID:      snapshot.TargetID,
+		CurrentCost:   snapshot.CurrentCost,
+		MinCost:       snapshot.MinCost,
+		MaxCost:       snapshot.MaxCost,
+		ChangePercent: snapshot.ChangePercent,
+		Alerted:       snapshot.Alerted,
+		CreatedAt:     snapshot.CreatedAt,
+	}
+}
@@ -13,23 +13,27 @@ type CreateProductRequest struct {
 	Processes []domain.ProcessConfig   `json:"processes" binding:"required,dive"`
 }
 
-// UpdateProductRequest 更新产品请求
+// UpdateProductRequest 更新产品请求（状态只能通过提交 / 审批 / 拒绝接口变更）
 type UpdateProductRequest struct {
 	Name      string                   `json:"name" binding:"omitempty,min=2,max=100"`
-	Status    string                   `json:"status" binding:"omitempty,oneof=draft submitted approved rejected"`
 	Materials []domain.MaterialConfig  `json:"materials" binding:"omitempty,dive"`
 	Processes []domain.ProcessConfig   `json:"processes" binding:"omitempty,dive"`
 }
 
 // ProductResponse 产品响应
 type ProductResponse struct {
-	ID        uint                     `json:"id"`
-	Name      string                   `json:"name"`
-	Status    string                   `json:"status"`
-	Materials []domain.MaterialConfig  `json:"materials"`
-	Processes []domain.ProcessConfig   `json:"processes"`
-	CreatedAt time.Time                `json:"created_at"`
-	UpdatedAt time.Time                `json:"updated_at"`
+	ID           uint                    `json:"id"`
+	Name         string                  `json:"name"`
+	Status       string                  `json:"status"`
+	Materials    []domain.MaterialConfig `json:"materials"`
+	Processes    []domain.ProcessConfig  `json:"processes"`
+	LeadTimeDays float64                 `json:"lead_time_days"` // 工艺路线标准周期（天）
+	Yield        float64                 `json:"yield"`          // 工艺路线总成品率
+	SubmittedBy  string                  `json:"submitted_by"`
+	ApprovalStep int                     `json:"approval_step"` // 已通过的审批级数
+	PendingRole  string                  `json:"pending_role"`  // 当前待审批角色（不限角色时为空）
+	CreatedAt    time.Time               `json:"created_at"`
+	UpdatedAt    time.Time               `json:"updated_at"`
 }
 
 // ProductListResponse 产品列表响应
@@ -40,7 +44,163 @@ type ProductListResponse struct {
 
 // CalculateCostRequest 计算成本请求
 type CalculateCostRequest struct {
-	ProductID   uint    `json:"product_id" binding:"required"`
-	Quantity    float64 `json:"quantity" binding:"required,gt=0"`
-	UseMinPrice bool    `json:"use_min_price"`
-}
\ No newline at end of file
+	ProductID   uint       `json:"product_id" binding:"required"`
+	Quantity    float64    `json:"quantity" binding:"required,gt=0"` // 订单 / 计划的成品数量，原料与工艺按实际需求量取阶梯价与起订量
+	UseMinPrice bool       `json:"use_min_price"`
+	Date        *time.Time `json:"date"`     // 按该日期生效的 BOM 版本与汇率计算（默认当前）
+	Currency    string     `json:"currency"` // 核算币种（默认本位币）
+	CostPolicyRequest
+}
+
+// CostPolicyRequest 成本核算参数（缩率 / 回修率为空时取产品历史订单，间接费用为空时取系统默认）
+type CostPolicyRequest struct {
+	Shrinkage  *float64              `json:"shrinkage" binding:"omitempty,gte=0,lt=95"`
+	ReworkRate *float64              `json:"rework_rate" binding:"omitempty,gte=0,lte=100"`
+	Overheads  []domain.OverheadRate `json:"overheads" binding:"omitempty,dive"`
+}
+
+// SimulateCostRequest 成本模拟请求（同一场景可应用到多个产品）
+type SimulateCostRequest struct {
+	ProductIDs  []uint                 `json:"product_ids" binding:"required,min=1,max=100"`
+	Quantity    float64                `json:"quantity" binding:"required,gt=0"` // 成品数量，取价方式同成本计算
+	UseMinPrice bool                   `json:"use_min_price"` // 基准价取最低价还是最高价
+	Date        *time.Time             `json:"date"`
+	Currency    string                 `json:"currency"` // 核算币种（默认本位币），指定单价的覆盖以该币种计
+	Prices      []domain.PriceOverride `json:"prices" binding:"omitempty,dive"`
+	Ratios      []domain.RatioOverride `json:"ratios" binding:"omitempty,dive"`
+	CostPolicyRequest
+}
+
+// CostComparison 单个产品的基准与场景成本对比
+type CostComparison struct {
+	ProductID           uint               `json:"product_id"`
+	ProductName         string             `json:"product_name"`
+	Baseline            *domain.CostResult `json:"baseline"`
+	Scenario            *domain.CostResult `json:"scenario"`
+	UnitCostDiff        float64            `json:"unit_cost_diff"`
+	UnitCostDiffPercent float64            `json:"unit_cost_diff_percent"`
+	TotalCostDiff       float64            `json:"total_cost_diff"`
+	Error               string             `json:"error,omitempty"` // 该产品无法计算时的原因
+}
+
+// CostSimulationResponse 成本模拟结果
+type CostSimulationResponse struct {
+	Quantity float64           `json:"quantity"`
+	Items    []*CostComparison `json:"items"`
+}
+// CreateBOMVersionRequest 新建 BOM 版本请求（原料或工艺为空时沿用当前生效的配方）
+type CreateBOMVersionRequest struct {
+	Materials []domain.MaterialConfig `json:"materials" binding:"omitempty,dive"`
+	Processes []domain.ProcessConfig  `json:"processes" binding:"omitempty,dive"`
+	Remark    string                  `json:"remark" binding:"omitempty,max=255"`
+}
+
+// UpdateBOMVersionRequest 更新 BOM 版本请求（状态只能通过提交 / 审批 / 拒绝接口变更）
+type UpdateBOMVersionRequest struct {
+	Materials []domain.MaterialConfig `json:"materials" binding:"omitempty,dive"`
+	Processes []domain.ProcessConfig  `json:"processes" binding:"omitempty,dive"`
+	Remark    *string                 `json:"remark" binding:"omitempty,max=255"`
+}
+
+// BOMApprovalRequest BOM 版本审批操作请求（审批通过时可指定生效日期，默认立即生效）
+type BOMApprovalRequest struct {
+	ApprovalActionRequest
+	EffectiveFrom *time.Time `json:"effective_from"`
+}
+
+// BOMVersionResponse BOM 版本响应（version 为 0 表示产品自身配方）
+type BOMVersionResponse struct {
+	ID            uint                    `json:"id"`
+	ProductID     uint                    `json:"product_id"`
+	Version       int                     `json:"version"`
+	Status        string                  `json:"status"`
+	Materials     []domain.MaterialConfig `json:"materials"`
+	Processes     []domain.ProcessConfig  `json:"processes"`
+	EffectiveFrom *time.Time              `json:"effective_from,omitempty"`
+	Remark        string                  `json:"remark"`
+	LeadTimeDays  float64                 `json:"lead_time_days"`
+	Yield         float64                 `json:"yield"`
+	CreatedBy     uint                    `json:"created_by"`
+	SubmittedBy   string                  `json:"submitted_by"`
+	ApprovalStep  int                     `json:"approval_step"`
+	PendingRole   string                  `json:"pending_role"`
+	ApprovedAt    *time.Time              `json:"approved_at,omitempty"`
+	CreatedAt     time.Time               `json:"created_at"`
+	UpdatedAt     time.Time               `json:"updated_at"`
+}
+
+// ApprovalActionRequest 审批操作请求
+type ApprovalActionRequest struct {
+	Comment string `json:"comment" binding:"omitempty,max=500"`
+	Reason  string `json:"reason" binding:"omitempty,max=500"` // 拒绝原因（拒绝时必填）
+}
+
+// ApprovalResponse 审批记录响应
+type ApprovalResponse struct {
+	ID           uint      `json:"id"`
+	ProductID    uint      `json:"product_id"`
+	BOMVersionID uint      `json:"bom_version_id"` // 0 表示产品本身
+	Action       string    `json:"action"`
+	Step         int       `json:"step"`
+	StepRole     string    `json:"step_role"`
+	FromStatus   string    `json:"from_status"`
+	ToStatus     string    `json:"to_status"`
+	ActorID      string    `json:"actor_id"`
+	ActorName    string    `json:"actor_name"`
+	ActorRole    string    `json:"actor_role"`
+	Comment      string    `json:"comment"`
+	Reason       string    `json:"reason,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// ApprovalInboxItem 待审批事项
+type ApprovalInboxItem struct {
+	Type         string    `json:"type"` // product / bom
+	ProductID    uint      `json:"product_id"`
+	ProductName  string    `json:"product_name"`
+	BOMVersionID uint      `json:"bom_version_id,omitempty"`
+	BOMVersion   int       `json:"bom_version,omitempty"`
+	Step         int       `json:"step"`   // 待处理的审批级别（从1开始）
+	Levels       int       `json:"levels"` // 审批总级数
+	StepRole     string    `json:"step_role"`
+	SubmittedBy  string    `json:"submitted_by"`
+	SubmittedAt  time.Time `json:"submitted_at"`
+}
+
+// CostSnapshotResponse 成本快照响应
+type CostSnapshotResponse struct {
+	ID            uint      `json:"id"`
+	BOMVersionID  uint      `json:"bom_version_id"`
+	Trigger       string    `json:"trigger"`               // quote / daily
+	TargetType    string    `json:"target_type,omitempty"` // 触发报价的目标类型
+	TargetID      uint      `json:"target_id,omitempty"`
+	CurrentCost   float64   `json:"current_cost"`
+	MinCost       float64   `json:"min_cost"`
+	MaxCost       float64   `json:"max_cost"`
+	ChangePercent float64   `json:"change_percent"` // 相对预警基准的变动（%）
+	Alerted       bool      `json:"alerted"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// CostTrendResponse 成本趋势响应
+type CostTrendResponse struct {
+	ProductID     uint                    `json:"product_id"`
+	ProductName   string                  `json:"product_name"`
+	From          time.Time               `json:"from"`
+	To            time.Time               `json:"to"`
+	FirstCost     float64                 `json:"first_cost"`
+	LastCost      float64                 `json:"last_cost"`
+	LowestCost    float64                 `json:"lowest_cost"`
+	HighestCost   float64                 `json:"highest_cost"`
+	ChangePercent float64                 `json:"change_percent"` // 区间首尾变动（%）
+	Points        []*CostSnapshotResponse `json:"points"`
+}
+
+// SnapshotRunResponse 每日快照执行结果
+type SnapshotRunResponse struct {
+	RunAt     time.Time `json:"run_at"`
+	Products  int       `json:"products"`
+	Snapshots int       `json:"snapshots"`
+	Alerts    int       `json:"alerts"`
+	Failed    int       `json:"failed"` // 配方不完整或缺少报价而无法核算的产品数
+}
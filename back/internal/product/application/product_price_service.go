@@ -5,31 +5,28 @@ import (
 
 	"back/internal/product/domain"
 	"back/internal/product/infra"
-	pricingApp "back/internal/pricing/application"
 )
 
 // ProductPriceService 产品价格服务
 type ProductPriceService struct {
-	productRepo      *infra.ProductRepo
-	materialPriceSvc *pricingApp.MaterialPriceService
-	processPriceSvc  *pricingApp.ProcessPriceService
+	productRepo *infra.ProductRepo
+	calculator  *CostCalculator
 }
 
-// NewProductPriceService 创建产品价格服务
+// NewProductPriceService 创建产品价格服务（与成本计算器共用核算）
 func NewProductPriceService(
 	productRepo *infra.ProductRepo,
-	materialPriceSvc *pricingApp.MaterialPriceService,
-	processPriceSvc *pricingApp.ProcessPriceService,
+	calculator *CostCalculator,
 ) *ProductPriceService {
 	return &ProductPriceService{
-		productRepo:      productRepo,
-		materialPriceSvc: materialPriceSvc,
-		processPriceSvc:  processPriceSvc,
+		productRepo: productRepo,
+		calculator:  calculator,
 	}
 }
 
-// ProductPriceResponse 产品价格响应
+// ProductPriceResponse 产品价格响应（每单位成品）
 type ProductPriceResponse struct {
+	Currency         string  `json:"currency"` // 本位币
 	CurrentPrice     float64 `json:"current_price"`
 	HistoricalHigh   float64 `json:"historical_high"`
 	HistoricalLow    float64 `json:"historical_low"`
@@ -43,107 +40,53 @@ func (s *ProductPriceService) GetPrice(ctx context.Context, productID uint) (*Pr
 		return nil, err
 	}
 
-	// 2. 验证产品配置
-	if err := product.Validate(); err != nil {
-		return nil, err
-	}
+	resp, _, err := s.priceOf(ctx, product)
+	return resp, err
+}
 
-	// 3. 计算当前价格
-	currentPrice, err := s.calculateCurrentPrice(ctx, product)
+// priceOf 按当前生效的 BOM 分别以最新报价、历史最高价、历史最低价核算单位成本（本位币，按当日汇率）
+func (s *ProductPriceService) priceOf(ctx context.Context, product *domain.Product) (*ProductPriceResponse, *domain.BOMVersion, error) {
+	// 1. 取当前生效的 BOM 并确定核算参数
+	bom, err := s.calculator.effectiveBOM(ctx, product.ID, nil)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-
-	// 4. 计算历史最高价
-	highPrice, err := s.calculateHighPrice(ctx, product)
+	policy, err := s.calculator.policyFor(ctx, product.ID, &CostPolicyRequest{})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-
-	// 5. 计算历史最低价
-	lowPrice, err := s.calculateLowPrice(ctx, product)
+	money, err := s.calculator.valuationFor("", nil)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return &ProductPriceResponse{
-		CurrentPrice:   currentPrice,
-		HistoricalHigh: highPrice,
-		HistoricalLow:  lowPrice,
-	}, nil
-}
-
-// calculateCurrentPrice 计算当前价格（基于最新报价）
-func (s *ProductPriceService) calculateCurrentPrice(ctx context.Context, product *domain.Product) (float64, error) {
-	// 计算材料成本
-	materialCost := 0.0
-	for _, m := range product.Materials {
-		priceData, err := s.materialPriceSvc.GetCurrentPrice(ctx, m.MaterialID)
-		if err != nil {
-			return 0, err
-		}
-		materialCost += priceData.Price * m.Ratio
-	}
-
-	// 计算工艺成本
-	processCost := 0.0
-	for _, p := range product.Processes {
-		priceData, err := s.processPriceSvc.GetCurrentPrice(ctx, p.ProcessID)
+	// 2. 分别核算
+	prices := make([]float64, 3)
+	for i, lookup := range []priceLookup{
+		s.calculator.currentPrice(),
+		s.calculator.historicalPrice(false),
+		s.calculator.historicalPrice(true),
+	} {
+		result, err := s.calculator.compute(ctx, product, bom, 1, lookup, policy, money)
 		if err != nil {
-			return 0, err
+			return nil, nil, err
 		}
-		processCost += priceData.Price
+		prices[i] = result.UnitCost
 	}
 
-	return materialCost + processCost, nil
-}
-
-// calculateHighPrice 计算历史最高价
-func (s *ProductPriceService) calculateHighPrice(ctx context.Context, product *domain.Product) (float64, error) {
-	// 计算材料最高成本
-	materialCost := 0.0
-	for _, m := range product.Materials {
-		priceData, err := s.materialPriceSvc.GetMaxPrice(ctx, m.MaterialID)
-		if err != nil {
-			return 0, err
-		}
-		materialCost += priceData.Price * m.Ratio
-	}
-
-	// 计算工艺最高成本
-	processCost := 0.0
-	for _, p := range product.Processes {
-		priceData, err := s.processPriceSvc.GetMaxPrice(ctx, p.ProcessID)
-		if err != nil {
-			return 0, err
-		}
-		processCost += priceData.Price
-	}
-
-	return materialCost + processCost, nil
+	return &ProductPriceResponse{
+		Currency:       money.currency,
+		CurrentPrice:   prices[0],
+		HistoricalHigh: prices[1],
+		HistoricalLow:  prices[2],
+	}, bom, nil
 }
 
-// calculateLowPrice 计算历史最低价
-func (s *ProductPriceService) calculateLowPrice(ctx context.Context, product *domain.Product) (float64, error) {
-	// 计算材料最低成本
-	materialCost := 0.0
-	for _, m := range product.Materials {
-		priceData, err := s.materialPriceSvc.GetMinPrice(ctx, m.MaterialID)
-		if err != nil {
-			return 0, err
-		}
-		materialCost += priceData.Price * m.Ratio
-	}
-
-	// 计算工艺最低成本
-	processCost := 0.0
-	for _, p := range product.Processes {
-		priceData, err := s.processPriceSvc.GetMinPrice(ctx, p.ProcessID)
-		if err != nil {
-			return 0, err
-		}
-		processCost += priceData.Price
+// routingFactors 工艺路线及各工序投入系数、总成品率（按损耗放大投入）
+func routingFactors(processes []domain.ProcessConfig) ([]domain.ProcessConfig, map[int]float64, float64, error) {
+	if err := domain.ValidateRouting(processes); err != nil {
+		return nil, nil, 0, err
 	}
-
-	return materialCost + processCost, nil
+	steps := domain.NormalizeRouting(processes)
+	return steps, domain.InputFactors(steps), domain.OverallYield(steps), nil
 }
@@ -2,6 +2,7 @@ package application
 
 import (
 	"context"
+	"sort"
 	"strconv"
 	
 	"back/internal/product/domain"
@@ -19,13 +20,15 @@ type ESSync interface {
 type ProductService struct {
 	repo   *infra.ProductRepo
 	esSync ESSync
+	chain  domain.ApprovalChain
 }
 
-// NewProductService 创建产品服务
-func NewProductService(repo *infra.ProductRepo, esSync ESSync) *ProductService {
+// NewProductService 创建产品服务（approvalChain 为各级审批角色，为空时一级审批）
+func NewProductService(repo *infra.ProductRepo, esSync ESSync, approvalChain []string) *ProductService {
 	return &ProductService{
 		repo:   repo,
 		esSync: esSync,
+		chain:  approvalChain,
 	}
 }
 
@@ -55,14 +58,20 @@ func (s *ProductService) Create(ctx context.Context, req *CreateProductRequest)
 	}
 
 	// 5. Domain Model → DTO
+	steps := product.Routing()
 	return &ProductResponse{
-		ID:        product.ID,
-		Name:      product.Name,
-		Status:    product.Status,
-		Materials: product.Materials,
-		Processes: product.Processes,
-		CreatedAt: product.CreatedAt,
-		UpdatedAt: product.UpdatedAt,
+		ID:           product.ID,
+		Name:         product.Name,
+		Status:       product.Status,
+		Materials:    product.Materials,
+		Processes:    product.Processes,
+		LeadTimeDays: domain.LeadTimeDays(steps),
+		Yield:        domain.OverallYield(steps),
+		SubmittedBy:  product.SubmittedBy,
+		ApprovalStep: product.ApprovalStep,
+		PendingRole:  domain.PendingRole(product.Status, product.ApprovalStep, s.chain),
+		CreatedAt:    product.CreatedAt,
+		UpdatedAt:    product.UpdatedAt,
 	}, nil
 }
 
@@ -73,14 +82,20 @@ func (s *ProductService) Get(ctx context.Context, id uint) (*ProductResponse, er
 		return nil, err
 	}
 
+	steps := product.Routing()
 	return &ProductResponse{
-		ID:        product.ID,
-		Name:      product.Name,
-		Status:    product.Status,
-		Materials: product.Materials,
-		Processes: product.Processes,
-		CreatedAt: product.CreatedAt,
-		UpdatedAt: product.UpdatedAt,
+		ID:           product.ID,
+		Name:         product.Name,
+		Status:       product.Status,
+		Materials:    product.Materials,
+		Processes:    product.Processes,
+		LeadTimeDays: domain.LeadTimeDays(steps),
+		Yield:        domain.OverallYield(steps),
+		SubmittedBy:  product.SubmittedBy,
+		ApprovalStep: product.ApprovalStep,
+		PendingRole:  domain.PendingRole(product.Status, product.ApprovalStep, s.chain),
+		CreatedAt:    product.CreatedAt,
+		UpdatedAt:    product.UpdatedAt,
 	}, nil
 }
 
@@ -111,31 +126,13 @@ func (s *ProductService) Update(ctx context.Context, id uint, req *UpdateProduct
 		}
 	}
 	
-	// 状态更新
-	if req.Status != "" {
-		switch req.Status {
-		case domain.ProductStatusSubmitted:
-			if err := product.Submit(); err != nil {
-				return err
-			}
-		case domain.ProductStatusApproved:
-			if err := product.Approve(); err != nil {
-				return err
-			}
-		case domain.ProductStatusRejected:
-			if err := product.Reject(); err != nil {
-				return err
-			}
-		}
-	}
-	
 	// 3. 验证
 	if err := product.Validate(); err != nil {
 		return err
 	}
 	
 	// 4. 保存
-	if err := s.repo.Update(ctx, product); err != nil {
+	if err := s.save(ctx, product, nil); err != nil {
 		return err
 	}
 	
@@ -147,6 +144,147 @@ func (s *ProductService) Update(ctx context.Context, id uint, req *UpdateProduct
 	return nil
 }
 
+// Transition 提交 / 审批 / 拒绝产品
+func (s *ProductService) Transition(ctx context.Context, id uint, status string, req *ApprovalActionRequest, actor domain.Approver) (*ApprovalResponse, error) {
+	product, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	approval, err := s.transition(product, status, actor, req.Comment, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.save(ctx, product, approval); err != nil {
+		return nil, err
+	}
+
+	if s.esSync != nil {
+		s.esSync.Update(product)
+	}
+	return toApprovalResponse(approval), nil
+}
+
+// GetApprovals 获取产品（含 BOM 版本）的审批历史
+func (s *ProductService) GetApprovals(ctx context.Context, productID uint) ([]*ApprovalResponse, error) {
+	if _, err := s.repo.FindByID(ctx, productID); err != nil {
+		return nil, err
+	}
+	approvals, err := s.repo.FindApprovals(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*ApprovalResponse, len(approvals))
+	for i, approval := range approvals {
+		result[i] = toApprovalResponse(approval)
+	}
+	return result, nil
+}
+
+// Inbox 审批人的待办：当前一级由其角色处理、且不是本人提交的产品与 BOM 版本
+func (s *ProductService) Inbox(ctx context.Context, actor domain.Approver) ([]*ApprovalInboxItem, error) {
+	products, err := s.repo.FindByStatus(ctx, domain.ProductStatusSubmitted, -1, 0)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := s.repo.FindSubmittedBOMVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []*ApprovalInboxItem{}
+	if actor.LoginID == "" {
+		return items, nil
+	}
+	for _, p := range products {
+		if p.SubmittedBy == actor.LoginID || !s.chain.CanAct(p.ApprovalStep, actor.Role) {
+			continue
+		}
+		items = append(items, &ApprovalInboxItem{
+			Type:        "product",
+			ProductID:   p.ID,
+			ProductName: p.Name,
+			Step:        p.ApprovalStep + 1,
+			Levels:      s.chain.Levels(),
+			StepRole:    s.chain.RoleAt(p.ApprovalStep),
+			SubmittedBy: p.SubmittedBy,
+			SubmittedAt: p.UpdatedAt,
+		})
+	}
+
+	productIDs := make([]uint, 0, len(versions))
+	for _, v := range versions {
+		productIDs = append(productIDs, v.ProductID)
+	}
+	owners, err := s.repo.FindByIDs(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[uint]string, len(owners))
+	for _, p := range owners {
+		names[p.ID] = p.Name
+	}
+	for _, v := range versions {
+		name, ok := names[v.ProductID]
+		if !ok || v.SubmittedBy == actor.LoginID || !s.chain.CanAct(v.ApprovalStep, actor.Role) {
+			continue
+		}
+		items = append(items, &ApprovalInboxItem{
+			Type:         "bom",
+			ProductID:    v.ProductID,
+			ProductName:  name,
+			BOMVersionID: v.ID,
+			BOMVersion:   v.Version,
+			Step:         v.ApprovalStep + 1,
+			Levels:       s.chain.Levels(),
+			StepRole:     s.chain.RoleAt(v.ApprovalStep),
+			SubmittedBy:  v.SubmittedBy,
+			SubmittedAt:  v.UpdatedAt,
+		})
+	}
+
+	// 先提交的排在前面
+	sort.SliceStable(items, func(i, j int) bool { return items[i].SubmittedAt.Before(items[j].SubmittedAt) })
+	return items, nil
+}
+
+// transition 按目标状态执行审批流转，status 为空时不变更
+func (s *ProductService) transition(product *domain.Product, status string, actor domain.Approver, comment, reason string) (*domain.ProductApproval, error) {
+	var approval *domain.ProductApproval
+	var err error
+	switch status {
+	case domain.ProductStatusSubmitted:
+		approval, err = product.Submit(actor)
+	case domain.ProductStatusApproved:
+		approval, err = product.Approve(actor, s.chain)
+	case domain.ProductStatusRejected:
+		approval, err = product.Reject(actor, s.chain, reason)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	approval.ProductID = product.ID
+	approval.Comment = comment
+	return approval, nil
+}
+
+// save 保存产品与审批记录
+func (s *ProductService) save(ctx context.Context, product *domain.Product, approval *domain.ProductApproval) error {
+	return s.repo.Transaction(ctx, func(txRepo *infra.ProductRepo) error {
+		if err := txRepo.Update(ctx, product); err != nil {
+			return err
+		}
+		if approval == nil {
+			return nil
+		}
+		return txRepo.SaveApproval(ctx, approval)
+	})
+}
+
 // Delete 删除产品
 func (s *ProductService) Delete(ctx context.Context, id uint) error {
 	// 1. 查询产品
@@ -176,4 +314,67 @@ func (s *ProductService) Delete(ctx context.Context, id uint) error {
 // Exists 检查产品是否存在（供其他模块调用）
 func (s *ProductService) Exists(ctx context.Context, id uint) (bool, error) {
 	return s.repo.ExistsByID(ctx, id)
-}
\ No newline at end of file
+}
+
+// GetRouting 获取指定 BOM 版本的工艺路线及各工序投入系数，bomVersionID 为 0 时取产品自身配方（供 plan 模块调用）
+func (s *ProductService) GetRouting(ctx context.Context, productID, bomVersionID uint) ([]*RoutingStep, error) {
+	bom, err := loadBOM(ctx, s.repo, productID, bomVersionID)
+	if err != nil {
+		return nil, err
+	}
+
+	steps := bom.Routing()
+	factors := domain.InputFactors(steps)
+	result := make([]*RoutingStep, len(steps))
+	for i, step := range steps {
+		result[i] = &RoutingStep{
+			ProcessID:    step.ProcessID,
+			Sequence:     step.Sequence,
+			Predecessors: step.Predecessors,
+			YieldLoss:    step.YieldLoss,
+			LeadTimeDays: step.LeadTimeDays,
+			Optional:     step.Optional,
+			InputFactor:  factors[step.Sequence],
+		}
+	}
+	return result, nil
+}
+
+// GetMaterials 获取指定 BOM 版本的原料配比，bomVersionID 为 0 时取产品自身配方（供 plan 模块调用）
+func (s *ProductService) GetMaterials(ctx context.Context, productID, bomVersionID uint) ([]domain.MaterialConfig, error) {
+	bom, err := loadBOM(ctx, s.repo, productID, bomVersionID)
+	if err != nil {
+		return nil, err
+	}
+	return bom.Materials, nil
+}
+
+// RoutingStep 工艺路线工序（供其他模块使用）
+type RoutingStep struct {
+	ProcessID    uint
+	Sequence     int
+	Predecessors []int
+	YieldLoss    float64 // 损耗率（%）
+	LeadTimeDays float64
+	Optional     bool
+	InputFactor  float64 // 每产出1单位成品该工序需投入的数量
+}
+
+func toApprovalResponse(approval *domain.ProductApproval) *ApprovalResponse {
+	return &ApprovalResponse{
+		ID:           approval.ID,
+		ProductID:    approval.ProductID,
+		BOMVersionID: approval.BOMVersionID,
+		Action:       approval.Action,
+		Step:         approval.Step,
+		StepRole:     approval.StepRole,
+		FromStatus:   approval.FromStatus,
+		ToStatus:     approval.ToStatus,
+		ActorID:      approval.ActorID,
+		ActorName:    approval.ActorName,
+		ActorRole:    approval.ActorRole,
+		Comment:      approval.Comment,
+		Reason:       approval.Reason,
+		CreatedAt:    approval.CreatedAt,
+	}
+}
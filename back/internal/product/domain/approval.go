@@ -0,0 +1,167 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// 审批动作
+const (
+	ApprovalActionSubmit  = "submit"  // 提交
+	ApprovalActionApprove = "approve" // 审批通过（多级审批时为通过当前一级）
+	ApprovalActionReject  = "reject"  // 拒绝
+)
+
+// RoleAdmin 管理员角色（可代任一级审批）
+const RoleAdmin = "admin"
+
+// Approver 审批操作人（以登录账号标识）
+type Approver struct {
+	LoginID string
+	Name    string
+	Role    string
+}
+
+// ApprovalChain 多级审批链：按顺序列出每一级审批人的角色，为空时任一有审批权限者一次审批即可
+type ApprovalChain []string
+
+// Levels 审批级数
+func (c ApprovalChain) Levels() int {
+	if len(c) == 0 {
+		return 1
+	}
+	return len(c)
+}
+
+// RoleAt 第 step 级（从0开始）的审批角色，空表示不限
+func (c ApprovalChain) RoleAt(step int) string {
+	if step < 0 || step >= len(c) {
+		return ""
+	}
+	return c[step]
+}
+
+// CanAct 角色是否可以处理第 step 级审批
+func (c ApprovalChain) CanAct(step int, role string) bool {
+	expected := c.RoleAt(step)
+	return expected == "" || expected == role || role == RoleAdmin
+}
+
+// ProductApproval 审批记录（产品或 BOM 版本的每次提交 / 审批 / 拒绝一条）
+type ProductApproval struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	ProductID    uint      `gorm:"not null;index" json:"productId"`
+	BOMVersionID uint      `gorm:"default:0;index" json:"bomVersionId"` // 0 表示产品本身
+	Action       string    `gorm:"size:20;not null" json:"action"`
+	Step         int       `gorm:"default:0" json:"step"`   // 审批级别（从1开始，提交为0）
+	StepRole     string    `gorm:"size:50" json:"stepRole"` // 该级要求的审批角色
+	FromStatus   string    `gorm:"size:20" json:"fromStatus"`
+	ToStatus     string    `gorm:"size:20" json:"toStatus"`
+	ActorID      string    `gorm:"size:50;not null;index" json:"actorId"` // 操作人登录账号
+	ActorName    string    `gorm:"size:100" json:"actorName"`
+	ActorRole    string    `gorm:"size:50" json:"actorRole"`
+	Comment      string    `gorm:"size:500" json:"comment"`
+	Reason       string    `gorm:"size:500" json:"reason"` // 拒绝原因
+	CreatedAt    time.Time `gorm:"autoCreateTime;index" json:"createdAt"`
+}
+
+// TableName 表名
+func (ProductApproval) TableName() string {
+	return "product_approvals"
+}
+
+// approvalFlow 审批流转（Product 与 BOMVersion 共用）
+type approvalFlow struct {
+	status      *string
+	submittedBy *string
+	step        *int
+}
+
+// submit 提交审批（草稿或被拒绝后重新提交）
+func (f approvalFlow) submit(actor Approver) (*ProductApproval, error) {
+	if *f.status != ProductStatusDraft && *f.status != ProductStatusRejected {
+		return nil, ErrCannotSubmit
+	}
+	if actor.LoginID == "" {
+		return nil, ErrApproverRequired
+	}
+
+	record := f.record(ApprovalActionSubmit, actor, 0, "")
+	*f.status = ProductStatusSubmitted
+	*f.submittedBy = actor.LoginID
+	*f.step = 0
+	record.ToStatus = *f.status
+	return record, nil
+}
+
+// approve 通过当前一级审批，最后一级通过后状态变为已审批
+func (f approvalFlow) approve(actor Approver, chain ApprovalChain) (*ProductApproval, error) {
+	if *f.status != ProductStatusSubmitted {
+		return nil, ErrCannotApprove
+	}
+	if err := f.checkApprover(actor, chain); err != nil {
+		return nil, err
+	}
+
+	record := f.record(ApprovalActionApprove, actor, *f.step+1, chain.RoleAt(*f.step))
+	*f.step++
+	if *f.step >= chain.Levels() {
+		*f.status = ProductStatusApproved
+	}
+	record.ToStatus = *f.status
+	return record, nil
+}
+
+// reject 拒绝（任一级均可拒绝，须填写原因）
+func (f approvalFlow) reject(actor Approver, chain ApprovalChain, reason string) (*ProductApproval, error) {
+	if *f.status != ProductStatusSubmitted {
+		return nil, ErrCannotReject
+	}
+	if strings.TrimSpace(reason) == "" {
+		return nil, ErrRejectReasonRequired
+	}
+	if err := f.checkApprover(actor, chain); err != nil {
+		return nil, err
+	}
+
+	record := f.record(ApprovalActionReject, actor, *f.step+1, chain.RoleAt(*f.step))
+	record.Reason = strings.TrimSpace(reason)
+	*f.status = ProductStatusRejected
+	*f.step = 0
+	record.ToStatus = *f.status
+	return record, nil
+}
+
+// checkApprover 禁止审批自己的提交，并校验当前一级的审批角色（无法识别的操作人不能审批）
+func (f approvalFlow) checkApprover(actor Approver, chain ApprovalChain) error {
+	if actor.LoginID == "" {
+		return ErrApproverRequired
+	}
+	if actor.LoginID == *f.submittedBy {
+		return ErrSelfApproval
+	}
+	if !chain.CanAct(*f.step, actor.Role) {
+		return ErrNotCurrentApprover
+	}
+	return nil
+}
+
+func (f approvalFlow) record(action string, actor Approver, step int, stepRole string) *ProductApproval {
+	return &ProductApproval{
+		Action:     action,
+		Step:       step,
+		StepRole:   stepRole,
+		FromStatus: *f.status,
+		ActorID:    actor.LoginID,
+		ActorName:  actor.Name,
+		ActorRole:  actor.Role,
+	}
+}
+
+// PendingRole 当前待审批的角色（非已提交状态或不限角色时为空）
+func PendingRole(status string, step int, chain ApprovalChain) string {
+	if status != ProductStatusSubmitted {
+		return ""
+	}
+	return chain.RoleAt(step)
+}
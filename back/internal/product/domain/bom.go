@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// BOMVersion 产品 BOM 版本（原料配比与工艺路线），审批通过后自生效日期起用于生产与成本计算
+type BOMVersion struct {
+	ID            uint               `gorm:"primaryKey" json:"id"`
+	ProductID     uint               `gorm:"not null;uniqueIndex:idx_product_bom_version" json:"productId"`
+	Version       int                `gorm:"not null;uniqueIndex:idx_product_bom_version" json:"version"`
+	Status        string             `gorm:"size:20;default:draft;index" json:"status"`
+	Materials     MaterialConfigJSON `gorm:"type:jsonb" json:"materials"`
+	Processes     ProcessConfigJSON  `gorm:"type:jsonb" json:"processes"`
+	EffectiveFrom *time.Time         `gorm:"type:timestamp;index" json:"effectiveFrom,omitempty"` // 生效日期（审批时确定）
+	Remark        string             `gorm:"size:255" json:"remark"`
+	CreatedBy     uint               `gorm:"index" json:"createdBy"`
+	SubmittedBy   string             `gorm:"size:50" json:"submittedBy"`    // 提交人登录账号
+	ApprovalStep  int                `gorm:"default:0" json:"approvalStep"` // 已通过的审批级数
+	ApprovedAt    *time.Time         `gorm:"type:timestamp" json:"approvedAt,omitempty"`
+	CreatedAt     time.Time          `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt     time.Time          `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+// TableName 表名
+func (BOMVersion) TableName() string {
+	return "product_bom_versions"
+}
+
+// BaseBOM 产品自身的配方（未建立 BOM 版本或均未生效时使用，版本号为0）
+func BaseBOM(p *Product) *BOMVersion {
+	return &BOMVersion{
+		ProductID: p.ID,
+		Status:    p.Status,
+		Materials: p.Materials,
+		Processes: p.Processes,
+	}
+}
+
+// Validate 验证 BOM 配方
+func (b *BOMVersion) Validate() error {
+	return validateRecipe(b.Materials, b.Processes)
+}
+
+// Routing 返回按序号排列的工艺路线
+func (b *BOMVersion) Routing() []ProcessConfig {
+	return NormalizeRouting(b.Processes)
+}
+
+// Submit 提交 BOM 版本审批（草稿或被拒绝后修改的版本）
+func (b *BOMVersion) Submit(actor Approver) (*ProductApproval, error) {
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+	if err := ValidateRouting(b.Processes); err != nil {
+		return nil, err
+	}
+
+	return b.flow().submit(actor)
+}
+
+// Approve 审批通过当前一级，最后一级通过时按 effectiveFrom 生效
+func (b *BOMVersion) Approve(actor Approver, chain ApprovalChain, effectiveFrom time.Time) (*ProductApproval, error) {
+	record, err := b.flow().approve(actor, chain)
+	if err != nil {
+		return nil, err
+	}
+
+	if b.Status == ProductStatusApproved {
+		now := time.Now()
+		b.EffectiveFrom = &effectiveFrom
+		b.ApprovedAt = &now
+	}
+	return record, nil
+}
+
+// Reject 拒绝（须填写原因）
+func (b *BOMVersion) Reject(actor Approver, chain ApprovalChain, reason string) (*ProductApproval, error) {
+	return b.flow().reject(actor, chain, reason)
+}
+
+func (b *BOMVersion) flow() approvalFlow {
+	return approvalFlow{status: &b.Status, submittedBy: &b.SubmittedBy, step: &b.ApprovalStep}
+}
+
+// UpdateRecipe 修改配方（已提交或已审批的版本不能修改）
+func (b *BOMVersion) UpdateRecipe(materials []MaterialConfig, processes []ProcessConfig) error {
+	if !b.IsEditable() {
+		return ErrCannotUpdateBOMVersion
+	}
+	if len(materials) > 0 {
+		b.Materials = materials
+	}
+	if len(processes) > 0 {
+		b.Processes = processes
+	}
+	return b.Validate()
+}
+
+// IsEditable 是否可修改（草稿或被拒绝）
+func (b *BOMVersion) IsEditable() bool {
+	return b.Status == ProductStatusDraft || b.Status == ProductStatusRejected
+}
+
+// validateRecipe 验证原料配比与工艺
+func validateRecipe(materials []MaterialConfig, processes []ProcessConfig) error {
+	if err := validateMaterials(materials); err != nil {
+		return err
+	}
+
+	if len(processes) == 0 {
+		return ErrProcessesRequired
+	}
+
+	return nil
+}
+
+// validateMaterials 校验原料配比
+func validateMaterials(materials []MaterialConfig) error {
+	if len(materials) == 0 {
+		return ErrMaterialsRequired
+	}
+
+	// 验证原料占比总和为1
+	sum := 0.0
+	for _, m := range materials {
+		if m.Ratio <= 0 {
+			return ErrInvalidMaterialRatio
+		}
+		sum += m.Ratio
+	}
+	if math.Abs(sum-1.0) > 0.0001 {
+		return ErrMaterialRatioSumNotOne
+	}
+	return nil
+}
@@ -1,36 +1,208 @@
 package domain
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxLossPercent 缩率与工序损耗率上限（%），与计划模块 MRP 一致
+const MaxLossPercent = 95.0
+
+// 成本加成项类型
+const (
+	CostAdderProcessQuantity = "process_quantity" // 工序数量倍数
+	CostAdderYieldLoss       = "yield_loss"       // 工艺路线损耗
+	CostAdderShrinkage       = "shrinkage"        // 历史缩率
+	CostAdderRework          = "rework"           // 预计回修
+	CostAdderOverhead        = "overhead"         // 间接费用分摊
+)
+
 // MaterialCostItem 原料成本明细
 type MaterialCostItem struct {
-	MaterialID   uint    `json:"material_id"`
-	MaterialName string  `json:"material_name"`
-	Ratio        float64 `json:"ratio"`
-	Price        float64 `json:"price"`
-	Cost         float64 `json:"cost"`
+	MaterialID       uint    `json:"material_id"`
+	MaterialName     string  `json:"material_name"`
+	Ratio            float64 `json:"ratio"`
+	RequiredQuantity float64 `json:"required_quantity"`           // 按成品数量、损耗与缩率计算的需求量
+	PurchaseQuantity float64 `json:"purchase_quantity,omitempty"` // 按起订量调整后的采购量
+	Price            float64 `json:"price"`                       // 换算为核算币种的单价（起订量多采部分折算到需求量）
+	QuotePrice       float64 `json:"quote_price"`                 // 报价原价（适用的阶梯单价）
+	QuoteCurrency    string  `json:"quote_currency"`              // 报价币种
+	SupplierID       uint    `json:"supplier_id"`                 // 价格来源供应商
+	SupplierName     string  `json:"supplier_name"`
+	InputFactor      float64 `json:"input_factor"` // 投入系数（按工艺路线总成品率与缩率放大）
+	Cost             float64 `json:"cost"`
 }
 
 // ProcessCostItem 工艺成本明细
 type ProcessCostItem struct {
-	ProcessID   uint    `json:"process_id"`
-	ProcessName string  `json:"process_name"`
-	Price       float64 `json:"price"`
-	Cost        float64 `json:"cost"`
+	ProcessID        uint    `json:"process_id"`
+	ProcessName      string  `json:"process_name"`
+	Sequence         int     `json:"sequence"`
+	Quantity         float64 `json:"quantity"`                    // 每单位投入的加工数量
+	YieldLoss        float64 `json:"yield_loss"`                  // 损耗率（%）
+	InputFactor      float64 `json:"input_factor"`                // 投入系数（本工序及后续工序损耗累积，含缩率）
+	RequiredQuantity float64 `json:"required_quantity"`           // 按成品数量、工序数量与损耗计算的加工量
+	PurchaseQuantity float64 `json:"purchase_quantity,omitempty"` // 按起订量调整后的采购量
+	Price            float64 `json:"price"`                       // 换算为核算币种的单价（起订量多采部分折算到需求量）
+	QuotePrice       float64 `json:"quote_price"`                 // 报价原价（适用的阶梯单价）
+	QuoteCurrency    string  `json:"quote_currency"`              // 报价币种
+	SupplierID       uint    `json:"supplier_id"`                 // 价格来源供应商
+	SupplierName     string  `json:"supplier_name"`
+	Cost             float64 `json:"cost"`
+}
+
+// CostAdder 成本加成项（单位成本上的增量）
+type CostAdder struct {
+	Type   string  `json:"type"`
+	Name   string  `json:"name,omitempty"`
+	Rate   float64 `json:"rate"` // 百分比
+	Amount float64 `json:"amount"`
 }
 
 // CostBreakdown 成本明细
 type CostBreakdown struct {
 	Materials []MaterialCostItem `json:"materials"`
 	Processes []ProcessCostItem  `json:"processes"`
+	Adders    []CostAdder        `json:"adders"`
+}
+
+// OverheadRate 间接费用分摊比例
+type OverheadRate struct {
+	Name    string  `json:"name" binding:"required,max=50"`
+	Percent float64 `json:"percent" binding:"gte=0,lte=100"` // 占直接成本（含回修）的百分比
 }
 
-// CostResult 成本计算结果
+// CostPolicy 成本核算参数
+type CostPolicy struct {
+	Shrinkage  float64        `json:"shrinkage"`   // 缩率（%），放大全部投入
+	ReworkRate float64        `json:"rework_rate"` // 预计回修率（%），按工艺成本加计回修费用
+	Overheads  []OverheadRate `json:"overheads"`
+}
+
+// Validate 校验核算参数
+func (p CostPolicy) Validate() error {
+	if p.Shrinkage < 0 || p.Shrinkage >= MaxLossPercent {
+		return fmt.Errorf("%w: shrinkage must be between 0 and %g", ErrInvalidCostPolicy, MaxLossPercent)
+	}
+	if p.ReworkRate < 0 || p.ReworkRate > 100 {
+		return fmt.Errorf("%w: rework rate must be between 0 and 100", ErrInvalidCostPolicy)
+	}
+	for _, o := range p.Overheads {
+		if o.Percent < 0 || o.Percent > 100 {
+			return fmt.Errorf("%w: overhead %q must be between 0 and 100", ErrInvalidCostPolicy, o.Name)
+		}
+	}
+	return nil
+}
+
+// ShrinkFactor 缩率放大系数
+func (p CostPolicy) ShrinkFactor() float64 {
+	return 1 / (1 - p.Shrinkage/100)
+}
+
+// ProcessQuantity 工序数量（未设置时为 1）
+func ProcessQuantity(quantity float64) float64 {
+	if quantity <= 0 {
+		return 1
+	}
+	return quantity
+}
+
+// ParseOverheadRates 解析 "名称:百分比" 形式的配置项，格式错误的项忽略
+func ParseOverheadRates(items []string) []OverheadRate {
+	var rates []OverheadRate
+	for _, item := range items {
+		name, value, ok := strings.Cut(item, ":")
+		if !ok {
+			continue
+		}
+		percent, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || percent < 0 {
+			continue
+		}
+		rates = append(rates, OverheadRate{Name: strings.TrimSpace(name), Percent: percent})
+	}
+	return rates
+}
+
+// CostResult 成本计算结果（单位成本为每单位成品）
 type CostResult struct {
 	ProductID    uint           `json:"product_id"`
 	ProductName  string         `json:"product_name"`
+	BOMVersionID uint           `json:"bom_version_id"` // 计算所用 BOM 版本（0 为产品自身配方）
+	BOMVersion   int            `json:"bom_version"`
 	Quantity     float64        `json:"quantity"`
-	MaterialCost float64        `json:"material_cost"`
+	Currency     string         `json:"currency"`  // 核算币种
+	RateDate     string         `json:"rate_date"` // 报价换算所用汇率日期
+	Policy       CostPolicy     `json:"policy"`
+	BaseCost     float64        `json:"base_cost"`     // 按单价与配比直接相加、未计任何加成的成本
+	MaterialCost float64        `json:"material_cost"` // 含工序数量、损耗与缩率
 	ProcessCost  float64        `json:"process_cost"`
+	ReworkCost   float64        `json:"rework_cost"`
+	OverheadCost float64        `json:"overhead_cost"`
+	Yield        float64        `json:"yield"` // 工艺路线总成品率
 	UnitCost     float64        `json:"unit_cost"`
 	TotalCost    float64        `json:"total_cost"`
 	Breakdown    *CostBreakdown `json:"breakdown"`
-}
\ No newline at end of file
+}
+
+// Apply 按核算参数计算各明细成本并汇总
+//
+// 调用前明细需填好单价、配比、工序数量和工艺路线投入系数。计算依次叠加
+// 工序数量 → 工艺损耗 → 缩率 → 回修 → 间接费用，每一步的增量记为一条加成项，
+// 因此 BaseCost 加上全部加成项等于 UnitCost。
+func (r *CostResult) Apply(policy CostPolicy, yield float64) {
+	shrinkFactor := policy.ShrinkFactor()
+	base, withQuantity, withYield := 0.0, 0.0, 0.0
+
+	r.MaterialCost = 0
+	for i := range r.Breakdown.Materials {
+		m := &r.Breakdown.Materials[i]
+		cost := m.Price * m.Ratio
+		base += cost
+		withQuantity += cost
+		withYield += cost / yield
+
+		m.InputFactor = shrinkFactor / yield
+		m.Cost = cost * m.InputFactor
+		r.MaterialCost += m.Cost
+	}
+
+	r.ProcessCost = 0
+	for i := range r.Breakdown.Processes {
+		p := &r.Breakdown.Processes[i]
+		p.Quantity = ProcessQuantity(p.Quantity)
+		base += p.Price
+		withQuantity += p.Price * p.Quantity
+		withYield += p.Price * p.Quantity * p.InputFactor
+
+		p.InputFactor *= shrinkFactor
+		p.Cost = p.Price * p.Quantity * p.InputFactor
+		r.ProcessCost += p.Cost
+	}
+
+	direct := r.MaterialCost + r.ProcessCost
+	r.ReworkCost = r.ProcessCost * policy.ReworkRate / 100
+	adders := []CostAdder{
+		{Type: CostAdderProcessQuantity, Amount: withQuantity - base},
+		{Type: CostAdderYieldLoss, Rate: (1 - yield) * 100, Amount: withYield - withQuantity},
+		{Type: CostAdderShrinkage, Rate: policy.Shrinkage, Amount: direct - withYield},
+		{Type: CostAdderRework, Rate: policy.ReworkRate, Amount: r.ReworkCost},
+	}
+
+	// 间接费用按直接成本（含回修）分摊
+	r.OverheadCost = 0
+	for _, o := range policy.Overheads {
+		amount := (direct + r.ReworkCost) * o.Percent / 100
+		r.OverheadCost += amount
+		adders = append(adders, CostAdder{Type: CostAdderOverhead, Name: o.Name, Rate: o.Percent, Amount: amount})
+	}
+
+	r.Policy = policy
+	r.Yield = yield
+	r.BaseCost = base
+	r.UnitCost = direct + r.ReworkCost + r.OverheadCost
+	r.TotalCost = r.UnitCost * r.Quantity
+	r.Breakdown.Adders = adders
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// 成本快照触发方式
+const (
+	SnapshotTriggerQuote = "quote" // 供应商报价
+	SnapshotTriggerDaily = "daily" // 每日定时
+)
+
+// CostSnapshot 产品单位成本快照
+type CostSnapshot struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	ProductID     uint      `gorm:"not null;index:idx_cost_snapshot_product_time" json:"productId"`
+	BOMVersionID  uint      `gorm:"default:0" json:"bomVersionId"`
+	Trigger       string    `gorm:"size:20;not null" json:"trigger"`
+	TargetType    string    `gorm:"size:20" json:"targetType,omitempty"` // 触发报价的目标类型（material / process）
+	TargetID      uint      `gorm:"default:0" json:"targetId,omitempty"`
+	CurrentCost   float64   `gorm:"type:decimal(12,4);not null" json:"currentCost"` // 按最新报价
+	MinCost       float64   `gorm:"type:decimal(12,4)" json:"minCost"`              // 按历史最低价
+	MaxCost       float64   `gorm:"type:decimal(12,4)" json:"maxCost"`              // 按历史最高价
+	BaselineCost  float64   `gorm:"type:decimal(12,4)" json:"baselineCost"`         // 预警比较的基准成本
+	ChangePercent float64   `gorm:"type:decimal(8,2)" json:"changePercent"`         // 相对基准的变动（%）
+	Alerted       bool      `gorm:"default:false" json:"alerted"`
+	CreatedAt     time.Time `gorm:"autoCreateTime;index:idx_cost_snapshot_product_time" json:"createdAt"`
+}
+
+// TableName 表名
+func (CostSnapshot) TableName() string {
+	return "product_cost_snapshots"
+}
+
+// NextBaseline 下一次快照的比较基准：预警后以本次成本为新基准，否则沿用原基准
+func (s *CostSnapshot) NextBaseline() float64 {
+	if s.Alerted || s.BaselineCost <= 0 {
+		return s.CurrentCost
+	}
+	return s.BaselineCost
+}
+
+// Compare 与基准比较并计算变动比例，返回是否超过预警阈值（%）
+func (s *CostSnapshot) Compare(baseline, threshold float64) bool {
+	s.BaselineCost = baseline
+	if baseline <= 0 {
+		s.BaselineCost = s.CurrentCost
+		return false
+	}
+	s.ChangePercent = (s.CurrentCost - baseline) / baseline * 100
+	return threshold > 0 && math.Abs(s.ChangePercent) > threshold
+}
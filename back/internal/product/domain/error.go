@@ -1,6 +1,9 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	// 基础校验
@@ -19,10 +22,34 @@ var (
 	// 工艺数量
 	ErrInvalidProcessQuantity = errors.New("process quantity must be greater than 0")
 
+	// 工艺路线
+	ErrInvalidRouting        = errors.New("invalid routing")
+	ErrInvalidSequence       = fmt.Errorf("%w: sequences must be numbered 1..n without gaps or duplicates", ErrInvalidRouting)
+	ErrInvalidPredecessor    = fmt.Errorf("%w: predecessors must reference an earlier step", ErrInvalidRouting)
+	ErrInvalidYieldLoss      = fmt.Errorf("%w: yield loss must be a percentage between 0 and %g", ErrInvalidRouting, MaxLossPercent)
+	ErrInvalidLeadTime       = fmt.Errorf("%w: lead time cannot be negative", ErrInvalidRouting)
+	ErrFirstStepOptional     = fmt.Errorf("%w: the first step cannot be optional", ErrInvalidRouting)
+
+	// BOM 版本
+	ErrBOMVersionNotFound     = errors.New("bom version not found")
+	ErrCannotUpdateBOMVersion = errors.New("can only modify draft or rejected bom versions")
+	ErrCannotDeleteBOMVersion = errors.New("cannot delete approved or submitted bom versions")
+
+	// 成本核算与模拟
+	ErrInvalidCostPolicy = errors.New("invalid cost policy")
+	ErrInvalidOverride   = errors.New("invalid price override")
+
+	// 审批
+	ErrSelfApproval         = errors.New("cannot approve or reject your own submission")
+	ErrNotCurrentApprover   = errors.New("approver role does not match the current approval step")
+	ErrApproverRequired     = errors.New("approver identity is required")
+	ErrRejectReasonRequired = errors.New("a reason is required when rejecting")
+
 	// 状态流转
-	ErrCannotSubmit                 = errors.New("can only submit draft products")
+	ErrCannotSubmit                 = errors.New("can only submit draft or rejected products")
 	ErrCannotApprove                = errors.New("can only approve submitted products")
 	ErrCannotReject                 = errors.New("can only reject submitted products")
+	ErrInvalidTargetStatus          = errors.New("invalid target status")
 	ErrCannotUpdateApprovedProduct  = errors.New("cannot update approved products")
 	ErrCannotDeleteApprovedProduct  = errors.New("cannot delete approved products")
 )
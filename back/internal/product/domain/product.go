@@ -24,10 +24,15 @@ type MaterialConfig struct {
 	Ratio      float64 `json:"ratio"` // 占比（总和必须为1）
 }
 
-// ProcessConfig 工艺配置
+// ProcessConfig 工艺配置（工艺路线中的一道工序）
 type ProcessConfig struct {
-	ProcessID uint    `json:"process_id"`
-	Quantity  float64 `json:"quantity"` // 数量（可选）
+	ProcessID    uint    `json:"process_id"`
+	Quantity     float64 `json:"quantity"`               // 数量（可选）
+	Sequence     int     `json:"sequence"`               // 工序序号（从1开始连续编号；全为0时按列表顺序）
+	Predecessors []int   `json:"predecessors,omitempty"` // 前道工序序号（为空时接在上一序号之后）
+	YieldLoss    float64 `json:"yield_loss"`             // 损耗率（%）
+	LeadTimeDays float64 `json:"lead_time_days"`         // 标准周期（天）
+	Optional     bool    `json:"optional"`               // 是否可选工序
 }
 
 // MaterialConfigJSON 原料配置 JSON（用于 GORM JSONB）
@@ -72,14 +77,16 @@ func (p ProcessConfigJSON) Value() (driver.Value, error) {
 
 // Product 产品聚合根
 type Product struct {
-	ID        uint               `gorm:"primaryKey" json:"id"`
-	Name      string             `gorm:"size:100;not null;index" json:"name"`
-	Status    string             `gorm:"size:20;default:draft;index" json:"status"`
-	Materials MaterialConfigJSON `gorm:"type:jsonb" json:"materials"`
-	Processes ProcessConfigJSON  `gorm:"type:jsonb" json:"processes"`
-	CreatedAt time.Time          `gorm:"autoCreateTime" json:"createdAt"`
-	UpdatedAt time.Time          `gorm:"autoUpdateTime" json:"updatedAt"`
-	DeletedAt gorm.DeletedAt     `gorm:"index" json:"-"`
+	ID           uint               `gorm:"primaryKey" json:"id"`
+	Name         string             `gorm:"size:100;not null;index" json:"name"`
+	Status       string             `gorm:"size:20;default:draft;index" json:"status"`
+	Materials    MaterialConfigJSON `gorm:"type:jsonb" json:"materials"`
+	Processes    ProcessConfigJSON  `gorm:"type:jsonb" json:"processes"`
+	SubmittedBy  string             `gorm:"size:50" json:"submittedBy"`    // 提交人登录账号
+	ApprovalStep int                `gorm:"default:0" json:"approvalStep"` // 已通过的审批级数
+	CreatedAt    time.Time          `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt    time.Time          `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt    gorm.DeletedAt     `gorm:"index" json:"-"`
 }
 
 // TableName 表名
@@ -97,62 +104,34 @@ func (p *Product) Validate() error {
 		return ErrProductNameInvalid
 	}
 	
-	if len(p.Materials) == 0 {
-		return ErrMaterialsRequired
-	}
-	
-	// 验证原料占比总和为1
-	sum := 0.0
-	for _, m := range p.Materials {
-		if m.Ratio <= 0 {
-			return ErrInvalidMaterialRatio
-		}
-		sum += m.Ratio
-	}
-	if math.Abs(sum-1.0) > 0.0001 {
-		return ErrMaterialRatioSumNotOne
-	}
-	
-	if len(p.Processes) == 0 {
-		return ErrProcessesRequired
-	}
-	
-	return nil
+	return validateRecipe(p.Materials, p.Processes)
 }
 
-// Submit 提交产品（从草稿变为已提交）
-func (p *Product) Submit() error {
-	if p.Status != ProductStatusDraft {
-		return ErrCannotSubmit
-	}
-
+// Submit 提交产品审批（草稿或被拒绝的产品）
+func (p *Product) Submit(actor Approver) (*ProductApproval, error) {
 	// 提交前验证
 	if err := p.Validate(); err != nil {
-		return err
+		return nil, err
+	}
+	if err := p.ValidateRouting(); err != nil {
+		return nil, err
 	}
 
-	p.Status = ProductStatusSubmitted
-	return nil
+	return p.flow().submit(actor)
 }
 
-// Approve 审批通过
-func (p *Product) Approve() error {
-	if p.Status != ProductStatusSubmitted {
-		return ErrCannotApprove
-	}
-
-	p.Status = ProductStatusApproved
-	return nil
+// Approve 审批通过当前一级（不能审批自己的提交）
+func (p *Product) Approve(actor Approver, chain ApprovalChain) (*ProductApproval, error) {
+	return p.flow().approve(actor, chain)
 }
 
-// Reject 拒绝
-func (p *Product) Reject() error {
-	if p.Status != ProductStatusSubmitted {
-		return ErrCannotReject
-	}
+// Reject 拒绝（须填写原因）
+func (p *Product) Reject(actor Approver, chain ApprovalChain, reason string) (*ProductApproval, error) {
+	return p.flow().reject(actor, chain, reason)
+}
 
-	p.Status = ProductStatusRejected
-	return nil
+func (p *Product) flow() approvalFlow {
+	return approvalFlow{status: &p.Status, submittedBy: &p.SubmittedBy, step: &p.ApprovalStep}
 }
 
 // UpdateName 更新名称
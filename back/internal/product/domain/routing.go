@@ -0,0 +1,126 @@
+package domain
+
+import "sort"
+
+// Routing 返回按序号排列的工艺路线
+func (p *Product) Routing() []ProcessConfig {
+	return NormalizeRouting(p.Processes)
+}
+
+// ValidateRouting 校验工艺路线
+func (p *Product) ValidateRouting() error {
+	return ValidateRouting(p.Processes)
+}
+
+// NormalizeRouting 按序号排列工艺路线：序号全为0的旧数据按列表顺序编号，未配置前道工序的接在上一序号之后
+func NormalizeRouting(processes []ProcessConfig) []ProcessConfig {
+	steps := make([]ProcessConfig, len(processes))
+	copy(steps, processes)
+
+	numbered := false
+	for _, step := range steps {
+		if step.Sequence != 0 {
+			numbered = true
+			break
+		}
+	}
+	if !numbered {
+		for i := range steps {
+			steps[i].Sequence = i + 1
+		}
+	}
+	sort.SliceStable(steps, func(i, j int) bool { return steps[i].Sequence < steps[j].Sequence })
+
+	for i := range steps {
+		if len(steps[i].Predecessors) == 0 && steps[i].Sequence > 1 {
+			steps[i].Predecessors = []int{steps[i].Sequence - 1}
+		}
+	}
+	return steps
+}
+
+// ValidateRouting 校验工艺路线：序号 1..n 连续不重复，前道工序必须是更小的序号（保证无环），损耗率与周期合法
+func ValidateRouting(processes []ProcessConfig) error {
+	steps := NormalizeRouting(processes)
+	for i, step := range steps {
+		if step.Sequence != i+1 {
+			return ErrInvalidSequence
+		}
+		for _, pred := range step.Predecessors {
+			if pred < 1 || pred >= step.Sequence {
+				return ErrInvalidPredecessor
+			}
+		}
+		if step.YieldLoss < 0 || step.YieldLoss >= MaxLossPercent {
+			return ErrInvalidYieldLoss
+		}
+		if step.LeadTimeDays < 0 {
+			return ErrInvalidLeadTime
+		}
+	}
+	if len(steps) > 0 && steps[0].Optional {
+		return ErrFirstStepOptional
+	}
+	return nil
+}
+
+// InputFactors 各工序的投入系数：每产出1单位成品，该工序需投入的数量（本工序及其后续工序的损耗逐级累积）
+func InputFactors(steps []ProcessConfig) map[int]float64 {
+	successors := make(map[int][]int, len(steps))
+	loss := make(map[int]float64, len(steps))
+	for _, step := range steps {
+		loss[step.Sequence] = step.YieldLoss
+		for _, pred := range step.Predecessors {
+			successors[pred] = append(successors[pred], step.Sequence)
+		}
+	}
+
+	// 前道工序序号更小，倒序即可先得到后续工序的下游集合
+	downstream := make(map[int]map[int]bool, len(steps))
+	factors := make(map[int]float64, len(steps))
+	for i := len(steps) - 1; i >= 0; i-- {
+		seq := steps[i].Sequence
+		set := map[int]bool{seq: true}
+		for _, succ := range successors[seq] {
+			for s := range downstream[succ] {
+				set[s] = true
+			}
+		}
+		downstream[seq] = set
+
+		yield := 1.0
+		for s := range set {
+			yield *= 1 - loss[s]/100
+		}
+		factors[seq] = 1 / yield
+	}
+	return factors
+}
+
+// OverallYield 工艺路线总成品率（各工序损耗累积）
+func OverallYield(steps []ProcessConfig) float64 {
+	yield := 1.0
+	for _, step := range steps {
+		yield *= 1 - step.YieldLoss/100
+	}
+	return yield
+}
+
+// LeadTimeDays 工艺路线标准周期（关键路径上各工序周期之和）
+func LeadTimeDays(steps []ProcessConfig) float64 {
+	finish := make(map[int]float64, len(steps))
+	total := 0.0
+	for _, step := range steps {
+		start := 0.0
+		for _, pred := range step.Predecessors {
+			if finish[pred] > start {
+				start = finish[pred]
+			}
+		}
+		finish[step.Sequence] = start + step.LeadTimeDays
+		if finish[step.Sequence] > total {
+			total = finish[step.Sequence]
+		}
+	}
+	return total
+}
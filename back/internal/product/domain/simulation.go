@@ -0,0 +1,132 @@
+package domain
+
+import "fmt"
+
+// 价格覆盖的目标类型
+const (
+	OverrideTargetMaterial = "material"
+	OverrideTargetProcess  = "process"
+)
+
+// 价格覆盖方式
+const (
+	OverrideModeAbsolute = "absolute" // 指定单价
+	OverrideModePercent  = "percent"  // 在基准价上涨跌百分比（8 表示 +8%）
+	OverrideModeSupplier = "supplier" // 使用指定供应商的最新报价
+)
+
+// PriceOverride 模拟场景中的价格覆盖
+type PriceOverride struct {
+	TargetType string  `json:"target_type" binding:"required,oneof=material process"`
+	TargetID   uint    `json:"target_id" binding:"required"`
+	Mode       string  `json:"mode" binding:"required,oneof=absolute percent supplier"`
+	Value      float64 `json:"value"`                 // absolute 为单价，percent 为百分比
+	SupplierID uint    `json:"supplier_id,omitempty"` // supplier 方式必填
+}
+
+// Validate 校验覆盖项
+func (o PriceOverride) Validate() error {
+	switch o.Mode {
+	case OverrideModeAbsolute:
+		if o.Value <= 0 {
+			return fmt.Errorf("%w: absolute price must be greater than 0", ErrInvalidOverride)
+		}
+	case OverrideModePercent:
+		if o.Value <= -100 {
+			return fmt.Errorf("%w: percent change must be greater than -100", ErrInvalidOverride)
+		}
+	case OverrideModeSupplier:
+		if o.SupplierID == 0 {
+			return fmt.Errorf("%w: supplier_id is required for supplier overrides", ErrInvalidOverride)
+		}
+	default:
+		return fmt.Errorf("%w: unknown mode %q", ErrInvalidOverride, o.Mode)
+	}
+	return nil
+}
+
+// Apply 在基准价上应用覆盖（supplier 方式由调用方取报价，不经过此处）
+func (o PriceOverride) Apply(base float64) float64 {
+	switch o.Mode {
+	case OverrideModeAbsolute:
+		return o.Value
+	case OverrideModePercent:
+		return base * (1 + o.Value/100)
+	}
+	return base
+}
+
+// RatioOverride 模拟场景中的原料占比调整
+type RatioOverride struct {
+	ProductID  uint    `json:"product_id,omitempty"` // 0 表示对所有包含该原料的产品生效
+	MaterialID uint    `json:"material_id" binding:"required"`
+	Ratio      float64 `json:"ratio" binding:"gte=0,lte=1"` // 0 表示去掉该原料
+}
+
+// CostScenario 成本模拟场景
+type CostScenario struct {
+	Prices []PriceOverride `json:"prices"`
+	Ratios []RatioOverride `json:"ratios"`
+}
+
+// Validate 校验场景
+func (s *CostScenario) Validate() error {
+	for _, o := range s.Prices {
+		if err := o.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PriceOverride 查找某原料 / 工艺的价格覆盖
+func (s *CostScenario) PriceOverride(targetType string, targetID uint) (PriceOverride, bool) {
+	for _, o := range s.Prices {
+		if o.TargetType == targetType && o.TargetID == targetID {
+			return o, true
+		}
+	}
+	return PriceOverride{}, false
+}
+
+// ApplyRatios 按场景调整产品的原料配比，返回新的配比（不修改原切片）
+//
+// 指定产品的调整可以引入 BOM 中没有的原料；对所有产品生效的调整只改已有原料。
+// 调整后的占比合计仍须为 1。
+func (s *CostScenario) ApplyRatios(productID uint, materials []MaterialConfig) ([]MaterialConfig, error) {
+	ratios := make(map[uint]float64, len(materials))
+	order := make([]uint, 0, len(materials))
+	for _, m := range materials {
+		ratios[m.MaterialID] = m.Ratio
+		order = append(order, m.MaterialID)
+	}
+
+	changed := false
+	for _, o := range s.Ratios {
+		if o.ProductID != 0 && o.ProductID != productID {
+			continue
+		}
+		if _, ok := ratios[o.MaterialID]; !ok {
+			if o.ProductID == 0 {
+				continue
+			}
+			order = append(order, o.MaterialID)
+		}
+		ratios[o.MaterialID] = o.Ratio
+		changed = true
+	}
+	if !changed {
+		return materials, nil
+	}
+
+	result := make([]MaterialConfig, 0, len(order))
+	for _, id := range order {
+		if ratios[id] > 0 {
+			result = append(result, MaterialConfig{MaterialID: id, Ratio: ratios[id]})
+		}
+	}
+	if err := validateMaterials(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -3,8 +3,11 @@ package infra
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"back/internal/product/domain"
 	"back/pkg/repo"
@@ -24,6 +27,13 @@ func NewProductRepo(db *gorm.DB) *ProductRepo {
 	}
 }
 
+// Transaction 在事务中执行
+func (r *ProductRepo) Transaction(ctx context.Context, fn func(txRepo *ProductRepo) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(NewProductRepo(tx))
+	})
+}
+
 // Save 保存产品
 func (r *ProductRepo) Save(ctx context.Context, product *domain.Product) error {
 	return r.Create(ctx, product)
@@ -98,3 +108,171 @@ func (r *ProductRepo) FindByStatus(ctx context.Context, status string, limit, of
 func (r *ProductRepo) Count(ctx context.Context) (int64, error) {
 	return r.Repo.Count(ctx, map[string]interface{}{})
 }
+
+// ==================== BOM 版本 ====================
+
+// SaveBOMVersion 保存 BOM 版本
+func (r *ProductRepo) SaveBOMVersion(ctx context.Context, bom *domain.BOMVersion) error {
+	return r.db.WithContext(ctx).Save(bom).Error
+}
+
+// FindBOMVersionByID 根据 ID 查询 BOM 版本
+func (r *ProductRepo) FindBOMVersionByID(ctx context.Context, id uint) (*domain.BOMVersion, error) {
+	var bom domain.BOMVersion
+	err := r.db.WithContext(ctx).First(&bom, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrBOMVersionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &bom, nil
+}
+
+// FindBOMVersions 查询产品的所有 BOM 版本（新版本在前）
+func (r *ProductRepo) FindBOMVersions(ctx context.Context, productID uint) ([]*domain.BOMVersion, error) {
+	var result []*domain.BOMVersion
+	err := r.db.WithContext(ctx).
+		Where("product_id = ?", productID).
+		Order("version DESC").
+		Find(&result).Error
+	return result, err
+}
+
+// LockProduct 锁定产品行（在事务中串行化同一产品的 BOM 版本号分配）
+func (r *ProductRepo) LockProduct(ctx context.Context, productID uint) error {
+	var product domain.Product
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		First(&product, productID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.ErrProductNotFound
+	}
+	return err
+}
+
+// NextBOMVersion 产品的下一个 BOM 版本号
+func (r *ProductRepo) NextBOMVersion(ctx context.Context, productID uint) (int, error) {
+	var maxVersion int
+	err := r.db.WithContext(ctx).
+		Model(&domain.BOMVersion{}).
+		Where("product_id = ?", productID).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&maxVersion).Error
+	return maxVersion + 1, err
+}
+
+// FindEffectiveBOMVersion 查询某时间点生效的 BOM 版本（已审批且生效日期最晚的一个），不存在时返回 nil
+func (r *ProductRepo) FindEffectiveBOMVersion(ctx context.Context, productID uint, at time.Time) (*domain.BOMVersion, error) {
+	var bom domain.BOMVersion
+	err := r.db.WithContext(ctx).
+		Where("product_id = ? AND status = ? AND effective_from <= ?", productID, domain.ProductStatusApproved, at).
+		Order("effective_from DESC, version DESC").
+		First(&bom).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &bom, nil
+}
+
+// DeleteBOMVersion 删除 BOM 版本
+func (r *ProductRepo) DeleteBOMVersion(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&domain.BOMVersion{}, id).Error
+}
+
+// FindSubmittedBOMVersions 查询待审批的 BOM 版本
+func (r *ProductRepo) FindSubmittedBOMVersions(ctx context.Context) ([]*domain.BOMVersion, error) {
+	var result []*domain.BOMVersion
+	err := r.db.WithContext(ctx).
+		Where("status = ?", domain.ProductStatusSubmitted).
+		Order("updated_at ASC").
+		Find(&result).Error
+	return result, err
+}
+
+// FindByIDs 根据 ID 批量查询产品
+func (r *ProductRepo) FindByIDs(ctx context.Context, ids []uint) ([]*domain.Product, error) {
+	var result []*domain.Product
+	if len(ids) == 0 {
+		return result, nil
+	}
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&result).Error
+	return result, err
+}
+
+// ==================== 审批记录 ====================
+
+// SaveApproval 保存审批记录
+func (r *ProductRepo) SaveApproval(ctx context.Context, approval *domain.ProductApproval) error {
+	return r.db.WithContext(ctx).Create(approval).Error
+}
+
+// FindApprovals 查询产品（含其 BOM 版本）的审批记录（新记录在前）
+func (r *ProductRepo) FindApprovals(ctx context.Context, productID uint) ([]*domain.ProductApproval, error) {
+	var result []*domain.ProductApproval
+	err := r.db.WithContext(ctx).
+		Where("product_id = ?", productID).
+		Order("created_at DESC, id DESC").
+		Find(&result).Error
+	return result, err
+}
+
+// ==================== 成本快照 ====================
+
+// FindByComponent 查询配方（产品自身或已审批 BOM 版本）中用到某原料 / 工艺的产品（配方不包含的报价目标类型返回空）
+func (r *ProductRepo) FindByComponent(ctx context.Context, targetType string, targetID uint) ([]*domain.Product, error) {
+	var column, key string
+	switch targetType {
+	case domain.OverrideTargetMaterial:
+		column, key = "materials", "material_id"
+	case domain.OverrideTargetProcess:
+		column, key = "processes", "process_id"
+	default:
+		return nil, nil
+	}
+	contains := fmt.Sprintf(`[{"%s": %d}]`, key, targetID)
+
+	var result []*domain.Product
+	err := r.db.WithContext(ctx).
+		Where(column+" @> ?::jsonb OR id IN (?)", contains,
+			r.db.Model(&domain.BOMVersion{}).
+				Select("product_id").
+				Where("status = ? AND "+column+" @> ?::jsonb", domain.ProductStatusApproved, contains)).
+		Find(&result).Error
+	return result, err
+}
+
+// SaveCostSnapshot 保存成本快照
+func (r *ProductRepo) SaveCostSnapshot(ctx context.Context, snapshot *domain.CostSnapshot) error {
+	return r.db.WithContext(ctx).Create(snapshot).Error
+}
+
+// FindLatestCostSnapshot 查询产品最近一次成本快照，不存在时返回 nil
+func (r *ProductRepo) FindLatestCostSnapshot(ctx context.Context, productID uint) (*domain.CostSnapshot, error) {
+	var snapshot domain.CostSnapshot
+	err := r.db.WithContext(ctx).
+		Where("product_id = ?", productID).
+		Order("created_at DESC, id DESC").
+		First(&snapshot).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// FindCostSnapshots 查询产品在时间范围内的成本快照（按时间升序）
+func (r *ProductRepo) FindCostSnapshots(ctx context.Context, productID uint, from, to time.Time) ([]*domain.CostSnapshot, error) {
+	var result []*domain.CostSnapshot
+	err := r.db.WithContext(ctx).
+		Where("product_id = ? AND created_at >= ? AND created_at < ?", productID, from, to).
+		Order("created_at ASC, id ASC").
+		Find(&result).Error
+	return result, err
+}
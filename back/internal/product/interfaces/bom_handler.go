@@ -0,0 +1,317 @@
+package interfaces
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"back/internal/product/application"
+	"back/internal/product/domain"
+	"back/pkg/audit"
+	"back/pkg/endpoint"
+)
+
+// BOMHandler 产品 BOM 版本 Handler
+type BOMHandler struct {
+	service *application.BOMService
+}
+
+// NewBOMHandler 创建 Handler
+func NewBOMHandler(service *application.BOMService) *BOMHandler {
+	return &BOMHandler{service: service}
+}
+
+// List 获取产品 BOM 版本列表
+// @Summary      获取产品 BOM 版本列表
+// @Description  获取产品的所有 BOM 版本（新版本在前）
+// @Tags         产品管理
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "产品ID"
+// @Success      200 {array} application.BOMVersionResponse "获取成功"
+// @Failure      404 {object} map[string]string "产品不存在"
+// @Security     Bearer
+// @Router       /product/{id}/bom [get]
+func (h *BOMHandler) List(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的ID"})
+		return
+	}
+
+	resp, err := h.service.List(c.Request.Context(), uint(productID))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Create 新建 BOM 版本
+// @Summary      新建 BOM 版本
+// @Description  为产品新建草稿 BOM 版本，原料或工艺为空时沿用当前生效的配方
+// @Tags         产品管理
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "产品ID"
+// @Param        request body application.CreateBOMVersionRequest true "BOM 配方"
+// @Success      200 {object} application.BOMVersionResponse "创建成功"
+// @Failure      400 {object} map[string]string "请求参数错误"
+// @Failure      404 {object} map[string]string "产品不存在"
+// @Security     Bearer
+// @Router       /product/{id}/bom [post]
+func (h *BOMHandler) Create(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的ID"})
+		return
+	}
+
+	var req application.CreateBOMVersionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	createdBy, _ := strconv.Atoi(c.GetString("loginId"))
+	resp, err := h.service.Create(c.Request.Context(), uint(productID), &req, uint(createdBy))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetEffective 获取生效的 BOM
+// @Summary      获取生效的 BOM
+// @Description  获取产品在指定日期生效的 BOM 版本（无生效版本时返回产品自身配方，version 为 0）
+// @Tags         产品管理
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "产品ID"
+// @Param        date query string false "日期（YYYY-MM-DD，默认当前）"
+// @Success      200 {object} application.BOMVersionResponse "获取成功"
+// @Failure      400 {object} map[string]string "请求参数错误"
+// @Failure      404 {object} map[string]string "产品不存在"
+// @Security     Bearer
+// @Router       /product/{id}/bom/effective [get]
+func (h *BOMHandler) GetEffective(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的ID"})
+		return
+	}
+
+	at := time.Now()
+	if date := c.Query("date"); date != "" {
+		day, err := time.ParseInLocation("2006-01-02", date, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的日期"})
+			return
+		}
+		// 取当天结束时刻，当天生效的版本也计入
+		at = day.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+
+	resp, err := h.service.GetEffective(c.Request.Context(), uint(productID), at)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Update 更新 BOM 版本
+// @Summary      更新 BOM 版本
+// @Description  修改草稿或被拒绝版本的配方与备注（提交 / 审批 / 拒绝使用单独的接口）
+// @Tags         产品管理
+// @Accept       json
+// @Produce      json
+// @Param        versionId path int true "BOM 版本ID"
+// @Param        request body application.UpdateBOMVersionRequest true "更新内容"
+// @Success      200 {object} application.BOMVersionResponse "更新成功"
+// @Failure      400 {object} map[string]string "请求参数错误"
+// @Failure      404 {object} map[string]string "BOM 版本不存在"
+// @Security     Bearer
+// @Router       /product/bom/{versionId} [put]
+func (h *BOMHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("versionId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的ID"})
+		return
+	}
+
+	var req application.UpdateBOMVersionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.service.Update(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Submit 提交 BOM 版本审批
+// @Summary      提交 BOM 版本审批
+// @Description  将草稿或被拒绝的 BOM 版本提交审批
+// @Tags         产品审批
+// @Accept       json
+// @Produce      json
+// @Param        versionId path int true "BOM 版本ID"
+// @Param        request body application.BOMApprovalRequest false "备注"
+// @Success      200 {object} application.ApprovalResponse "审批记录"
+// @Failure      400 {object} map[string]string "当前状态不能提交"
+// @Failure      404 {object} map[string]string "BOM 版本不存在"
+// @Security     Bearer
+// @Router       /product/bom/{versionId}/submit [post]
+func (h *BOMHandler) Submit(c *gin.Context) {
+	h.transition(c, domain.ProductStatusSubmitted)
+}
+
+// Approve 审批通过 BOM 版本
+// @Summary      审批通过 BOM 版本
+// @Description  通过当前一级审批，最后一级通过后版本按生效日期（默认立即）生效；不能审批本人提交的版本
+// @Tags         产品审批
+// @Accept       json
+// @Produce      json
+// @Param        versionId path int true "BOM 版本ID"
+// @Param        request body application.BOMApprovalRequest false "审批意见与生效日期"
+// @Success      200 {object} application.ApprovalResponse "审批记录"
+// @Failure      400 {object} map[string]string "当前状态不能审批"
+// @Failure      403 {object} map[string]string "不能审批本人提交的版本或非当前审批人"
+// @Failure      404 {object} map[string]string "BOM 版本不存在"
+// @Security     Bearer
+// @Router       /product/bom/{versionId}/approve [post]
+func (h *BOMHandler) Approve(c *gin.Context) {
+	h.transition(c, domain.ProductStatusApproved)
+}
+
+// Reject 拒绝 BOM 版本
+// @Summary      拒绝 BOM 版本
+// @Description  拒绝 BOM 版本审批，必须填写拒绝原因
+// @Tags         产品审批
+// @Accept       json
+// @Produce      json
+// @Param        versionId path int true "BOM 版本ID"
+// @Param        request body application.BOMApprovalRequest true "拒绝原因"
+// @Success      200 {object} application.ApprovalResponse "审批记录"
+// @Failure      400 {object} map[string]string "未填写拒绝原因或当前状态不能拒绝"
+// @Failure      403 {object} map[string]string "不能审批本人提交的版本或非当前审批人"
+// @Failure      404 {object} map[string]string "BOM 版本不存在"
+// @Security     Bearer
+// @Router       /product/bom/{versionId}/reject [post]
+func (h *BOMHandler) Reject(c *gin.Context) {
+	h.transition(c, domain.ProductStatusRejected)
+}
+
+// transition 提交 / 审批 / 拒绝的公共处理
+func (h *BOMHandler) transition(c *gin.Context, status string) {
+	id, err := strconv.ParseUint(c.Param("versionId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的ID"})
+		return
+	}
+
+	var req application.BOMApprovalRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	resp, err := h.service.Transition(c.Request.Context(), uint(id), status, &req, getApprover(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	if recorder := audit.Get(c); recorder != nil {
+		recorder.SetResourceID(uint(id))
+		recorder.SetNew(resp)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Delete 删除 BOM 版本
+// @Summary      删除 BOM 版本
+// @Description  删除草稿或被拒绝的 BOM 版本
+// @Tags         产品管理
+// @Accept       json
+// @Produce      json
+// @Param        versionId path int true "BOM 版本ID"
+// @Success      200 {object} map[string]string "删除成功"
+// @Failure      400 {object} map[string]string "已提交或已审批的版本不能删除"
+// @Failure      404 {object} map[string]string "BOM 版本不存在"
+// @Security     Bearer
+// @Router       /product/bom/{versionId} [delete]
+func (h *BOMHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("versionId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的ID"})
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), uint(id)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
+
+// handleError 错误映射
+func (h *BOMHandler) handleError(c *gin.Context, err error) {
+	if code, msg, ok := approvalError(err); ok {
+		c.JSON(code, gin.H{"error": msg})
+		return
+	}
+	switch {
+	case errors.Is(err, domain.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "产品不存在"})
+	case errors.Is(err, domain.ErrBOMVersionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "BOM 版本不存在"})
+	case errors.Is(err, domain.ErrCannotUpdateBOMVersion):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "已提交或已审批的 BOM 版本不能修改"})
+	case errors.Is(err, domain.ErrCannotDeleteBOMVersion):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "已提交或已审批的 BOM 版本不能删除"})
+	case errors.Is(err, domain.ErrInvalidRouting),
+		errors.Is(err, domain.ErrMaterialsRequired),
+		errors.Is(err, domain.ErrProcessesRequired),
+		errors.Is(err, domain.ErrInvalidMaterialRatio),
+		errors.Is(err, domain.ErrMaterialRatioSumNotOne),
+		errors.Is(err, domain.ErrCannotSubmit),
+		errors.Is(err, domain.ErrCannotApprove),
+		errors.Is(err, domain.ErrCannotReject),
+		errors.Is(err, domain.ErrInvalidTargetStatus):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// GetRoutes 返回路由定义
+func (h *BOMHandler) GetRoutes() []endpoint.RouteDefinition {
+	return []endpoint.RouteDefinition{
+		{Method: "GET", Path: "/product/:id/bom", Handler: h.List, Domain: "product", Action: "detail"},
+		{Method: "POST", Path: "/product/:id/bom", Handler: h.Create, Domain: "product", Action: "update"},
+		{Method: "GET", Path: "/product/:id/bom/effective", Handler: h.GetEffective, Domain: "product", Action: "detail"},
+		{Method: "PUT", Path: "/product/bom/:versionId", Handler: h.Update, Domain: "product", Action: "update"},
+		{Method: "POST", Path: "/product/bom/:versionId/submit", Handler: h.Submit, Domain: "product", Action: "submit"},
+		{Method: "POST", Path: "/product/bom/:versionId/approve", Handler: h.Approve, Domain: "product", Action: "approve"},
+		{Method: "POST", Path: "/product/bom/:versionId/reject", Handler: h.Reject, Domain: "product", Action: "approve"},
+		{Method: "DELETE", Path: "/product/bom/:versionId", Handler: h.Delete, Domain: "product", Action: "delete"},
+	}
+}
@@ -0,0 +1,112 @@
+package interfaces
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"back/internal/product/application"
+	"back/internal/product/domain"
+	"back/pkg/endpoint"
+)
+
+// defaultTrendDays 成本趋势默认查询天数
+const defaultTrendDays = 90
+
+// CostSnapshotHandler 产品成本快照 Handler
+type CostSnapshotHandler struct {
+	service *application.CostSnapshotService
+}
+
+// NewCostSnapshotHandler 创建 Handler
+func NewCostSnapshotHandler(service *application.CostSnapshotService) *CostSnapshotHandler {
+	return &CostSnapshotHandler{service: service}
+}
+
+// GetTrend 获取产品成本趋势
+// @Summary      获取产品成本趋势
+// @Description  按报价触发与每日生成的成本快照返回产品单位成本趋势（默认最近 90 天）
+// @Tags         产品管理
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "产品ID"
+// @Param        from query string false "开始日期（YYYY-MM-DD）"
+// @Param        to query string false "结束日期（YYYY-MM-DD，含当天）"
+// @Success      200 {object} application.CostTrendResponse "成本趋势"
+// @Failure      400 {object} map[string]string "请求参数错误"
+// @Failure      404 {object} map[string]string "产品不存在"
+// @Security     Bearer
+// @Router       /product/{id}/cost-trend [get]
+func (h *CostSnapshotHandler) GetTrend(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的ID"})
+		return
+	}
+
+	today := time.Now()
+	to := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, 1)
+	if date := c.Query("to"); date != "" {
+		day, err := time.ParseInLocation("2006-01-02", date, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的结束日期"})
+			return
+		}
+		to = day.AddDate(0, 0, 1)
+	}
+	from := to.AddDate(0, 0, -defaultTrendDays)
+	if date := c.Query("from"); date != "" {
+		from, err = time.ParseInLocation("2006-01-02", date, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的开始日期"})
+			return
+		}
+	}
+	if !from.Before(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "开始日期不能晚于结束日期"})
+		return
+	}
+
+	resp, err := h.service.GetTrend(c.Request.Context(), uint(productID), from, to)
+	if err != nil {
+		if errors.Is(err, domain.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "产品不存在"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// RunSnapshots 立即生成成本快照
+// @Summary      立即生成成本快照
+// @Description  为所有产品生成一次成本快照（与每日定时任务相同），成本变动超过阈值的已审批产品发送预警
+// @Tags         产品管理
+// @Accept       json
+// @Produce      json
+// @Success      200 {object} application.SnapshotRunResponse "执行结果"
+// @Failure      500 {object} map[string]string "服务器错误"
+// @Security     Bearer
+// @Router       /product/cost/snapshot [post]
+func (h *CostSnapshotHandler) RunSnapshots(c *gin.Context) {
+	resp, err := h.service.RunDailySnapshots(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetRoutes 返回路由定义
+func (h *CostSnapshotHandler) GetRoutes() []endpoint.RouteDefinition {
+	return []endpoint.RouteDefinition{
+		{Method: "GET", Path: "/product/:id/cost-trend", Handler: h.GetTrend, Domain: "product", Action: "cost"},
+		{Method: "POST", Path: "/product/cost/snapshot", Handler: h.RunSnapshots, Domain: "product", Action: "snapshot"},
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"back/pkg/audit"
 	"back/pkg/endpoint"
 	"back/internal/product/application"
 	"back/internal/product/domain"
@@ -118,6 +119,10 @@ func (h *ProductHandler) Update(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "已审批的产品不能修改"})
 			return
 		}
+		if errors.Is(err, domain.ErrInvalidRouting) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -157,7 +162,7 @@ func (h *ProductHandler) Delete(c *gin.Context) {
 
 // CalculateCost 计算产品成本
 // @Summary      计算产品成本
-// @Description  根据产品ID和数量计算产品成本
+// @Description  按每单位成品计算产品成本，计入工序数量、工艺损耗、缩率、预计回修和间接费用，明细中逐项列出加成
 // @Tags         产品管理
 // @Accept       json
 // @Produce      json
@@ -180,6 +185,47 @@ func (h *ProductHandler) CalculateCost(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "产品不存在"})
 			return
 		}
+		if errors.Is(err, domain.ErrInvalidRouting) || errors.Is(err, domain.ErrInvalidCostPolicy) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// SimulateCost 成本模拟
+// @Summary      成本模拟
+// @Description  按价格覆盖（指定单价 / 百分比涨跌 / 指定供应商报价）和原料配比调整模拟成本，返回各产品基准与场景成本对比
+// @Tags         产品管理
+// @Accept       json
+// @Produce      json
+// @Param        request body application.SimulateCostRequest true "模拟场景"
+// @Success      200 {object} application.CostSimulationResponse "模拟结果"
+// @Failure      400 {object} map[string]string "请求参数错误"
+// @Failure      404 {object} map[string]string "产品不存在"
+// @Failure      500 {object} map[string]string "服务器错误"
+// @Security     Bearer
+// @Router       /product/cost/simulate [post]
+func (h *ProductHandler) SimulateCost(c *gin.Context) {
+	var req application.SimulateCostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.calculator.Simulate(c.Request.Context(), &req)
+	if err != nil {
+		if errors.Is(err, domain.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "产品不存在"})
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidOverride) || errors.Is(err, domain.ErrInvalidCostPolicy) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -215,6 +261,183 @@ func (h *ProductHandler) GetPrice(c *gin.Context) {
 	c.JSON(http.StatusOK, result)
 }
 
+// Submit 提交产品审批
+// @Summary      提交产品审批
+// @Description  将草稿或被拒绝的产品提交审批
+// @Tags         产品审批
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "产品ID"
+// @Param        request body application.ApprovalActionRequest false "备注"
+// @Success      200 {object} application.ApprovalResponse "审批记录"
+// @Failure      400 {object} map[string]string "当前状态不能提交"
+// @Failure      404 {object} map[string]string "产品不存在"
+// @Security     Bearer
+// @Router       /product/{id}/submit [post]
+func (h *ProductHandler) Submit(c *gin.Context) {
+	h.transition(c, domain.ProductStatusSubmitted)
+}
+
+// Approve 审批通过产品
+// @Summary      审批通过产品
+// @Description  通过当前一级审批，最后一级通过后产品变为已审批；不能审批本人提交的产品
+// @Tags         产品审批
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "产品ID"
+// @Param        request body application.ApprovalActionRequest false "审批意见"
+// @Success      200 {object} application.ApprovalResponse "审批记录"
+// @Failure      400 {object} map[string]string "当前状态不能审批"
+// @Failure      403 {object} map[string]string "不能审批本人提交的产品或非当前审批人"
+// @Failure      404 {object} map[string]string "产品不存在"
+// @Security     Bearer
+// @Router       /product/{id}/approve [post]
+func (h *ProductHandler) Approve(c *gin.Context) {
+	h.transition(c, domain.ProductStatusApproved)
+}
+
+// Reject 拒绝产品
+// @Summary      拒绝产品
+// @Description  拒绝产品审批，必须填写拒绝原因
+// @Tags         产品审批
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "产品ID"
+// @Param        request body application.ApprovalActionRequest true "拒绝原因"
+// @Success      200 {object} application.ApprovalResponse "审批记录"
+// @Failure      400 {object} map[string]string "未填写拒绝原因或当前状态不能拒绝"
+// @Failure      403 {object} map[string]string "不能审批本人提交的产品或非当前审批人"
+// @Failure      404 {object} map[string]string "产品不存在"
+// @Security     Bearer
+// @Router       /product/{id}/reject [post]
+func (h *ProductHandler) Reject(c *gin.Context) {
+	h.transition(c, domain.ProductStatusRejected)
+}
+
+// transition 提交 / 审批 / 拒绝的公共处理
+func (h *ProductHandler) transition(c *gin.Context, status string) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的ID"})
+		return
+	}
+
+	var req application.ApprovalActionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	resp, err := h.service.Transition(c.Request.Context(), uint(id), status, &req, getApprover(c))
+	if err != nil {
+		if errors.Is(err, domain.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "产品不存在"})
+			return
+		}
+		if code, msg, ok := approvalError(err); ok {
+			c.JSON(code, gin.H{"error": msg})
+			return
+		}
+		if errors.Is(err, domain.ErrCannotSubmit) ||
+			errors.Is(err, domain.ErrCannotApprove) ||
+			errors.Is(err, domain.ErrCannotReject) ||
+			errors.Is(err, domain.ErrInvalidRouting) ||
+			errors.Is(err, domain.ErrMaterialsRequired) ||
+			errors.Is(err, domain.ErrProcessesRequired) ||
+			errors.Is(err, domain.ErrInvalidMaterialRatio) ||
+			errors.Is(err, domain.ErrMaterialRatioSumNotOne) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if recorder := audit.Get(c); recorder != nil {
+		recorder.SetResourceID(uint(id))
+		recorder.SetNew(resp)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetApprovals 获取审批历史
+// @Summary      获取审批历史
+// @Description  获取产品及其 BOM 版本的全部审批记录（最新在前）
+// @Tags         产品审批
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "产品ID"
+// @Success      200 {array} application.ApprovalResponse "审批记录"
+// @Failure      404 {object} map[string]string "产品不存在"
+// @Security     Bearer
+// @Router       /product/{id}/approvals [get]
+func (h *ProductHandler) GetApprovals(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的ID"})
+		return
+	}
+
+	resp, err := h.service.GetApprovals(c.Request.Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, domain.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "产品不存在"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Inbox 审批待办
+// @Summary      审批待办
+// @Description  当前用户待处理的产品与 BOM 版本审批（不含本人提交的）
+// @Tags         产品审批
+// @Accept       json
+// @Produce      json
+// @Success      200 {array} application.ApprovalInboxItem "待办列表"
+// @Failure      500 {object} map[string]string "服务器错误"
+// @Security     Bearer
+// @Router       /product/approval/inbox [get]
+func (h *ProductHandler) Inbox(c *gin.Context) {
+	resp, err := h.service.Inbox(c.Request.Context(), getApprover(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// getApprover 从上下文获取当前操作人
+func getApprover(c *gin.Context) domain.Approver {
+	return domain.Approver{
+		LoginID: c.GetString("loginId"),
+		Name:    c.GetString("username"),
+		Role:    c.GetString("role"),
+	}
+}
+
+// approvalError 审批相关错误映射
+func approvalError(err error) (int, string, bool) {
+	switch {
+	case errors.Is(err, domain.ErrSelfApproval):
+		return http.StatusForbidden, "不能审批本人提交的申请", true
+	case errors.Is(err, domain.ErrNotCurrentApprover):
+		return http.StatusForbidden, "当前审批级别不由您的角色处理", true
+	case errors.Is(err, domain.ErrApproverRequired):
+		return http.StatusForbidden, "无法识别当前操作人", true
+	case errors.Is(err, domain.ErrRejectReasonRequired):
+		return http.StatusBadRequest, "拒绝时必须填写原因", true
+	}
+	return 0, "", false
+}
+
 // GetRoutes 返回路由定义
 func (h *ProductHandler) GetRoutes() []endpoint.RouteDefinition {
 	return []endpoint.RouteDefinition{
@@ -224,5 +447,11 @@ func (h *ProductHandler) GetRoutes() []endpoint.RouteDefinition {
 		{Method: "PUT", Path: "/product/:id", Handler: h.Update, Domain: "product", Action: "update"},
 		{Method: "DELETE", Path: "/product/:id", Handler: h.Delete, Domain: "product", Action: "delete"},
 		{Method: "POST", Path: "/product/calculate-cost", Handler: h.CalculateCost, Domain: "product", Action: "cost"},
+		{Method: "POST", Path: "/product/cost/simulate", Handler: h.SimulateCost, Domain: "product", Action: "cost"},
+		{Method: "POST", Path: "/product/:id/submit", Handler: h.Submit, Domain: "product", Action: "submit"},
+		{Method: "POST", Path: "/product/:id/approve", Handler: h.Approve, Domain: "product", Action: "approve"},
+		{Method: "POST", Path: "/product/:id/reject", Handler: h.Reject, Domain: "product", Action: "approve"},
+		{Method: "GET", Path: "/product/:id/approvals", Handler: h.GetApprovals, Domain: "product", Action: "detail"},
+		{Method: "GET", Path: "/product/approval/inbox", Handler: h.Inbox, Domain: "product", Action: "approve"},
 	}
 }
\ No newline at end of file
@@ -0,0 +1,68 @@
+package application
+
+import (
+	"time"
+
+	orderApp "back/internal/order/application"
+	"back/internal/sales/domain"
+)
+
+// SalesPriceRequest 创建 / 更新销售价目请求
+type SalesPriceRequest struct {
+	ClientID    uint       `json:"client_id"`                    // 客户专属价（与 price_group 二选一）
+	PriceGroup  string     `json:"price_group" binding:"max=50"` // 客户价目组
+	ProductID   uint       `json:"product_id" binding:"required"`
+	Price       float64    `json:"price" binding:"required,gt=0"`
+	Currency    string     `json:"currency"` // 销售币种（默认本位币）
+	MinQuantity float64    `json:"min_quantity" binding:"gte=0"`
+	ValidFrom   *time.Time `json:"valid_from"` // 生效日期（默认当前时间）
+	ValidTo     *time.Time `json:"valid_to"`   // 失效日期（为空时长期有效）
+	Remark      string     `json:"remark" binding:"max=500"`
+}
+
+// SalesPriceListResponse 销售价目列表
+type SalesPriceListResponse struct {
+	Total  int64               `json:"total"`
+	Prices []domain.SalesPrice `json:"prices"`
+}
+
+// ApplicablePriceResponse 客户适用价目（下单前预填单价）
+type ApplicablePriceResponse struct {
+	Price   *domain.SalesPrice     `json:"price"`             // 无适用价目时为 null
+	Warning *orderApp.PriceWarning `json:"warning,omitempty"` // 价目低于成本加成下限
+}
+
+// CreateQuotationRequest 创建报价单请求
+type CreateQuotationRequest struct {
+	ClientID   uint                   `json:"client_id" binding:"required"`
+	Currency   string                 `json:"currency"`    // 报价币种（默认本位币）
+	ValidUntil *time.Time             `json:"valid_until"` // 报价有效期（默认 30 天）
+	Remark     string                 `json:"remark" binding:"max=500"`
+	Items      []QuotationItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+// QuotationItemRequest 报价明细
+type QuotationItemRequest struct {
+	ProductID uint     `json:"product_id" binding:"required"`
+	Quantity  float64  `json:"quantity" binding:"required,gt=0"`
+	UnitPrice *float64 `json:"unit_price" binding:"omitempty,gte=0"` // 为空时取客户适用的销售价目
+	Remark    string   `json:"remark" binding:"max=500"`
+}
+
+// QuotationResponse 报价单（含低于成本加成下限的明细预警，预警仅供内部查看，不写入报价文件）
+type QuotationResponse struct {
+	*domain.Quotation
+	Warnings []QuotationWarning `json:"warnings,omitempty"`
+}
+
+// QuotationWarning 报价明细低价预警
+type QuotationWarning struct {
+	ProductID uint `json:"product_id"`
+	*orderApp.PriceWarning
+}
+
+// QuotationListResponse 报价单列表
+type QuotationListResponse struct {
+	Total      int64              `json:"total"`
+	Quotations []domain.Quotation `json:"quotations"`
+}
@@ -0,0 +1,163 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+
+	"back/internal/sales/domain"
+)
+
+// quotationSheet 报价文件工作表名
+const quotationSheet = "Quotation"
+
+// quotationColumns 报价明细列（中英双语表头）
+var quotationColumns = []string{"序号 No.", "产品 Product", "数量 Quantity", "单价 Unit Price", "金额 Amount", "备注 Remark"}
+
+// DocumentName 报价文件名
+func DocumentName(quotation *domain.Quotation) string {
+	return fmt.Sprintf("quotation_%s.xlsx", quotation.QuotationNo)
+}
+
+// WriteDocument 生成面向客户的报价文件（XLSX，不含成本与预警信息）
+func (s *QuotationService) WriteDocument(ctx context.Context, quotation *domain.Quotation, w io.Writer) error {
+	client, err := s.clientService.Get(ctx, quotation.ClientID)
+	if err != nil {
+		return err
+	}
+	products := make([]string, len(quotation.Items))
+	for i, item := range quotation.Items {
+		product, err := s.productService.Get(ctx, item.ProductID)
+		if err != nil {
+			return err
+		}
+		products[i] = product.Name
+	}
+
+	book := excelize.NewFile()
+	defer book.Close()
+	if err := book.SetSheetName(book.GetSheetName(0), quotationSheet); err != nil {
+		return err
+	}
+
+	titleStyle, err := book.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true, Size: 16},
+		Alignment: &excelize.Alignment{Horizontal: "center"},
+	})
+	if err != nil {
+		return err
+	}
+	headerStyle, err := book.NewStyle(&excelize.Style{
+		Font:   &excelize.Font{Bold: true},
+		Fill:   excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"#D9E1F2"}},
+		Border: cellBorders(),
+	})
+	if err != nil {
+		return err
+	}
+	cellStyle, err := book.NewStyle(&excelize.Style{Border: cellBorders()})
+	if err != nil {
+		return err
+	}
+	moneyStyle, err := book.NewStyle(&excelize.Style{Border: cellBorders(), NumFmt: 4}) // #,##0.00
+	if err != nil {
+		return err
+	}
+
+	customer := client.CustomName
+	if client.CustomNameEn != "" {
+		customer = fmt.Sprintf("%s (%s)", client.CustomName, client.CustomNameEn)
+	}
+	address := client.AddressEn
+	if address == "" {
+		address = client.Address
+	}
+	phone := client.UnitPhone
+	if phone == "" {
+		phone = client.Mobile
+	}
+
+	rows := [][]interface{}{
+		{"报价单号 No.", quotation.QuotationNo, "", "报价日期 Date", quotation.QuotedAt.Format("2006-01-02")},
+		{"客户 Customer", customer, "", "有效期至 Valid Until", quotation.ValidUntil.Format("2006-01-02")},
+		{"联系人 Attn", client.Contactor, "", "电话 Tel", phone},
+		{"地址 Address", address, "", "币种 Currency", quotation.Currency},
+	}
+
+	// 1. 标题与抬头
+	if err := book.SetCellValue(quotationSheet, "A1", "报价单 QUOTATION"); err != nil {
+		return err
+	}
+	if err := book.MergeCell(quotationSheet, "A1", "F1"); err != nil {
+		return err
+	}
+	if err := book.SetCellStyle(quotationSheet, "A1", "F1", titleStyle); err != nil {
+		return err
+	}
+	for i, row := range rows {
+		if err := book.SetSheetRow(quotationSheet, fmt.Sprintf("A%d", i+3), &row); err != nil {
+			return err
+		}
+	}
+
+	// 2. 明细
+	header := len(rows) + 4
+	if err := book.SetSheetRow(quotationSheet, fmt.Sprintf("A%d", header), &quotationColumns); err != nil {
+		return err
+	}
+	if err := book.SetCellStyle(quotationSheet, fmt.Sprintf("A%d", header), fmt.Sprintf("F%d", header), headerStyle); err != nil {
+		return err
+	}
+	for i, item := range quotation.Items {
+		line := header + 1 + i
+		row := []interface{}{i + 1, products[i], item.Quantity, item.UnitPrice, item.Amount, item.Remark}
+		if err := book.SetSheetRow(quotationSheet, fmt.Sprintf("A%d", line), &row); err != nil {
+			return err
+		}
+		if err := book.SetCellStyle(quotationSheet, fmt.Sprintf("A%d", line), fmt.Sprintf("F%d", line), cellStyle); err != nil {
+			return err
+		}
+		if err := book.SetCellStyle(quotationSheet, fmt.Sprintf("D%d", line), fmt.Sprintf("E%d", line), moneyStyle); err != nil {
+			return err
+		}
+	}
+
+	// 3. 合计与备注
+	total := header + len(quotation.Items) + 1
+	totalRow := []interface{}{"合计 Total", "", "", quotation.Currency, quotation.TotalAmount}
+	if err := book.SetSheetRow(quotationSheet, fmt.Sprintf("A%d", total), &totalRow); err != nil {
+		return err
+	}
+	if err := book.SetCellStyle(quotationSheet, fmt.Sprintf("A%d", total), fmt.Sprintf("F%d", total), headerStyle); err != nil {
+		return err
+	}
+	if err := book.SetCellStyle(quotationSheet, fmt.Sprintf("E%d", total), fmt.Sprintf("E%d", total), moneyStyle); err != nil {
+		return err
+	}
+	if remark := strings.TrimSpace(quotation.Remark); remark != "" {
+		if err := book.SetCellValue(quotationSheet, fmt.Sprintf("A%d", total+2), "备注 Remark: "+remark); err != nil {
+			return err
+		}
+	}
+
+	for col, width := range map[string]float64{"A": 16, "B": 36, "C": 14, "D": 20, "E": 18, "F": 30} {
+		if err := book.SetColWidth(quotationSheet, col, col, width); err != nil {
+			return err
+		}
+	}
+
+	_, err = book.WriteTo(w)
+	return err
+}
+
+// cellBorders 明细单元格细边框
+func cellBorders() []excelize.Border {
+	borders := make([]excelize.Border, 0, 4)
+	for _, side := range []string{"left", "top", "right", "bottom"} {
+		borders = append(borders, excelize.Border{Type: side, Color: "#000000", Style: 1})
+	}
+	return borders
+}
@@ -0,0 +1,122 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	clientApp "back/internal/client/application"
+	currencyApp "back/internal/currency/application"
+	productApp "back/internal/product/application"
+	productDomain "back/internal/product/domain"
+	"back/internal/sales/domain"
+	"back/internal/sales/infra"
+)
+
+// DefaultQuotationDays 未指定有效期时报价单的有效天数
+const DefaultQuotationDays = 30
+
+// QuotationService 客户报价单服务
+type QuotationService struct {
+	repo            *infra.QuotationRepo
+	priceService    *SalesPriceService
+	clientService   *clientApp.ClientService
+	productService  *productApp.ProductService
+	currencyService *currencyApp.CurrencyService
+}
+
+// NewQuotationService 创建报价单服务
+func NewQuotationService(
+	repo *infra.QuotationRepo,
+	priceService *SalesPriceService,
+	clientService *clientApp.ClientService,
+	productService *productApp.ProductService,
+	currencyService *currencyApp.CurrencyService,
+) *QuotationService {
+	return &QuotationService{
+		repo:            repo,
+		priceService:    priceService,
+		clientService:   clientService,
+		productService:  productService,
+		currencyService: currencyService,
+	}
+}
+
+// Create 创建报价单：未填写单价的明细取客户适用的销售价目（须与报价币种一致）
+func (s *QuotationService) Create(ctx context.Context, req *CreateQuotationRequest, creatorID uint, creatorName string) (*QuotationResponse, error) {
+	if _, err := s.clientService.Get(ctx, req.ClientID); err != nil {
+		return nil, err
+	}
+	currency, err := s.currencyService.Normalize(req.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	validUntil := now.AddDate(0, 0, DefaultQuotationDays)
+	if req.ValidUntil != nil {
+		validUntil = *req.ValidUntil
+	}
+
+	items := make([]domain.QuotationItem, len(req.Items))
+	var warnings []QuotationWarning
+	for i, line := range req.Items {
+		exists, err := s.productService.Exists(ctx, line.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, productDomain.ErrProductNotFound
+		}
+
+		item := domain.QuotationItem{ProductID: line.ProductID, Quantity: line.Quantity, Remark: line.Remark}
+		if line.UnitPrice != nil {
+			item.UnitPrice = *line.UnitPrice
+			item.PriceSource = domain.PriceSourceManual
+		} else {
+			price, err := s.priceService.find(ctx, req.ClientID, line.ProductID, line.Quantity, currency, now)
+			if err != nil {
+				return nil, err
+			}
+			if price == nil {
+				return nil, fmt.Errorf("%w: product %d in %s", domain.ErrNoApplicablePrice, line.ProductID, currency)
+			}
+			item.UnitPrice = price.Price
+			item.PriceSource = domain.PriceSourceList
+			item.PriceID = price.ID
+		}
+		items[i] = item
+
+		if warning := s.priceService.CheckPrice(ctx, item.ProductID, item.UnitPrice, currency, now); warning != nil {
+			warnings = append(warnings, QuotationWarning{ProductID: item.ProductID, PriceWarning: warning})
+		}
+	}
+
+	quotation, err := domain.NewQuotation(req.ClientID, currency, validUntil, items, creatorID, creatorName, now)
+	if err != nil {
+		return nil, err
+	}
+	quotation.Remark = req.Remark
+
+	if err := s.repo.Create(ctx, quotation); err != nil {
+		return nil, err
+	}
+	return &QuotationResponse{Quotation: quotation, Warnings: warnings}, nil
+}
+
+// Get 获取报价单
+func (s *QuotationService) Get(ctx context.Context, id uint) (*domain.Quotation, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// List 报价单列表（clientID 为 0 时不限客户）
+func (s *QuotationService) List(ctx context.Context, clientID uint, limit, offset int) (*QuotationListResponse, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	quotations, total, err := s.repo.FindList(ctx, clientID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return &QuotationListResponse{Total: total, Quotations: quotations}, nil
+}
@@ -0,0 +1,195 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	clientApp "back/internal/client/application"
+	currencyApp "back/internal/currency/application"
+	orderApp "back/internal/order/application"
+	productApp "back/internal/product/application"
+	productDomain "back/internal/product/domain"
+	"back/internal/sales/domain"
+	"back/internal/sales/infra"
+)
+
+// SalesPriceService 销售价目服务（实现订单模块的 SalesPricing）
+type SalesPriceService struct {
+	repo                *infra.SalesPriceRepo
+	clientService       *clientApp.ClientService
+	productService      *productApp.ProductService
+	productPriceService *productApp.ProductPriceService
+	currencyService     *currencyApp.CurrencyService
+	marginFloor         float64
+}
+
+// NewSalesPriceService 创建销售价目服务（marginFloor 为售价相对单位成本的加成下限，%）
+func NewSalesPriceService(
+	repo *infra.SalesPriceRepo,
+	clientService *clientApp.ClientService,
+	productService *productApp.ProductService,
+	productPriceService *productApp.ProductPriceService,
+	currencyService *currencyApp.CurrencyService,
+	marginFloor float64,
+) *SalesPriceService {
+	return &SalesPriceService{
+		repo:                repo,
+		clientService:       clientService,
+		productService:      productService,
+		productPriceService: productPriceService,
+		currencyService:     currencyService,
+		marginFloor:         marginFloor,
+	}
+}
+
+// Create 创建销售价目
+func (s *SalesPriceService) Create(ctx context.Context, req *SalesPriceRequest, creatorID uint, creatorName string) (*domain.SalesPrice, error) {
+	price := &domain.SalesPrice{CreatedBy: creatorID, CreatorName: creatorName}
+	if err := s.apply(ctx, price, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Save(ctx, price); err != nil {
+		return nil, err
+	}
+	return price, nil
+}
+
+// Update 更新销售价目
+func (s *SalesPriceService) Update(ctx context.Context, id uint, req *SalesPriceRequest) (*domain.SalesPrice, error) {
+	price, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apply(ctx, price, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Save(ctx, price); err != nil {
+		return nil, err
+	}
+	return price, nil
+}
+
+// apply 校验客户、产品、币种并写入价目
+func (s *SalesPriceService) apply(ctx context.Context, price *domain.SalesPrice, req *SalesPriceRequest) error {
+	currency, err := s.currencyService.Normalize(req.Currency)
+	if err != nil {
+		return err
+	}
+	if req.ClientID != 0 {
+		if _, err := s.clientService.Get(ctx, req.ClientID); err != nil {
+			return err
+		}
+	}
+	exists, err := s.productService.Exists(ctx, req.ProductID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return productDomain.ErrProductNotFound
+	}
+
+	validFrom := time.Now()
+	if req.ValidFrom != nil {
+		validFrom = *req.ValidFrom
+	}
+
+	price.ClientID = req.ClientID
+	price.PriceGroup = req.PriceGroup
+	price.ProductID = req.ProductID
+	price.Price = req.Price
+	price.Currency = currency
+	price.MinQuantity = req.MinQuantity
+	price.ValidFrom = validFrom
+	price.ValidTo = req.ValidTo
+	price.Remark = req.Remark
+	return price.Validate()
+}
+
+// Get 获取销售价目
+func (s *SalesPriceService) Get(ctx context.Context, id uint) (*domain.SalesPrice, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// Delete 删除销售价目（已引用该价目的订单与报价单保留原单价）
+func (s *SalesPriceService) Delete(ctx context.Context, id uint) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// List 销售价目列表
+func (s *SalesPriceService) List(ctx context.Context, filter infra.SalesPriceFilter, limit, offset int) (*SalesPriceListResponse, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	prices, total, err := s.repo.FindList(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return &SalesPriceListResponse{Total: total, Prices: prices}, nil
+}
+
+// Applicable 查询客户适用价目及低价预警（currency 为空时不限币种）
+func (s *SalesPriceService) Applicable(ctx context.Context, clientID, productID uint, quantity float64, currency string) (*ApplicablePriceResponse, error) {
+	if currency != "" {
+		normalized, err := s.currencyService.Normalize(currency)
+		if err != nil {
+			return nil, err
+		}
+		currency = normalized
+	}
+
+	now := time.Now()
+	price, err := s.find(ctx, clientID, productID, quantity, currency, now)
+	if err != nil || price == nil {
+		return &ApplicablePriceResponse{}, err
+	}
+	return &ApplicablePriceResponse{
+		Price:   price,
+		Warning: s.CheckPrice(ctx, productID, price.Price, price.Currency, now),
+	}, nil
+}
+
+// ApplicablePrice 客户适用的销售价目（无适用价目时返回 nil）
+func (s *SalesPriceService) ApplicablePrice(ctx context.Context, clientID, productID uint, quantity float64, currency string, at time.Time) (*orderApp.SalesPriceMatch, error) {
+	price, err := s.find(ctx, clientID, productID, quantity, currency, at)
+	if err != nil || price == nil {
+		return nil, err
+	}
+	return &orderApp.SalesPriceMatch{PriceID: price.ID, UnitPrice: price.Price, Currency: price.Currency}, nil
+}
+
+// find 按客户专属价、客户价目组价依次选取适用价目
+func (s *SalesPriceService) find(ctx context.Context, clientID, productID uint, quantity float64, currency string, at time.Time) (*domain.SalesPrice, error) {
+	client, err := s.clientService.Get(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	prices, err := s.repo.FindCandidates(ctx, productID, clientID, client.PriceGroup)
+	if err != nil {
+		return nil, err
+	}
+	return domain.SelectPrice(prices, clientID, quantity, currency, at), nil
+}
+
+// CheckPrice 单价低于产品当前单位成本加成下限时返回预警（成本无法核算或无汇率时返回 nil）
+func (s *SalesPriceService) CheckPrice(ctx context.Context, productID uint, unitPrice float64, currency string, at time.Time) *orderApp.PriceWarning {
+	cost, err := s.productPriceService.GetPrice(ctx, productID)
+	if err != nil || cost.CurrentPrice <= 0 {
+		return nil
+	}
+	unitCost, err := s.currencyService.Convert(ctx, cost.CurrentPrice, cost.Currency, currency, at)
+	if err != nil {
+		return nil
+	}
+
+	floor := unitCost * (1 + s.marginFloor/100)
+	if unitPrice >= floor {
+		return nil
+	}
+	return &orderApp.PriceWarning{
+		UnitPrice:   unitPrice,
+		Currency:    currency,
+		UnitCost:    unitCost,
+		MarginFloor: s.marginFloor,
+		FloorPrice:  floor,
+	}
+}
@@ -0,0 +1,22 @@
+package domain
+
+import "errors"
+
+var (
+	ErrSalesPriceNotFound   = errors.New("sales price not found")
+	ErrPriceScopeRequired   = errors.New("either client_id or price_group is required")
+	ErrPriceScopeConflict   = errors.New("client_id and price_group are mutually exclusive")
+	ErrPriceGroupTooLong    = errors.New("price_group cannot exceed 50 characters")
+	ErrProductIDRequired    = errors.New("product_id is required")
+	ErrInvalidPrice         = errors.New("price must be greater than 0")
+	ErrCurrencyRequired     = errors.New("currency is required")
+	ErrInvalidMinQuantity   = errors.New("min_quantity must not be negative")
+	ErrInvalidValidity      = errors.New("valid_to must be after valid_from")
+	ErrNoApplicablePrice    = errors.New("no applicable sales price")
+	ErrQuotationNotFound    = errors.New("quotation not found")
+	ErrQuotationItems       = errors.New("quotation must contain at least one item")
+	ErrQuotationQuantity    = errors.New("quotation quantity must be greater than 0")
+	ErrQuotationUnitPrice   = errors.New("quotation unit price must not be negative")
+	ErrQuotationValidity    = errors.New("valid_until must be after quotation date")
+	ErrQuotationClientEmpty = errors.New("client_id is required")
+)
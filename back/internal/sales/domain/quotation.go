@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"time"
+
+	"back/pkg/serial"
+)
+
+// 报价明细单价来源
+const (
+	PriceSourceList   = "price_list" // 取自销售价目
+	PriceSourceManual = "manual"     // 手工录入
+)
+
+// Quotation 客户报价单
+type Quotation struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	QuotationNo string    `gorm:"size:50;uniqueIndex;not null" json:"quotation_no"`
+	ClientID    uint      `gorm:"not null;index" json:"client_id"`
+	Currency    string    `gorm:"size:3;not null" json:"currency"`
+	QuotedAt    time.Time `gorm:"not null" json:"quoted_at"`
+	ValidUntil  time.Time `gorm:"not null" json:"valid_until"` // 报价有效期
+	TotalAmount float64   `gorm:"type:decimal(14,2);not null" json:"total_amount"`
+	Remark      string    `gorm:"size:500" json:"remark"`
+	CreatedBy   uint      `gorm:"not null" json:"created_by"`
+	CreatorName string    `gorm:"size:100" json:"creator_name"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	Items []QuotationItem `gorm:"foreignKey:QuotationID;constraint:OnDelete:CASCADE" json:"items,omitempty"`
+}
+
+// TableName 表名
+func (Quotation) TableName() string {
+	return "sales_quotations"
+}
+
+// QuotationItem 报价明细
+type QuotationItem struct {
+	ID          uint    `gorm:"primaryKey" json:"id"`
+	QuotationID uint    `gorm:"not null;index" json:"quotation_id"`
+	ProductID   uint    `gorm:"not null" json:"product_id"`
+	Quantity    float64 `gorm:"type:decimal(12,2);not null" json:"quantity"`
+	UnitPrice   float64 `gorm:"type:decimal(12,4);not null" json:"unit_price"`
+	Amount      float64 `gorm:"type:decimal(14,2);not null" json:"amount"`
+	PriceSource string  `gorm:"size:20;not null" json:"price_source"`
+	PriceID     uint    `gorm:"default:0" json:"price_id,omitempty"` // 来源销售价目（手工录入时为 0）
+	Remark      string  `gorm:"size:500" json:"remark"`
+}
+
+// TableName 表名
+func (QuotationItem) TableName() string {
+	return "sales_quotation_items"
+}
+
+// NewQuotation 创建报价单（计算明细金额及合计）
+func NewQuotation(clientID uint, currency string, validUntil time.Time, items []QuotationItem, creatorID uint, creatorName string, now time.Time) (*Quotation, error) {
+	if clientID == 0 {
+		return nil, ErrQuotationClientEmpty
+	}
+	if currency == "" {
+		return nil, ErrCurrencyRequired
+	}
+	if len(items) == 0 {
+		return nil, ErrQuotationItems
+	}
+	if !validUntil.After(now) {
+		return nil, ErrQuotationValidity
+	}
+
+	total := 0.0
+	for i := range items {
+		if items[i].ProductID == 0 {
+			return nil, ErrProductIDRequired
+		}
+		if items[i].Quantity <= 0 {
+			return nil, ErrQuotationQuantity
+		}
+		if items[i].UnitPrice < 0 {
+			return nil, ErrQuotationUnitPrice
+		}
+		items[i].Amount = items[i].Quantity * items[i].UnitPrice
+		total += items[i].Amount
+	}
+
+	return &Quotation{
+		QuotationNo: serial.New("QT", now),
+		ClientID:    clientID,
+		Currency:    currency,
+		QuotedAt:    now,
+		ValidUntil:  validUntil,
+		TotalAmount: total,
+		CreatedBy:   creatorID,
+		CreatorName: creatorName,
+		Items:       items,
+	}, nil
+}
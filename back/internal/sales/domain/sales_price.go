@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// SalesPrice 销售价目（客户专属价或客户价目组价）
+type SalesPrice struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	ClientID    uint       `gorm:"not null;default:0;index" json:"client_id,omitempty"` // 客户专属价（0 表示按价目组）
+	PriceGroup  string     `gorm:"size:50;index" json:"price_group,omitempty"`          // 客户价目组（对应客户的 priceGroup）
+	ProductID   uint       `gorm:"not null;index" json:"product_id"`
+	Price       float64    `gorm:"type:decimal(12,4);not null" json:"price"`         // 单位售价
+	Currency    string     `gorm:"size:3;not null" json:"currency"`                  // 销售币种
+	MinQuantity float64    `gorm:"type:decimal(12,2);default:0" json:"min_quantity"` // 起订量（数量达到后适用，0 表示不限）
+	ValidFrom   time.Time  `gorm:"not null" json:"valid_from"`
+	ValidTo     *time.Time `json:"valid_to"` // 失效日期（为空时长期有效）
+	Remark      string     `gorm:"size:500" json:"remark"`
+	CreatedBy   uint       `gorm:"not null" json:"created_by"`
+	CreatorName string     `gorm:"size:100" json:"creator_name"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 表名
+func (SalesPrice) TableName() string {
+	return "sales_prices"
+}
+
+// Validate 验证销售价目（客户与价目组二选一）
+func (p *SalesPrice) Validate() error {
+	if p.ClientID == 0 && p.PriceGroup == "" {
+		return ErrPriceScopeRequired
+	}
+	if p.ClientID != 0 && p.PriceGroup != "" {
+		return ErrPriceScopeConflict
+	}
+	if len(p.PriceGroup) > 50 {
+		return ErrPriceGroupTooLong
+	}
+	if p.ProductID == 0 {
+		return ErrProductIDRequired
+	}
+	if p.Price <= 0 {
+		return ErrInvalidPrice
+	}
+	if p.Currency == "" {
+		return ErrCurrencyRequired
+	}
+	if p.MinQuantity < 0 {
+		return ErrInvalidMinQuantity
+	}
+	if p.ValidTo != nil && !p.ValidTo.After(p.ValidFrom) {
+		return ErrInvalidValidity
+	}
+	return nil
+}
+
+// IsEffective 是否在指定时间有效
+func (p *SalesPrice) IsEffective(at time.Time) bool {
+	if at.Before(p.ValidFrom) {
+		return false
+	}
+	return p.ValidTo == nil || at.Before(*p.ValidTo)
+}
+
+// Applies 是否适用于指定数量、币种（currency 为空时不限币种）及时间
+func (p *SalesPrice) Applies(quantity float64, currency string, at time.Time) bool {
+	if currency != "" && p.Currency != currency {
+		return false
+	}
+	return quantity >= p.MinQuantity && p.IsEffective(at)
+}
+
+// SelectPrice 选取适用价目：客户专属价优先于价目组价；同一范围内取已达到的最高起订量，再取最近生效的
+func SelectPrice(prices []SalesPrice, clientID uint, quantity float64, currency string, at time.Time) *SalesPrice {
+	candidates := make([]SalesPrice, 0, len(prices))
+	for _, p := range prices {
+		if p.Applies(quantity, currency, at) {
+			candidates = append(candidates, p)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if specific := a.ClientID == clientID; specific != (b.ClientID == clientID) {
+			return specific
+		}
+		if a.MinQuantity != b.MinQuantity {
+			return a.MinQuantity > b.MinQuantity
+		}
+		if !a.ValidFrom.Equal(b.ValidFrom) {
+			return a.ValidFrom.After(b.ValidFrom)
+		}
+		return a.ID > b.ID
+	})
+	return &candidates[0]
+}
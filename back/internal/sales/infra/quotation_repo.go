@@ -0,0 +1,59 @@
+package infra
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"back/internal/sales/domain"
+)
+
+// QuotationRepo 报价单仓储
+type QuotationRepo struct {
+	db *gorm.DB
+}
+
+// NewQuotationRepo 创建报价单仓储
+func NewQuotationRepo(db *gorm.DB) *QuotationRepo {
+	return &QuotationRepo{db: db}
+}
+
+// Create 创建报价单（含明细）
+func (r *QuotationRepo) Create(ctx context.Context, quotation *domain.Quotation) error {
+	return r.db.WithContext(ctx).Create(quotation).Error
+}
+
+// FindByID 根据 ID 查询报价单（含明细）
+func (r *QuotationRepo) FindByID(ctx context.Context, id uint) (*domain.Quotation, error) {
+	var quotation domain.Quotation
+	err := r.db.WithContext(ctx).
+		Preload("Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id ASC")
+		}).
+		First(&quotation, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrQuotationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &quotation, nil
+}
+
+// FindList 查询报价单列表（不含明细）
+func (r *QuotationRepo) FindList(ctx context.Context, clientID uint, limit, offset int) ([]domain.Quotation, int64, error) {
+	var quotations []domain.Quotation
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&domain.Quotation{})
+	if clientID != 0 {
+		query = query.Where("client_id = ?", clientID)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&quotations).Error
+	return quotations, total, err
+}
@@ -0,0 +1,94 @@
+package infra
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"back/internal/sales/domain"
+)
+
+// SalesPriceRepo 销售价目仓储
+type SalesPriceRepo struct {
+	db *gorm.DB
+}
+
+// NewSalesPriceRepo 创建销售价目仓储
+func NewSalesPriceRepo(db *gorm.DB) *SalesPriceRepo {
+	return &SalesPriceRepo{db: db}
+}
+
+// SalesPriceFilter 销售价目查询条件（零值表示不限）
+type SalesPriceFilter struct {
+	ClientID   uint
+	PriceGroup string
+	ProductID  uint
+}
+
+// Save 保存销售价目
+func (r *SalesPriceRepo) Save(ctx context.Context, price *domain.SalesPrice) error {
+	return r.db.WithContext(ctx).Save(price).Error
+}
+
+// FindByID 根据 ID 查询销售价目
+func (r *SalesPriceRepo) FindByID(ctx context.Context, id uint) (*domain.SalesPrice, error) {
+	var price domain.SalesPrice
+	err := r.db.WithContext(ctx).First(&price, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrSalesPriceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &price, nil
+}
+
+// Delete 删除销售价目
+func (r *SalesPriceRepo) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&domain.SalesPrice{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrSalesPriceNotFound
+	}
+	return nil
+}
+
+// FindList 查询销售价目列表
+func (r *SalesPriceRepo) FindList(ctx context.Context, filter SalesPriceFilter, limit, offset int) ([]domain.SalesPrice, int64, error) {
+	var prices []domain.SalesPrice
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&domain.SalesPrice{})
+	if filter.ClientID != 0 {
+		query = query.Where("client_id = ?", filter.ClientID)
+	}
+	if filter.PriceGroup != "" {
+		query = query.Where("price_group = ?", filter.PriceGroup)
+	}
+	if filter.ProductID != 0 {
+		query = query.Where("product_id = ?", filter.ProductID)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("product_id ASC, client_id DESC, price_group ASC, min_quantity ASC, valid_from DESC").
+		Limit(limit).Offset(offset).Find(&prices).Error
+	return prices, total, err
+}
+
+// FindCandidates 查询产品对客户可用的全部价目（客户专属价及其价目组价，不过滤有效期）
+func (r *SalesPriceRepo) FindCandidates(ctx context.Context, productID, clientID uint, priceGroup string) ([]domain.SalesPrice, error) {
+	var prices []domain.SalesPrice
+	query := r.db.WithContext(ctx).Where("product_id = ?", productID)
+	if priceGroup != "" {
+		query = query.Where("client_id = ? OR (client_id = 0 AND price_group = ?)", clientID, priceGroup)
+	} else {
+		query = query.Where("client_id = ?", clientID)
+	}
+	err := query.Find(&prices).Error
+	return prices, err
+}
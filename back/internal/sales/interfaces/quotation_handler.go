@@ -0,0 +1,145 @@
+package interfaces
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"back/internal/sales/application"
+	"back/pkg/endpoint"
+)
+
+// QuotationHandler 客户报价单 Handler
+type QuotationHandler struct {
+	service *application.QuotationService
+}
+
+// NewQuotationHandler 创建 Handler
+func NewQuotationHandler(service *application.QuotationService) *QuotationHandler {
+	return &QuotationHandler{service: service}
+}
+
+// Create godoc
+// @Summary      创建报价单
+// @Description  为客户创建报价单，未填写单价的明细取客户适用的销售价目（须与报价币种一致）；低于成本加成下限的明细在 warnings 中返回（不写入报价文件）
+// @Tags         销售报价
+// @Accept       json
+// @Produce      json
+// @Param        request body application.CreateQuotationRequest true "报价单"
+// @Success      200 {object} application.QuotationResponse "创建成功"
+// @Failure      400 {object} map[string]string "请求参数错误或无适用价目"
+// @Failure      404 {object} map[string]string "客户或产品不存在"
+// @Security     Bearer
+// @Router       /sales/quotations [post]
+func (h *QuotationHandler) Create(c *gin.Context) {
+	var req application.CreateQuotationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, username := getOperator(c)
+	resp, err := h.service.Create(c.Request.Context(), &req, userID, username)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	recordAudit(c, resp.ID, resp.Quotation)
+	c.JSON(http.StatusOK, resp)
+}
+
+// List godoc
+// @Summary      报价单列表
+// @Description  按客户分页查询报价单，按创建时间倒序
+// @Tags         销售报价
+// @Produce      json
+// @Param        client_id query int false "客户ID"
+// @Param        limit query int false "每页数量" default(20)
+// @Param        offset query int false "偏移量" default(0)
+// @Success      200 {object} application.QuotationListResponse "报价单列表"
+// @Security     Bearer
+// @Router       /sales/quotations [get]
+func (h *QuotationHandler) List(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	resp, err := h.service.List(c.Request.Context(), queryUint(c, "client_id"), limit, offset)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Get godoc
+// @Summary      报价单详情
+// @Description  查询报价单及明细
+// @Tags         销售报价
+// @Produce      json
+// @Param        id path int true "报价单ID"
+// @Success      200 {object} domain.Quotation "报价单"
+// @Failure      404 {object} map[string]string "报价单不存在"
+// @Security     Bearer
+// @Router       /sales/quotations/{id} [get]
+func (h *QuotationHandler) Get(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	quotation, err := h.service.Get(c.Request.Context(), id)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, quotation)
+}
+
+// Document godoc
+// @Summary      下载报价文件
+// @Description  生成面向客户的报价单 XLSX（中英双语抬头、明细与合计，不含成本信息）
+// @Tags         销售报价
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param        id path int true "报价单ID"
+// @Success      200 {file} file "XLSX 文件"
+// @Failure      404 {object} map[string]string "报价单不存在"
+// @Security     Bearer
+// @Router       /sales/quotations/{id}/document [get]
+func (h *QuotationHandler) Document(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	quotation, err := h.service.Get(c.Request.Context(), id)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	c.Header("Content-Disposition", "attachment; filename="+application.DocumentName(quotation))
+	if err := h.service.WriteDocument(c.Request.Context(), quotation, c.Writer); err != nil {
+		// 尚未写出内容时返回 JSON 错误
+		if !c.Writer.Written() {
+			c.Header("Content-Type", "")
+			c.Header("Content-Disposition", "")
+			handleError(c, err)
+		}
+		return
+	}
+}
+
+// GetRoutes 获取路由定义
+func (h *QuotationHandler) GetRoutes() []endpoint.RouteDefinition {
+	return []endpoint.RouteDefinition{
+		{Method: "POST", Path: "/sales/quotations", Handler: h.Create, Domain: "sales", Action: "quotation"},
+		{Method: "GET", Path: "/sales/quotations", Handler: h.List, Domain: "", Action: ""},
+		{Method: "GET", Path: "/sales/quotations/:id", Handler: h.Get, Domain: "", Action: ""},
+		{Method: "GET", Path: "/sales/quotations/:id/document", Handler: h.Document, Domain: "", Action: ""},
+	}
+}
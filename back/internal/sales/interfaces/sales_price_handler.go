@@ -0,0 +1,283 @@
+package interfaces
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	clientDomain "back/internal/client/domain"
+	currencyDomain "back/internal/currency/domain"
+	productDomain "back/internal/product/domain"
+	"back/internal/sales/application"
+	"back/internal/sales/domain"
+	"back/internal/sales/infra"
+	"back/pkg/audit"
+	"back/pkg/endpoint"
+)
+
+// SalesPriceHandler 销售价目 Handler
+type SalesPriceHandler struct {
+	service *application.SalesPriceService
+}
+
+// NewSalesPriceHandler 创建 Handler
+func NewSalesPriceHandler(service *application.SalesPriceService) *SalesPriceHandler {
+	return &SalesPriceHandler{service: service}
+}
+
+// handleError 统一错误响应
+func handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrSalesPriceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "销售价目不存在"})
+	case errors.Is(err, domain.ErrQuotationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "报价单不存在"})
+	case errors.Is(err, clientDomain.ErrClientNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "客户不存在"})
+	case errors.Is(err, productDomain.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "产品不存在"})
+	case errors.Is(err, domain.ErrPriceScopeRequired), errors.Is(err, domain.ErrPriceScopeConflict):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "客户与价目组须且只能填写一项"})
+	case errors.Is(err, domain.ErrInvalidValidity):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "失效日期必须晚于生效日期"})
+	case errors.Is(err, domain.ErrQuotationValidity):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "报价有效期必须晚于当前时间"})
+	case errors.Is(err, domain.ErrNoApplicablePrice):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "没有适用的销售价目，请填写单价: " + err.Error()})
+	case errors.Is(err, domain.ErrPriceGroupTooLong), errors.Is(err, domain.ErrInvalidPrice),
+		errors.Is(err, domain.ErrInvalidMinQuantity), errors.Is(err, domain.ErrQuotationItems),
+		errors.Is(err, domain.ErrQuotationQuantity), errors.Is(err, domain.ErrQuotationUnitPrice):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, currencyDomain.ErrInvalidCurrency):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的币种代码"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// parseID 解析路径中的 ID
+func parseID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的ID"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// queryUint 解析可选的无符号整数查询参数（缺省为 0）
+func queryUint(c *gin.Context, key string) uint {
+	value, _ := strconv.ParseUint(c.Query(key), 10, 32)
+	return uint(value)
+}
+
+// getOperator 从上下文获取当前操作人
+func getOperator(c *gin.Context) (uint, string) {
+	id, _ := strconv.Atoi(c.GetString("loginId"))
+	return uint(id), c.GetString("username")
+}
+
+// recordAudit 记录审计
+func recordAudit(c *gin.Context, id uint, value interface{}) {
+	if recorder := audit.Get(c); recorder != nil {
+		recorder.SetResourceID(id)
+		recorder.SetNew(value)
+	}
+}
+
+// Create godoc
+// @Summary      创建销售价目
+// @Description  为客户（client_id）或客户价目组（price_group）设置产品售价，可指定币种、有效期与起订量
+// @Tags         销售价目
+// @Accept       json
+// @Produce      json
+// @Param        request body application.SalesPriceRequest true "销售价目"
+// @Success      200 {object} domain.SalesPrice "创建成功"
+// @Failure      400 {object} map[string]string "请求参数错误"
+// @Failure      404 {object} map[string]string "客户或产品不存在"
+// @Security     Bearer
+// @Router       /sales/prices [post]
+func (h *SalesPriceHandler) Create(c *gin.Context) {
+	var req application.SalesPriceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, username := getOperator(c)
+	price, err := h.service.Create(c.Request.Context(), &req, userID, username)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	recordAudit(c, price.ID, price)
+	c.JSON(http.StatusOK, price)
+}
+
+// Update godoc
+// @Summary      更新销售价目
+// @Description  整体更新销售价目（已引用该价目的订单与报价单保留原单价）
+// @Tags         销售价目
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "价目ID"
+// @Param        request body application.SalesPriceRequest true "销售价目"
+// @Success      200 {object} domain.SalesPrice "更新成功"
+// @Failure      400 {object} map[string]string "请求参数错误"
+// @Failure      404 {object} map[string]string "价目、客户或产品不存在"
+// @Security     Bearer
+// @Router       /sales/prices/{id} [put]
+func (h *SalesPriceHandler) Update(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	var req application.SalesPriceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	price, err := h.service.Update(c.Request.Context(), id, &req)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	recordAudit(c, price.ID, price)
+	c.JSON(http.StatusOK, price)
+}
+
+// Delete godoc
+// @Summary      删除销售价目
+// @Description  删除销售价目
+// @Tags         销售价目
+// @Produce      json
+// @Param        id path int true "价目ID"
+// @Success      200 {object} map[string]string "删除成功"
+// @Failure      404 {object} map[string]string "价目不存在"
+// @Security     Bearer
+// @Router       /sales/prices/{id} [delete]
+func (h *SalesPriceHandler) Delete(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	if recorder := audit.Get(c); recorder != nil {
+		recorder.SetResourceID(id)
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
+
+// Get godoc
+// @Summary      销售价目详情
+// @Description  查询销售价目
+// @Tags         销售价目
+// @Produce      json
+// @Param        id path int true "价目ID"
+// @Success      200 {object} domain.SalesPrice "销售价目"
+// @Failure      404 {object} map[string]string "价目不存在"
+// @Security     Bearer
+// @Router       /sales/prices/{id} [get]
+func (h *SalesPriceHandler) Get(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	price, err := h.service.Get(c.Request.Context(), id)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, price)
+}
+
+// List godoc
+// @Summary      销售价目列表
+// @Description  按客户、价目组、产品分页查询销售价目（含已失效价目）
+// @Tags         销售价目
+// @Produce      json
+// @Param        client_id query int false "客户ID"
+// @Param        price_group query string false "价目组"
+// @Param        product_id query int false "产品ID"
+// @Param        limit query int false "每页数量" default(20)
+// @Param        offset query int false "偏移量" default(0)
+// @Success      200 {object} application.SalesPriceListResponse "价目列表"
+// @Security     Bearer
+// @Router       /sales/prices [get]
+func (h *SalesPriceHandler) List(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	filter := infra.SalesPriceFilter{
+		ClientID:   queryUint(c, "client_id"),
+		PriceGroup: c.Query("price_group"),
+		ProductID:  queryUint(c, "product_id"),
+	}
+
+	resp, err := h.service.List(c.Request.Context(), filter, limit, offset)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Applicable godoc
+// @Summary      客户适用价目
+// @Description  查询客户在指定数量下当前适用的售价（客户专属价优先于价目组价，取已达到的最高起订量），供下单时预填单价；低于成本加成下限时附带预警
+// @Tags         销售价目
+// @Produce      json
+// @Param        client_id query int true "客户ID"
+// @Param        product_id query int true "产品ID"
+// @Param        quantity query number false "数量" default(1)
+// @Param        currency query string false "币种（为空时不限）"
+// @Success      200 {object} application.ApplicablePriceResponse "适用价目（无适用价目时 price 为 null）"
+// @Failure      400 {object} map[string]string "请求参数错误"
+// @Failure      404 {object} map[string]string "客户不存在"
+// @Security     Bearer
+// @Router       /sales/prices/applicable [get]
+func (h *SalesPriceHandler) Applicable(c *gin.Context) {
+	clientID := queryUint(c, "client_id")
+	productID := queryUint(c, "product_id")
+	if clientID == 0 || productID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "client_id 和 product_id 不能为空"})
+		return
+	}
+	quantity, err := strconv.ParseFloat(c.DefaultQuery("quantity", "1"), 64)
+	if err != nil || quantity <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的数量"})
+		return
+	}
+
+	resp, err := h.service.Applicable(c.Request.Context(), clientID, productID, quantity, c.Query("currency"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetRoutes 获取路由定义
+func (h *SalesPriceHandler) GetRoutes() []endpoint.RouteDefinition {
+	return []endpoint.RouteDefinition{
+		{Method: "POST", Path: "/sales/prices", Handler: h.Create, Domain: "sales", Action: "price"},
+		{Method: "GET", Path: "/sales/prices", Handler: h.List, Domain: "", Action: ""},
+		{Method: "GET", Path: "/sales/prices/applicable", Handler: h.Applicable, Domain: "", Action: ""},
+		{Method: "GET", Path: "/sales/prices/:id", Handler: h.Get, Domain: "", Action: ""},
+		{Method: "PUT", Path: "/sales/prices/:id", Handler: h.Update, Domain: "sales", Action: "price"},
+		{Method: "DELETE", Path: "/sales/prices/:id", Handler: h.Delete, Domain: "sales", Action: "price"},
+	}
+}
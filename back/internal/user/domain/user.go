@@ -20,6 +20,7 @@ const (
 	RoleSalesManager         = "salesManager"         // 销售经理
 	RoleSalesAssistant       = "salesAssistant"       // 销售助理
 	RoleFabricDeveloper      = "fabricDeveloper"      // 布料开发
+	RolePurchasing           = "purchasing"           // 采购
 )
 
 // 获取所有角色
@@ -37,6 +38,7 @@ func GetAllRoles() []string {
 		RoleSalesManager,
 		RoleSalesAssistant,
 		RoleFabricDeveloper,
+		RolePurchasing,
 	}
 }
 
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/elastic/go-elasticsearch/v8"
 	"github.com/elastic/go-elasticsearch/v8/esapi"
@@ -299,4 +300,46 @@ func (s *ESSync) Delete(indexName, docID string) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+// DeleteOlderThan 删除指定时间字段早于 before 的文档（用于定时重建的快照索引清理过期文档）
+func (s *ESSync) DeleteOlderThan(indexName, field string, before time.Time) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"query": map[string]interface{}{
+			"range": map[string]interface{}{
+				field: map[string]interface{}{"lt": before},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	refresh := true
+	req := esapi.DeleteByQueryRequest{
+		Index:   []string{indexName},
+		Body:    bytes.NewReader(body),
+		Refresh: &refresh,
+	}
+
+	res, err := req.Do(context.Background(), s.client)
+	if err != nil {
+		if s.logger != nil {
+			s.logger.Error("delete by query failed", "error", err)
+		}
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		// 索引不存在不算错误
+		if res.StatusCode == 404 {
+			return nil
+		}
+		if s.logger != nil {
+			s.logger.Error("delete by query error", "status", res.Status())
+		}
+		return fmt.Errorf("delete by query error: %s", res.String())
+	}
+
+	return nil
+}
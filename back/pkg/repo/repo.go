@@ -21,13 +21,13 @@ func NewRepo[T any](db *gorm.DB) *Repo[T] {
 
 // Create 创建
 func (r *Repo[T]) Create(ctx context.Context, entity *T) error {
-	return r.db.WithContext(ctx).Create(entity).Error
+	return Conn(ctx, r.db).Create(entity).Error
 }
 
 // GetByID 根据ID查询
 func (r *Repo[T]) GetByID(ctx context.Context, id uint) (*T, error) {
 	var entity T
-	err := r.db.WithContext(ctx).First(&entity, id).Error
+	err := Conn(ctx, r.db).First(&entity, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -37,7 +37,7 @@ func (r *Repo[T]) GetByID(ctx context.Context, id uint) (*T, error) {
 // List 列表查询（带分页）
 func (r *Repo[T]) List(ctx context.Context, limit, offset int) ([]T, error) {
 	var list []T
-	query := r.db.WithContext(ctx)
+	query := Conn(ctx, r.db)
 	
 	if limit > 0 {
 		query = query.Limit(limit)
@@ -52,13 +52,13 @@ func (r *Repo[T]) List(ctx context.Context, limit, offset int) ([]T, error) {
 
 // Update 更新
 func (r *Repo[T]) Update(ctx context.Context, entity *T) error {
-	return r.db.WithContext(ctx).Save(entity).Error
+	return Conn(ctx, r.db).Save(entity).Error
 }
 
 // UpdateFields 更新部分字段
 func (r *Repo[T]) UpdateFields(ctx context.Context, id uint, fields map[string]interface{}) error {
 	var entity T
-	return r.db.WithContext(ctx).
+	return Conn(ctx, r.db).
 		Model(&entity).
 		Where("id = ?", id).
 		Updates(fields).Error
@@ -67,7 +67,7 @@ func (r *Repo[T]) UpdateFields(ctx context.Context, id uint, fields map[string]i
 // Delete 删除
 func (r *Repo[T]) Delete(ctx context.Context, id uint) error {
 	var entity T
-	return r.db.WithContext(ctx).Delete(&entity, id).Error
+	return Conn(ctx, r.db).Delete(&entity, id).Error
 }
 
 // ──────────────────────────────────────
@@ -77,14 +77,14 @@ func (r *Repo[T]) Delete(ctx context.Context, id uint) error {
 // FindWhere 条件查询
 func (r *Repo[T]) FindWhere(ctx context.Context, condition string, args ...interface{}) ([]T, error) {
 	var list []T
-	err := r.db.WithContext(ctx).Where(condition, args...).Find(&list).Error
+	err := Conn(ctx, r.db).Where(condition, args...).Find(&list).Error
 	return list, err
 }
 
 // First 查询第一条
 func (r *Repo[T]) First(ctx context.Context, query map[string]interface{}) (*T, error) {
 	var entity T
-	db := r.db.WithContext(ctx)
+	db := Conn(ctx, r.db)
 	
 	for k, v := range query {
 		db = db.Where(k+" = ?", v)
@@ -100,7 +100,7 @@ func (r *Repo[T]) First(ctx context.Context, query map[string]interface{}) (*T,
 // Count 统计数量
 func (r *Repo[T]) Count(ctx context.Context, query map[string]interface{}) (int64, error) {
 	var count int64
-	db := r.db.WithContext(ctx).Model(new(T))
+	db := Conn(ctx, r.db).Model(new(T))
 	
 	for k, v := range query {
 		db = db.Where(k+" = ?", v)
@@ -122,13 +122,13 @@ func (r *Repo[T]) Exists(ctx context.Context, query map[string]interface{}) (boo
 
 // BatchCreate 批量创建
 func (r *Repo[T]) BatchCreate(ctx context.Context, entities []T) error {
-	return r.db.WithContext(ctx).Create(&entities).Error
+	return Conn(ctx, r.db).Create(&entities).Error
 }
 
 // BatchDelete 批量删除
 func (r *Repo[T]) BatchDelete(ctx context.Context, ids []uint) error {
 	var entity T
-	return r.db.WithContext(ctx).Delete(&entity, ids).Error
+	return Conn(ctx, r.db).Delete(&entity, ids).Error
 }
 
 // ──────────────────────────────────────
@@ -137,7 +137,7 @@ func (r *Repo[T]) BatchDelete(ctx context.Context, ids []uint) error {
 
 // Transaction 执行事务
 func (r *Repo[T]) Transaction(ctx context.Context, fn func(*gorm.DB) error) error {
-	return r.db.WithContext(ctx).Transaction(fn)
+	return Conn(ctx, r.db).Transaction(fn)
 }
 
 // WithTx 返回带事务的 Repo
@@ -151,12 +151,12 @@ func (r *Repo[T]) WithTx(tx *gorm.DB) *Repo[T] {
 
 // Raw 原生查询
 func (r *Repo[T]) Raw(ctx context.Context, sql string, dest interface{}, args ...interface{}) error {
-	return r.db.WithContext(ctx).Raw(sql, args...).Scan(dest).Error
+	return Conn(ctx, r.db).Raw(sql, args...).Scan(dest).Error
 }
 
 // Exec 执行原生 SQL
 func (r *Repo[T]) Exec(ctx context.Context, sql string, args ...interface{}) error {
-	return r.db.WithContext(ctx).Exec(sql, args...).Error
+	return Conn(ctx, r.db).Exec(sql, args...).Error
 }
 
 // ──────────────────────────────────────
@@ -166,7 +166,7 @@ func (r *Repo[T]) Exec(ctx context.Context, sql string, args ...interface{}) err
 // GetByIDWithPreload 带预加载的查询
 func (r *Repo[T]) GetByIDWithPreload(ctx context.Context, id uint, preloads ...string) (*T, error) {
 	var entity T
-	db := r.db.WithContext(ctx)
+	db := Conn(ctx, r.db)
 	
 	for _, preload := range preloads {
 		db = db.Preload(preload)
@@ -182,7 +182,7 @@ func (r *Repo[T]) GetByIDWithPreload(ctx context.Context, id uint, preloads ...s
 // ListWithPreload 带预加载的列表查询
 func (r *Repo[T]) ListWithPreload(ctx context.Context, limit, offset int, preloads ...string) ([]T, error) {
 	var list []T
-	db := r.db.WithContext(ctx)
+	db := Conn(ctx, r.db)
 	
 	for _, preload := range preloads {
 		db = db.Preload(preload)
@@ -0,0 +1,25 @@
+package repo
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// txKey 上下文中保存事务的键
+type txKey struct{}
+
+// WithTx 将事务放入上下文，其他仓储通过 Conn 加入同一事务
+func WithTx(ctx context.Context, tx *gorm.DB) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// Conn 返回执行语句的连接：db 已绑定事务时使用 db，否则加入上下文中的事务（没有时直接使用 db）
+func Conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if _, inTx := db.Statement.ConnPool.(gorm.TxCommitter); !inTx {
+		if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+			return tx.WithContext(ctx)
+		}
+	}
+	return db.WithContext(ctx)
+}
@@ -0,0 +1,107 @@
+package schedule
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	applog "back/pkg/log"
+)
+
+// JobFunc 定时任务函数
+type JobFunc func(ctx context.Context) error
+
+// job 定时任务定义
+type job struct {
+	name string
+	next func(now time.Time) time.Time // 计算下次执行时间
+	run  JobFunc
+}
+
+// Scheduler 进程内定时任务调度器（单实例运行）
+type Scheduler struct {
+	logger *applog.Logger
+	jobs   []*job
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewScheduler 创建调度器
+func NewScheduler(logger *applog.Logger) *Scheduler {
+	return &Scheduler{logger: logger}
+}
+
+// Every 按固定间隔执行
+func (s *Scheduler) Every(name string, interval time.Duration, fn JobFunc) {
+	s.jobs = append(s.jobs, &job{
+		name: name,
+		next: func(now time.Time) time.Time { return now.Add(interval) },
+		run:  fn,
+	})
+}
+
+// Daily 每天在指定时刻执行（本地时间）
+func (s *Scheduler) Daily(name string, hour, minute int, fn JobFunc) {
+	s.jobs = append(s.jobs, &job{
+		name: name,
+		next: func(now time.Time) time.Time {
+			next := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
+			if !next.After(now) {
+				next = next.AddDate(0, 0, 1)
+			}
+			return next
+		},
+		run: fn,
+	})
+}
+
+// Start 启动所有任务
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, j)
+	}
+}
+
+// Stop 停止所有任务并等待正在执行的任务结束
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+// loop 单个任务的执行循环
+func (s *Scheduler) loop(ctx context.Context, j *job) {
+	defer s.wg.Done()
+
+	for {
+		timer := time.NewTimer(time.Until(j.next(time.Now())))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			s.execute(ctx, j)
+		}
+	}
+}
+
+// execute 执行任务（捕获 panic，避免影响其他任务）
+func (s *Scheduler) execute(ctx context.Context, j *job) {
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error("定时任务异常", applog.String("job", j.name), applog.Any("panic", r))
+		}
+	}()
+
+	if err := j.run(ctx); err != nil {
+		s.logger.Error("定时任务执行失败", applog.String("job", j.name), applog.Error(err))
+		return
+	}
+	s.logger.Info("定时任务执行完成", applog.String("job", j.name), applog.Duration("elapsed", time.Since(start)))
+}
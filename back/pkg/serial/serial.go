@@ -0,0 +1,15 @@
+package serial
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// New 生成单号：前缀 + 时间（精确到秒）+ 6 位随机后缀，同一秒内创建的单号不会冲突
+func New(prefix string, at time.Time) string {
+	suffix := make([]byte, 3)
+	_, _ = rand.Read(suffix)
+	return prefix + at.Format("20060102150405") + strings.ToUpper(hex.EncodeToString(suffix))
+}
@@ -0,0 +1,126 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// SignatureHeader 请求体 HMAC-SHA256 签名头（sha256=<hex>，未配置密钥时不发送）
+const SignatureHeader = "X-Webhook-Signature"
+
+// EventHeader 事件类型头
+const EventHeader = "X-Webhook-Event"
+
+// ErrForbiddenAddress 目标地址为本机、链路本地或内网地址
+var ErrForbiddenAddress = errors.New("webhook destination is a loopback, link-local or private address")
+
+// Sender 出站 Webhook 发送器（JSON POST，2xx 视为成功）
+//
+// 只允许连接公网地址：建立连接时校验实际连接的 IP（防止 DNS 重绑定），且不跟随重定向。
+type Sender struct {
+	client *http.Client
+	secret string
+}
+
+// NewSender 创建发送器（secret 为空时不签名）
+func NewSender(timeout time.Duration, secret string) *Sender {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+				return ErrForbiddenAddress
+			}
+			return nil
+		},
+	}
+	return &Sender{
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: &http.Transport{DialContext: dialer.DialContext},
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		secret: secret,
+	}
+}
+
+// ValidateURL 校验 Webhook 地址（须为 http / https 绝对地址，且解析后的地址均为公网地址）
+func ValidateURL(ctx context.Context, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("invalid webhook url: %q", raw)
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil {
+		return fmt.Errorf("resolve webhook host %q: %w", u.Hostname(), err)
+	}
+	for _, addr := range addrs {
+		if !publicIP(addr.IP) {
+			return ErrForbiddenAddress
+		}
+	}
+	return nil
+}
+
+// sharedAddressSpace 运营商级 NAT 地址段（100.64.0.0/10）
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// publicIP 是否为可访问的公网地址（排除本机、链路本地、内网、组播与未指定地址）
+func publicIP(ip net.IP) bool {
+	return !ip.IsLoopback() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsPrivate() &&
+		!ip.IsUnspecified() &&
+		!sharedAddressSpace.Contains(ip)
+}
+
+// Post 发送事件
+func (s *Sender) Post(ctx context.Context, target, event string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, event)
+	if s.secret != "" {
+		mac := hmac.New(sha256.New, []byte(s.secret))
+		mac.Write(body)
+		req.Header.Set(SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}